@@ -109,6 +109,7 @@ server:
   debug_log: false
   log_path: './logs'
   plugin_path: './plugins'
+  plugin_pool_size: 5
   enable_https: false
   cert_file_path: './etc/cert.pem'
   key_file_path: './etc/key.pem'
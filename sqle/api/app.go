@@ -141,8 +141,26 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.GET("/configurations/wechat", v1.GetWeChatConfiguration, AdminUserAllowed())
 		v1Router.PATCH("/configurations/wechat", v1.UpdateWeChatConfigurationV1, AdminUserAllowed())
 		v1Router.POST("/configurations/wechat/test", v1.TestWeChatConfigurationV1, AdminUserAllowed())
+		v1Router.GET("/configurations/slack", v1.GetSlackConfiguration, AdminUserAllowed())
+		v1Router.PATCH("/configurations/slack", v1.UpdateSlackConfiguration, AdminUserAllowed())
+		v1Router.POST("/configurations/slack/test", v1.TestSlackConfiguration, AdminUserAllowed())
+		v1Router.GET("/configurations/ding_talk", v1.GetDingTalkConfiguration, AdminUserAllowed())
+		v1Router.PATCH("/configurations/ding_talk", v1.UpdateDingTalkConfiguration, AdminUserAllowed())
+		v1Router.POST("/configurations/ding_talk/test", v1.TestDingTalkConfiguration, AdminUserAllowed())
+		v1Router.GET("/configurations/feishu", v1.GetFeishuConfiguration, AdminUserAllowed())
+		v1Router.PATCH("/configurations/feishu", v1.UpdateFeishuConfiguration, AdminUserAllowed())
+		v1Router.POST("/configurations/feishu/test", v1.TestFeishuConfiguration, AdminUserAllowed())
+		v1Router.GET("/configurations/mattermost", v1.GetMattermostConfiguration, AdminUserAllowed())
+		v1Router.PATCH("/configurations/mattermost", v1.UpdateMattermostConfiguration, AdminUserAllowed())
+		v1Router.POST("/configurations/mattermost/test", v1.TestMattermostConfiguration, AdminUserAllowed())
 		v1Router.GET("/configurations/system_variables", v1.GetSystemVariables, AdminUserAllowed())
 		v1Router.PATCH("/configurations/system_variables", v1.UpdateSystemVariables, AdminUserAllowed())
+		v1Router.GET("/configurations/drivers/plugin_pools", v1.GetPluginPoolsStats, AdminUserAllowed())
+		v1Router.GET("/configurations/drivers/plugins", v1.GetPlugins, AdminUserAllowed())
+		v1Router.POST("/configurations/drivers/plugins", v1.InstallPlugin, AdminUserAllowed())
+		v1Router.POST("/configurations/drivers/plugins/:plugin_name/reload", v1.ReloadPlugin, AdminUserAllowed())
+		v1Router.DELETE("/configurations/drivers/plugins/:plugin_name/", v1.UnloadPlugin, AdminUserAllowed())
+		v1Router.GET("/configurations/drivers/plugins/:plugin_name/versions", v1.GetPluginVersions, AdminUserAllowed())
 		v1Router.GET("/configurations/license", v1.GetLicense, AdminUserAllowed())
 		v1Router.POST("/configurations/license", v1.SetLicense, AdminUserAllowed())
 		v1Router.GET("/configurations/license/info", v1.GetSQLELicenseInfo, AdminUserAllowed())
@@ -169,6 +187,8 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.PATCH("/user", v1.UpdateCurrentUser)
 	v1Router.GET("/user_tips", v1.GetUserTips)
 	v1Router.PUT("/user/password", v1.UpdateCurrentUserPassword)
+	v1Router.GET("/user/notify_setting", v1.GetCurrentUserNotifySetting)
+	v1Router.PATCH("/user/notify_setting", v1.UpdateCurrentUserNotifySetting)
 
 	// operations
 	v1Router.GET("/operations", v1.GetOperations)
@@ -227,7 +247,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/audit_plans/:audit_plan_name/", v1.GetAuditPlan)
 	v1Router.GET("/audit_plans", v1.GetAuditPlans)
 	v1Router.GET("/audit_plans/:audit_plan_name/reports", v1.GetAuditPlanReports)
+	v1Router.GET("/audit_plans/:audit_plan_name/runs", v1.GetAuditPlanRuns)
 	v1Router.GET("/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/", v1.GetAuditPlanReport)
+	v1Router.GET("/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/export", v1.ExportAuditPlanReport)
 	// deprecated
 	v1Router.GET("/audit_plans/:audit_plan_name/report/:audit_plan_report_id/", DeprecatedBy(apiV2))
 	v2Router.GET("/audit_plans/:audit_plan_name/report/:audit_plan_report_id/", v2.GetAuditPlanReportSQLs)
@@ -239,6 +261,8 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.POST("/audit_plans/:audit_plan_name/sqls/full", v1.FullSyncAuditPlanSQLs, sqleMiddleware.ScannerVerifier())
 	v1Router.POST("/audit_plans/:audit_plan_name/sqls/partial", v1.PartialSyncAuditPlanSQLs, sqleMiddleware.ScannerVerifier())
 	v1Router.POST("/audit_plans/:audit_plan_name/trigger", v1.TriggerAuditPlan)
+	v1Router.POST("/audit_plans/:audit_plan_name/pause", v1.PauseAuditPlan)
+	v1Router.POST("/audit_plans/:audit_plan_name/resume", v1.ResumeAuditPlan)
 	v1Router.GET("/audit_plan_metas", v1.GetAuditPlanMetas)
 	v1Router.PATCH("/audit_plans/:audit_plan_name/notify_config", v1.UpdateAuditPlanNotifyConfig)
 	v1Router.GET("/audit_plans/:audit_plan_name/notify_config", v1.GetAuditPlanNotifyConfig)
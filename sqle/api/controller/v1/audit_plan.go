@@ -15,6 +15,7 @@ import (
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/notification"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/pkg/report"
 	"github.com/actiontech/sqle/sqle/server"
 	"github.com/actiontech/sqle/sqle/server/auditplan"
 	"github.com/actiontech/sqle/sqle/utils"
@@ -440,6 +441,28 @@ type AuditPlanResV1 struct {
 	InstanceDatabase string          `json:"audit_plan_instance_database" example:"app1"`
 	RuleTemplateName string          `json:"rule_template_name" example:"default_MySQL"`
 	Meta             AuditPlanMetaV1 `json:"audit_plan_meta"`
+	Status           string          `json:"audit_plan_status" enums:"enabled,disabled"`
+	Health           string          `json:"audit_plan_health" enums:"healthy,unhealthy,unknown"`
+	LastRunStatus    string          `json:"audit_plan_last_run_status" enums:"success,failed,"`
+	LastRunAt        string          `json:"audit_plan_last_run_at" example:"RFC3339"`
+}
+
+const (
+	AuditPlanHealthHealthy   = "healthy"
+	AuditPlanHealthUnhealthy = "unhealthy"
+	AuditPlanHealthUnknown   = "unknown"
+)
+
+// getAuditPlanHealth returns the health of audit plan by the status of its latest run.
+func getAuditPlanHealth(lastRunStatus string) string {
+	switch lastRunStatus {
+	case model.AuditPlanRunStatusSuccess:
+		return AuditPlanHealthHealthy
+	case model.AuditPlanRunStatusFailed:
+		return AuditPlanHealthUnhealthy
+	default:
+		return AuditPlanHealthUnknown
+	}
 }
 
 // @Summary 获取审核计划信息列表
@@ -508,6 +531,15 @@ func GetAuditPlans(c echo.Context) error {
 			RuleTemplateName: ap.RuleTemplateName.String,
 			Token:            ap.Token,
 			Meta:             convertAuditPlanMetaToRes(meta),
+			Status:           model.AuditPlanStatusEnabled,
+			Health:           getAuditPlanHealth(ap.LastRunStatus.String),
+			LastRunStatus:    ap.LastRunStatus.String,
+		}
+		if ap.Status.String == model.AuditPlanStatusDisabled {
+			auditPlansResV1[i].Status = model.AuditPlanStatusDisabled
+		}
+		if ap.LastRunAt != nil {
+			auditPlansResV1[i].LastRunAt = ap.LastRunAt.Format(time.RFC3339)
 		}
 	}
 	return c.JSON(http.StatusOK, &GetAuditPlansResV1{
@@ -552,18 +584,34 @@ func GetAuditPlan(c echo.Context) error {
 	}
 	meta.Params = ap.Params
 
+	lastRun, hasRun, err := storage.GetLatestAuditPlanRun(ap.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res := AuditPlanResV1{
+		Name:             ap.Name,
+		Cron:             ap.CronExpression,
+		DBType:           ap.DBType,
+		InstanceName:     ap.InstanceName,
+		InstanceDatabase: ap.InstanceDatabase,
+		RuleTemplateName: ap.RuleTemplateName,
+		Token:            ap.Token,
+		Meta:             convertAuditPlanMetaToRes(meta),
+		Status:           model.AuditPlanStatusEnabled,
+		Health:           AuditPlanHealthUnknown,
+	}
+	if !ap.IsEnabled() {
+		res.Status = model.AuditPlanStatusDisabled
+	}
+	if hasRun {
+		res.Health = getAuditPlanHealth(lastRun.Status)
+		res.LastRunStatus = lastRun.Status
+		res.LastRunAt = lastRun.EndAt.Format(time.RFC3339)
+	}
+
 	return c.JSON(http.StatusOK, &GetAuditPlanResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data: AuditPlanResV1{
-			Name:             ap.Name,
-			Cron:             ap.CronExpression,
-			DBType:           ap.DBType,
-			InstanceName:     ap.InstanceName,
-			InstanceDatabase: ap.InstanceDatabase,
-			RuleTemplateName: ap.RuleTemplateName,
-			Token:            ap.Token,
-			Meta:             convertAuditPlanMetaToRes(meta),
-		},
+		Data:    res,
 	})
 }
 
@@ -584,6 +632,10 @@ type AuditPlanReportResV1 struct {
 	Score      int32   `json:"score"`
 	PassRate   float64 `json:"pass_rate"`
 	Timestamp  string  `json:"audit_plan_report_timestamp" example:"RFC3339"`
+
+	NewFindingCount        uint `json:"new_finding_count"`
+	PersistingFindingCount uint `json:"persisting_finding_count"`
+	ResolvedFindingCount   uint `json:"resolved_finding_count"`
 }
 
 // @Summary 获取指定审核计划的报告列表
@@ -633,6 +685,10 @@ func GetAuditPlanReports(c echo.Context) error {
 			Score:      auditPlanReport.Score.Int32,
 			PassRate:   auditPlanReport.PassRate.Float64,
 			Timestamp:  auditPlanReport.CreateAt,
+
+			NewFindingCount:        auditPlanReport.NewFindingCount,
+			PersistingFindingCount: auditPlanReport.PersistingFindingCount,
+			ResolvedFindingCount:   auditPlanReport.ResolvedFindingCount,
 		}
 	}
 	return c.JSON(http.StatusOK, &GetAuditPlanReportsResV1{
@@ -642,6 +698,104 @@ func GetAuditPlanReports(c echo.Context) error {
 	})
 }
 
+type GetAuditPlanRunsReqV1 struct {
+	FilterRunStatus string `json:"filter_run_status" query:"filter_run_status" valid:"omitempty,oneof=success failed"`
+	PageIndex       uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize        uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type GetAuditPlanRunsResV1 struct {
+	controller.BaseRes
+	Data      []AuditPlanRunResV1 `json:"data"`
+	TotalNums uint64              `json:"total_nums"`
+}
+
+type AuditPlanRunResV1 struct {
+	Id              uint   `json:"audit_plan_run_id" example:"1"`
+	RunType         string `json:"run_type" enums:"audit,collect"`
+	TriggerType     string `json:"trigger_type" enums:"schedule,manual"`
+	StartAt         string `json:"start_at" example:"RFC3339"`
+	EndAt           string `json:"end_at" example:"RFC3339"`
+	DurationMs      int64  `json:"duration_ms" example:"1500"`
+	SQLCount        uint   `json:"sql_count"`
+	AuditedSQLCount uint   `json:"audited_sql_count"`
+	ReportId        string `json:"audit_plan_report_id" example:"1"`
+	Status          string `json:"status" enums:"success,failed"`
+	ErrorMessage    string `json:"error_message"`
+}
+
+// @Summary 获取指定审核计划的运行记录
+// @Description get audit plan run history
+// @Id getAuditPlanRunsV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param audit_plan_name path string true "audit plan name"
+// @Param filter_run_status query string false "filter run status" Enums(success,failed)
+// @Param page_index query uint32 false "page index"
+// @Param page_size query uint32 false "size of per page"
+// @Success 200 {object} v1.GetAuditPlanRunsResV1
+// @router /v1/audit_plans/{audit_plan_name}/runs [get]
+func GetAuditPlanRuns(c echo.Context) error {
+	s := model.GetStorage()
+
+	req := new(GetAuditPlanRunsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+
+	apName := c.Param("audit_plan_name")
+	err := CheckCurrentUserCanAccessAuditPlan(c, apName, model.OP_AUDIT_PLAN_VIEW_OTHERS)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	var offset uint32
+	if req.PageIndex >= 1 {
+		offset = req.PageSize * (req.PageIndex - 1)
+	}
+
+	data := map[string]interface{}{
+		"audit_plan_name":   apName,
+		"filter_run_status": req.FilterRunStatus,
+		"limit":             req.PageSize,
+		"offset":            offset,
+	}
+	runs, count, err := s.GetAuditPlanRunsByReq(data)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	runsResV1 := make([]AuditPlanRunResV1, len(runs))
+	for i, run := range runs {
+		runsResV1[i] = AuditPlanRunResV1{
+			Id:              run.ID,
+			RunType:         run.RunType,
+			TriggerType:     run.TriggerType,
+			SQLCount:        run.SQLCount,
+			AuditedSQLCount: run.AuditedSQLCount,
+			Status:          run.Status,
+			ErrorMessage:    run.ErrorMessage.String,
+		}
+		if run.AuditPlanReportID != 0 {
+			runsResV1[i].ReportId = fmt.Sprintf("%v", run.AuditPlanReportID)
+		}
+		if run.StartAt != nil {
+			runsResV1[i].StartAt = run.StartAt.Format(time.RFC3339)
+		}
+		if run.EndAt != nil {
+			runsResV1[i].EndAt = run.EndAt.Format(time.RFC3339)
+		}
+		if run.StartAt != nil && run.EndAt != nil {
+			runsResV1[i].DurationMs = run.EndAt.Sub(*run.StartAt).Milliseconds()
+		}
+	}
+	return c.JSON(http.StatusOK, &GetAuditPlanRunsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      runsResV1,
+		TotalNums: count,
+	})
+}
+
 type GetAuditPlanReportResV1 struct {
 	controller.BaseRes
 	Data AuditPlanReportResV1 `json:"data"`
@@ -685,6 +839,10 @@ func GetAuditPlanReport(c echo.Context) error {
 			Score:      report.Score,
 			PassRate:   report.PassRate,
 			Timestamp:  report.CreatedAt.Format(time.RFC3339),
+
+			NewFindingCount:        report.NewFindingCount,
+			PersistingFindingCount: report.PersistingFindingCount,
+			ResolvedFindingCount:   report.ResolvedFindingCount,
 		},
 	})
 }
@@ -723,6 +881,74 @@ func GetAuditPlanReportSQLs(c echo.Context) error {
 	return nil
 }
 
+type ExportAuditPlanReportReqV1 struct {
+	ExportFormat string `json:"export_format" query:"export_format" enums:"csv,xlsx,html" valid:"omitempty,oneof=csv xlsx html"`
+}
+
+// @Summary 导出指定审核计划的SQL审核报告
+// @Description export audit plan report with the summary and SQLs
+// @Id exportAuditPlanReportV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param audit_plan_name path string true "audit plan name"
+// @Param audit_plan_report_id path string true "audit plan report id"
+// @Param export_format query string false "export format, default is csv" Enums(csv, xlsx, html)
+// @Success 200 file 1 "audit plan report file"
+// @router /v1/audit_plans/{audit_plan_name}/reports/{audit_plan_report_id}/export [get]
+func ExportAuditPlanReport(c echo.Context) error {
+	req := new(ExportAuditPlanReportReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	apName := c.Param("audit_plan_name")
+	err := CheckCurrentUserCanAccessAuditPlan(c, apName, model.OP_AUDIT_PLAN_VIEW_OTHERS)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	id := c.Param("audit_plan_report_id")
+	reportID, err := strconv.Atoi(id)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("parse audit plan report id failed: %v", err)))
+	}
+	s := model.GetStorage()
+	apReport, exist, err := s.GetAuditPlanReportByID(uint(reportID))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist || apReport.AuditPlan == nil || apReport.AuditPlan.Name != apName {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("audit plan report not exist")))
+	}
+
+	reportSQLs, _, err := s.GetAuditPlanReportSQLsByReq(map[string]interface{}{
+		"audit_plan_report_id": apReport.ID,
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	r := &report.Report{
+		Title:      fmt.Sprintf("审核计划报告_%v_%v", apName, id),
+		Score:      apReport.Score,
+		PassRate:   apReport.PassRate,
+		AuditLevel: apReport.AuditLevel,
+		Columns:    []string{"序号", "SQL", "SQL审核结果", "SQL位置", "问题状态"},
+	}
+	for _, sql := range reportSQLs {
+		r.Rows = append(r.Rows, &report.Row{
+			AuditLevel: sql.AuditLevel,
+			Values: []string{
+				strconv.FormatUint(uint64(sql.Number), 10),
+				sql.SQL,
+				sql.AuditResult,
+				sql.Location,
+				sql.FindingStatus,
+			},
+		})
+	}
+	return exportReport(c, r, report.Format(req.ExportFormat))
+}
+
 type FullSyncAuditPlanSQLsReqV1 struct {
 	SQLs []AuditPlanSQLReqV1 `json:"audit_plan_sql_list" form:"audit_plan_sql_list" valid:"dive"`
 }
@@ -733,6 +959,12 @@ type AuditPlanSQLReqV1 struct {
 	LastReceiveText      string `json:"audit_plan_sql_last_receive_text" form:"audit_plan_sql_last_receive_text" example:"select * from t1 where id = 1"`
 	LastReceiveTimestamp string `json:"audit_plan_sql_last_receive_timestamp" form:"audit_plan_sql_last_receive_timestamp" example:"RFC3339"`
 	Schema               string `json:"audit_plan_sql_schema" from:"audit_plan_sql_schema" example:"db1"`
+	QueryTimeAvg         string `json:"audit_plan_sql_query_time_avg" form:"audit_plan_sql_query_time_avg" example:"0.56"`
+	QueryTimeMax         string `json:"audit_plan_sql_query_time_max" form:"audit_plan_sql_query_time_max" example:"1.2"`
+	MapperNamespace      string `json:"audit_plan_sql_mapper_namespace" form:"audit_plan_sql_mapper_namespace" example:"com.example.UserMapper"`
+	MapperStatementID    string `json:"audit_plan_sql_mapper_statement_id" form:"audit_plan_sql_mapper_statement_id" example:"getUser"`
+	SourceFile           string `json:"audit_plan_sql_source_file" form:"audit_plan_sql_source_file" example:"mapper/UserMapper.xml"`
+	SourceLine           string `json:"audit_plan_sql_source_line" form:"audit_plan_sql_source_line" example:"12"`
 }
 
 // @Summary 全量同步SQL到审核计划
@@ -857,6 +1089,38 @@ func checkAndConvertToModelAuditPlanSQL(c echo.Context, apName string, reqSQLs [
 			"last_receive_timestamp": reqSQL.LastReceiveTimestamp,
 			server.AuditSchema:       reqSQL.Schema,
 		}
+		// query time is only provided by the scanner which collects SQL from database, such as slow log.
+		if reqSQL.QueryTimeAvg != "" {
+			queryTimeAvg, err := strconv.ParseFloat(reqSQL.QueryTimeAvg, 64)
+			if err != nil {
+				return nil, err
+			}
+			info["query_time_avg"] = queryTimeAvg
+		}
+		if reqSQL.QueryTimeMax != "" {
+			queryTimeMax, err := strconv.ParseFloat(reqSQL.QueryTimeMax, 64)
+			if err != nil {
+				return nil, err
+			}
+			info["query_time_max"] = queryTimeMax
+		}
+		// source location is only provided by the scanner which extracts SQL from code, such as MyBatis mapper.
+		if reqSQL.MapperNamespace != "" {
+			info["mapper_namespace"] = reqSQL.MapperNamespace
+		}
+		if reqSQL.MapperStatementID != "" {
+			info["mapper_statement_id"] = reqSQL.MapperStatementID
+		}
+		if reqSQL.SourceFile != "" {
+			info["source_file"] = reqSQL.SourceFile
+		}
+		if reqSQL.SourceLine != "" {
+			sourceLine, err := strconv.ParseUint(reqSQL.SourceLine, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			info["source_line"] = sourceLine
+		}
 		sqls[i] = &auditplan.SQL{
 			Fingerprint: fp,
 			SQLContent:  reqSQL.LastReceiveText,
@@ -932,10 +1196,54 @@ func TriggerAuditPlan(c echo.Context) error {
 			Score:      report.Score,
 			PassRate:   report.PassRate,
 			Timestamp:  report.CreatedAt.Format(time.RFC3339),
+
+			NewFindingCount:        report.NewFindingCount,
+			PersistingFindingCount: report.PersistingFindingCount,
+			ResolvedFindingCount:   report.ResolvedFindingCount,
 		},
 	})
 }
 
+// @Summary 暂停审核计划
+// @Description pause audit plan, the collected SQLs and reports are kept
+// @Id pauseAuditPlanV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param audit_plan_name path string true "audit plan name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/audit_plans/{audit_plan_name}/pause [post]
+func PauseAuditPlan(c echo.Context) error {
+	return updateAuditPlanStatus(c, model.AuditPlanStatusDisabled)
+}
+
+// @Summary 恢复审核计划
+// @Description resume audit plan
+// @Id resumeAuditPlanV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param audit_plan_name path string true "audit plan name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/audit_plans/{audit_plan_name}/resume [post]
+func ResumeAuditPlan(c echo.Context) error {
+	return updateAuditPlanStatus(c, model.AuditPlanStatusEnabled)
+}
+
+func updateAuditPlanStatus(c echo.Context, status string) error {
+	apName := c.Param("audit_plan_name")
+	err := CheckCurrentUserCanAccessAuditPlan(c, apName, 0)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	storage := model.GetStorage()
+	err = storage.UpdateAuditPlanByName(apName, map[string]interface{}{"status": status})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	manager := auditplan.GetManager()
+	return controller.JSONBaseErrorReq(c, manager.SyncTask(apName))
+}
+
 func CheckCurrentUserCanAccessAuditPlan(c echo.Context, apName string, opCode int) error {
 	storage := model.GetStorage()
 
@@ -974,12 +1282,14 @@ func CheckCurrentUserCanAccessAuditPlan(c echo.Context, apName string, opCode in
 }
 
 type UpdateAuditPlanNotifyConfigReqV1 struct {
-	NotifyInterval      *int    `json:"notify_interval" default:"10"`
-	NotifyLevel         *string `json:"notify_level" default:"warn" enums:"normal,notice,warn,error" valid:"oneof=normal notice warn error"`
-	EnableEmailNotify   *bool   `json:"enable_email_notify"`
-	EnableWebHookNotify *bool   `json:"enable_web_hook_notify"`
-	WebHookURL          *string `json:"web_hook_url"`
-	WebHookTemplate     *string `json:"web_hook_template"`
+	NotifyInterval *int    `json:"notify_interval" default:"10"`
+	NotifyLevel    *string `json:"notify_level" default:"warn" enums:"normal,notice,warn,error" valid:"oneof=normal notice warn error"`
+	// NotifyOnlyNewFindings notifies only for the findings which are not in the previous report.
+	NotifyOnlyNewFindings *bool   `json:"notify_only_new_findings"`
+	EnableEmailNotify     *bool   `json:"enable_email_notify"`
+	EnableWebHookNotify   *bool   `json:"enable_web_hook_notify"`
+	WebHookURL            *string `json:"web_hook_url"`
+	WebHookTemplate       *string `json:"web_hook_template"`
 }
 
 // @Summary 更新审核计划通知设置
@@ -1017,10 +1327,16 @@ func UpdateAuditPlanNotifyConfig(c echo.Context) error {
 	if req.NotifyLevel != nil {
 		updateAttr["notify_level"] = *req.NotifyLevel
 	}
+	if req.NotifyOnlyNewFindings != nil {
+		updateAttr["notify_only_new_findings"] = *req.NotifyOnlyNewFindings
+	}
 	if req.WebHookURL != nil {
 		updateAttr["web_hook_url"] = *req.WebHookURL
 	}
 	if req.WebHookTemplate != nil {
+		if err := notification.ValidateWebHookTemplate(*req.WebHookTemplate); err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+		}
 		updateAttr["web_hook_template"] = *req.WebHookTemplate
 	}
 
@@ -1035,12 +1351,37 @@ type GetAuditPlanNotifyConfigResV1 struct {
 }
 
 type GetAuditPlanNotifyConfigResDataV1 struct {
-	NotifyInterval      int    `json:"notify_interval"`
-	NotifyLevel         string `json:"notify_level"`
-	EnableEmailNotify   bool   `json:"enable_email_notify"`
-	EnableWebHookNotify bool   `json:"enable_web_hook_notify"`
-	WebHookURL          string `json:"web_hook_url"`
-	WebHookTemplate     string `json:"web_hook_template"`
+	NotifyInterval        int    `json:"notify_interval"`
+	NotifyLevel           string `json:"notify_level"`
+	NotifyOnlyNewFindings bool   `json:"notify_only_new_findings"`
+	EnableEmailNotify     bool   `json:"enable_email_notify"`
+	EnableWebHookNotify   bool   `json:"enable_web_hook_notify"`
+	WebHookURL            string `json:"web_hook_url"`
+	WebHookTemplate       string `json:"web_hook_template"`
+
+	LastWebHookDelivery *AuditPlanNotifyRecordResV1 `json:"last_web_hook_delivery,omitempty"`
+}
+
+type AuditPlanNotifyRecordResV1 struct {
+	Status       string `json:"status" enums:"success,failed"`
+	Attempts     int    `json:"attempts"`
+	StatusCode   int    `json:"status_code"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	SendAt       string `json:"send_at"`
+}
+
+func getLatestWebHookDelivery(s *model.Storage, apID uint) (*AuditPlanNotifyRecordResV1, error) {
+	record, exist, err := s.GetLatestAuditPlanNotifyRecord(apID, model.NotifyChannelWebHook)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return &AuditPlanNotifyRecordResV1{
+		Status:       record.Status,
+		Attempts:     record.Attempts,
+		StatusCode:   record.StatusCode,
+		ErrorMessage: record.ErrorMessage,
+		SendAt:       record.CreatedAt.Format(time.RFC3339),
+	}, nil
 }
 
 // @Summary 获取审核任务消息推送设置
@@ -1061,16 +1402,22 @@ func GetAuditPlanNotifyConfig(c echo.Context) error {
 
 	s := model.GetStorage()
 	ap, _, err := s.GetAuditPlanByName(apName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	delivery, err := getLatestWebHookDelivery(s, ap.ID)
 
 	return c.JSON(http.StatusOK, GetAuditPlanNotifyConfigResV1{
 		BaseRes: controller.NewBaseReq(err),
 		Data: GetAuditPlanNotifyConfigResDataV1{
-			NotifyInterval:      ap.NotifyInterval,
-			NotifyLevel:         ap.NotifyLevel,
-			EnableEmailNotify:   ap.EnableEmailNotify,
-			EnableWebHookNotify: ap.EnableWebHookNotify,
-			WebHookURL:          ap.WebHookURL,
-			WebHookTemplate:     ap.WebHookTemplate,
+			NotifyInterval:        ap.NotifyInterval,
+			NotifyLevel:           ap.NotifyLevel,
+			NotifyOnlyNewFindings: ap.NotifyOnlyNewFindings,
+			EnableEmailNotify:     ap.EnableEmailNotify,
+			EnableWebHookNotify:   ap.EnableWebHookNotify,
+			WebHookURL:            ap.WebHookURL,
+			WebHookTemplate:       ap.WebHookTemplate,
+			LastWebHookDelivery:   delivery,
 		},
 	})
 }
@@ -1083,6 +1430,8 @@ type TestAuditPlanNotifyConfigResV1 struct {
 type TestAuditPlanNotifyConfigResDataV1 struct {
 	IsNotifySendNormal bool   `json:"is_notify_send_normal"`
 	SendErrorMessage   string `json:"send_error_message,omitempty"`
+	// WebHookDelivery is the delivery result of web hook if web hook notify is enabled.
+	WebHookDelivery *AuditPlanNotifyRecordResV1 `json:"web_hook_delivery,omitempty"`
 }
 
 // @Summary 测试审核任务消息推送
@@ -1121,13 +1470,22 @@ func TestAuditPlanNotifyConfig(c echo.Context) error {
 	}
 	ap.CreateUser = user
 
-	err = notification.GetAuditPlanNotifier().Send(&notification.TestNotify{}, ap)
-	if err != nil {
+	sendErr := notification.GetAuditPlanNotifier().Send(&notification.TestNotify{}, ap)
+
+	var delivery *AuditPlanNotifyRecordResV1
+	if ap.EnableWebHookNotify {
+		delivery, err = getLatestWebHookDelivery(s, ap.ID)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+	if sendErr != nil {
 		return c.JSON(http.StatusOK, TestAuditPlanNotifyConfigResV1{
 			BaseRes: controller.NewBaseReq(nil),
 			Data: TestAuditPlanNotifyConfigResDataV1{
 				IsNotifySendNormal: false,
-				SendErrorMessage:   err.Error(),
+				SendErrorMessage:   sendErr.Error(),
+				WebHookDelivery:    delivery,
 			},
 		})
 	}
@@ -1136,6 +1494,7 @@ func TestAuditPlanNotifyConfig(c echo.Context) error {
 		Data: TestAuditPlanNotifyConfigResDataV1{
 			IsNotifySendNormal: true,
 			SendErrorMessage:   "success",
+			WebHookDelivery:    delivery,
 		},
 	})
 }
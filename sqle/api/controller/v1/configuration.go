@@ -3,6 +3,7 @@ package v1
 import (
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/config"
@@ -229,6 +230,391 @@ func GetWeChatConfiguration(c echo.Context) error {
 	return getWeChatConfiguration(c)
 }
 
+type UpdateSlackConfigurationReqV1 struct {
+	EnableSlackNotify *bool   `json:"enable_slack_notify" from:"enable_slack_notify" description:"是否启用Slack通知"`
+	WebHookURL        *string `json:"web_hook_url" from:"web_hook_url" description:"Slack Incoming Webhook地址" valid:"omitempty,url"`
+}
+
+// @Summary 添加 Slack 配置
+// @Description update Slack configuration
+// @Accept json
+// @Id updateSlackConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param instance body v1.UpdateSlackConfigurationReqV1 true "update Slack configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/slack [patch]
+func UpdateSlackConfiguration(c echo.Context) error {
+	req := new(UpdateSlackConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	slackC, _, err := s.GetSlackConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.EnableSlackNotify != nil {
+		slackC.EnableSlackNotify = *req.EnableSlackNotify
+	}
+	if req.WebHookURL != nil {
+		slackC.WebHookURL = *req.WebHookURL
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(slackC))
+}
+
+type GetSlackConfigurationResV1 struct {
+	controller.BaseRes
+	Data SlackConfigurationResV1 `json:"data"`
+}
+
+type SlackConfigurationResV1 struct {
+	EnableSlackNotify bool   `json:"enable_slack_notify"`
+	WebHookURL        string `json:"web_hook_url"`
+}
+
+// @Summary 获取 Slack 配置
+// @Description get Slack configuration
+// @Id getSlackConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetSlackConfigurationResV1
+// @router /v1/configurations/slack [get]
+func GetSlackConfiguration(c echo.Context) error {
+	slackC, _, err := model.GetStorage().GetSlackConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetSlackConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: SlackConfigurationResV1{
+			EnableSlackNotify: slackC.EnableSlackNotify,
+			WebHookURL:        slackC.WebHookURL,
+		},
+	})
+}
+
+type TestChatConfigurationResV1 struct {
+	controller.BaseRes
+	Data TestChatConfigurationResDataV1 `json:"data"`
+}
+
+type TestChatConfigurationResDataV1 struct {
+	IsMessageSendNormal bool   `json:"is_message_send_normal"`
+	SendErrorMessage    string `json:"send_error_message,omitempty"`
+}
+
+func testChatConfiguration(c echo.Context, sendErr error) error {
+	if sendErr != nil {
+		return c.JSON(http.StatusOK, &TestChatConfigurationResV1{
+			BaseRes: controller.NewBaseReq(nil),
+			Data: TestChatConfigurationResDataV1{
+				IsMessageSendNormal: false,
+				SendErrorMessage:    sendErr.Error(),
+			},
+		})
+	}
+	return c.JSON(http.StatusOK, &TestChatConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: TestChatConfigurationResDataV1{
+			IsMessageSendNormal: true,
+			SendErrorMessage:    "ok",
+		},
+	})
+}
+
+// @Summary 测试 Slack 配置
+// @Description test Slack configuration, the test message is sent even if the Slack notify is disabled
+// @Id testSlackConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.TestChatConfigurationResV1
+// @router /v1/configurations/slack/test [post]
+func TestSlackConfiguration(c echo.Context) error {
+	slackC, exist, err := model.GetStorage().GetSlackConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return testChatConfiguration(c, fmt.Errorf("Slack configuration is not set"))
+	}
+	notifier := &notification.SlackNotifier{}
+	return testChatConfiguration(c, notifier.Send(slackC, &notification.TestNotify{}, nil))
+}
+
+type UpdateDingTalkConfigurationReqV1 struct {
+	EnableDingTalkNotify *bool   `json:"enable_ding_talk_notify" from:"enable_ding_talk_notify" description:"是否启用钉钉通知"`
+	WebHookURL           *string `json:"web_hook_url" from:"web_hook_url" description:"钉钉机器人Webhook地址" valid:"omitempty,url"`
+	Secret               *string `json:"secret" from:"secret" description:"钉钉机器人加签密钥"`
+}
+
+// @Summary 添加 钉钉 配置
+// @Description update DingTalk configuration
+// @Accept json
+// @Id updateDingTalkConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param instance body v1.UpdateDingTalkConfigurationReqV1 true "update DingTalk configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/ding_talk [patch]
+func UpdateDingTalkConfiguration(c echo.Context) error {
+	req := new(UpdateDingTalkConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	dingTalkC, _, err := s.GetDingTalkConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.EnableDingTalkNotify != nil {
+		dingTalkC.EnableDingTalkNotify = *req.EnableDingTalkNotify
+	}
+	if req.WebHookURL != nil {
+		dingTalkC.WebHookURL = *req.WebHookURL
+	}
+	if req.Secret != nil {
+		dingTalkC.Secret = *req.Secret
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(dingTalkC))
+}
+
+type GetDingTalkConfigurationResV1 struct {
+	controller.BaseRes
+	Data DingTalkConfigurationResV1 `json:"data"`
+}
+
+type DingTalkConfigurationResV1 struct {
+	EnableDingTalkNotify bool   `json:"enable_ding_talk_notify"`
+	WebHookURL           string `json:"web_hook_url"`
+	IsSecretSet          bool   `json:"is_secret_set"`
+}
+
+// @Summary 获取 钉钉 配置
+// @Description get DingTalk configuration
+// @Id getDingTalkConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetDingTalkConfigurationResV1
+// @router /v1/configurations/ding_talk [get]
+func GetDingTalkConfiguration(c echo.Context) error {
+	dingTalkC, _, err := model.GetStorage().GetDingTalkConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetDingTalkConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: DingTalkConfigurationResV1{
+			EnableDingTalkNotify: dingTalkC.EnableDingTalkNotify,
+			WebHookURL:           dingTalkC.WebHookURL,
+			IsSecretSet:          dingTalkC.Secret != "",
+		},
+	})
+}
+
+// @Summary 测试 钉钉 配置
+// @Description test DingTalk configuration, the test message is sent even if the DingTalk notify is disabled
+// @Id testDingTalkConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.TestChatConfigurationResV1
+// @router /v1/configurations/ding_talk/test [post]
+func TestDingTalkConfiguration(c echo.Context) error {
+	dingTalkC, exist, err := model.GetStorage().GetDingTalkConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return testChatConfiguration(c, fmt.Errorf("DingTalk configuration is not set"))
+	}
+	notifier := &notification.DingTalkNotifier{}
+	return testChatConfiguration(c, notifier.Send(dingTalkC, &notification.TestNotify{}, nil))
+}
+
+type UpdateFeishuConfigurationReqV1 struct {
+	EnableFeishuNotify *bool   `json:"enable_feishu_notify" from:"enable_feishu_notify" description:"是否启用飞书通知"`
+	WebHookURL         *string `json:"web_hook_url" from:"web_hook_url" description:"飞书机器人Webhook地址" valid:"omitempty,url"`
+	Secret             *string `json:"secret" from:"secret" description:"飞书机器人签名校验密钥"`
+}
+
+// @Summary 添加 飞书 配置
+// @Description update Feishu configuration
+// @Accept json
+// @Id updateFeishuConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param instance body v1.UpdateFeishuConfigurationReqV1 true "update Feishu configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/feishu [patch]
+func UpdateFeishuConfiguration(c echo.Context) error {
+	req := new(UpdateFeishuConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	feishuC, _, err := s.GetFeishuConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.EnableFeishuNotify != nil {
+		feishuC.EnableFeishuNotify = *req.EnableFeishuNotify
+	}
+	if req.WebHookURL != nil {
+		feishuC.WebHookURL = *req.WebHookURL
+	}
+	if req.Secret != nil {
+		feishuC.Secret = *req.Secret
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(feishuC))
+}
+
+type GetFeishuConfigurationResV1 struct {
+	controller.BaseRes
+	Data FeishuConfigurationResV1 `json:"data"`
+}
+
+type FeishuConfigurationResV1 struct {
+	EnableFeishuNotify bool   `json:"enable_feishu_notify"`
+	WebHookURL         string `json:"web_hook_url"`
+	IsSecretSet        bool   `json:"is_secret_set"`
+}
+
+// @Summary 获取 飞书 配置
+// @Description get Feishu configuration
+// @Id getFeishuConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetFeishuConfigurationResV1
+// @router /v1/configurations/feishu [get]
+func GetFeishuConfiguration(c echo.Context) error {
+	feishuC, _, err := model.GetStorage().GetFeishuConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetFeishuConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: FeishuConfigurationResV1{
+			EnableFeishuNotify: feishuC.EnableFeishuNotify,
+			WebHookURL:         feishuC.WebHookURL,
+			IsSecretSet:        feishuC.Secret != "",
+		},
+	})
+}
+
+// @Summary 测试 飞书 配置
+// @Description test Feishu configuration, the test message is sent even if the Feishu notify is disabled
+// @Id testFeishuConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.TestChatConfigurationResV1
+// @router /v1/configurations/feishu/test [post]
+func TestFeishuConfiguration(c echo.Context) error {
+	feishuC, exist, err := model.GetStorage().GetFeishuConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return testChatConfiguration(c, fmt.Errorf("Feishu configuration is not set"))
+	}
+	notifier := &notification.FeishuNotifier{}
+	return testChatConfiguration(c, notifier.Send(feishuC, &notification.TestNotify{}, nil))
+}
+
+type UpdateMattermostConfigurationReqV1 struct {
+	EnableMattermostNotify *bool   `json:"enable_mattermost_notify" from:"enable_mattermost_notify" description:"是否启用Mattermost通知"`
+	WebHookURL             *string `json:"web_hook_url" from:"web_hook_url" description:"Mattermost Incoming Webhook地址" valid:"omitempty,url"`
+	Channel                *string `json:"channel" from:"channel" description:"覆盖Webhook默认的频道"`
+	Username               *string `json:"username" from:"username" description:"覆盖Webhook默认的用户名"`
+}
+
+// @Summary 添加 Mattermost 配置
+// @Description update Mattermost configuration
+// @Accept json
+// @Id updateMattermostConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param instance body v1.UpdateMattermostConfigurationReqV1 true "update Mattermost configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/mattermost [patch]
+func UpdateMattermostConfiguration(c echo.Context) error {
+	req := new(UpdateMattermostConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	mattermostC, _, err := s.GetMattermostConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.EnableMattermostNotify != nil {
+		mattermostC.EnableMattermostNotify = *req.EnableMattermostNotify
+	}
+	if req.WebHookURL != nil {
+		mattermostC.WebHookURL = *req.WebHookURL
+	}
+	if req.Channel != nil {
+		mattermostC.Channel = *req.Channel
+	}
+	if req.Username != nil {
+		mattermostC.Username = *req.Username
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(mattermostC))
+}
+
+type GetMattermostConfigurationResV1 struct {
+	controller.BaseRes
+	Data MattermostConfigurationResV1 `json:"data"`
+}
+
+type MattermostConfigurationResV1 struct {
+	EnableMattermostNotify bool   `json:"enable_mattermost_notify"`
+	WebHookURL             string `json:"web_hook_url"`
+	Channel                string `json:"channel"`
+	Username               string `json:"username"`
+}
+
+// @Summary 获取 Mattermost 配置
+// @Description get Mattermost configuration
+// @Id getMattermostConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetMattermostConfigurationResV1
+// @router /v1/configurations/mattermost [get]
+func GetMattermostConfiguration(c echo.Context) error {
+	mattermostC, _, err := model.GetStorage().GetMattermostConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetMattermostConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: MattermostConfigurationResV1{
+			EnableMattermostNotify: mattermostC.EnableMattermostNotify,
+			WebHookURL:             mattermostC.WebHookURL,
+			Channel:                mattermostC.Channel,
+			Username:               mattermostC.Username,
+		},
+	})
+}
+
+// @Summary 测试 Mattermost 配置
+// @Description test Mattermost configuration, the test message is sent even if the Mattermost notify is disabled
+// @Id testMattermostConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.TestChatConfigurationResV1
+// @router /v1/configurations/mattermost/test [post]
+func TestMattermostConfiguration(c echo.Context) error {
+	mattermostC, exist, err := model.GetStorage().GetMattermostConfiguration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return testChatConfiguration(c, fmt.Errorf("Mattermost configuration is not set"))
+	}
+	notifier := &notification.MattermostNotifier{}
+	return testChatConfiguration(c, notifier.Send(mattermostC, &notification.TestNotify{}, nil))
+}
+
 type GetLDAPConfigurationResV1 struct {
 	controller.BaseRes
 	Data LDAPConfigurationResV1 `json:"data"`
@@ -351,7 +737,8 @@ func UpdateLDAPConfiguration(c echo.Context) error {
 }
 
 type UpdateSystemVariablesReqV1 struct {
-	WorkflowExpiredHours *int `json:"workflow_expired_hours" form:"workflow_expired_hours" example:"720"`
+	WorkflowExpiredHours *int    `json:"workflow_expired_hours" form:"workflow_expired_hours" example:"720"`
+	Url                  *string `json:"url" form:"url" example:"http://10.186.61.32:10000" description:"SQLE访问地址, 用于消息通知中的工单链接" valid:"omitempty,url"`
 }
 
 // @Summary 修改系统变量
@@ -379,6 +766,15 @@ func UpdateSystemVariables(c echo.Context) error {
 			return controller.JSONBaseErrorReq(c, err)
 		}
 	}
+	if req.Url != nil {
+		sv := &model.SystemVariable{
+			Key:   model.SystemVariableSqleUrl,
+			Value: *req.Url}
+
+		if err := s.Save(sv); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
 	return controller.JSONBaseErrorReq(c, nil)
 }
 
@@ -388,7 +784,8 @@ type GetSystemVariablesResV1 struct {
 }
 
 type SystemVariablesResV1 struct {
-	WorkflowExpiredHours int `json:"workflow_expired_hours"`
+	WorkflowExpiredHours int    `json:"workflow_expired_hours"`
+	Url                  string `json:"url"`
 }
 
 // @Summary 获取系统变量
@@ -404,11 +801,16 @@ func GetSystemVariables(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+	url, err := s.GetSqleUrl()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
 
 	return c.JSON(http.StatusOK, &GetSystemVariablesResV1{
 		BaseRes: controller.NewBaseReq(nil),
 		Data: SystemVariablesResV1{
 			WorkflowExpiredHours: int(wfExpiredHours),
+			Url:                  url,
 		},
 	})
 }
@@ -419,21 +821,105 @@ type GetDriversResV1 struct {
 }
 
 type DriversResV1 struct {
-	Drivers []string `json:"driver_name_list"`
+	Drivers      []string                  `json:"driver_name_list"`
+	Capabilities []DriverCapabilitiesResV1 `json:"driver_capabilities_list"`
+}
+
+type DriverCapabilitiesResV1 struct {
+	DBType           string   `json:"db_type"`
+	Rollback         bool     `json:"rollback"`
+	TransactionalDDL bool     `json:"transactional_ddl"`
+	ExplainFormats   []string `json:"explain_formats"`
+	OfflineAudit     bool     `json:"offline_audit"`
+	SQLQuery         bool     `json:"sql_query"`
+	SQLAnalysis      bool     `json:"sql_analysis"`
+}
+
+func convertCapabilitiesToRes(dbType string, c driver.Capabilities) DriverCapabilitiesResV1 {
+	explainFormats := c.ExplainFormats
+	if explainFormats == nil {
+		explainFormats = []string{}
+	}
+	return DriverCapabilitiesResV1{
+		DBType:           dbType,
+		Rollback:         c.Rollback,
+		TransactionalDDL: c.TransactionalDDL,
+		ExplainFormats:   explainFormats,
+		OfflineAudit:     c.OfflineAudit,
+		SQLQuery:         c.Query,
+		SQLAnalysis:      c.Analysis,
+	}
 }
 
 // GetDrivers get support Driver list.
 // @Summary 获取当前 server 支持的审核类型
-// @Description get drivers
+// @Description get drivers and their capabilities
 // @Id getDriversV1
 // @Tags configuration
 // @Security ApiKeyAuth
 // @Success 200 {object} v1.GetDriversResV1
 // @router /v1/configurations/drivers [get]
 func GetDrivers(c echo.Context) error {
+	allCapabilities := driver.AllCapabilities()
+	capabilities := make([]DriverCapabilitiesResV1, 0, len(allCapabilities))
+	for dbType, c := range allCapabilities {
+		capabilities = append(capabilities, convertCapabilitiesToRes(dbType, c))
+	}
+	sort.Slice(capabilities, func(i, j int) bool {
+		return capabilities[i].DBType < capabilities[j].DBType
+	})
 	return c.JSON(http.StatusOK, &GetDriversResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    DriversResV1{Drivers: driver.AllDrivers()},
+		Data: DriversResV1{
+			Drivers:      driver.AllDrivers(),
+			Capabilities: capabilities,
+		},
+	})
+}
+
+type GetPluginPoolsStatsResV1 struct {
+	controller.BaseRes
+	Data []PluginPoolStatsResV1 `json:"data"`
+}
+
+type PluginPoolStatsResV1 struct {
+	PluginName    string `json:"plugin_name"`
+	Size          int    `json:"size"`
+	InUse         int    `json:"in_use"`
+	Idle          int    `json:"idle"`
+	Started       uint64 `json:"started"`
+	Restarted     uint64 `json:"restarted"`
+	Acquired      uint64 `json:"acquired"`
+	Waited        uint64 `json:"waited"`
+	AcquireFailed uint64 `json:"acquire_failed"`
+}
+
+// @Summary 获取插件进程池使用情况
+// @Description get the usage of the process pools of plugins
+// @Id getPluginPoolsStatsV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetPluginPoolsStatsResV1
+// @router /v1/configurations/drivers/plugin_pools [get]
+func GetPluginPoolsStats(c echo.Context) error {
+	stats := driver.PluginPoolsStats()
+	data := make([]PluginPoolStatsResV1, 0, len(stats))
+	for _, s := range stats {
+		data = append(data, PluginPoolStatsResV1{
+			PluginName:    s.PluginName,
+			Size:          s.Size,
+			InUse:         s.InUse,
+			Idle:          s.Idle,
+			Started:       s.Started,
+			Restarted:     s.Restarted,
+			Acquired:      s.Acquired,
+			Waited:        s.Waited,
+			AcquireFailed: s.AcquireFailed,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetPluginPoolsStatsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
 	})
 }
 
@@ -0,0 +1,279 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/labstack/echo/v4"
+)
+
+const InputPluginFileName = "plugin_file"
+
+// convertPluginError converts the error of driver plugin management to the
+// error with code.
+func convertPluginError(err error) error {
+	if _, ok := err.(*errors.CodeError); ok {
+		return err
+	}
+	switch err {
+	case nil:
+		return nil
+	case driver.ErrPluginNotExist:
+		return errors.New(errors.DataNotExist, err)
+	case driver.ErrBuiltInDriver:
+		return errors.New(errors.DataConflict, err)
+	case driver.ErrPluginPathNotConfigured:
+		return errors.New(errors.DataInvalid, err)
+	default:
+		return errors.New(errors.LoadDriverFail, err)
+	}
+}
+
+type GetPluginsResV1 struct {
+	controller.BaseRes
+	Data []PluginResV1 `json:"data"`
+}
+
+type PluginResV1 struct {
+	Name            string `json:"plugin_name"`
+	Version         int32  `json:"version"`
+	Checksum        string `json:"checksum"`
+	RuleCount       int    `json:"rule_count"`
+	SupportQuery    bool   `json:"support_query"`
+	SupportAnalysis bool   `json:"support_analysis"`
+	// CapabilitiesDeclared is false for the old plugin, its capabilities are
+	// guessed by SQLE.
+	CapabilitiesDeclared bool   `json:"capabilities_declared"`
+	MinSQLEVersion       string `json:"min_sqle_version"`
+}
+
+func convertPluginToRes(p *driver.PluginInfo) PluginResV1 {
+	return PluginResV1{
+		Name:                 p.Name,
+		Version:              p.Version,
+		Checksum:             p.Checksum,
+		RuleCount:            len(p.Rules),
+		SupportQuery:         p.SupportQuery,
+		SupportAnalysis:      p.SupportAnalysis,
+		CapabilitiesDeclared: p.Capabilities != nil,
+		MinSQLEVersion:       p.MinSQLEVersion,
+	}
+}
+
+// @Summary 获取已加载的插件列表
+// @Description get driver plugins loaded by SQLE
+// @Id getPluginsV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetPluginsResV1
+// @router /v1/configurations/drivers/plugins [get]
+func GetPlugins(c echo.Context) error {
+	plugins := driver.AllPlugins()
+	data := make([]PluginResV1, 0, len(plugins))
+	for _, p := range plugins {
+		data = append(data, convertPluginToRes(p))
+	}
+	return c.JSON(http.StatusOK, &GetPluginsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type InstallPluginResV1 struct {
+	controller.BaseRes
+	Data PluginResV1 `json:"data"`
+}
+
+// @Summary 上传并加载插件
+// @Description upload and load a driver plugin, the loaded plugin with the same name is replaced after the running audits are finished
+// @Accept mpfd
+// @Produce json
+// @Id installPluginV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param plugin_file formData file true "plugin binary file"
+// @Success 200 {object} v1.InstallPluginResV1
+// @router /v1/configurations/drivers/plugins [post]
+func InstallPlugin(c echo.Context) error {
+	file, err := c.FormFile(InputPluginFileName)
+	if err == http.ErrMissingFile {
+		return controller.JSONBaseErrorReq(c, errors.NewDataInvalidErr("plugin file is empty"))
+	}
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ReadUploadFileError, err))
+	}
+	src, err := file.Open()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ReadUploadFileError, err))
+	}
+	defer src.Close()
+
+	loaded := map[string]struct{}{}
+	for _, p := range driver.AllPlugins() {
+		loaded[p.Name] = struct{}{}
+	}
+	plugin, err := driver.InstallPlugin(file.Filename, src)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, convertPluginError(err))
+	}
+	action := model.PluginActionInstall
+	if _, ok := loaded[plugin.Name]; ok {
+		action = model.PluginActionReload
+	}
+	if err := syncPlugin(c, plugin, action); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &InstallPluginResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertPluginToRes(plugin),
+	})
+}
+
+// syncPlugin syncs the rules of plugin to storage and records the version.
+func syncPlugin(c echo.Context, plugin *driver.PluginInfo, action string) error {
+	s := model.GetStorage()
+	if err := s.SyncDriverRules(plugin.Name, plugin.Rules); err != nil {
+		return err
+	}
+	return s.Save(&model.PluginVersion{
+		PluginName:       plugin.Name,
+		Version:          plugin.Version,
+		Checksum:         plugin.Checksum,
+		Action:           action,
+		RuleCount:        len(plugin.Rules),
+		OperatorUserName: controller.GetUserName(c),
+	})
+}
+
+// @Summary 重新加载插件
+// @Description reload the driver plugin from its binary, the running audits are finished by the old one
+// @Id reloadPluginV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param plugin_name path string true "plugin name"
+// @Success 200 {object} v1.InstallPluginResV1
+// @router /v1/configurations/drivers/plugins/{plugin_name}/reload [post]
+func ReloadPlugin(c echo.Context) error {
+	plugin, err := driver.ReloadPlugin(c.Param("plugin_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, convertPluginError(err))
+	}
+	if err := syncPlugin(c, plugin, model.PluginActionReload); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &InstallPluginResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertPluginToRes(plugin),
+	})
+}
+
+// @Summary 卸载插件
+// @Description unload the driver plugin and remove its binary, the running audits are finished at first
+// @Id unloadPluginV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param plugin_name path string true "plugin name"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/drivers/plugins/{plugin_name}/ [delete]
+func UnloadPlugin(c echo.Context) error {
+	pluginName := c.Param("plugin_name")
+	s := model.GetStorage()
+
+	var plugin *driver.PluginInfo
+	for _, p := range driver.AllPlugins() {
+		if p.Name == pluginName {
+			plugin = p
+		}
+	}
+	if err := driver.UnloadPlugin(pluginName, checkPluginUsage); err != nil {
+		return controller.JSONBaseErrorReq(c, convertPluginError(err))
+	}
+	if plugin == nil {
+		return controller.JSONBaseErrorReq(c, nil)
+	}
+	err := s.Save(&model.PluginVersion{
+		PluginName:       plugin.Name,
+		Version:          plugin.Version,
+		Checksum:         plugin.Checksum,
+		Action:           model.PluginActionUnload,
+		RuleCount:        len(plugin.Rules),
+		OperatorUserName: controller.GetUserName(c),
+	})
+	if err != nil {
+		log.NewEntry().Errorf("save version of plugin %v failed: %v", pluginName, err)
+	}
+	return controller.JSONBaseErrorReq(c, nil)
+}
+
+// checkPluginUsage returns error if the plugin is used by the instances or
+// the audit plans.
+func checkPluginUsage(pluginName string) error {
+	s := model.GetStorage()
+	instances, err := s.GetInstancesByType(pluginName)
+	if err != nil {
+		return err
+	}
+	if len(instances) > 0 {
+		return errors.New(errors.DataConflict,
+			fmt.Errorf("plugin %v is used by %v instances", pluginName, len(instances)))
+	}
+	count, err := s.GetAuditPlanCountByDBType(pluginName)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New(errors.DataConflict,
+			fmt.Errorf("plugin %v is used by %v audit plans", pluginName, count))
+	}
+	return nil
+}
+
+type GetPluginVersionsResV1 struct {
+	controller.BaseRes
+	Data []PluginVersionResV1 `json:"data"`
+}
+
+type PluginVersionResV1 struct {
+	Version          int32      `json:"version"`
+	Checksum         string     `json:"checksum"`
+	Action           string     `json:"action" enums:"startup,install,reload,unload"`
+	RuleCount        int        `json:"rule_count"`
+	OperatorUserName string     `json:"operator_user_name"`
+	CreateTime       *time.Time `json:"create_time"`
+}
+
+// @Summary 获取插件版本历史
+// @Description get the version history of driver plugin
+// @Id getPluginVersionsV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param plugin_name path string true "plugin name"
+// @Success 200 {object} v1.GetPluginVersionsResV1
+// @router /v1/configurations/drivers/plugins/{plugin_name}/versions [get]
+func GetPluginVersions(c echo.Context) error {
+	versions, err := model.GetStorage().GetPluginVersions(c.Param("plugin_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]PluginVersionResV1, 0, len(versions))
+	for _, v := range versions {
+		data = append(data, PluginVersionResV1{
+			Version:          v.Version,
+			Checksum:         v.Checksum,
+			Action:           v.Action,
+			RuleCount:        v.RuleCount,
+			OperatorUserName: v.OperatorUserName,
+			CreateTime:       &v.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetPluginVersionsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
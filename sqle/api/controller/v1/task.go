@@ -1,9 +1,7 @@
 package v1
 
 import (
-	"bytes"
 	"context"
-	"encoding/csv"
 	"fmt"
 	"mime"
 	"net/http"
@@ -11,9 +9,11 @@ import (
 	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/report"
 	"github.com/actiontech/sqle/sqle/server"
 
 	mybatis_parser "github.com/actiontech/mybatis-mapper-2-sql"
@@ -144,19 +144,10 @@ func CreateAndAuditTask(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
 	}
 
-	drvMgr, err := newDriverManagerWithoutAudit(log.NewEntry(), instance, "")
-	if err != nil {
-		return controller.JSONBaseErrorReq(c, err)
-	}
-	defer drvMgr.Close(context.TODO())
-
-	d, err := drvMgr.GetAuditDriver()
+	nodes, err := parseSQLsWithInstance(instance, sql)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
-	if err := d.Ping(context.TODO()); err != nil {
-		return controller.JSONBaseErrorReq(c, err)
-	}
 
 	user, err := controller.GetCurrentUser(c)
 	if err != nil {
@@ -174,10 +165,6 @@ func CreateAndAuditTask(c echo.Context) error {
 	createAt := time.Now()
 	task.CreatedAt = createAt
 
-	nodes, err := d.Parse(context.TODO(), sql)
-	if err != nil {
-		return controller.JSONBaseErrorReq(c, err)
-	}
 	for n, node := range nodes {
 		task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
 			BaseSQL: model.BaseSQL{
@@ -203,6 +190,26 @@ func CreateAndAuditTask(c echo.Context) error {
 	})
 }
 
+// parseSQLsWithInstance parses the SQLs with the driver of the instance. The
+// driver manager is closed before return, so the plugin process is put back
+// before the task is audited, which acquires another one from the pool.
+func parseSQLsWithInstance(instance *model.Instance, sql string) ([]driver.Node, error) {
+	drvMgr, err := newDriverManagerWithoutAudit(log.NewEntry(), instance, "")
+	if err != nil {
+		return nil, err
+	}
+	defer drvMgr.Close(context.TODO())
+
+	d, err := drvMgr.GetAuditDriver()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Ping(context.TODO()); err != nil {
+		return nil, err
+	}
+	return d.Parse(context.TODO(), sql)
+}
+
 func checkCurrentUserCanAccessTask(c echo.Context, task *model.Task, ops []uint) error {
 	if controller.GetUserName(c) == model.DefaultAdminUser {
 		return nil
@@ -374,7 +381,8 @@ func GetTaskSQLs(c echo.Context) error {
 }
 
 type DownloadAuditTaskSQLsFileReqV1 struct {
-	NoDuplicate bool `json:"no_duplicate" query:"no_duplicate"`
+	NoDuplicate  bool   `json:"no_duplicate" query:"no_duplicate"`
+	ExportFormat string `json:"export_format" query:"export_format" enums:"csv,xlsx,html" valid:"omitempty,oneof=csv xlsx html"`
 }
 
 // @Summary 下载指定审核任务的SQLs信息报告
@@ -384,7 +392,8 @@ type DownloadAuditTaskSQLsFileReqV1 struct {
 // @Security ApiKeyAuth
 // @Param task_id path string true "task id"
 // @Param no_duplicate query boolean false "select unique (fingerprint and audit result) for task sql"
-// @Success 200 file 1 "sql report csv file"
+// @Param export_format query string false "export format, default is csv" Enums(csv, xlsx, html)
+// @Success 200 file 1 "sql report file"
 // @router /v1/tasks/audits/{task_id}/sql_report [get]
 func DownloadTaskSQLReportFile(c echo.Context) error {
 	req := new(DownloadAuditTaskSQLsFileReqV1)
@@ -414,12 +423,12 @@ func DownloadTaskSQLReportFile(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
-	buff := &bytes.Buffer{}
-	buff.WriteString("\xEF\xBB\xBF") // 写入UTF-8 BOM
-	cw := csv.NewWriter(buff)
-	err = cw.Write([]string{"序号", "SQL", "SQL审核状态", "SQL审核结果", "SQL执行状态", "SQL执行结果", "SQL对应的回滚语句", "SQL描述"})
-	if err != nil {
-		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	r := &report.Report{
+		Title:      fmt.Sprintf("SQL审核报告_%v_%v", task.InstanceName(), taskId),
+		Score:      task.Score,
+		PassRate:   task.PassRate,
+		AuditLevel: task.AuditLevel,
+		Columns:    []string{"序号", "SQL", "SQL审核状态", "SQL审核结果", "SQL执行状态", "SQL执行结果", "SQL对应的回滚语句", "SQL描述"},
 	}
 	for _, td := range taskSQLsDetail {
 		taskSql := &model.ExecuteSQL{
@@ -427,25 +436,21 @@ func DownloadTaskSQLReportFile(c echo.Context) error {
 			AuditStatus: td.AuditStatus,
 		}
 		taskSql.ExecStatus = td.ExecStatus
-		err := cw.Write([]string{
-			strconv.FormatUint(uint64(td.Number), 10),
-			td.ExecSQL,
-			taskSql.GetAuditStatusDesc(),
-			taskSql.GetAuditResultDesc(),
-			taskSql.GetExecStatusDesc(),
-			td.ExecResult,
-			td.RollbackSQL.String,
-			td.Description,
+		r.Rows = append(r.Rows, &report.Row{
+			AuditLevel: td.AuditLevel,
+			Values: []string{
+				strconv.FormatUint(uint64(td.Number), 10),
+				td.ExecSQL,
+				taskSql.GetAuditStatusDesc(),
+				taskSql.GetAuditResultDesc(),
+				taskSql.GetExecStatusDesc(),
+				td.ExecResult,
+				td.RollbackSQL.String,
+				td.Description,
+			},
 		})
-		if err != nil {
-			return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
-		}
 	}
-	cw.Flush()
-	fileName := fmt.Sprintf("SQL审核报告_%v_%v.csv", task.InstanceName(), taskId)
-	c.Response().Header().Set(echo.HeaderContentDisposition,
-		mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
-	return c.Blob(http.StatusOK, "text/csv", buff.Bytes())
+	return exportReport(c, r, report.Format(req.ExportFormat))
 }
 
 // @Summary 下载指定审核任务的SQL文件
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPooledDriver struct {
+	driver.Driver
+}
+
+func (d *mockPooledDriver) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (d *mockPooledDriver) Parse(ctx context.Context, sqlText string) ([]driver.Node, error) {
+	return []driver.Node{{Text: sqlText}}, nil
+}
+
+type mockPooledDriverManager struct {
+	driver.DriverManager
+	release func()
+}
+
+func (m *mockPooledDriverManager) GetAuditDriver() (driver.Driver, error) {
+	return &mockPooledDriver{}, nil
+}
+
+func (m *mockPooledDriverManager) Close(ctx context.Context) {
+	m.release()
+}
+
+func TestParseSQLsWithInstanceReleasesProcess(t *testing.T) {
+	// pool simulates the process pool of plugin with PluginPoolConfig{Size: 1},
+	// each driver manager holds the process until it's closed.
+	pool := make(chan struct{}, 1)
+	acquire := func() error {
+		select {
+		case pool <- struct{}{}:
+			return nil
+		case <-time.After(100 * time.Millisecond):
+			return errors.New("acquire plugin process timeout")
+		}
+	}
+	dbType := "nested-acquire-test"
+	driver.RegisterDriverManger(nil, dbType, func(log *logrus.Entry, dbType string, config *driver.Config, client *driver.PluginClient) (driver.DriverManager, error) {
+		if err := acquire(); err != nil {
+			return nil, err
+		}
+		return &mockPooledDriverManager{release: func() { <-pool }}, nil
+	})
+
+	nodes, err := parseSQLsWithInstance(&model.Instance{DbType: dbType}, "select 1")
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	// the task is audited with another driver manager after the SQLs are
+	// parsed, it must not wait for the process held by parsing.
+	drvMgr, err := newDriverManagerWithoutAudit(logrus.NewEntry(logrus.New()), &model.Instance{DbType: dbType}, "")
+	assert.NoError(t, err)
+	drvMgr.Close(context.TODO())
+}
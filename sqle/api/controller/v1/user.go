@@ -8,6 +8,7 @@ import (
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/labstack/echo/v4"
 )
 
@@ -430,6 +431,141 @@ func UpdateCurrentUserPassword(c echo.Context) error {
 	return controller.JSONBaseErrorReq(c, nil)
 }
 
+type NotifyPreferenceV1 struct {
+	EventType string   `json:"event_type" enums:"workflow_create,workflow_approve,workflow_reject,workflow_execute_success,workflow_execute_fail,audit_plan_report" valid:"required,oneof=workflow_create workflow_approve workflow_reject workflow_execute_success workflow_execute_fail audit_plan_report"`
+	Channels  []string `json:"channels" enums:"email,slack,ding_talk,feishu,mattermost" valid:"dive,oneof=email slack ding_talk feishu mattermost"`
+}
+
+type GetCurrentUserNotifySettingResV1 struct {
+	controller.BaseRes
+	Data CurrentUserNotifySettingResV1 `json:"data"`
+}
+
+type CurrentUserNotifySettingResV1 struct {
+	EnableDailyDigest       bool                 `json:"enable_daily_digest"`
+	Preferences             []NotifyPreferenceV1 `json:"preferences"`
+	SubscribedInstanceNames []string             `json:"subscribed_instance_names"`
+}
+
+// @Summary 获取个人通知设置
+// @Description get notify setting of current user, the event which is not in preferences is received on all channels
+// @Id getCurrentUserNotifySettingV1
+// @Tags user
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetCurrentUserNotifySettingResV1
+// @router /v1/user/notify_setting [get]
+func GetCurrentUserNotifySetting(c echo.Context) error {
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	setting, _, err := s.GetUserNotifySetting(user.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	subscriptions, err := s.GetUserNotifySubscriptions(user.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := CurrentUserNotifySettingResV1{
+		EnableDailyDigest:       setting.EnableDailyDigest,
+		Preferences:             []NotifyPreferenceV1{},
+		SubscribedInstanceNames: []string{},
+	}
+	for _, event := range model.NotifyEvents {
+		channels, ok := setting.Preferences[event]
+		if !ok {
+			continue
+		}
+		data.Preferences = append(data.Preferences, NotifyPreferenceV1{
+			EventType: event,
+			Channels:  channels,
+		})
+	}
+	for _, subscription := range subscriptions {
+		data.SubscribedInstanceNames = append(data.SubscribedInstanceNames, subscription.InstanceName)
+	}
+	return c.JSON(http.StatusOK, &GetCurrentUserNotifySettingResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type UpdateCurrentUserNotifySettingReqV1 struct {
+	EnableDailyDigest       *bool                 `json:"enable_daily_digest"`
+	Preferences             *[]NotifyPreferenceV1 `json:"preferences" valid:"omitempty,dive"`
+	SubscribedInstanceNames *[]string             `json:"subscribed_instance_names"`
+}
+
+// @Summary 更新个人通知设置
+// @Description update notify setting of current user, the preferences and subscribed instances are overridden if they are set
+// @Id updateCurrentUserNotifySettingV1
+// @Tags user
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param instance body v1.UpdateCurrentUserNotifySettingReqV1 true "update notify setting"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/user/notify_setting [patch]
+func UpdateCurrentUserNotifySetting(c echo.Context) error {
+	req := new(UpdateCurrentUserNotifySettingReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+
+	if req.SubscribedInstanceNames != nil {
+		names := utils.RemoveDuplicate(*req.SubscribedInstanceNames)
+		for _, name := range names {
+			_, exist, err := s.GetInstanceByName(name)
+			if err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+			if !exist {
+				return controller.JSONBaseErrorReq(c, errInstanceNotExist)
+			}
+			// the user can subscribe the instance only if the user can view
+			// the workflows of others on it.
+			canView, err := s.CheckUserCanViewInstanceWorkflows(user, name)
+			if err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+			if !canView {
+				return controller.JSONBaseErrorReq(c, errInstanceNoAccess)
+			}
+		}
+		err = s.UpdateUserNotifySubscriptions(user.ID, names)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
+	setting, _, err := s.GetUserNotifySetting(user.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.EnableDailyDigest != nil {
+		setting.EnableDailyDigest = *req.EnableDailyDigest
+	}
+	if req.Preferences != nil {
+		setting.Preferences = model.NotifyPreferences{}
+		for _, p := range *req.Preferences {
+			setting.Preferences[p.EventType] = utils.RemoveDuplicate(p.Channels)
+		}
+	}
+	err = s.Save(setting)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, nil)
+}
+
 type GetUsersReqV1 struct {
 	FilterUserName string `json:"filter_user_name" query:"filter_user_name"`
 	FilterRoleName string `json:"filter_role_name" query:"filter_role_name"`
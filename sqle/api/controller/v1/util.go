@@ -1,8 +1,17 @@
 package v1
 
 import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/driver"
+	sqleErr "github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/report"
+
+	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -43,3 +52,19 @@ func newDSN(instance *model.Instance, database string) (*driver.DSN, error) {
 		DatabaseName:     database,
 	}, nil
 }
+
+// exportReport responds the report as an attachment, the file name is the
+// report title with the extension of format. The default format is csv.
+func exportReport(c echo.Context, r *report.Report, format report.Format) error {
+	if format == "" {
+		format = report.FormatCSV
+	}
+	content, err := r.Export(format)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, sqleErr.New(sqleErr.WriteDataToTheFileError, err))
+	}
+	fileName := fmt.Sprintf("%v.%v", r.Title, format)
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return c.Blob(http.StatusOK, format.ContentType(), content)
+}
@@ -102,7 +102,11 @@ type GetAuditPlanReportSQLsResV2 struct {
 type AuditPlanReportSQLResV2 struct {
 	SQL         string `json:"audit_plan_report_sql" example:"select * from t1 where id = 1"`
 	AuditResult string `json:"audit_plan_report_sql_audit_result" example:"same format as task audit result"`
+	Fingerprint string `json:"audit_plan_report_sql_fingerprint" example:"select * from t1 where id = ?"`
 	Number      uint   `json:"number" example:"1"`
+	Location    string `json:"audit_plan_report_sql_location" example:"mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)"`
+	// FindingStatus compares the SQL with the previous report, it's empty if the SQL passes the audit.
+	FindingStatus string `json:"audit_plan_report_sql_finding_status" enums:"new,persisting,"`
 }
 
 // @Summary 获取指定审核计划的SQL审核详情
@@ -150,9 +154,12 @@ func GetAuditPlanReportSQLs(c echo.Context) error {
 	auditPlanReportSQLsResV2 := make([]AuditPlanReportSQLResV2, len(auditPlanReportSQLs))
 	for i, auditPlanReportSQL := range auditPlanReportSQLs {
 		auditPlanReportSQLsResV2[i] = AuditPlanReportSQLResV2{
-			SQL:         auditPlanReportSQL.SQL,
-			AuditResult: auditPlanReportSQL.AuditResult,
-			Number:      auditPlanReportSQL.Number,
+			SQL:           auditPlanReportSQL.SQL,
+			AuditResult:   auditPlanReportSQL.AuditResult,
+			Fingerprint:   auditPlanReportSQL.Fingerprint,
+			Number:        auditPlanReportSQL.Number,
+			Location:      auditPlanReportSQL.Location,
+			FindingStatus: auditPlanReportSQL.FindingStatus,
 		}
 	}
 	return c.JSON(http.StatusOK, &GetAuditPlanReportSQLsResV2{
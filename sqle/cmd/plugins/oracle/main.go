@@ -0,0 +1,42 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	adaptor "github.com/actiontech/sqle/sqle/pkg/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+	"github.com/actiontech/sqle/sqle/pkg/oracle"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+func main() {
+	plugin := adaptor.NewAdaptor(&adaptor.OracleDialector{})
+	plugin.AddAdditionalParams(&params.Param{
+		Key:   adaptor.OracleParamServiceName,
+		Value: "XE",
+		Desc:  "服务名",
+		Type:  params.ParamTypeString,
+	})
+
+	plugin.AddRuleHandlers(oracle.RuleHandlers, func(conn *sql.Conn, logger hclog.Logger) astrule.AuditState {
+		return oracle.NewAuditContext(conn, logger)
+	})
+
+	plugin.Serve(
+		adaptor.WithSQLParser(func(sql string) (interface{}, error) {
+			return oracle.Parse(sql)
+		}),
+		adaptor.WithSQLSplitter(oracle.SplitStatements),
+		adaptor.WithSQLClassifier(func(sql string) string {
+			stmt, err := oracle.Parse(sql)
+			if err == nil && stmt.IsDML() {
+				return driver.SQLTypeDML
+			}
+			return driver.SQLTypeDDL
+		}),
+		adaptor.WithCapabilities(driver.Capabilities{OfflineAudit: true}),
+	)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	adaptor "github.com/actiontech/sqle/sqle/pkg/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+	"github.com/actiontech/sqle/sqle/pkg/sqlserver"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+func listTablesInSchema(ctx context.Context, conf *driver.ListTablesInSchemaConf, dbConf adaptor.DbConf) (*driver.ListTablesInSchemaResult, error) {
+	tables, err := sqlserver.ListTables(ctx, dbConf.Conn, conf.Schema)
+	if err != nil {
+		return nil, err
+	}
+	result := &driver.ListTablesInSchemaResult{Tables: make([]driver.Table, 0, len(tables))}
+	for _, table := range tables {
+		result.Tables = append(result.Tables, driver.Table{Name: table})
+	}
+	return result, nil
+}
+
+func explain(ctx context.Context, conf *driver.ExplainConf, dbConf adaptor.DbConf) (*driver.ExplainResult, error) {
+	plan, err := sqlserver.Explain(ctx, dbConf.Conn, conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.ExplainResult{
+		ClassicResult: driver.ExplainClassicResult{
+			AnalysisInfoInTableFormat: plan.Table(),
+		},
+	}, nil
+}
+
+func main() {
+	audit := adaptor.NewAdaptor(&adaptor.MssqlDialector{})
+	audit.AddRuleHandlers(sqlserver.RuleHandlers, func(conn *sql.Conn, logger hclog.Logger) astrule.AuditState {
+		return sqlserver.NewAuditContext(conn, logger)
+	})
+
+	analysis := adaptor.NewAnalysisAdaptor(&adaptor.MssqlDialector{})
+	analysis.AddListTablesInSchemaFunc(listTablesInSchema)
+	analysis.AddExplainFunc(explain)
+
+	p := driver.NewPlugin()
+	p.AddDriverPlugin(audit.GeneratePlugin(
+		adaptor.WithSQLParser(func(sql string) (interface{}, error) {
+			return sqlserver.Parse(sql)
+		}),
+		adaptor.WithSQLSplitter(sqlserver.SplitBatches),
+		adaptor.WithSQLClassifier(func(sql string) string {
+			batch, err := sqlserver.Parse(sql)
+			if err == nil && !batch.IsDDL() {
+				return driver.SQLTypeDML
+			}
+			return driver.SQLTypeDDL
+		}),
+		adaptor.WithCapabilities(driver.Capabilities{
+			OfflineAudit:   true,
+			Analysis:       true,
+			ExplainFormats: []string{driver.ExplainFormatTraditional},
+		}),
+	))
+	p.AddAnalysisDriverPlugin(analysis.GeneratePlugin())
+	p.Serve()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/daemon"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFile string
+
+	daemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "Run scanner jobs defined in config file",
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logrus.WithField("scanner", "daemon")
+			cfg, err := daemon.LoadConfig(configFile)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+
+			d := daemon.New(log)
+			d.Apply(cfg)
+			defer d.Stop()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if cfg.StatusAddress != "" {
+				go func() {
+					log.Infof("status endpoint listen on %s", cfg.StatusAddress)
+					if err := daemon.ServeStatus(ctx, cfg.StatusAddress, d); err != nil {
+						log.Errorf("status endpoint exited, error: %v", err)
+					}
+				}()
+			}
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+			for sig := range sigCh {
+				if sig != syscall.SIGHUP {
+					log.Infof("receive signal %v, exit", sig)
+					return
+				}
+				newCfg, err := daemon.LoadConfig(configFile)
+				if err != nil {
+					log.Errorf("failed to reload config, keep the running jobs. error: %v", err)
+					continue
+				}
+				if newCfg.StatusAddress != cfg.StatusAddress {
+					log.Warnln("status_address can not be changed by reload, restart scannerd to apply it")
+				}
+				log.Infoln("reload config")
+				d.Apply(newCfg)
+			}
+		},
+	}
+)
+
+func init() {
+	daemonCmd.Flags().StringVarP(&configFile, "config", "c", "", "config file path")
+	_ = daemonCmd.MarkFlagRequired("config")
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/incremental"
+
+	"github.com/spf13/cobra"
+)
+
+var incrementalFlags struct {
+	gitBase      string
+	gitHead      string
+	submitMode   string
+	instanceType string
+	failLevel    string
+}
+
+// addIncrementalFlags adds the flags of git diff aware scanning, which is
+// used to check the SQLs touched by a merge request in CI.
+func addIncrementalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&incrementalFlags.gitBase, "git-base", "", "only scan the SQLs added or changed since the git revision, e.g. origin/main")
+	cmd.Flags().StringVar(&incrementalFlags.gitHead, "git-head", "", "the git revision to scan, the work tree is scanned if it's empty")
+	cmd.Flags().StringVar(&incrementalFlags.submitMode, "submit-mode", incremental.SubmitModeAudit, "how to submit the SQLs touched by git diff, audit: audit them once; partial: append them to audit plan")
+	cmd.Flags().StringVar(&incrementalFlags.instanceType, "instance-type", "MySQL", "the instance type of one-off audit")
+	cmd.Flags().StringVar(&incrementalFlags.failLevel, "fail-level", "error", "the audit level which fails the scan, normal|notice|warn|error")
+}
+
+// incrementalParams returns nil if "--git-base" is not set.
+func incrementalParams() *incremental.Params {
+	if incrementalFlags.gitBase == "" {
+		return nil
+	}
+	return &incremental.Params{
+		GitBase:      incrementalFlags.gitBase,
+		GitHead:      incrementalFlags.gitHead,
+		SubmitMode:   incrementalFlags.submitMode,
+		InstanceType: incrementalFlags.instanceType,
+		FailLevel:    incrementalFlags.failLevel,
+	}
+}
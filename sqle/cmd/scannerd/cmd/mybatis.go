@@ -26,6 +26,7 @@ var (
 				XMLDir:         dir,
 				APName:         rootCmdFlags.auditPlanName,
 				SkipErrorQuery: skipErrorQuery,
+				Incremental:    incrementalParams(),
 			}
 			log := logrus.WithField("scanner", "mybatis")
 			client := scanner.NewSQLEClient(scanner.DefaultTimeout, rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token)
@@ -49,5 +50,6 @@ func init() {
 	mybatisCmd.Flags().StringVarP(&dir, "dir", "D", "", "xml directory")
 	mybatisCmd.Flags().BoolVarP(&skipErrorQuery, "skip-error-query", "S", false, "skip the statement that the scanner failed to parse from within the xml file")
 	_ = mybatisCmd.MarkFlagRequired("dir")
+	addIncrementalFlags(mybatisCmd)
 	rootCmd.AddCommand(mybatisCmd)
 }
@@ -1,6 +1,10 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
 
 var (
 	rootCmdFlags struct {
@@ -13,6 +17,16 @@ var (
 	rootCmd = &cobra.Command{
 		Use:   "SQLE Scanner",
 		Short: "SQLE Scanner",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// the audit plan and token of daemon are defined in config file.
+			if cmd == daemonCmd {
+				return nil
+			}
+			if rootCmdFlags.auditPlanName == "" || rootCmdFlags.token == "" {
+				return fmt.Errorf(`required flag(s) "name", "token" not set`)
+			}
+			return nil
+		},
 	}
 )
 
@@ -21,8 +35,6 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.port, "port", "P", "10000", "sqle port")
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.auditPlanName, "name", "N", "", "audit plan name")
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.token, "token", "A", "", "sqle token")
-	_ = rootCmd.MarkPersistentFlagRequired("name")
-	_ = rootCmd.MarkPersistentFlagRequired("token")
 }
 
 func Execute() error {
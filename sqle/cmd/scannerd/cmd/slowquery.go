@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/slowquery"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/spool"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
 	"github.com/actiontech/sqle/sqle/pkg/scanner"
 
@@ -15,15 +16,19 @@ import (
 )
 
 var (
-	logFilePath string
+	logFilePath    string
+	checkpointFile string
+	spoolDir       string
+	spoolMaxSizeMB int64
 
 	slowlogCmd = &cobra.Command{
 		Use:   "slowquery",
 		Short: "Parse slow query",
 		Run: func(cmd *cobra.Command, args []string) {
 			param := &slowquery.Params{
-				LogFilePath: logFilePath,
-				APName:      rootCmdFlags.auditPlanName,
+				LogFilePath:    logFilePath,
+				APName:         rootCmdFlags.auditPlanName,
+				CheckpointFile: checkpointFile,
 			}
 			log := logrus.WithField("scanner", "slowquery")
 			client := scanner.NewSQLEClient(scanner.DefaultTimeout, rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token)
@@ -33,7 +38,17 @@ var (
 				os.Exit(1)
 			}
 
-			err = supervisor.Start(context.TODO(), scanner, 30, 1024)
+			var opts []supervisor.Option
+			if spoolDir != "" {
+				sp, err := spool.New(log, spoolDir, spoolMaxSizeMB*1024*1024)
+				if err != nil {
+					fmt.Println(color.RedString(err.Error()))
+					os.Exit(1)
+				}
+				opts = append(opts, supervisor.WithSpool(sp))
+			}
+
+			err = supervisor.Start(context.TODO(), scanner, 30, 1024, opts...)
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
@@ -44,6 +59,9 @@ var (
 
 func init() {
 	slowlogCmd.Flags().StringVarP(&logFilePath, "log-file", "", "", "log file absolute path")
+	slowlogCmd.Flags().StringVarP(&checkpointFile, "checkpoint-file", "", "", "file to save the position of uploaded slow log, used to resume after restart")
+	slowlogCmd.Flags().StringVarP(&spoolDir, "spool-dir", "", "", "directory to buffer the sql failed to upload, the sql is retried until uploaded")
+	slowlogCmd.Flags().Int64VarP(&spoolMaxSizeMB, "spool-max-size", "", 512, "max size(MB) of spool directory, the oldest sql is dropped when exceeded")
 	_ = slowlogCmd.MarkFlagRequired("log-file")
 	rootCmd.AddCommand(slowlogCmd)
 }
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/sourcecode"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourceCodeDir string
+
+	sourceCodeCmd = &cobra.Command{
+		Use:   "sourcecode",
+		Short: "Extract SQL from Java and Go source code",
+		Run: func(cmd *cobra.Command, args []string) {
+			param := &sourcecode.Params{
+				Dir:         sourceCodeDir,
+				APName:      rootCmdFlags.auditPlanName,
+				Incremental: incrementalParams(),
+			}
+			log := logrus.WithField("scanner", "sourcecode")
+			client := scanner.NewSQLEClient(scanner.DefaultTimeout, rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token)
+			scanner, err := sourcecode.New(param, log, client)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+
+			err = supervisor.Start(context.TODO(), scanner, 30, 1024)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	sourceCodeCmd.Flags().StringVarP(&sourceCodeDir, "dir", "D", "", "source code directory")
+	_ = sourceCodeCmd.MarkFlagRequired("dir")
+	addIncrementalFlags(sourceCodeCmd)
+	rootCmd.AddCommand(sourceCodeCmd)
+}
@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	JobTypeSlowQuery  = "slowquery"
+	JobTypeMyBatis    = "mybatis"
+	JobTypeSourceCode = "sourcecode"
+)
+
+// Config is the configuration of scannerd daemon, e.g.
+//
+//	sqle:
+//	  host: 127.0.0.1
+//	  port: 10000
+//	  token: xxx
+//	status_address: 127.0.0.1:12601
+//	data_dir: /var/lib/scannerd
+//	jobs:
+//	  - name: mysql-3306
+//	    type: slowquery
+//	    audit_plan: slow_log_3306
+//	    log_file: /data/mysql/3306/slow.log
+//	  - name: order-service
+//	    type: mybatis
+//	    audit_plan: order_service_mybatis
+//	    dir: /src/order-service/mapper
+//	    interval: 24h
+//	  - name: user-service
+//	    type: sourcecode
+//	    audit_plan: user_service_code
+//	    dir: /src/user-service
+//	    interval: 24h
+type Config struct {
+	SQLE SQLEConfig `yaml:"sqle"`
+	// StatusAddress is the listen address of status endpoint, it is disabled if empty.
+	StatusAddress string `yaml:"status_address"`
+	// DataDir is the directory to store spool and checkpoint of each job.
+	DataDir string      `yaml:"data_dir"`
+	Jobs    []JobConfig `yaml:"jobs"`
+}
+
+type SQLEConfig struct {
+	Host  string `yaml:"host"`
+	Port  string `yaml:"port"`
+	Token string `yaml:"token"`
+}
+
+type JobConfig struct {
+	Name      string `yaml:"name"`
+	Type      string `yaml:"type"`
+	AuditPlan string `yaml:"audit_plan"`
+	// Token overrides the token in sqle config, it's optional.
+	Token string `yaml:"token"`
+
+	// LogFile is the slow log file path of slowquery job.
+	LogFile string `yaml:"log_file"`
+	// SpoolMaxSizeMB is the max spool size of slowquery job, default is 512.
+	SpoolMaxSizeMB int64 `yaml:"spool_max_size_mb"`
+
+	// Dir is the xml directory of mybatis job or the code directory of sourcecode job.
+	Dir            string `yaml:"dir"`
+	SkipErrorQuery bool   `yaml:"skip_error_query"`
+	// Interval is the interval to rescan the directory of mybatis and sourcecode
+	// job, the job runs only once if it's zero.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// LoadConfig reads and validates the config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config %s", path)
+	}
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config %s", path)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	if c.SQLE.Host == "" {
+		c.SQLE.Host = "127.0.0.1"
+	}
+	if c.SQLE.Port == "" {
+		c.SQLE.Port = "10000"
+	}
+	names := map[string]struct{}{}
+	for i := range c.Jobs {
+		job := &c.Jobs[i]
+		if job.Name == "" {
+			return fmt.Errorf("the name of job %d is empty", i+1)
+		}
+		if _, ok := names[job.Name]; ok {
+			return fmt.Errorf("job %s is duplicated", job.Name)
+		}
+		names[job.Name] = struct{}{}
+
+		if job.AuditPlan == "" {
+			return fmt.Errorf("the audit_plan of job %s is empty", job.Name)
+		}
+		if job.Token == "" {
+			job.Token = c.SQLE.Token
+		}
+		if job.Token == "" {
+			return fmt.Errorf("the token of job %s is empty", job.Name)
+		}
+		switch job.Type {
+		case JobTypeSlowQuery:
+			if job.LogFile == "" {
+				return fmt.Errorf("the log_file of job %s is empty", job.Name)
+			}
+			if job.SpoolMaxSizeMB == 0 {
+				job.SpoolMaxSizeMB = 512
+			}
+		case JobTypeMyBatis, JobTypeSourceCode:
+			if job.Dir == "" {
+				return fmt.Errorf("the dir of job %s is empty", job.Name)
+			}
+		default:
+			return fmt.Errorf("the type %s of job %s is not supported", job.Type, job.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/mybatis"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/slowquery"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/sourcecode"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/spool"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	leastPushSecond = 30
+	pushBufferSize  = 1024
+
+	minRestartInterval = time.Second
+	maxRestartInterval = 5 * time.Minute
+)
+
+// Daemon runs the scanner jobs in config concurrently. It is goroutine safe.
+type Daemon struct {
+	l *logrus.Entry
+
+	mu   sync.Mutex
+	cfg  *Config
+	jobs map[string]*job
+
+	// newScanner creates scanner for job, it's replaced in unit test.
+	newScanner func(cfg *Config, jobCfg JobConfig, l *logrus.Entry) (scanners.Scanner, []supervisor.Option, error)
+}
+
+func New(l *logrus.Entry) *Daemon {
+	return &Daemon{
+		l:          l,
+		jobs:       map[string]*job{},
+		newScanner: newScanner,
+	}
+}
+
+// Apply starts the new jobs, restarts the changed jobs and stops the removed jobs in config.
+func (d *Daemon) Apply(cfg *Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expected := make(map[string]JobConfig, len(cfg.Jobs))
+	for _, jobCfg := range cfg.Jobs {
+		expected[jobCfg.Name] = jobCfg
+	}
+	sqleChanged := d.cfg != nil && (d.cfg.SQLE != cfg.SQLE || d.cfg.DataDir != cfg.DataDir)
+	for name, j := range d.jobs {
+		jobCfg, ok := expected[name]
+		if ok && !sqleChanged && reflect.DeepEqual(jobCfg, j.cfg) {
+			continue
+		}
+		d.l.WithField("job", name).Infoln("stop job")
+		j.stop()
+		delete(d.jobs, name)
+	}
+
+	d.cfg = cfg
+	for _, jobCfg := range cfg.Jobs {
+		if _, ok := d.jobs[jobCfg.Name]; ok {
+			continue
+		}
+		j := newJob(d, jobCfg)
+		d.jobs[jobCfg.Name] = j
+		d.l.WithField("job", jobCfg.Name).Infoln("start job")
+		j.start()
+	}
+}
+
+// Stop stops all jobs and waits for them to exit.
+func (d *Daemon) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for name, j := range d.jobs {
+		j.stop()
+		delete(d.jobs, name)
+	}
+}
+
+// Status returns the status of all jobs ordered as in config.
+func (d *Daemon) Status() []JobStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ret := make([]JobStatus, 0, len(d.jobs))
+	if d.cfg == nil {
+		return ret
+	}
+	for _, jobCfg := range d.cfg.Jobs {
+		if j, ok := d.jobs[jobCfg.Name]; ok {
+			ret = append(ret, j.getStatus())
+		}
+	}
+	return ret
+}
+
+func newScanner(cfg *Config, jobCfg JobConfig, l *logrus.Entry) (scanners.Scanner, []supervisor.Option, error) {
+	client := scanner.NewSQLEClient(scanner.DefaultTimeout, cfg.SQLE.Host, cfg.SQLE.Port).WithToken(jobCfg.Token)
+
+	switch jobCfg.Type {
+	case JobTypeSlowQuery:
+		params := &slowquery.Params{
+			LogFilePath: jobCfg.LogFile,
+			APName:      jobCfg.AuditPlan,
+		}
+		var opts []supervisor.Option
+		if cfg.DataDir != "" {
+			jobDir := filepath.Join(cfg.DataDir, jobCfg.Name)
+			params.CheckpointFile = filepath.Join(jobDir, "checkpoint.json")
+			sp, err := spool.New(l, filepath.Join(jobDir, "spool"), jobCfg.SpoolMaxSizeMB*1024*1024)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, supervisor.WithSpool(sp))
+		}
+		s, err := slowquery.New(params, l, client)
+		return s, opts, err
+	case JobTypeMyBatis:
+		s, err := mybatis.New(&mybatis.Params{
+			XMLDir:         jobCfg.Dir,
+			APName:         jobCfg.AuditPlan,
+			SkipErrorQuery: jobCfg.SkipErrorQuery,
+		}, l, client)
+		return s, nil, err
+	case JobTypeSourceCode:
+		s, err := sourcecode.New(&sourcecode.Params{
+			Dir:    jobCfg.Dir,
+			APName: jobCfg.AuditPlan,
+		}, l, client)
+		return s, nil, err
+	}
+	return nil, nil, errors.Errorf("the type %s of job %s is not supported", jobCfg.Type, jobCfg.Name)
+}
@@ -0,0 +1,178 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := os.MkdirTemp("", "scannerd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "scanners.yaml")
+
+	assert.NoError(t, os.WriteFile(path, []byte(`
+sqle:
+  token: token1
+jobs:
+  - name: slow
+    type: slowquery
+    audit_plan: ap1
+    log_file: /tmp/slow.log
+  - name: mapper
+    type: mybatis
+    audit_plan: ap2
+    token: token2
+    dir: /tmp/mapper
+    interval: 1h
+`), 0644))
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.SQLE.Host)
+	assert.Len(t, cfg.Jobs, 2)
+	assert.Equal(t, "token1", cfg.Jobs[0].Token)
+	assert.Equal(t, int64(512), cfg.Jobs[0].SpoolMaxSizeMB)
+	assert.Equal(t, "token2", cfg.Jobs[1].Token)
+	assert.Equal(t, time.Hour, cfg.Jobs[1].Interval)
+
+	for _, content := range []string{
+		"jobs:\n  - name: a\n    type: slowquery\n    audit_plan: ap\n    token: t\n",
+		"jobs:\n  - name: a\n    type: unknown\n    audit_plan: ap\n    token: t\n",
+		"jobs:\n  - name: a\n    type: mybatis\n    audit_plan: ap\n    dir: d\n",
+		"jobs:\n  - name: a\n    type: mybatis\n    audit_plan: ap\n    token: t\n    dir: d\n  - name: a\n    type: mybatis\n    audit_plan: ap\n    token: t\n    dir: d\n",
+		"unknown_key: 1\n",
+	} {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err = LoadConfig(path)
+		assert.Error(t, err, content)
+	}
+}
+
+type mockScanner struct {
+	sqlCh chan scanners.SQL
+}
+
+func (m *mockScanner) Run(ctx context.Context) error {
+	m.sqlCh <- scanners.SQL{RawText: "select 1"}
+	close(m.sqlCh)
+	<-ctx.Done()
+	return nil
+}
+
+func (m *mockScanner) SQLs() <-chan scanners.SQL {
+	return m.sqlCh
+}
+
+func (m *mockScanner) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	if sqls[0].RawText == "fail" {
+		return fmt.Errorf("upload failed")
+	}
+	return nil
+}
+
+func TestDaemon(t *testing.T) {
+	var mu sync.Mutex
+	created := map[string]int{}
+
+	d := New(logrus.New().WithField("test", "test"))
+	d.newScanner = func(cfg *Config, jobCfg JobConfig, l *logrus.Entry) (scanners.Scanner, []supervisor.Option, error) {
+		mu.Lock()
+		created[jobCfg.Name]++
+		mu.Unlock()
+		return &mockScanner{sqlCh: make(chan scanners.SQL, 1)}, nil, nil
+	}
+	createdCount := func(name string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return created[name]
+	}
+
+	d.Apply(&Config{Jobs: []JobConfig{
+		{Name: "a", Type: JobTypeMyBatis, AuditPlan: "ap1"},
+		{Name: "b", Type: JobTypeMyBatis, AuditPlan: "ap2"},
+	}})
+	assert.Eventually(t, func() bool {
+		status := d.Status()
+		return len(status) == 2 && status[0].State == JobStateFinished && status[1].State == JobStateFinished
+	}, 5*time.Second, 10*time.Millisecond)
+	status := d.Status()
+	assert.Equal(t, "a", status[0].Name)
+	assert.Equal(t, uint64(1), status[0].UploadedSQLCount)
+	assert.NotNil(t, status[0].LastUploadTime)
+
+	// reload: "a" is unchanged, "b" is changed, "c" is added.
+	d.Apply(&Config{Jobs: []JobConfig{
+		{Name: "a", Type: JobTypeMyBatis, AuditPlan: "ap1"},
+		{Name: "b", Type: JobTypeMyBatis, AuditPlan: "ap3"},
+		{Name: "c", Type: JobTypeMyBatis, AuditPlan: "ap4"},
+	}})
+	assert.Eventually(t, func() bool {
+		return createdCount("b") == 2 && createdCount("c") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, createdCount("a"))
+
+	// reload: "a" and "b" are removed.
+	d.Apply(&Config{Jobs: []JobConfig{
+		{Name: "c", Type: JobTypeMyBatis, AuditPlan: "ap4"},
+	}})
+	status = d.Status()
+	assert.Len(t, status, 1)
+	assert.Equal(t, "c", status[0].Name)
+
+	rec := httptest.NewRecorder()
+	NewStatusHandler(d).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, StatusURI, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	res := struct {
+		Jobs []JobStatus `json:"jobs"`
+	}{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Len(t, res.Jobs, 1)
+	assert.Equal(t, "ap4", res.Jobs[0].AuditPlan)
+
+	d.Stop()
+	assert.Len(t, d.Status(), 0)
+}
+
+func TestJobRecordUploadError(t *testing.T) {
+	d := New(logrus.New().WithField("test", "test"))
+	d.newScanner = func(cfg *Config, jobCfg JobConfig, l *logrus.Entry) (scanners.Scanner, []supervisor.Option, error) {
+		return &failedScanner{}, nil, nil
+	}
+	d.Apply(&Config{Jobs: []JobConfig{{Name: "a", Type: JobTypeMyBatis, AuditPlan: "ap1"}}})
+	defer d.Stop()
+
+	assert.Eventually(t, func() bool {
+		status := d.Status()
+		return status[0].State == JobStateWaiting && status[0].LastUploadError != "" && status[0].LastError != ""
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+type failedScanner struct {
+	mockScanner
+}
+
+func (f *failedScanner) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (f *failedScanner) SQLs() <-chan scanners.SQL {
+	sqlCh := make(chan scanners.SQL, 1)
+	sqlCh <- scanners.SQL{RawText: "fail"}
+	close(sqlCh)
+	return sqlCh
+}
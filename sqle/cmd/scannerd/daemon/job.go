@@ -0,0 +1,174 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	JobStateRunning  = "running"
+	JobStateWaiting  = "waiting"
+	JobStateFinished = "finished"
+)
+
+type JobStatus struct {
+	Name             string     `json:"name"`
+	Type             string     `json:"type"`
+	AuditPlan        string     `json:"audit_plan"`
+	State            string     `json:"state"`
+	StartTime        *time.Time `json:"start_time,omitempty"`
+	LastUploadTime   *time.Time `json:"last_upload_time,omitempty"`
+	LastUploadError  string     `json:"last_upload_error,omitempty"`
+	UploadedSQLCount uint64     `json:"uploaded_sql_count"`
+	// LastError is the error which stops the scanner, the scanner is restarted after a while.
+	LastError string `json:"last_error,omitempty"`
+}
+
+type job struct {
+	d   *Daemon
+	l   *logrus.Entry
+	cfg JobConfig
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status JobStatus
+}
+
+func newJob(d *Daemon, cfg JobConfig) *job {
+	return &job{
+		d:   d,
+		l:   d.l.WithField("job", cfg.Name),
+		cfg: cfg,
+		status: JobStatus{
+			Name:      cfg.Name,
+			Type:      cfg.Type,
+			AuditPlan: cfg.AuditPlan,
+			State:     JobStateWaiting,
+		},
+	}
+}
+
+func (j *job) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.done = make(chan struct{})
+	// the config may be replaced by reload, the job keeps the one it's started with.
+	cfg := j.d.cfg
+	go func() {
+		defer close(j.done)
+		j.loop(ctx, cfg)
+	}()
+}
+
+func (j *job) stop() {
+	j.cancel()
+	<-j.done
+}
+
+// loop runs the scanner until ctx is canceled. The scanner is restarted with
+// exponential backoff if it fails, and the one-shot scanner (e.g. mybatis) is
+// rerun after interval if it's configured.
+func (j *job) loop(ctx context.Context, cfg *Config) {
+	restartInterval := minRestartInterval
+	for {
+		err := j.runOnce(ctx, cfg)
+		if ctx.Err() != nil {
+			return
+		}
+
+		var wait time.Duration
+		if err != nil {
+			j.l.Errorf("scanner exited with error, restart after %v, error: %v", restartInterval, err)
+			wait = restartInterval
+			restartInterval *= 2
+			if restartInterval > maxRestartInterval {
+				restartInterval = maxRestartInterval
+			}
+		} else {
+			restartInterval = minRestartInterval
+			if j.cfg.Interval <= 0 {
+				j.setState(JobStateFinished, nil)
+				j.l.Infoln("scanner finished")
+				return
+			}
+			wait = j.cfg.Interval
+		}
+		j.setState(JobStateWaiting, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (j *job) runOnce(ctx context.Context, cfg *Config) error {
+	s, opts, err := j.d.newScanner(cfg, j.cfg, j.l)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	j.mu.Lock()
+	j.status.State = JobStateRunning
+	j.status.StartTime = &now
+	j.mu.Unlock()
+
+	return supervisor.Start(ctx, &statusScanner{Scanner: s, j: j}, leastPushSecond, pushBufferSize, opts...)
+}
+
+func (j *job) setState(state string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.State = state
+	if err != nil {
+		j.status.LastError = err.Error()
+	}
+}
+
+func (j *job) getStatus() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+func (j *job) recordUpload(count int, err error) {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.LastUploadTime = &now
+	if err != nil {
+		j.status.LastUploadError = err.Error()
+		return
+	}
+	j.status.LastUploadError = ""
+	j.status.UploadedSQLCount += uint64(count)
+}
+
+// statusScanner records the upload result of scanner to job status.
+type statusScanner struct {
+	scanners.Scanner
+	j *job
+}
+
+func (s *statusScanner) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	err := s.Scanner.Upload(ctx, sqls)
+	s.j.recordUpload(len(sqls), err)
+	return err
+}
+
+// SaveCheckpoint implements scanners.Checkpointer.
+func (s *statusScanner) SaveCheckpoint(checkpoint string) error {
+	if cp, ok := s.Scanner.(scanners.Checkpointer); ok {
+		return cp.SaveCheckpoint(checkpoint)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const StatusURI = "/v1/status"
+
+// NewStatusHandler returns the handler of status endpoint, it responses
+// the status of all jobs in JSON.
+func NewStatusHandler(d *Daemon) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(StatusURI, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Jobs []JobStatus `json:"jobs"`
+		}{
+			Jobs: d.Status(),
+		})
+	})
+	return mux
+}
+
+// ServeStatus serves the status endpoint on addr until ctx is canceled.
+func ServeStatus(ctx context.Context, addr string, d *Daemon) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: NewStatusHandler(d),
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
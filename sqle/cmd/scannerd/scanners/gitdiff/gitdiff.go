@@ -0,0 +1,210 @@
+package gitdiff
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Diff is the lines added or changed between two revisions of git repository,
+// it limits the scanner to the SQLs touched by a merge request.
+type Diff struct {
+	// dir is the directory to scan. It's a temporary copy of the head revision
+	// if head is specified, otherwise it's the directory in work tree.
+	dir    string
+	tmpDir string
+	// prefix is the path of the scanned directory relative to the repository root.
+	prefix string
+	// changes is the changed line ranges of head revision, the key is the file
+	// path relative to the repository root.
+	changes map[string][]lineRange
+}
+
+type lineRange struct {
+	start, end int
+}
+
+// New compares the base revision with the head revision of the repository
+// which dir belongs to. The work tree is compared if head is empty.
+func New(ctx context.Context, dir, base, head string) (*Diff, error) {
+	prefix, err := git(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	d := &Diff{
+		dir:    dir,
+		prefix: strings.TrimSuffix(strings.TrimSpace(string(prefix)), "/"),
+	}
+
+	args := []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "--diff-filter=AMR", base}
+	if head != "" {
+		args = append(args, head)
+	}
+	// the path of diff is always relative to the repository root.
+	args = append(args, "--", ".")
+	out, err := git(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	d.changes = parseDiff(out)
+
+	if head != "" {
+		if err := d.checkout(ctx, head); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Dir returns the directory to scan.
+func (d *Diff) Dir() string {
+	return d.dir
+}
+
+// Close removes the temporary copy of head revision.
+func (d *Diff) Close() error {
+	if d.tmpDir == "" {
+		return nil
+	}
+	return os.RemoveAll(d.tmpDir)
+}
+
+// Touched checks whether the lines in [start, end] of file are added or
+// changed. The file is relative to Dir(), and the whole file is checked if
+// end is less than or equal to 0.
+func (d *Diff) Touched(file string, start, end int) bool {
+	ranges, ok := d.changes[filepath.ToSlash(filepath.Join(d.prefix, file))]
+	if !ok {
+		return false
+	}
+	if end <= 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if r.start <= end && start <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// Files returns the count of files changed.
+func (d *Diff) Files() int {
+	return len(d.changes)
+}
+
+var hunkRegexp = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// parseDiff parses the output of "git diff --unified=0".
+func parseDiff(out []byte) map[string][]lineRange {
+	changes := map[string][]lineRange{}
+	var file string
+	s := bufio.NewScanner(bytes.NewReader(out))
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			m := hunkRegexp.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			if count == 0 {
+				// the lines are deleted after the start line, both of the lines
+				// around the deleted lines are considered as changed.
+				changes[file] = append(changes[file], lineRange{start: start, end: start + 1})
+				continue
+			}
+			changes[file] = append(changes[file], lineRange{start: start, end: start + count - 1})
+		}
+	}
+	return changes
+}
+
+// checkout exports the scanned directory of head revision to a temporary
+// directory, so that the work tree is not changed.
+func (d *Diff) checkout(ctx context.Context, head string) error {
+	tmpDir, err := os.MkdirTemp("", "scannerd-git-")
+	if err != nil {
+		return err
+	}
+	d.tmpDir = tmpDir
+
+	// "git archive" in a subdirectory only exports the subdirectory, the paths
+	// in archive are relative to it.
+	out, err := git(ctx, d.dir, "archive", "--format=tar", head)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(bytes.NewReader(out))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read git archive")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Join(tmpDir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(path, filepath.Clean(tmpDir)+string(os.PathSeparator)) {
+			return errors.Errorf("invalid file path %s in git archive", hdr.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := writeFile(path, tr); err != nil {
+			return err
+		}
+	}
+	d.dir = tmpDir
+	return nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
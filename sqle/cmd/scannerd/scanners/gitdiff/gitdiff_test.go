@@ -0,0 +1,136 @@
+package gitdiff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiff(t *testing.T) {
+	out := `diff --git a/app/UserMapper.xml b/app/UserMapper.xml
+index 1111111..2222222 100644
+--- a/app/UserMapper.xml
++++ b/app/UserMapper.xml
+@@ -3 +3 @@
+-old
++new
+@@ -10,0 +11,3 @@
++a
++b
++c
+@@ -20,2 +22,0 @@
+-d
+-e
+diff --git a/app/new.go b/app/new.go
+new file mode 100644
+--- /dev/null
++++ b/app/new.go
+@@ -0,0 +1,2 @@
++package app
++
+`
+	changes := parseDiff([]byte(out))
+	assert.Equal(t, map[string][]lineRange{
+		"app/UserMapper.xml": {{start: 3, end: 3}, {start: 11, end: 13}, {start: 22, end: 23}},
+		"app/new.go":         {{start: 1, end: 2}},
+	}, changes)
+}
+
+func TestTouched(t *testing.T) {
+	d := &Diff{
+		prefix: "app",
+		changes: map[string][]lineRange{
+			"app/UserMapper.xml": {{start: 11, end: 13}},
+		},
+	}
+	assert.True(t, d.Touched("UserMapper.xml", 0, 0))
+	assert.True(t, d.Touched("UserMapper.xml", 5, 11))
+	assert.True(t, d.Touched("UserMapper.xml", 13, 20))
+	assert.False(t, d.Touched("UserMapper.xml", 1, 10))
+	assert.False(t, d.Touched("UserMapper.xml", 14, 20))
+	assert.False(t, d.Touched("OrderMapper.xml", 0, 0))
+	assert.Equal(t, 1, d.Files())
+}
+
+func TestNew(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if !assert.NoError(t, err, string(out)) {
+			t.FailNow()
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("app/a.txt", "1\n2\n3\n")
+	write("app/b.txt", "1\n")
+	write("other/c.txt", "1\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+	run("tag", "base")
+
+	write("app/a.txt", "1\n2 changed\n3\n")
+	write("other/c.txt", "1 changed\n")
+	run("commit", "-q", "-am", "head")
+	run("tag", "head")
+
+	// the uncommitted change is only visible when the work tree is compared.
+	write("app/b.txt", "1 changed\n")
+
+	dir := filepath.Join(repo, "app")
+	d, err := New(context.TODO(), dir, "base", "head")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NotEqual(t, dir, d.Dir())
+	content, err := os.ReadFile(filepath.Join(d.Dir(), "a.txt"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "1\n2 changed\n3\n", string(content))
+	content, err = os.ReadFile(filepath.Join(d.Dir(), "b.txt"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "1\n", string(content))
+	assert.True(t, d.Touched("a.txt", 2, 2))
+	assert.False(t, d.Touched("a.txt", 3, 3))
+	assert.False(t, d.Touched("b.txt", 0, 0))
+	// the files out of the scanned directory are ignored.
+	assert.Equal(t, 1, d.Files())
+	tmpDir := d.Dir()
+	assert.NoError(t, d.Close())
+	_, err = os.Stat(tmpDir)
+	assert.True(t, os.IsNotExist(err))
+
+	d, err = New(context.TODO(), dir, "base", "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, dir, d.Dir())
+	assert.True(t, d.Touched("a.txt", 2, 2))
+	assert.True(t, d.Touched("b.txt", 1, 1))
+	assert.Equal(t, 2, d.Files())
+	assert.NoError(t, d.Close())
+
+	_, err = New(context.TODO(), dir, "not-exist-revision", "")
+	assert.Error(t, err)
+}
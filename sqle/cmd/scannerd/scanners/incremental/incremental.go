@@ -0,0 +1,230 @@
+package incremental
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SubmitModeAudit audits the SQLs once without saving them to audit plan.
+	SubmitModeAudit = "audit"
+	// SubmitModePartial appends the SQLs to audit plan and triggers the audit of audit plan.
+	SubmitModePartial = "partial"
+)
+
+// Params enables the scanner to extract only the SQLs added or changed
+// between two git revisions, which is used to check merge requests in CI.
+type Params struct {
+	GitBase string
+	// GitHead is the revision to be scanned, the work tree is scanned if it's empty.
+	GitHead      string
+	SubmitMode   string
+	InstanceType string
+	// FailLevel is the audit level which fails the check, default is "error".
+	FailLevel string
+}
+
+// Enabled returns true if the scanner should scan incrementally.
+func (p *Params) Enabled() bool {
+	return p != nil && p.GitBase != ""
+}
+
+func (p *Params) Validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	switch p.SubmitMode {
+	case "":
+		p.SubmitMode = SubmitModeAudit
+	case SubmitModeAudit, SubmitModePartial:
+	default:
+		return errors.Errorf("the submit mode %s is not supported", p.SubmitMode)
+	}
+	if p.SubmitMode == SubmitModeAudit && p.InstanceType == "" {
+		return errors.New("the instance type is required by one-off audit")
+	}
+	switch driver.RuleLevel(p.FailLevel) {
+	case driver.RuleLevelNull:
+		p.FailLevel = string(driver.RuleLevelError)
+	case driver.RuleLevelNormal, driver.RuleLevelNotice, driver.RuleLevelWarn, driver.RuleLevelError:
+	default:
+		return errors.Errorf("the fail level %s is not supported", p.FailLevel)
+	}
+	return nil
+}
+
+// Result is the audit result of a SQL touched by the diff.
+type Result struct {
+	SQL         scanners.SQL
+	AuditLevel  string
+	AuditResult string
+	Failed      bool
+}
+
+// Summary is the audit results of the SQLs touched by the diff.
+type Summary struct {
+	Results   []Result
+	FailLevel string
+}
+
+func (s *Summary) Failed() int {
+	failed := 0
+	for _, r := range s.Results {
+		if r.Failed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Print writes the summary in a human readable format, e.g.
+//
+//	[FAIL] mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)
+//	       SELECT * FROM users
+//	       [error]禁止使用SELECT *
+//	1 SQLs are touched, 1 failed
+func (s *Summary) Print(w io.Writer) {
+	for _, r := range s.Results {
+		status := "PASS"
+		if r.Failed {
+			status = "FAIL"
+		}
+		location := r.SQL.Location()
+		if location == "" {
+			location = "-"
+		}
+		fmt.Fprintf(w, "[%s] %s\n", status, location)
+		fmt.Fprintf(w, "       %s\n", strings.ReplaceAll(r.SQL.RawText, "\n", "\n       "))
+		if r.AuditResult != "" {
+			fmt.Fprintf(w, "       %s\n", strings.ReplaceAll(r.AuditResult, "\n", "\n       "))
+		}
+	}
+	fmt.Fprintf(w, "%d SQLs are touched, %d failed\n", len(s.Results), s.Failed())
+}
+
+// Submit audits the SQLs touched by the diff, the summary is printed to w. It
+// returns error if any SQL fails the audit.
+func Submit(c *scanner.Client, apName string, p *Params, sqls []scanners.SQL, w io.Writer) (*Summary, error) {
+	summary := &Summary{FailLevel: p.FailLevel}
+	if len(sqls) == 0 {
+		summary.Print(w)
+		return summary, nil
+	}
+
+	var err error
+	switch p.SubmitMode {
+	case SubmitModePartial:
+		err = submitPartial(c, apName, summary, sqls)
+	default:
+		err = submitAudit(c, p.InstanceType, summary, sqls)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Print(w)
+	if failed := summary.Failed(); failed > 0 {
+		return summary, errors.Errorf("%d of %d SQLs failed the audit", failed, len(summary.Results))
+	}
+	return summary, nil
+}
+
+// submitAudit audits the SQLs one by one, so the audit result is not mixed up
+// when SQLE splits the SQLs in a different way from scanner.
+func submitAudit(c *scanner.Client, instanceType string, summary *Summary, sqls []scanners.SQL) error {
+	for _, sql := range sqls {
+		res, err := c.DirectAuditReq(instanceType, sql.RawText)
+		if err != nil {
+			return err
+		}
+		// the SQL may be split into several statements, e.g. the SQL of
+		// MyBatis <foreach>, the results of them are merged.
+		level := driver.RuleLevelNull
+		results := make([]string, 0, len(res.SQLResults))
+		for _, sqlRes := range res.SQLResults {
+			if driver.RuleLevel(sqlRes.AuditLevel).More(level) {
+				level = driver.RuleLevel(sqlRes.AuditLevel)
+			}
+			if sqlRes.AuditResult != "" {
+				results = append(results, sqlRes.AuditResult)
+			}
+		}
+		summary.add(sql, string(level), strings.Join(results, "\n"))
+	}
+	return nil
+}
+
+func submitPartial(c *scanner.Client, apName string, summary *Summary, sqls []scanners.SQL) error {
+	reqs := make([]scanner.AuditPlanSQLReq, 0, len(sqls))
+	for _, sql := range sqls {
+		req := scanner.AuditPlanSQLReq{
+			Fingerprint:       sql.Fingerprint,
+			Counter:           "1",
+			LastReceiveText:   sql.RawText,
+			MapperNamespace:   sql.MapperNamespace,
+			MapperStatementID: sql.MapperStatementID,
+			SourceFile:        sql.SourceFile,
+		}
+		if sql.SourceLine > 0 {
+			req.SourceLine = fmt.Sprintf("%d", sql.SourceLine)
+		}
+		reqs = append(reqs, req)
+	}
+	if err := c.UploadReq(scanner.PartialUpload, apName, reqs); err != nil {
+		return err
+	}
+	reportID, err := c.TriggerAuditReq(apName)
+	if err != nil {
+		return err
+	}
+	reportSQLs, err := c.GetAuditReportSQLsReq(apName, reportID)
+	if err != nil {
+		return err
+	}
+
+	// the report contains all SQLs of audit plan, only the touched ones are
+	// summarized. The audit plan keeps one SQL for each fingerprint, so the
+	// SQLs which have the same fingerprint share the audit result.
+	results := make(map[string]string, len(reportSQLs))
+	for _, reportSQL := range reportSQLs {
+		results[reportSQL.Fingerprint] = reportSQL.AuditResult
+	}
+	for _, sql := range sqls {
+		result, ok := results[sql.Fingerprint]
+		if !ok {
+			return errors.Errorf("the audit result of SQL \"%s\" is not found in report %s", sql.RawText, reportID)
+		}
+		summary.add(sql, auditLevelOf(result), result)
+	}
+	return nil
+}
+
+func (s *Summary) add(sql scanners.SQL, level, result string) {
+	s.Results = append(s.Results, Result{
+		SQL:         sql,
+		AuditLevel:  level,
+		AuditResult: result,
+		Failed:      driver.RuleLevel(level).MoreOrEqual(driver.RuleLevel(s.FailLevel)),
+	})
+}
+
+var auditLevelRegexp = regexp.MustCompile(`\[(normal|notice|warn|error)\]`)
+
+// auditLevelOf returns the highest level in audit result, e.g. "[error]xxx\n[warn]yyy".
+func auditLevelOf(result string) string {
+	level := driver.RuleLevelNull
+	for _, m := range auditLevelRegexp.FindAllStringSubmatch(result, -1) {
+		if driver.RuleLevel(m[1]).More(level) {
+			level = driver.RuleLevel(m[1])
+		}
+	}
+	return string(level)
+}
@@ -0,0 +1,173 @@
+package incremental
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsValidate(t *testing.T) {
+	var p *Params
+	assert.False(t, p.Enabled())
+	assert.NoError(t, p.Validate())
+
+	p = &Params{}
+	assert.False(t, p.Enabled())
+	assert.NoError(t, p.Validate())
+
+	p = &Params{GitBase: "origin/main", InstanceType: "MySQL"}
+	assert.True(t, p.Enabled())
+	assert.NoError(t, p.Validate())
+	assert.Equal(t, SubmitModeAudit, p.SubmitMode)
+	assert.Equal(t, "error", p.FailLevel)
+
+	p = &Params{GitBase: "origin/main"}
+	assert.Error(t, p.Validate())
+
+	p = &Params{GitBase: "origin/main", SubmitMode: SubmitModePartial}
+	assert.NoError(t, p.Validate())
+
+	p = &Params{GitBase: "origin/main", SubmitMode: "full", InstanceType: "MySQL"}
+	assert.Error(t, p.Validate())
+
+	p = &Params{GitBase: "origin/main", InstanceType: "MySQL", FailLevel: "fatal"}
+	assert.Error(t, p.Validate())
+}
+
+func TestAuditLevelOf(t *testing.T) {
+	assert.Equal(t, "", auditLevelOf(""))
+	assert.Equal(t, "notice", auditLevelOf("[notice]xxx"))
+	assert.Equal(t, "error", auditLevelOf("[warn]xxx\n[error]yyy\n[notice]zzz"))
+}
+
+func TestSubmitAudit(t *testing.T) {
+	var reqs []scanner.DirectAuditReq
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, scanner.DirectAudit, r.URL.Path)
+		var req scanner.DirectAuditReq
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs = append(reqs, req)
+		switch req.SQLContent {
+		case "SELECT * FROM users;":
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok","data":{"sql_results":[
+				{"number":1,"exec_sql":"SELECT * FROM users","audit_result":"[error]禁止使用SELECT *","audit_level":"error"}
+			]}}`))
+		case "SELECT 1; SELECT * FROM t1":
+			// the SQL is split into several statements by SQLE.
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok","data":{"sql_results":[
+				{"number":1,"exec_sql":"SELECT 1","audit_result":"","audit_level":""},
+				{"number":2,"exec_sql":"SELECT * FROM t1","audit_result":"[warn]禁止使用SELECT *","audit_level":"warn"}
+			]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok","data":{"sql_results":[
+				{"number":1,"exec_sql":"SELECT id FROM users","audit_result":"","audit_level":""}
+			]}}`))
+		}
+	}))
+	defer ts.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	assert.NoError(t, err)
+	c := scanner.NewSQLEClient(scanner.DefaultTimeout, host, port)
+
+	p := &Params{GitBase: "origin/main", InstanceType: "MySQL"}
+	assert.NoError(t, p.Validate())
+	sqls := []scanners.SQL{
+		{RawText: "SELECT * FROM users;", SourceFile: "UserMapper.xml", SourceLine: 12, MapperNamespace: "UserMapper", MapperStatementID: "getUser"},
+		{RawText: "SELECT id FROM users", SourceFile: "user.go", SourceLine: 3},
+	}
+	buf := &bytes.Buffer{}
+	summary, err := Submit(c, "ap", p, sqls, buf)
+	assert.Error(t, err)
+	// the SQLs are audited one by one.
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, "MySQL", reqs[0].InstanceType)
+	assert.Equal(t, "SELECT * FROM users;", reqs[0].SQLContent)
+	assert.Equal(t, "SELECT id FROM users", reqs[1].SQLContent)
+	assert.Len(t, summary.Results, 2)
+	assert.True(t, summary.Results[0].Failed)
+	assert.False(t, summary.Results[1].Failed)
+	assert.Equal(t, 1, summary.Failed())
+	assert.Equal(t, `[FAIL] UserMapper.xml:12 (UserMapper.getUser)
+       SELECT * FROM users;
+       [error]禁止使用SELECT *
+[PASS] user.go:3
+       SELECT id FROM users
+2 SQLs are touched, 1 failed
+`, buf.String())
+
+	// the results of the statements split from one SQL are merged.
+	buf.Reset()
+	summary, err = Submit(c, "ap", p, []scanners.SQL{{RawText: "SELECT 1; SELECT * FROM t1"}}, buf)
+	assert.NoError(t, err)
+	assert.Len(t, summary.Results, 1)
+	assert.Equal(t, "warn", summary.Results[0].AuditLevel)
+	assert.Equal(t, "[warn]禁止使用SELECT *", summary.Results[0].AuditResult)
+	assert.False(t, summary.Results[0].Failed)
+
+	buf.Reset()
+	summary, err = Submit(c, "ap", p, nil, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Failed())
+	assert.Equal(t, "0 SQLs are touched, 0 failed\n", buf.String())
+}
+
+func TestSubmitPartial(t *testing.T) {
+	var uploadReq scanner.PartialSyncAuditPlanSQLsReq
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/audit_plans/ap/sqls/partial":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&uploadReq))
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok"}`))
+		case "/v1/audit_plans/ap/trigger":
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok","data":{"audit_plan_report_id":"1"}}`))
+		case "/v2/audit_plans/ap/report/1/":
+			// the audit plan keeps one SQL for the SQLs which have the same fingerprint.
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok","total_nums":2,"data":[
+				{"audit_plan_report_sql":"SELECT * FROM users WHERE id = 2","audit_plan_report_sql_fingerprint":"SELECT * FROM users WHERE id = ?","audit_plan_report_sql_audit_result":"[error]禁止使用SELECT *"},
+				{"audit_plan_report_sql":"SELECT id FROM orders","audit_plan_report_sql_fingerprint":"SELECT id FROM orders","audit_plan_report_sql_audit_result":""}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	assert.NoError(t, err)
+	c := scanner.NewSQLEClient(scanner.DefaultTimeout, host, port)
+
+	p := &Params{GitBase: "origin/main", SubmitMode: SubmitModePartial}
+	assert.NoError(t, p.Validate())
+	sqls := []scanners.SQL{
+		{RawText: "SELECT * FROM users WHERE id = 1", Fingerprint: "SELECT * FROM users WHERE id = ?", SourceFile: "user.go", SourceLine: 3},
+		{RawText: "SELECT * FROM users WHERE id = 2", Fingerprint: "SELECT * FROM users WHERE id = ?", SourceFile: "user.go", SourceLine: 7},
+		{RawText: "SELECT id FROM orders", Fingerprint: "SELECT id FROM orders", SourceFile: "order.go", SourceLine: 5},
+	}
+	buf := &bytes.Buffer{}
+	summary, err := Submit(c, "ap", p, sqls, buf)
+	assert.Error(t, err)
+	assert.Len(t, uploadReq.SQLs, 3)
+	assert.Len(t, summary.Results, 3)
+	assert.True(t, summary.Results[0].Failed)
+	assert.True(t, summary.Results[1].Failed)
+	assert.False(t, summary.Results[2].Failed)
+	assert.Equal(t, 2, summary.Failed())
+	assert.Equal(t, `[FAIL] user.go:3
+       SELECT * FROM users WHERE id = 1
+       [error]禁止使用SELECT *
+[FAIL] user.go:7
+       SELECT * FROM users WHERE id = 2
+       [error]禁止使用SELECT *
+[PASS] order.go:5
+       SELECT id FROM orders
+3 SQLs are touched, 2 failed
+`, buf.String())
+}
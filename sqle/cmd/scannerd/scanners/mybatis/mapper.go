@@ -0,0 +1,466 @@
+package mybatis
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxVariants limits the SQLs generated for one mapper statement.
+const maxVariants = 16
+
+// maxIncludeDepth prevents the circular <include> from expanding infinitely.
+const maxIncludeDepth = 16
+
+// Statement is a SQL statement defined in MyBatis mapper, e.g. <select id="getUser">.
+type Statement struct {
+	Namespace string
+	ID        string
+	File      string
+	Line      int
+	EndLine   int
+
+	node *xmlNode
+	// includes are the <sql> fragments referred by the statement directly or
+	// indirectly, it's set by expander.
+	includes []*fragment
+}
+
+type xmlNode struct {
+	name     string // element name, it's empty for text node
+	attrs    map[string]string
+	text     string
+	line     int
+	endLine  int
+	children []*xmlNode
+}
+
+func (n *xmlNode) attr(name string) string {
+	return n.attrs[name]
+}
+
+// mapperFile is the parsed MyBatis mapper file.
+type mapperFile struct {
+	namespace  string
+	file       string
+	statements []*Statement
+	// fragments are the <sql> elements, key is "namespace.id".
+	fragments map[string]*fragment
+}
+
+// fragment is the reusable <sql> element referred by <include>.
+type fragment struct {
+	namespace string
+	file      string
+	node      *xmlNode
+}
+
+// parseMapper parses MyBatis mapper, it returns nil if the root element is not
+// <mapper>, e.g. iBatis <sqlMap>.
+func parseMapper(file, content string) (*mapperFile, error) {
+	root, err := parseXMLTree(content)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil || root.name != "mapper" {
+		return nil, nil
+	}
+
+	m := &mapperFile{
+		namespace: root.attr("namespace"),
+		file:      file,
+		fragments: map[string]*fragment{},
+	}
+	for _, child := range root.children {
+		switch child.name {
+		case "sql":
+			m.fragments[m.namespace+"."+child.attr("id")] = &fragment{namespace: m.namespace, file: file, node: child}
+		case "select", "insert", "update", "delete":
+			m.statements = append(m.statements, &Statement{
+				Namespace: m.namespace,
+				ID:        child.attr("id"),
+				File:      file,
+				Line:      child.line,
+				EndLine:   child.endLine,
+				node:      child,
+			})
+		}
+	}
+	return m, nil
+}
+
+func parseXMLTree(content string) (*xmlNode, error) {
+	// newlines holds the offsets of line breaks, it's used to locate the line of element.
+	var newlines []int
+	for i, c := range content {
+		if c == '\n' {
+			newlines = append(newlines, i)
+		}
+	}
+	lineAt := func(offset int64) int {
+		return sort.SearchInts(newlines, int(offset)) + 1
+	}
+
+	d := xml.NewDecoder(strings.NewReader(content))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		offset := d.InputOffset()
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			n := &xmlNode{
+				name:  tt.Name.Local,
+				attrs: make(map[string]string, len(tt.Attr)),
+				line:  lineAt(offset),
+			}
+			for _, attr := range tt.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("multiple root elements")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack[len(stack)-1].endLine = lineAt(d.InputOffset() - 1)
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) == 0 {
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, &xmlNode{text: string(tt), line: lineAt(offset)})
+		}
+	}
+	return root, nil
+}
+
+// variantSpec decides the branches of dynamic SQL elements used by a variant.
+type variantSpec struct {
+	// ifValue is the result of all <if> tests.
+	ifValue bool
+	// choices maps the <choose> element to the index of the chosen branch, the
+	// index equals to the count of <when> means none of <when> is matched. The
+	// first <when> is chosen if the element is not in choices.
+	choices map[*xmlNode]int
+}
+
+// chooseBranches returns the branches of <choose>. The last branch is the
+// <otherwise> or nil if the element does not have one.
+func chooseBranches(n *xmlNode) []*xmlNode {
+	var whens []*xmlNode
+	var otherwise *xmlNode
+	for _, child := range n.children {
+		switch child.name {
+		case "when":
+			whens = append(whens, child)
+		case "otherwise":
+			otherwise = child
+		}
+	}
+	return append(whens, otherwise)
+}
+
+// expander expands the dynamic SQL elements of mapper statements. The <include>
+// can refer to the <sql> defined in other mapper files.
+type expander struct {
+	fragments map[string]*fragment
+}
+
+func newExpander(mappers []*mapperFile) *expander {
+	e := &expander{fragments: map[string]*fragment{}}
+	for _, m := range mappers {
+		for key, f := range m.fragments {
+			e.fragments[key] = f
+		}
+	}
+	return e
+}
+
+// Expand generates the representative SQLs of statement: all <if> are true,
+// all <if> are false, and each branch of <choose>. The duplicated SQLs are removed.
+func (e *expander) Expand(stmt *Statement) ([]string, error) {
+	specs := []*variantSpec{{ifValue: true}}
+
+	// render once to find out the <choose> elements, including the ones in <include>.
+	r := &renderer{e: e, spec: specs[0], namespace: stmt.Namespace}
+	if _, err := r.render(stmt.node.children, nil, 0); err != nil {
+		return nil, err
+	}
+	falseSpec := &variantSpec{choices: map[*xmlNode]int{}}
+	for _, choose := range r.chooses {
+		falseSpec.choices[choose] = len(chooseBranches(choose)) - 1
+	}
+	specs = append(specs, falseSpec)
+	for _, choose := range r.chooses {
+		for i := 1; i < len(chooseBranches(choose)); i++ {
+			specs = append(specs, &variantSpec{ifValue: true, choices: map[*xmlNode]int{choose: i}})
+		}
+	}
+
+	sqls := []string{}
+	exists := map[string]struct{}{}
+	includes := map[*fragment]struct{}{}
+	stmt.includes = nil
+	for _, spec := range specs {
+		r := &renderer{e: e, spec: spec, namespace: stmt.Namespace}
+		sql, err := r.render(stmt.node.children, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range r.includes {
+			if _, ok := includes[f]; !ok {
+				includes[f] = struct{}{}
+				stmt.includes = append(stmt.includes, f)
+			}
+		}
+		sql = compactSpace(sql)
+		if _, ok := exists[sql]; ok || sql == "" {
+			continue
+		}
+		exists[sql] = struct{}{}
+		sqls = append(sqls, sql)
+		if len(sqls) >= maxVariants {
+			break
+		}
+	}
+	return sqls, nil
+}
+
+type renderer struct {
+	e         *expander
+	spec      *variantSpec
+	namespace string
+
+	chooses  []*xmlNode
+	seen     map[*xmlNode]struct{}
+	includes []*fragment
+}
+
+func (r *renderer) render(nodes []*xmlNode, props map[string]string, depth int) (string, error) {
+	buf := strings.Builder{}
+	for _, n := range nodes {
+		s, err := r.renderNode(n, props, depth)
+		if err != nil {
+			return "", err
+		}
+		if s != "" {
+			buf.WriteString(" ")
+			buf.WriteString(s)
+		}
+	}
+	return buf.String(), nil
+}
+
+func (r *renderer) renderNode(n *xmlNode, props map[string]string, depth int) (string, error) {
+	switch n.name {
+	case "":
+		return substitute(n.text, props), nil
+	case "if":
+		if !r.spec.ifValue {
+			return "", nil
+		}
+		return r.render(n.children, props, depth)
+	case "choose":
+		if r.seen == nil {
+			r.seen = map[*xmlNode]struct{}{}
+		}
+		if _, ok := r.seen[n]; !ok {
+			r.seen[n] = struct{}{}
+			r.chooses = append(r.chooses, n)
+		}
+		branches := chooseBranches(n)
+		branch := branches[r.spec.choices[n]]
+		if branch == nil {
+			return "", nil
+		}
+		return r.render(branch.children, props, depth)
+	case "where":
+		s, err := r.render(n.children, props, depth)
+		if err != nil {
+			return "", err
+		}
+		return trim(s, "WHERE", "AND |OR ", "", ""), nil
+	case "set":
+		s, err := r.render(n.children, props, depth)
+		if err != nil {
+			return "", err
+		}
+		return trim(s, "SET", "", "", ","), nil
+	case "trim":
+		s, err := r.render(n.children, props, depth)
+		if err != nil {
+			return "", err
+		}
+		return trim(s, n.attr("prefix"), n.attr("prefixOverrides"), n.attr("suffix"), n.attr("suffixOverrides")), nil
+	case "foreach":
+		// one item is enough to represent the SQL, the fingerprint of "in (?, ?)" and "in (?)" is the same.
+		s, err := r.render(n.children, props, depth)
+		if err != nil {
+			return "", err
+		}
+		return n.attr("open") + s + " " + n.attr("close"), nil
+	case "include":
+		return r.renderInclude(n, props, depth)
+	case "bind", "selectKey", "property":
+		return "", nil
+	default:
+		return r.render(n.children, props, depth)
+	}
+}
+
+func (r *renderer) renderInclude(n *xmlNode, props map[string]string, depth int) (string, error) {
+	if depth >= maxIncludeDepth {
+		return "", fmt.Errorf("the depth of <include> exceeds %d, it may be circular", maxIncludeDepth)
+	}
+	refID := substitute(n.attr("refid"), props)
+	f, ok := r.e.fragments[r.namespace+"."+refID]
+	if !ok {
+		f, ok = r.e.fragments[refID]
+	}
+	if !ok {
+		return "", fmt.Errorf("the <sql> element %s referred by <include> is not found", refID)
+	}
+
+	r.includes = append(r.includes, f)
+
+	// the properties defined in <include> are only visible to the fragment.
+	newProps := make(map[string]string, len(props))
+	for k, v := range props {
+		newProps[k] = v
+	}
+	for _, child := range n.children {
+		if child.name == "property" {
+			newProps[child.attr("name")] = substitute(child.attr("value"), props)
+		}
+	}
+
+	// the <include> in the fragment refers to the namespace where the fragment is defined.
+	namespace := r.namespace
+	r.namespace = f.namespace
+	defer func() { r.namespace = namespace }()
+	return r.render(f.node.children, newProps, depth+1)
+}
+
+// trim implements <trim>, the overrides are separated by "|" and case-insensitive.
+func trim(s, prefix, prefixOverrides, suffix, suffixOverrides string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	for _, override := range splitOverrides(prefixOverrides) {
+		if hasPrefixFold(s, override) {
+			s = strings.TrimSpace(s[len(override):])
+			break
+		}
+	}
+	for _, override := range splitOverrides(suffixOverrides) {
+		if len(s) >= len(override) && strings.EqualFold(s[len(s)-len(override):], override) {
+			s = strings.TrimSpace(s[:len(s)-len(override)])
+			break
+		}
+	}
+	return strings.TrimSpace(prefix + " " + s + " " + suffix)
+}
+
+// hasPrefixFold checks the prefix case-insensitively, the keyword prefix such as
+// "AND" should not match "ANDROID".
+func hasPrefixFold(s, prefix string) bool {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return false
+	}
+	if len(s) == len(prefix) || !isWordChar(prefix[len(prefix)-1]) {
+		return true
+	}
+	return !isWordChar(s[len(prefix)])
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func splitOverrides(overrides string) []string {
+	var ret []string
+	for _, override := range strings.Split(overrides, "|") {
+		// the override "AND " should match "AND\n" too.
+		override = strings.TrimSpace(override)
+		if override != "" {
+			ret = append(ret, override)
+		}
+	}
+	return ret
+}
+
+var (
+	paramRegexp    = regexp.MustCompile(`#\{[^}]*\}`)
+	variableRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// the ${} follows the operators or LIMIT is a value.
+	valueContextRegexp = regexp.MustCompile(`(?i)([=<>(,+\-*/]|\blimit|\boffset)\s*$`)
+)
+
+// substitute replaces the "#{}" with placeholder and the "${}" with the value of
+// property. The "${}" without property is replaced by placeholder if it's used
+// as a value, otherwise it's replaced by its name, e.g. "ORDER BY ${column}".
+func substitute(text string, props map[string]string) string {
+	text = paramRegexp.ReplaceAllString(text, "?")
+
+	buf := strings.Builder{}
+	last := 0
+	for _, loc := range variableRegexp.FindAllStringSubmatchIndex(text, -1) {
+		buf.WriteString(text[last:loc[0]])
+		name := strings.TrimSpace(text[loc[2]:loc[3]])
+		if value, ok := props[name]; ok {
+			buf.WriteString(value)
+		} else if valueContextRegexp.MatchString(buf.String()) {
+			buf.WriteString("?")
+		} else {
+			// the name may be "column,jdbcType=VARCHAR".
+			buf.WriteString(strings.Split(name, ",")[0])
+		}
+		last = loc[1]
+	}
+	buf.WriteString(text[last:])
+	return buf.String()
+}
+
+// compactSpace replaces the consecutive whitespaces out of quotes with one space.
+func compactSpace(sql string) string {
+	buf := strings.Builder{}
+	var quote rune
+	space := false
+	for _, c := range strings.TrimSpace(sql) {
+		if quote == 0 && (c == ' ' || c == '\t' || c == '\n' || c == '\r') {
+			space = true
+			continue
+		}
+		if space {
+			buf.WriteRune(' ')
+			space = false
+		}
+		switch {
+		case quote == 0 && (c == '\'' || c == '"' || c == '`'):
+			quote = c
+		case quote == c:
+			quote = 0
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
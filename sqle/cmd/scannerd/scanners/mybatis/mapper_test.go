@@ -0,0 +1,129 @@
+package mybatis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func expandMappers(t *testing.T, contents ...string) map[string][]string {
+	var mappers []*mapperFile
+	for i, content := range contents {
+		m, err := parseMapper(string(rune('a'+i))+".xml", content)
+		assert.NoError(t, err)
+		mappers = append(mappers, m)
+	}
+	e := newExpander(mappers)
+	ret := map[string][]string{}
+	for _, m := range mappers {
+		for _, stmt := range m.statements {
+			sqls, err := e.Expand(stmt)
+			assert.NoError(t, err)
+			ret[stmt.ID] = sqls
+		}
+	}
+	return ret
+}
+
+func TestParseMapper(t *testing.T) {
+	m, err := parseMapper("user.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.example.UserMapper">
+    <sql id="columns">id, name</sql>
+    <select id="getUser">
+        SELECT <include refid="columns"/> FROM users WHERE id = #{id}
+    </select>
+
+    <delete id="deleteUser">DELETE FROM users WHERE id = #{id}</delete>
+</mapper>`)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example.UserMapper", m.namespace)
+	assert.Contains(t, m.fragments, "com.example.UserMapper.columns")
+	assert.Len(t, m.statements, 2)
+	assert.Equal(t, "getUser", m.statements[0].ID)
+	assert.Equal(t, 4, m.statements[0].Line)
+	assert.Equal(t, "deleteUser", m.statements[1].ID)
+	assert.Equal(t, 8, m.statements[1].Line)
+
+	// iBatis sqlMap is not parsed.
+	m, err = parseMapper("user.xml", `<sqlMap namespace="User"><select id="getUser">SELECT 1</select></sqlMap>`)
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestExpandDynamicSQL(t *testing.T) {
+	sqls := expandMappers(t, `
+<mapper namespace="User">
+    <select id="listUsers">
+        SELECT * FROM users
+        <where>
+            <if test="name != null">AND name = #{name}</if>
+            <if test="age != null">AND age > #{age}</if>
+        </where>
+        ORDER BY ${orderBy} LIMIT ${limit}
+    </select>
+    <select id="findUser">
+        SELECT * FROM users WHERE
+        <choose>
+            <when test="id != null">id = #{id}</when>
+            <when test="email != null">email = #{email}</when>
+        </choose>
+    </select>
+    <update id="updateUser">
+        UPDATE users
+        <trim prefix="SET" suffixOverrides=",">
+            <if test="name != null">name = #{name},</if>
+            age = #{age},
+        </trim>
+        WHERE id IN
+        <foreach collection="ids" item="id" open="(" separator="," close=")">#{id}</foreach>
+    </update>
+</mapper>`)
+
+	assert.Equal(t, []string{
+		"SELECT * FROM users WHERE name = ? AND age > ? ORDER BY orderBy LIMIT ?",
+		"SELECT * FROM users ORDER BY orderBy LIMIT ?",
+	}, sqls["listUsers"])
+	assert.Equal(t, []string{
+		"SELECT * FROM users WHERE id = ?",
+		"SELECT * FROM users WHERE",
+		"SELECT * FROM users WHERE email = ?",
+	}, sqls["findUser"])
+	assert.Equal(t, []string{
+		"UPDATE users SET name = ?, age = ? WHERE id IN ( ? )",
+		"UPDATE users SET age = ? WHERE id IN ( ? )",
+	}, sqls["updateUser"])
+}
+
+func TestExpandIncludeAcrossMappers(t *testing.T) {
+	sqls := expandMappers(t, `
+<mapper namespace="Common">
+    <sql id="table">${prefix}_users</sql>
+    <sql id="columns">id, <include refid="extraColumns"/></sql>
+    <sql id="extraColumns">name</sql>
+</mapper>`, `
+<mapper namespace="User">
+    <sql id="extraColumns">email</sql>
+    <select id="getUser">
+        SELECT <include refid="Common.columns"/> FROM
+        <include refid="Common.table"><property name="prefix" value="t"/></include>
+    </select>
+</mapper>`)
+	// the <include> in fragment refers to the namespace of the fragment.
+	assert.Equal(t, []string{"SELECT id, name FROM t_users"}, sqls["getUser"])
+
+	for _, content := range []string{
+		`<mapper namespace="User"><select id="s"><include refid="notExist"/></select></mapper>`,
+		`<mapper namespace="User"><select id="s"><include refid="c"/></select><sql id="c"><include refid="c"/></sql></mapper>`,
+	} {
+		m, err := parseMapper("a.xml", content)
+		assert.NoError(t, err)
+		_, err = newExpander([]*mapperFile{m}).Expand(m.statements[0])
+		assert.Error(t, err)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t1 WHERE id = ? ORDER BY c1", substitute("SELECT * FROM t1 WHERE id = #{id} ORDER BY ${c1}", nil))
+	assert.Equal(t, "SELECT * FROM t_2022 WHERE id IN (?) LIMIT ?, ?", substitute("SELECT * FROM t_${year} WHERE id IN (${ids}) LIMIT ${offset}, ${size}", map[string]string{"year": "2022"}))
+	assert.Equal(t, "SELECT 'a  b' FROM t1", compactSpace("  SELECT   'a  b'\n\tFROM t1 "))
+}
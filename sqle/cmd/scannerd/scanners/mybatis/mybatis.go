@@ -7,11 +7,14 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	mybatisParser "github.com/actiontech/mybatis-mapper-2-sql"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/gitdiff"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/incremental"
 	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/pkg/scanner"
 	"github.com/sirupsen/logrus"
@@ -24,25 +27,32 @@ type MyBatis struct {
 	needTrigger bool
 	sqls        []scanners.SQL
 
-	allSQL []driver.Node
+	allSQL []SQL
 	getAll chan struct{}
 
 	apName         string
 	xmlDir         string
 	skipErrorQuery bool
+	incremental    *incremental.Params
 }
 
 type Params struct {
 	XMLDir         string
 	APName         string
 	SkipErrorQuery bool
+	// Incremental is optional, only the SQLs touched by git diff are audited if it's enabled.
+	Incremental *incremental.Params
 }
 
 func New(params *Params, l *logrus.Entry, c *scanner.Client) (*MyBatis, error) {
+	if err := params.Incremental.Validate(); err != nil {
+		return nil, err
+	}
 	return &MyBatis{
 		xmlDir:         params.XMLDir,
 		apName:         params.APName,
 		skipErrorQuery: params.SkipErrorQuery,
+		incremental:    params.Incremental,
 		l:              l,
 		c:              c,
 		getAll:         make(chan struct{}),
@@ -50,7 +60,13 @@ func New(params *Params, l *logrus.Entry, c *scanner.Client) (*MyBatis, error) {
 }
 
 func (mb *MyBatis) Run(ctx context.Context) error {
-	sqls, err := GetSQLFromPath(mb.xmlDir, mb.skipErrorQuery)
+	var sqls []SQL
+	var err error
+	if mb.incremental.Enabled() {
+		sqls, err = mb.getTouchedSQLs(ctx)
+	} else {
+		sqls, err = GetSQLFromPath(mb.xmlDir, mb.skipErrorQuery)
+	}
 	if err != nil {
 		return err
 	}
@@ -62,6 +78,29 @@ func (mb *MyBatis) Run(ctx context.Context) error {
 	return nil
 }
 
+// getTouchedSQLs returns the SQLs generated by the statements which are added
+// or changed in git diff, including the ones whose <include> is changed.
+func (mb *MyBatis) getTouchedSQLs(ctx context.Context) ([]SQL, error) {
+	diff, err := gitdiff.New(ctx, mb.xmlDir, mb.incremental.GitBase, mb.incremental.GitHead)
+	if err != nil {
+		return nil, err
+	}
+	defer diff.Close()
+
+	sqls, err := GetSQLFromPath(diff.Dir(), mb.skipErrorQuery)
+	if err != nil {
+		return nil, err
+	}
+	touched := make([]SQL, 0, len(sqls))
+	for _, sql := range sqls {
+		if sql.Touched(diff) {
+			touched = append(touched, sql)
+		}
+	}
+	mb.l.Infof("%d of %d SQLs are touched by %d changed files", len(touched), len(sqls), diff.Files())
+	return touched, nil
+}
+
 func (mb *MyBatis) SQLs() <-chan scanners.SQL {
 	// todo: channel size configurable
 	sqlCh := make(chan scanners.SQL, 10240)
@@ -70,8 +109,12 @@ func (mb *MyBatis) SQLs() <-chan scanners.SQL {
 		<-mb.getAll
 		for _, sql := range mb.allSQL {
 			sqlCh <- scanners.SQL{
-				Fingerprint: sql.Fingerprint,
-				RawText:     sql.Text,
+				Fingerprint:       sql.Fingerprint,
+				RawText:           sql.Text,
+				MapperNamespace:   sql.Namespace,
+				MapperStatementID: sql.StatementID,
+				SourceFile:        sql.File,
+				SourceLine:        sql.Line,
 			}
 		}
 		mb.needTrigger = true
@@ -87,6 +130,11 @@ func (mb *MyBatis) Upload(ctx context.Context, sqls []scanners.SQL) error {
 		return nil
 	}
 
+	if mb.incremental.Enabled() {
+		_, err := incremental.Submit(mb.c, mb.apName, mb.incremental, mb.sqls, os.Stdout)
+		return err
+	}
+
 	// key=fingerPrint val=count
 	counterMap := make(map[string]uint, len(mb.sqls))
 
@@ -101,12 +149,19 @@ func (mb *MyBatis) Upload(ctx context.Context, sqls []scanners.SQL) error {
 	reqBody := make([]scanner.AuditPlanSQLReq, 0, len(nodeList))
 	now := time.Now().Format(time.RFC3339)
 	for _, sql := range nodeList {
-		reqBody = append(reqBody, scanner.AuditPlanSQLReq{
+		req := scanner.AuditPlanSQLReq{
 			Fingerprint:          sql.Fingerprint,
 			Counter:              fmt.Sprintf("%v", counterMap[sql.Fingerprint]),
 			LastReceiveText:      sql.RawText,
 			LastReceiveTimestamp: now,
-		})
+			MapperNamespace:      sql.MapperNamespace,
+			MapperStatementID:    sql.MapperStatementID,
+			SourceFile:           sql.SourceFile,
+		}
+		if sql.SourceLine > 0 {
+			req.SourceLine = strconv.Itoa(sql.SourceLine)
+		}
+		reqBody = append(reqBody, req)
 	}
 
 	err := mb.c.UploadReq(scanner.FullUpload, mb.apName, reqBody)
@@ -121,7 +176,41 @@ func (mb *MyBatis) Upload(ctx context.Context, sqls []scanners.SQL) error {
 	return mb.c.GetAuditReportReq(mb.apName, reportID)
 }
 
-func GetSQLFromPath(pathName string, skipErrorQuery bool) (allSQL []driver.Node, err error) {
+// SQL is a SQL generated from MyBatis mapper statement.
+type SQL struct {
+	driver.Node
+
+	// Namespace and StatementID identify the statement in mapper, they are
+	// empty if the SQL is parsed from iBatis sqlMap.
+	Namespace   string
+	StatementID string
+	File        string
+	Line        int
+	EndLine     int
+
+	includes []*fragment
+}
+
+// Touched checks whether the statement or the <sql> fragments it refers to are
+// changed in diff. The SQL from iBatis sqlMap is touched if its file is changed.
+func (s SQL) Touched(diff *gitdiff.Diff) bool {
+	if s.Line == 0 {
+		return diff.Touched(s.File, 0, 0)
+	}
+	if diff.Touched(s.File, s.Line, s.EndLine) {
+		return true
+	}
+	for _, f := range s.includes {
+		if diff.Touched(f.file, f.node.line, f.node.endLine) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSQLFromPath gets SQLs from all XML files under the path. The <include>
+// can refer to the <sql> defined in other files under the path.
+func GetSQLFromPath(pathName string, skipErrorQuery bool) ([]SQL, error) {
 	if !path.IsAbs(pathName) {
 		pwd, err := os.Getwd()
 		if err != nil {
@@ -130,41 +219,135 @@ func GetSQLFromPath(pathName string, skipErrorQuery bool) (allSQL []driver.Node,
 		pathName = path.Join(pwd, pathName)
 	}
 
+	files, err := getXMLFiles(pathName)
+	if err != nil {
+		return nil, err
+	}
+	return getSQLFromFiles(pathName, files, skipErrorQuery)
+}
+
+func getXMLFiles(pathName string) (files []string, err error) {
 	fileInfos, err := ioutil.ReadDir(pathName)
 	if err != nil {
 		return nil, err
 	}
 	for _, fi := range fileInfos {
-		var sqlList []driver.Node
 		if fi.IsDir() {
-			sqlList, err = GetSQLFromPath(path.Join(pathName, fi.Name()), skipErrorQuery)
+			subFiles, err := getXMLFiles(path.Join(pathName, fi.Name()))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, subFiles...)
 		} else if strings.HasSuffix(fi.Name(), "xml") {
-			sqlList, err = GetSQLFromFile(path.Join(pathName, fi.Name()), skipErrorQuery)
+			files = append(files, path.Join(pathName, fi.Name()))
 		}
+	}
+	return files, nil
+}
+
+func GetSQLFromFile(file string, skipErrorQuery bool) ([]SQL, error) {
+	return getSQLFromFiles("", []string{file}, skipErrorQuery)
+}
 
+// getSQLFromFiles parses all files before expanding the statements, so that
+// the <sql> fragments defined in all files are known. The file path in SQL is
+// relative to the baseDir if it is not empty.
+func getSQLFromFiles(baseDir string, files []string, skipErrorQuery bool) ([]SQL, error) {
+	var r []SQL
+	var mappers []*mapperFile
+	for _, file := range files {
+		content, err := ReadFileContent(file)
 		if err != nil {
 			return nil, err
 		}
-		allSQL = append(allSQL, sqlList...)
+		name := file
+		if baseDir != "" {
+			if rel, err := filepath.Rel(baseDir, file); err == nil {
+				name = rel
+			}
+		}
+
+		m, err := parseMapper(name, content)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s failed: %v", name, err)
+		}
+		if m != nil {
+			mappers = append(mappers, m)
+			continue
+		}
+
+		// the iBatis sqlMap is not expanded, each statement is flattened into one SQL.
+		sqls, err := mybatisParser.ParseXMLQuery(content, skipErrorQuery)
+		if err != nil {
+			return nil, err
+		}
+		for _, sql := range sqls {
+			nodes, err := Parse(context.TODO(), sql)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				r = append(r, SQL{Node: n, File: name})
+			}
+		}
+	}
+
+	e := newExpander(mappers)
+	for _, m := range mappers {
+		for _, stmt := range m.statements {
+			sqls, err := expandStatement(e, stmt)
+			if err != nil {
+				if skipErrorQuery {
+					continue
+				}
+				return nil, err
+			}
+			r = append(r, sqls...)
+		}
 	}
-	return allSQL, err
+	return r, nil
 }
 
-func GetSQLFromFile(file string, skipErrorQuery bool) (r []driver.Node, err error) {
-	content, err := ReadFileContent(file)
+// expandStatement returns the SQLs generated by statement. The variants which
+// can not be parsed are ignored, e.g. the "IN ( )" generated by <foreach> whose
+// body is empty. The first variant is kept if none of the variants is valid,
+// so that the syntax error can be found by audit.
+func expandStatement(e *expander, stmt *Statement) ([]SQL, error) {
+	variants, err := e.Expand(stmt)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("expand statement %s.%s in %s:%d failed: %v", stmt.Namespace, stmt.ID, stmt.File, stmt.Line, err)
 	}
-	sqls, err := mybatisParser.ParseXMLQuery(content, skipErrorQuery)
-	if err != nil {
-		return nil, err
+	if len(variants) == 0 {
+		return nil, nil
 	}
-	for _, sql := range sqls {
-		n, err := Parse(context.TODO(), sql)
+
+	validVariants := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		if IsParsable(variant) {
+			validVariants = append(validVariants, variant)
+		}
+	}
+	if len(validVariants) == 0 {
+		validVariants = variants[:1]
+	}
+
+	var r []SQL
+	for _, variant := range validVariants {
+		nodes, err := Parse(context.TODO(), variant)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("parse statement %s.%s in %s:%d failed: %v", stmt.Namespace, stmt.ID, stmt.File, stmt.Line, err)
+		}
+		for _, n := range nodes {
+			r = append(r, SQL{
+				Node:        n,
+				Namespace:   stmt.Namespace,
+				StatementID: stmt.ID,
+				File:        stmt.File,
+				Line:        stmt.Line,
+				EndLine:     stmt.EndLine,
+				includes:    stmt.includes,
+			})
 		}
-		r = append(r, n...)
 	}
 	return r, nil
 }
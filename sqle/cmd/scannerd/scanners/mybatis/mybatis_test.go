@@ -2,10 +2,14 @@ package mybatis
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/incremental"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,7 +38,11 @@ func TestMyBatis(t *testing.T) {
 	for v := range sqlCh {
 		sqlBuf = append(sqlBuf, v)
 	}
-	assert.Len(t, sqlBuf, 10)
+	assert.Len(t, sqlBuf, 14)
+	assert.Equal(t, "Test", sqlBuf[0].MapperNamespace)
+	assert.Equal(t, "testParameters", sqlBuf[0].MapperStatementID)
+	assert.Equal(t, "test.xml", sqlBuf[0].SourceFile)
+	assert.Equal(t, 16, sqlBuf[0].SourceLine)
 
 	// test MyBatis scanner will hang until caller called ctx.Cancel().
 	scanner, err = New(params, logrus.New().WithField("test", "test"), nil)
@@ -58,3 +66,67 @@ func TestMyBatis(t *testing.T) {
 	_, ok = <-exitCh
 	assert.False(t, ok)
 }
+
+func TestMyBatisIncremental(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if !assert.NoError(t, err, string(out)) {
+			t.FailNow()
+		}
+	}
+	mapper := func(columns, where string) {
+		content := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="UserMapper">
+    <sql id="columns">` + columns + `</sql>
+    <select id="getUser">
+        SELECT <include refid="columns"/> FROM users WHERE id = #{id}
+    </select>
+    <select id="getUserByName">
+        SELECT id FROM users WHERE ` + where + `
+    </select>
+    <select id="countUser">
+        SELECT COUNT(*) FROM users
+    </select>
+</mapper>
+`
+		if err := os.WriteFile(filepath.Join(repo, "UserMapper.xml"), []byte(content), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	mapper("id, name", "name = #{name}")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+
+	mapper("id, name, email", "name = #{name}")
+	scanner, err := New(&Params{
+		XMLDir:      repo,
+		Incremental: &incremental.Params{GitBase: "HEAD", InstanceType: "MySQL"},
+	}, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+	sqls, err := scanner.getTouchedSQLs(context.TODO())
+	assert.NoError(t, err)
+	if assert.Len(t, sqls, 1) {
+		assert.Equal(t, "getUser", sqls[0].StatementID)
+	}
+
+	mapper("id, name", "email = #{email}")
+	sqls, err = scanner.getTouchedSQLs(context.TODO())
+	assert.NoError(t, err)
+	if assert.Len(t, sqls, 1) {
+		assert.Equal(t, "getUserByName", sqls[0].StatementID)
+	}
+
+	_, err = New(&Params{
+		XMLDir:      repo,
+		Incremental: &incremental.Params{GitBase: "HEAD", SubmitMode: "unknown"},
+	}, logrus.New().WithField("test", "test"), nil)
+	assert.Error(t, err)
+}
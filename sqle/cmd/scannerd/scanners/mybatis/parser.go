@@ -51,6 +51,20 @@ func ParseSql(sql string) ([]ast.Node, error) {
 	return nodes, nil
 }
 
+// IsParsable checks whether all statements in sql are supported by parser.
+func IsParsable(sql string) bool {
+	stmts, err := parseSql(sql)
+	if err != nil {
+		return false
+	}
+	for _, stmt := range stmts {
+		if _, ok := stmt.(*ast.UnparsedStmt); ok {
+			return false
+		}
+	}
+	return true
+}
+
 func parseSql(sql string) ([]ast.StmtNode, error) {
 	p := parser.New()
 	stmts, _, err := p.PerfectParse(sql, "", "")
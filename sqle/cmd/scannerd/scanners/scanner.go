@@ -2,6 +2,8 @@ package scanners
 
 import (
 	"context"
+	"fmt"
+	"time"
 )
 
 type SQL struct {
@@ -9,6 +11,57 @@ type SQL struct {
 	RawText     string
 	Counter     int
 	Schema      string
+
+	// QueryTimeSum and QueryTimeMax are the total and the max execution time
+	// (in seconds) of the SQLs which have the same fingerprint.
+	QueryTimeSum float64
+	QueryTimeMax float64
+
+	// LastReceiveTimestamp is the time when the SQL is executed or collected.
+	LastReceiveTimestamp time.Time
+
+	// MapperNamespace and MapperStatementID identify the statement in MyBatis
+	// mapper which the SQL is generated from.
+	MapperNamespace   string
+	MapperStatementID string
+	// SourceFile and SourceLine are the location of the SQL in source code.
+	SourceFile string
+	SourceLine int
+
+	// Checkpoint is the position of the source after this SQL, it is opaque to
+	// supervisor and is empty if the scanner does not implement Checkpointer.
+	Checkpoint string
+}
+
+// Merge aggregates the counter and latency of another SQL with the same fingerprint.
+// The raw text, schema and timestamp are replaced by the latest one.
+func (s *SQL) Merge(other SQL) {
+	s.Counter += other.Counter
+	s.QueryTimeSum += other.QueryTimeSum
+	if other.QueryTimeMax > s.QueryTimeMax {
+		s.QueryTimeMax = other.QueryTimeMax
+	}
+	if !other.LastReceiveTimestamp.Before(s.LastReceiveTimestamp) {
+		s.RawText = other.RawText
+		s.Schema = other.Schema
+		s.LastReceiveTimestamp = other.LastReceiveTimestamp
+	}
+}
+
+// Location returns the location of SQL in source code, e.g.
+// "mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)".
+func (s *SQL) Location() string {
+	if s.SourceFile == "" {
+		return ""
+	}
+	ret := s.SourceFile
+	if s.SourceLine > 0 {
+		ret = fmt.Sprintf("%s:%d", ret, s.SourceLine)
+	}
+	if s.MapperStatementID != "" {
+		ret = fmt.Sprintf("%s (%s.%s)", ret, s.MapperNamespace, s.MapperStatementID)
+	}
+	return ret
 }
 
 // Scanner is a interface for all Scanners.
@@ -23,3 +76,11 @@ type Scanner interface {
 	// Upload upload sqls to underlying client.
 	Upload(ctx context.Context, sqls []SQL) error
 }
+
+// Checkpointer is an optional interface for the scanners which can resume
+// from where they stopped after restart.
+type Checkpointer interface {
+	// SaveCheckpoint persists the checkpoint carried by SQL. It is called after
+	// the SQLs before the checkpoint are uploaded or buffered in spool.
+	SaveCheckpoint(checkpoint string) error
+}
@@ -0,0 +1,212 @@
+//go:build !enterprise
+// +build !enterprise
+
+package slowquery
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a query recorded in the slow log.
+type Entry struct {
+	Time         time.Time
+	User         string
+	Host         string
+	Schema       string
+	QueryTime    float64
+	LockTime     float64
+	RowsSent     int64
+	RowsExamined int64
+	SQL          string
+}
+
+var (
+	// e.g. "# Query_time: 1.000  Lock_time: 0.000 Rows_sent: 1  Rows_examined: 0"
+	// e.g. "# Thread_id: 12  Schema: test  QC_hit: No"
+	headerAttrRegexp = regexp.MustCompile(`([A-Za-z_]+): (\S*)`)
+	// e.g. "# User@Host: root[root] @ localhost [127.0.0.1]  Id:    10"
+	userHostRegexp = regexp.MustCompile(`^# User@Host: ([^\[]*)\[([^\]]*)\] @ (\S*) \[([^\]]*)\]`)
+	// e.g. "SET timestamp=1660892342;"
+	setTimestampRegexp = regexp.MustCompile(`(?i)^SET timestamp=(\d+);$`)
+	// e.g. "use test;"
+	useSchemaRegexp = regexp.MustCompile("(?i)^use `?([^`;]+)`?;$")
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,      // MySQL 5.7+, e.g. "2022-08-19T06:59:02.123456Z"
+	"060102 15:04:05",     // MySQL 5.6 and Percona Server 5.6, e.g. "220819  6:59:02"
+	"2006-01-02T15:04:05", // MySQL 5.7 with log_timestamps=SYSTEM and no time zone
+}
+
+// parser parses MySQL and Percona Server slow log line by line.
+//
+// The parser is not goroutine safe.
+type parser struct {
+	onEntry func(*Entry)
+
+	// schema is the current schema of the slow log file. MySQL only writes
+	// "use db;" when the schema is different from the last written one.
+	schema string
+
+	entry    *Entry
+	sqlLines []string
+}
+
+func newParser(onEntry func(*Entry)) *parser {
+	return &parser{onEntry: onEntry}
+}
+
+// Feed parses one line of the slow log, the line should not contain the line break.
+func (p *parser) Feed(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case trimmed == "":
+		return
+	case isServerHeader(line):
+		p.Flush()
+		return
+	case strings.HasPrefix(line, "# Time:") || strings.HasPrefix(line, "# User@Host:"):
+		if len(p.sqlLines) > 0 {
+			p.Flush()
+		}
+		p.parseHeader(line)
+		return
+	case strings.HasPrefix(line, "#") && len(p.sqlLines) == 0:
+		p.parseHeader(line)
+		return
+	case strings.HasPrefix(line, "# ") && p.Pending():
+		// the statement is completed, a header line starts a new entry. Otherwise,
+		// the line is a comment of a multi-line statement.
+		p.Flush()
+		p.parseHeader(line)
+		return
+	}
+
+	if len(p.sqlLines) == 0 {
+		if m := setTimestampRegexp.FindStringSubmatch(trimmed); m != nil {
+			ts, err := strconv.ParseInt(m[1], 10, 64)
+			if err == nil {
+				p.currentEntry().Time = time.Unix(ts, 0)
+			}
+			return
+		}
+		if m := useSchemaRegexp.FindStringSubmatch(trimmed); m != nil {
+			p.schema = m[1]
+			p.currentEntry().Schema = m[1]
+			return
+		}
+	}
+	p.currentEntry()
+	p.sqlLines = append(p.sqlLines, line)
+}
+
+// Pending returns true if the parser holds a complete statement which is not flushed.
+func (p *parser) Pending() bool {
+	if len(p.sqlLines) == 0 {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSpace(p.sqlLines[len(p.sqlLines)-1]), ";")
+}
+
+// Flush emits the entry being parsed. Entries without SQL, such as
+// "# administrator command: Quit;", are dropped.
+func (p *parser) Flush() {
+	entry := p.entry
+	sqlLines := p.sqlLines
+	p.entry = nil
+	p.sqlLines = nil
+	if entry == nil || len(sqlLines) == 0 {
+		return
+	}
+
+	sql := strings.TrimSpace(strings.Join(sqlLines, "\n"))
+	sql = strings.TrimSpace(strings.TrimSuffix(sql, ";"))
+	if sql == "" {
+		return
+	}
+	entry.SQL = sql
+	if entry.Schema == "" {
+		entry.Schema = p.schema
+	}
+	p.onEntry(entry)
+}
+
+func (p *parser) currentEntry() *Entry {
+	if p.entry == nil {
+		p.entry = &Entry{}
+	}
+	return p.entry
+}
+
+func (p *parser) parseHeader(line string) {
+	entry := p.currentEntry()
+
+	if strings.HasPrefix(line, "# Time:") {
+		if t, ok := parseTime(strings.TrimSpace(strings.TrimPrefix(line, "# Time:"))); ok {
+			entry.Time = t
+		}
+		return
+	}
+
+	if m := userHostRegexp.FindStringSubmatch(line); m != nil {
+		entry.User = strings.TrimSpace(m[2])
+		entry.Host = m[3]
+		if m[4] != "" {
+			entry.Host = m[4]
+		}
+		return
+	}
+
+	for _, attr := range headerAttrRegexp.FindAllStringSubmatch(line, -1) {
+		key, value := attr[1], attr[2]
+		switch key {
+		case "Query_time":
+			entry.QueryTime, _ = strconv.ParseFloat(value, 64)
+		case "Lock_time":
+			entry.LockTime, _ = strconv.ParseFloat(value, 64)
+		case "Rows_sent":
+			entry.RowsSent, _ = strconv.ParseInt(value, 10, 64)
+		case "Rows_examined":
+			entry.RowsExamined, _ = strconv.ParseInt(value, 10, 64)
+		case "Schema":
+			// Percona Server writes current schema in header.
+			if value != "" {
+				entry.Schema = value
+				p.schema = value
+			}
+		}
+	}
+}
+
+// isServerHeader checks the lines written when the server start or the log is flushed, e.g.
+//
+//	/usr/sbin/mysqld, Version: 5.7.36-log (MySQL Community Server (GPL)). started with:
+//	Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+//	Time                 Id Command    Argument
+func isServerHeader(line string) bool {
+	switch {
+	case strings.Contains(line, ", Version: ") && strings.HasSuffix(line, "started with:"):
+		return true
+	case strings.HasPrefix(line, "Tcp port: "):
+		return true
+	case strings.HasPrefix(line, "Time ") && strings.Contains(line, "Id Command"):
+		return true
+	}
+	return false
+}
+
+func parseTime(value string) (time.Time, bool) {
+	value = strings.Join(strings.Fields(value), " ")
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -5,34 +5,208 @@ package slowquery
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
-	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/percona/go-mysql/query"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-var errSlowQueryNotImplemented = errors.NewNotImplemented("SlowQuery Scanner")
+const defaultPollInterval = time.Second
+
+// SlowQuery is a scanner which follows MySQL (or Percona Server) slow log
+// and uploads the slow queries to a SQLE audit plan.
+type SlowQuery struct {
+	l *logrus.Entry
+	c *scanner.Client
 
-type SlowQuery struct{}
+	sqlCh chan scanners.SQL
+
+	apName         string
+	logFilePath    string
+	checkpointFile string
+	pollInterval   time.Duration
+}
 
 type Params struct {
 	LogFilePath string
 	APName      string
+
+	// CheckpointFile persists the position of the slow log which has been
+	// uploaded, so the scanner resumes from it after restart. It's optional.
+	CheckpointFile string
+}
+
+// checkpoint is the position of slow log after a slow query.
+type checkpoint struct {
+	position
+	// Schema is the current schema of slow log at the position.
+	Schema string `json:"schema"`
 }
 
 func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SlowQuery, error) {
-	return &SlowQuery{}, errSlowQueryNotImplemented
+	if _, err := os.Stat(params.LogFilePath); err != nil {
+		return nil, errors.Wrapf(err, "slow log file %s is not accessible", params.LogFilePath)
+	}
+	return &SlowQuery{
+		l:              l,
+		c:              c,
+		sqlCh:          make(chan scanners.SQL, 10240),
+		apName:         params.APName,
+		logFilePath:    params.LogFilePath,
+		checkpointFile: params.CheckpointFile,
+		pollInterval:   defaultPollInterval,
+	}, nil
+}
+
+func (sq *SlowQuery) Run(ctx context.Context) error {
+	defer close(sq.sqlCh)
+
+	start, err := sq.loadCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	t := newTailer(sq.l, sq.logFilePath, sq.pollInterval)
+	var p *parser
+	// lastPos is the position after the last SQL line of the entry being parsed.
+	var lastPos position
+	p = newParser(func(entry *Entry) {
+		sql, ok := sq.convertEntry(entry)
+		if !ok {
+			return
+		}
+		if sq.checkpointFile != "" {
+			data, _ := json.Marshal(checkpoint{position: lastPos, Schema: p.schema})
+			sql.Checkpoint = string(data)
+		}
+		select {
+		case sq.sqlCh <- sql:
+		case <-ctx.Done():
+		}
+	})
+	p.schema = start.Schema
+
+	onLine := func(line string) {
+		p.Feed(line)
+		if len(p.sqlLines) > 0 {
+			lastPos = t.Position()
+		}
+	}
+	return t.Run(ctx, start.position, onLine, func() {
+		// MySQL writes a slow log entry at once, so the complete statement
+		// at the end of file can be emitted without waiting for the next entry.
+		if p.Pending() {
+			p.Flush()
+		}
+	})
 }
 
-func (s *SlowQuery) Run(ctx context.Context) error {
-	return errSlowQueryNotImplemented
+func (sq *SlowQuery) loadCheckpoint() (checkpoint, error) {
+	cp := checkpoint{}
+	if sq.checkpointFile == "" {
+		return cp, nil
+	}
+	data, err := ioutil.ReadFile(sq.checkpointFile)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, errors.Wrapf(err, "failed to read checkpoint %s", sq.checkpointFile)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		sq.l.Warnf("checkpoint %s is corrupted, read slow log from the beginning. error: %v", sq.checkpointFile, err)
+		return checkpoint{}, nil
+	}
+	sq.l.Infof("resume from checkpoint, offset: %v", cp.Offset)
+	return cp, nil
+}
+
+// SaveCheckpoint implements scanners.Checkpointer.
+func (sq *SlowQuery) SaveCheckpoint(cp string) error {
+	if sq.checkpointFile == "" {
+		return nil
+	}
+	tmp := sq.checkpointFile + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(sq.checkpointFile), 0750); err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+	if err := ioutil.WriteFile(tmp, []byte(cp), 0640); err != nil {
+		return errors.Wrap(err, "failed to save checkpoint")
+	}
+	return errors.Wrap(os.Rename(tmp, sq.checkpointFile), "failed to save checkpoint")
 }
 
 func (sq *SlowQuery) SQLs() <-chan scanners.SQL {
-	return nil
+	return sq.sqlCh
 }
 
 func (sq *SlowQuery) Upload(ctx context.Context, sqls []scanners.SQL) error {
-	return errSlowQueryNotImplemented
+	aggregated := aggregateSQLs(sqls)
+
+	reqBody := make([]scanner.AuditPlanSQLReq, 0, len(aggregated))
+	for _, sql := range aggregated {
+		reqBody = append(reqBody, scanner.AuditPlanSQLReq{
+			Fingerprint:          sql.Fingerprint,
+			Counter:              fmt.Sprintf("%v", sql.Counter),
+			LastReceiveText:      sql.RawText,
+			LastReceiveTimestamp: sql.LastReceiveTimestamp.Format(time.RFC3339),
+			Schema:               sql.Schema,
+			QueryTimeAvg:         fmt.Sprintf("%v", sql.QueryTimeSum/float64(sql.Counter)),
+			QueryTimeMax:         fmt.Sprintf("%v", sql.QueryTimeMax),
+		})
+	}
+	return sq.c.UploadReq(scanner.PartialUpload, sq.apName, reqBody)
+}
+
+func (sq *SlowQuery) convertEntry(entry *Entry) (scanners.SQL, bool) {
+	fingerprint, err := util.Fingerprint(entry.SQL, true)
+	if err != nil {
+		// the statement may be not supported by the parser, or truncated by
+		// "log_slow_max_query_length" of Percona Server.
+		sq.l.Debugf("failed to generate fingerprint by parser, use pt-fingerprint instead. sql: %s, error: %v", entry.SQL, err)
+		fingerprint = query.Fingerprint(entry.SQL)
+	}
+	if fingerprint == "" {
+		return scanners.SQL{}, false
+	}
+
+	ts := entry.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return scanners.SQL{
+		Fingerprint:          fingerprint,
+		RawText:              entry.SQL,
+		Counter:              1,
+		Schema:               entry.Schema,
+		QueryTimeSum:         entry.QueryTime,
+		QueryTimeMax:         entry.QueryTime,
+		LastReceiveTimestamp: ts,
+	}, true
+}
+
+// aggregateSQLs merges the SQLs with the same fingerprint, the order of the first occurrence is kept.
+func aggregateSQLs(sqls []scanners.SQL) []*scanners.SQL {
+	index := make(map[string]*scanners.SQL, len(sqls))
+	ret := make([]*scanners.SQL, 0, len(sqls))
+	for _, sql := range sqls {
+		if agg, ok := index[sql.Fingerprint]; ok {
+			agg.Merge(sql)
+			continue
+		}
+		agg := sql
+		index[sql.Fingerprint] = &agg
+		ret = append(ret, &agg)
+	}
+	return ret
 }
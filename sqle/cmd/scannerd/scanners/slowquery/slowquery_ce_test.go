@@ -0,0 +1,193 @@
+//go:build !enterprise
+// +build !enterprise
+
+package slowquery
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseFile(t *testing.T, path string) []*Entry {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var entries []*Entry
+	p := newParser(func(e *Entry) {
+		entries = append(entries, e)
+	})
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		p.Feed(s.Text())
+	}
+	p.Flush()
+	return entries
+}
+
+func TestParseMySQLSlowLog(t *testing.T) {
+	entries := parseFile(t, "./testdata/mysql57_slow.log")
+	assert.Len(t, entries, 2)
+
+	assert.Equal(t, "select sleep(2)", entries[0].SQL)
+	assert.Equal(t, "sqle", entries[0].Schema)
+	assert.Equal(t, "root", entries[0].User)
+	assert.Equal(t, "localhost", entries[0].Host)
+	assert.Equal(t, 2.000513, entries[0].QueryTime)
+	assert.Equal(t, int64(1), entries[0].RowsSent)
+	assert.Equal(t, int64(1660892342), entries[0].Time.Unix())
+
+	assert.Equal(t, "select *\nfrom t1\nwhere id = 1", entries[1].SQL)
+	// the schema is inherited from the last "use" statement.
+	assert.Equal(t, "sqle", entries[1].Schema)
+	assert.Equal(t, "app", entries[1].User)
+	assert.Equal(t, "10.0.0.12", entries[1].Host)
+	assert.Equal(t, 1.5, entries[1].QueryTime)
+	assert.Equal(t, int64(10000), entries[1].RowsExamined)
+}
+
+func TestParsePerconaSlowLog(t *testing.T) {
+	entries := parseFile(t, "./testdata/percona_slow.log")
+	assert.Len(t, entries, 2)
+
+	assert.Equal(t, "update t1 set c1 = 1 where id = 2", entries[0].SQL)
+	assert.Equal(t, "db1", entries[0].Schema)
+	assert.Equal(t, 3.1, entries[0].QueryTime)
+
+	assert.Equal(t, "select 1", entries[1].SQL)
+	assert.Equal(t, "db1", entries[1].Schema)
+	assert.Equal(t, int64(1660892343), entries[1].Time.Unix())
+}
+
+func TestParseTime(t *testing.T) {
+	ts, ok := parseTime("2022-08-19T06:59:02.123456Z")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1660892342), ts.Unix())
+
+	ts, ok = parseTime("220819  6:59:02")
+	assert.True(t, ok)
+	assert.Equal(t, 6, ts.Hour())
+
+	_, ok = parseTime("invalid")
+	assert.False(t, ok)
+}
+
+func TestAggregateSQLs(t *testing.T) {
+	now := time.Now()
+	sqls := aggregateSQLs([]scanners.SQL{
+		{Fingerprint: "select * from t1 where id=?", RawText: "select * from t1 where id=1", Counter: 1, QueryTimeSum: 1, QueryTimeMax: 1, LastReceiveTimestamp: now},
+		{Fingerprint: "select 1", RawText: "select 1", Counter: 1, QueryTimeSum: 2, QueryTimeMax: 2, LastReceiveTimestamp: now},
+		{Fingerprint: "select * from t1 where id=?", RawText: "select * from t1 where id=2", Counter: 1, QueryTimeSum: 3, QueryTimeMax: 3, LastReceiveTimestamp: now.Add(time.Second)},
+	})
+	assert.Len(t, sqls, 2)
+	assert.Equal(t, 2, sqls[0].Counter)
+	assert.Equal(t, float64(4), sqls[0].QueryTimeSum)
+	assert.Equal(t, float64(3), sqls[0].QueryTimeMax)
+	assert.Equal(t, "select * from t1 where id=2", sqls[0].RawText)
+	assert.Equal(t, 1, sqls[1].Counter)
+}
+
+func TestSlowQueryFollowRotation(t *testing.T) {
+	dir, err := os.MkdirTemp("", "slowquery")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "slow.log")
+
+	appendLog := func(path, content string) {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		assert.NoError(t, err)
+		_, err = f.WriteString(content)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+	appendLog(logPath, "# Query_time: 1.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nSET timestamp=1660892342;\nselect 1;\n")
+
+	sq, err := New(&Params{LogFilePath: logPath}, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+	sq.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- sq.Run(ctx)
+	}()
+
+	receive := func() scanners.SQL {
+		select {
+		case sql := <-sq.SQLs():
+			return sql
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to receive sql")
+		}
+		return scanners.SQL{}
+	}
+	assert.Equal(t, "select 1", receive().RawText)
+
+	// rotate the slow log by rename, then the server write to a new file.
+	assert.NoError(t, os.Rename(logPath, logPath+".1"))
+	appendLog(logPath+".1", "# Query_time: 2.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nselect 2;\n")
+	appendLog(logPath, "# Query_time: 3.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nselect 3;\n")
+
+	sql := receive()
+	assert.Equal(t, "select 2", sql.RawText)
+	assert.Equal(t, float64(2), sql.QueryTimeMax)
+	assert.Equal(t, "select 3", receive().RawText)
+
+	cancel()
+	assert.NoError(t, <-runErrCh)
+	_, ok := <-sq.SQLs()
+	assert.False(t, ok)
+}
+
+func TestSlowQueryResumeFromCheckpoint(t *testing.T) {
+	dir, err := os.MkdirTemp("", "slowquery")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "slow.log")
+	params := &Params{LogFilePath: logPath, CheckpointFile: filepath.Join(dir, "checkpoint.json")}
+
+	assert.NoError(t, os.WriteFile(logPath, []byte("# Query_time: 1.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nuse db1;\nselect 1;\n"), 0644))
+
+	runAndReceive := func() scanners.SQL {
+		sq, err := New(params, logrus.New().WithField("test", "test"), nil)
+		assert.NoError(t, err)
+		sq.pollInterval = 10 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go sq.Run(ctx)
+
+		select {
+		case sql := <-sq.SQLs():
+			assert.NoError(t, sq.SaveCheckpoint(sql.Checkpoint))
+			return sql
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to receive sql")
+		}
+		return scanners.SQL{}
+	}
+	assert.Equal(t, "select 1", runAndReceive().RawText)
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("# Query_time: 2.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nselect 2;\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	// the scanner resumes from checkpoint, and the schema is kept.
+	sql := runAndReceive()
+	assert.Equal(t, "select 2", sql.RawText)
+	assert.Equal(t, "db1", sql.Schema)
+
+	// the log is replaced by a new file, read it from the beginning.
+	assert.NoError(t, os.WriteFile(logPath, []byte("# Query_time: 3.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nselect 3;\n# Query_time: 4.0  Lock_time: 0.0 Rows_sent: 1  Rows_examined: 0\nselect 4;\n"), 0644))
+	assert.Equal(t, "select 3", runAndReceive().RawText)
+}
@@ -0,0 +1,204 @@
+//go:build !enterprise
+// +build !enterprise
+
+package slowquery
+
+import (
+	"bufio"
+	"context"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// headMaxSize is the max size of file head used to identify a file.
+const headMaxSize = 1024
+
+// position is the position in file after a line. The file is identified by
+// the checksum of its head, since it may be rotated and replaced by a new one.
+type position struct {
+	Offset       int64  `json:"offset"`
+	HeadSize     int    `json:"head_size"`
+	HeadChecksum uint32 `json:"head_checksum"`
+}
+
+// tailer follows a file like "tail -F". It reopens the file when the file
+// is rotated (renamed or removed and recreated) or truncated.
+type tailer struct {
+	l            *logrus.Entry
+	path         string
+	pollInterval time.Duration
+
+	file   *os.File
+	reader *bufio.Reader
+	// offset is the position after the last complete line.
+	offset int64
+	// partial holds the incomplete line at the end of file.
+	partial string
+	// head holds the first headMaxSize bytes of the file which are read.
+	head []byte
+}
+
+func newTailer(l *logrus.Entry, path string, pollInterval time.Duration) *tailer {
+	return &tailer{
+		l:            l,
+		path:         filepath.Clean(path),
+		pollInterval: pollInterval,
+	}
+}
+
+// Run reads the file from start and calls onLine for each complete line. It
+// calls onIdle when it reaches the end of file. Run blocks until ctx is canceled.
+//
+// The file is read from the beginning if start is not the position of current file.
+func (t *tailer) Run(ctx context.Context, start position, onLine func(line string), onIdle func()) error {
+	if err := t.open(start); err != nil {
+		return err
+	}
+	defer t.close()
+
+	for {
+		if err := t.readToEOF(onLine); err != nil {
+			return err
+		}
+		onIdle()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(t.pollInterval):
+		}
+
+		reopen, err := t.checkRotation()
+		if err != nil {
+			return err
+		}
+		if reopen {
+			// the lines written to the old file before rotation should not be lost.
+			if err := t.readToEOF(onLine); err != nil {
+				return err
+			}
+			onIdle()
+			t.close()
+			if err := t.open(position{}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (t *tailer) readToEOF(onLine func(line string)) error {
+	for {
+		line, err := t.reader.ReadString('\n')
+		if err == io.EOF {
+			t.partial += line
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", t.path)
+		}
+		line = t.partial + line
+		t.partial = ""
+		t.offset += int64(len(line))
+		if len(t.head) < headMaxSize {
+			n := headMaxSize - len(t.head)
+			if n > len(line) {
+				n = len(line)
+			}
+			t.head = append(t.head, line[:n]...)
+		}
+		onLine(line)
+	}
+}
+
+// Position returns the position after the last complete line which was passed to onLine.
+func (t *tailer) Position() position {
+	return position{
+		Offset:       t.offset,
+		HeadSize:     len(t.head),
+		HeadChecksum: crc32.ChecksumIEEE(t.head),
+	}
+}
+
+func (t *tailer) open(start position) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", t.path)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to stat %s", t.path)
+	}
+
+	offset := start.Offset
+	if offset > fi.Size() {
+		// the file is truncated or replaced, start from the beginning.
+		offset = 0
+	}
+	headSize := offset
+	if headSize > headMaxSize {
+		headSize = headMaxSize
+	}
+	head := make([]byte, headSize)
+	if _, err := f.ReadAt(head, 0); err != nil && err != io.EOF {
+		f.Close()
+		return errors.Wrapf(err, "failed to read %s", t.path)
+	}
+	if offset > 0 && (int64(start.HeadSize) > headSize || crc32.ChecksumIEEE(head[:start.HeadSize]) != start.HeadChecksum) {
+		t.l.Infof("file %s is not the one of checkpoint, read it from the beginning", t.path)
+		offset = 0
+		head = head[:0]
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to seek %s", t.path)
+	}
+	t.file = f
+	t.reader = bufio.NewReader(f)
+	t.offset = offset
+	t.partial = ""
+	t.head = head
+	return nil
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// checkRotation returns true if the file should be reopened from the beginning.
+func (t *tailer) checkRotation() (bool, error) {
+	newFi, err := os.Stat(t.path)
+	if os.IsNotExist(err) {
+		// the file is moved and the new one is not created yet, keep waiting.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to stat %s", t.path)
+	}
+	curFi, err := t.file.Stat()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to stat %s", t.path)
+	}
+
+	switch {
+	case !os.SameFile(curFi, newFi):
+		t.l.Infof("file %s is rotated, reopen it", t.path)
+		return true, nil
+	case newFi.Size() < t.offset+int64(len(t.partial)):
+		t.l.Infof("file %s is truncated, read it from the beginning", t.path)
+		// the content of the truncated file is unknown, drop the partial line.
+		t.partial = ""
+		return true, nil
+	}
+	return false, nil
+}
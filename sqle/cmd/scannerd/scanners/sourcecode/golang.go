@@ -0,0 +1,169 @@
+package sourcecode
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// goExtractor extracts the string literals and the concatenations of them
+// from Go files. The files in a directory belong to one package, so the
+// constants defined in package scope can be referred by all of them.
+type goExtractor struct{}
+
+func (e *goExtractor) extract(files []string) (map[string][]literal, error) {
+	contents, err := readFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	astFiles := make(map[string]*ast.File, len(files))
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, contents[file], 0)
+		if err != nil {
+			// the file may be a template or contain build errors, skip it.
+			continue
+		}
+		astFiles[file] = f
+	}
+
+	ev := &goEvaluator{decls: map[string]ast.Expr{}, values: map[string]string{}}
+	for _, f := range astFiles {
+		ev.collectConsts(f)
+	}
+
+	ret := make(map[string][]literal, len(astFiles))
+	for file, f := range astFiles {
+		ast.Inspect(f, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			switch x := expr.(type) {
+			case *ast.BasicLit:
+			case *ast.BinaryExpr:
+				if x.Op != token.ADD {
+					return true
+				}
+			default:
+				return true
+			}
+			text, hasLiteral, ok := ev.eval(expr)
+			if !ok || !hasLiteral {
+				return true
+			}
+			ret[file] = append(ret[file], literal{
+				text:    text,
+				line:    fset.Position(expr.Pos()).Line,
+				endLine: fset.Position(expr.End()).Line,
+			})
+			// the sub expressions are parts of the string, do not extract them again.
+			return false
+		})
+	}
+	return ret, nil
+}
+
+// goEvaluator evaluates the string expressions, the constants are resolved
+// by the name since the type information is unavailable.
+type goEvaluator struct {
+	decls  map[string]ast.Expr
+	values map[string]string
+	// resolving is used to detect the circular reference of constants.
+	resolving map[string]struct{}
+}
+
+// collectConsts collects the constants and the variables whose value is a
+// string expression, both in package scope and function scope.
+func (ev *goEvaluator) collectConsts(f *ast.File) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || (decl.Tok != token.CONST && decl.Tok != token.VAR) {
+			return true
+		}
+		for _, spec := range decl.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Names) != len(vs.Values) {
+				continue
+			}
+			for i, name := range vs.Names {
+				ev.decls[name.Name] = vs.Values[i]
+			}
+		}
+		return true
+	})
+}
+
+func (ev *goEvaluator) resolve(name string) (string, bool) {
+	if value, ok := ev.values[name]; ok {
+		return value, true
+	}
+	decl, ok := ev.decls[name]
+	if !ok {
+		return "", false
+	}
+	if ev.resolving == nil {
+		ev.resolving = map[string]struct{}{}
+	}
+	if _, ok := ev.resolving[name]; ok {
+		return "", false
+	}
+	ev.resolving[name] = struct{}{}
+	defer delete(ev.resolving, name)
+
+	value, _, ok := ev.eval(decl)
+	if !ok {
+		return "", false
+	}
+	ev.values[name] = value
+	return value, true
+}
+
+// eval returns the value of string expression, the parts which can not be
+// resolved are replaced by "?". The hasLiteral is false if the expression
+// does not contain any string literal, ok is false if the expression is not
+// a string expression.
+func (ev *goEvaluator) eval(expr ast.Expr) (value string, hasLiteral bool, ok bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return "", false, false
+		}
+		value, err := strconv.Unquote(x.Value)
+		if err != nil {
+			return "", false, false
+		}
+		return value, true, true
+	case *ast.ParenExpr:
+		return ev.eval(x.X)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return "", false, false
+		}
+		left, leftHasLiteral, ok := ev.eval(x.X)
+		if !ok {
+			return "", false, false
+		}
+		right, rightHasLiteral, ok := ev.eval(x.Y)
+		if !ok {
+			return "", false, false
+		}
+		return left + right, leftHasLiteral || rightHasLiteral, true
+	case *ast.Ident:
+		if value, ok := ev.resolve(x.Name); ok {
+			return value, false, true
+		}
+		return "?", false, true
+	case *ast.SelectorExpr:
+		// the constant of other package, e.g. "model.TableName".
+		if value, ok := ev.resolve(x.Sel.Name); ok {
+			return value, false, true
+		}
+		return "?", false, true
+	case *ast.CallExpr, *ast.IndexExpr, *ast.StarExpr:
+		return "?", false, true
+	}
+	return "", false, false
+}
@@ -0,0 +1,259 @@
+package sourcecode
+
+import (
+	"strings"
+	"unicode"
+)
+
+// javaExtractor extracts the string literals and the concatenations of them
+// from Java files, such as:
+//
+//	jdbcTemplate.query("SELECT * FROM users WHERE id = " + id, mapper);
+//	@Query(value = "SELECT * FROM users WHERE email = ?1", nativeQuery = true)
+//
+// The constants, e.g. "static final String TABLE = "users";", can be referred
+// by the files in the same directory (package).
+type javaExtractor struct{}
+
+func (e *javaExtractor) extract(files []string) (map[string][]literal, error) {
+	contents, err := readFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	tokensByFile := make(map[string][]javaToken, len(files))
+	consts := map[string]string{}
+	for _, file := range files {
+		tokens := tokenizeJava(string(contents[file]))
+		tokensByFile[file] = tokens
+		collectJavaConsts(tokens, consts)
+	}
+
+	ret := make(map[string][]literal, len(files))
+	for _, file := range files {
+		tokens := tokensByFile[file]
+		jpqlEnd := -1
+		for i := 0; i < len(tokens); i++ {
+			if end, ok := jpqlQueryEnd(tokens, i); ok {
+				jpqlEnd = end
+			}
+			if tokens[i].kind != javaTokenString || i < jpqlEnd {
+				continue
+			}
+			// the expression may start with a constant, e.g. "BASE_SQL + " WHERE id = ?"".
+			start := i
+			for start >= 2 && tokens[start-1].is("+") && tokens[start-2].kind == javaTokenIdent {
+				start -= 2
+				for start >= 2 && tokens[start-1].is(".") && tokens[start-2].kind == javaTokenIdent {
+					start -= 2
+				}
+			}
+			text, end := evalJavaExpr(tokens, start, consts)
+			ret[file] = append(ret[file], literal{text: text, line: tokens[start].line, endLine: tokens[end-1].line})
+			i = end - 1
+		}
+	}
+	return ret, nil
+}
+
+// jpqlQueryEnd checks whether tokens[start] is the beginning of a JPA @Query
+// annotation whose query is JPQL rather than native SQL. It returns the index
+// of the token after the annotation.
+func jpqlQueryEnd(tokens []javaToken, start int) (int, bool) {
+	if start+2 >= len(tokens) || !tokens[start].is("@") || tokens[start+1].text != "Query" || !tokens[start+2].is("(") {
+		return 0, false
+	}
+	end := skipBalanced(tokens, start+2)
+	for i := start + 3; i+2 < end; i++ {
+		if tokens[i].text == "nativeQuery" && tokens[i+1].is("=") && tokens[i+2].text == "true" {
+			return 0, false
+		}
+	}
+	return end, true
+}
+
+const (
+	javaTokenIdent = iota
+	javaTokenString
+	javaTokenPunct
+)
+
+type javaToken struct {
+	kind int
+	text string
+	line int
+}
+
+func (t javaToken) is(punct string) bool {
+	return t.kind == javaTokenPunct && t.text == punct
+}
+
+// tokenizeJava splits the Java code into identifiers, string literals and
+// punctuations, the comments and char literals are dropped.
+func tokenizeJava(code string) []javaToken {
+	var tokens []javaToken
+	src := []rune(code)
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			i += 2
+			for i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/') {
+				if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case c == '"' && i+2 < len(src) && src[i+1] == '"' && src[i+2] == '"':
+			// text block, e.g. """\n SELECT * FROM users\n """
+			startLine := line
+			i += 3
+			buf := strings.Builder{}
+			for i < len(src) && !(src[i] == '"' && i+2 < len(src) && src[i+1] == '"' && src[i+2] == '"') {
+				if src[i] == '\n' {
+					line++
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					buf.WriteRune(unescapeJava(src[i+1]))
+					i += 2
+					continue
+				}
+				buf.WriteRune(src[i])
+				i++
+			}
+			i += 3
+			tokens = append(tokens, javaToken{kind: javaTokenString, text: buf.String(), line: startLine})
+		case c == '"' || c == '\'':
+			i++
+			buf := strings.Builder{}
+			for i < len(src) && src[i] != c && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) {
+					buf.WriteRune(unescapeJava(src[i+1]))
+					i += 2
+					continue
+				}
+				buf.WriteRune(src[i])
+				i++
+			}
+			i++
+			if c == '"' {
+				tokens = append(tokens, javaToken{kind: javaTokenString, text: buf.String(), line: line})
+			} else {
+				// the char literal is an operand which is not a string.
+				tokens = append(tokens, javaToken{kind: javaTokenIdent, text: "'", line: line})
+			}
+		case c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '$' || unicode.IsLetter(src[i]) || unicode.IsDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, javaToken{kind: javaTokenIdent, text: string(src[start:i]), line: line})
+		default:
+			tokens = append(tokens, javaToken{kind: javaTokenPunct, text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens
+}
+
+func unescapeJava(c rune) rune {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	}
+	return c
+}
+
+// collectJavaConsts collects the string variables initialized by string
+// expression, e.g. "private static final String TABLE = "users";".
+func collectJavaConsts(tokens []javaToken, consts map[string]string) {
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].kind != javaTokenIdent || tokens[i].text != "String" ||
+			tokens[i+1].kind != javaTokenIdent || !tokens[i+2].is("=") {
+			continue
+		}
+		if tokens[i+3].kind != javaTokenString && tokens[i+3].kind != javaTokenIdent {
+			continue
+		}
+		text, end := evalJavaExpr(tokens, i+3, consts)
+		if end < len(tokens) && tokens[end].is(";") {
+			consts[tokens[i+1].text] = text
+		}
+	}
+}
+
+// evalJavaExpr evaluates the string concatenation starts from tokens[start],
+// it returns the value and the index of the token after the expression. The
+// operands which are not string literal or known constant are replaced by "?".
+func evalJavaExpr(tokens []javaToken, start int, consts map[string]string) (string, int) {
+	buf := strings.Builder{}
+	i := start
+	for i < len(tokens) {
+		switch tokens[i].kind {
+		case javaTokenString:
+			buf.WriteString(tokens[i].text)
+			i++
+		case javaTokenIdent:
+			// the operand may be "Constants.TABLE", "user.getName()" or "ids[0]".
+			name := tokens[i].text
+			i++
+			for i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].kind == javaTokenIdent {
+				name = tokens[i+1].text
+				i += 2
+			}
+			isCall := false
+			for i < len(tokens) && (tokens[i].is("(") || tokens[i].is("[")) {
+				isCall = true
+				i = skipBalanced(tokens, i)
+				for i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].kind == javaTokenIdent {
+					i += 2
+				}
+			}
+			if value, ok := consts[name]; ok && !isCall {
+				buf.WriteString(value)
+			} else {
+				buf.WriteString("?")
+			}
+		default:
+			return buf.String(), i
+		}
+		if i < len(tokens) && tokens[i].is("+") && i+1 < len(tokens) && tokens[i+1].kind != javaTokenPunct {
+			i++
+			continue
+		}
+		break
+	}
+	return buf.String(), i
+}
+
+// skipBalanced returns the index of the token after the matched bracket of tokens[start].
+func skipBalanced(tokens []javaToken, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("(") || tokens[i].is("["):
+			depth++
+		case tokens[i].is(")") || tokens[i].is("]"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(tokens)
+}
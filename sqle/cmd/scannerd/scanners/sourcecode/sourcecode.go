@@ -0,0 +1,323 @@
+package sourcecode
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/gitdiff"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/incremental"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/mybatis"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SourceCode extracts the SQL literals embedded in Java and Go source code,
+// e.g. the SQL passed to JdbcTemplate, @Query annotations, database/sql, sqlx
+// and GORM raw queries.
+type SourceCode struct {
+	l *logrus.Entry
+	c *scanner.Client
+
+	needTrigger bool
+	sqls        []scanners.SQL
+
+	allSQL []SQL
+	getAll chan struct{}
+
+	apName      string
+	dir         string
+	incremental *incremental.Params
+}
+
+type Params struct {
+	Dir    string
+	APName string
+	// Incremental is optional, only the SQLs touched by git diff are audited if it's enabled.
+	Incremental *incremental.Params
+}
+
+func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SourceCode, error) {
+	if err := params.Incremental.Validate(); err != nil {
+		return nil, err
+	}
+	return &SourceCode{
+		dir:         params.Dir,
+		apName:      params.APName,
+		incremental: params.Incremental,
+		l:           l,
+		c:           c,
+		getAll:      make(chan struct{}),
+	}, nil
+}
+
+func (sc *SourceCode) Run(ctx context.Context) error {
+	var sqls []SQL
+	var err error
+	if sc.incremental.Enabled() {
+		sqls, err = sc.getTouchedSQLs(ctx)
+	} else {
+		sqls, err = GetSQLFromPath(sc.dir)
+	}
+	if err != nil {
+		return err
+	}
+	sc.l.Infof("extracted %d SQLs from %s", len(sqls), sc.dir)
+
+	sc.allSQL = sqls
+	close(sc.getAll)
+
+	<-ctx.Done()
+	return nil
+}
+
+// getTouchedSQLs returns the SQLs whose lines are added or changed in git diff.
+func (sc *SourceCode) getTouchedSQLs(ctx context.Context) ([]SQL, error) {
+	diff, err := gitdiff.New(ctx, sc.dir, sc.incremental.GitBase, sc.incremental.GitHead)
+	if err != nil {
+		return nil, err
+	}
+	defer diff.Close()
+
+	sqls, err := GetSQLFromPath(diff.Dir())
+	if err != nil {
+		return nil, err
+	}
+	touched := make([]SQL, 0, len(sqls))
+	for _, sql := range sqls {
+		if diff.Touched(sql.File, sql.Line, sql.EndLine) {
+			touched = append(touched, sql)
+		}
+	}
+	return touched, nil
+}
+
+func (sc *SourceCode) SQLs() <-chan scanners.SQL {
+	// todo: channel size configurable
+	sqlCh := make(chan scanners.SQL, 10240)
+
+	go func() {
+		<-sc.getAll
+		for _, sql := range sc.allSQL {
+			sqlCh <- scanners.SQL{
+				Fingerprint: sql.Fingerprint,
+				RawText:     sql.Text,
+				SourceFile:  sql.File,
+				SourceLine:  sql.Line,
+			}
+		}
+		sc.needTrigger = true
+		close(sqlCh)
+	}()
+	return sqlCh
+}
+
+func (sc *SourceCode) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	sc.sqls = append(sc.sqls, sqls...)
+
+	if !sc.needTrigger {
+		return nil
+	}
+
+	if sc.incremental.Enabled() {
+		_, err := incremental.Submit(sc.c, sc.apName, sc.incremental, sc.sqls, os.Stdout)
+		return err
+	}
+
+	// key=fingerPrint val=count
+	counterMap := make(map[string]uint, len(sc.sqls))
+
+	nodeList := make([]scanners.SQL, 0, len(sc.sqls))
+	for _, node := range sc.sqls {
+		counterMap[node.Fingerprint]++
+		if counterMap[node.Fingerprint] <= 1 {
+			nodeList = append(nodeList, node)
+		}
+	}
+
+	reqBody := make([]scanner.AuditPlanSQLReq, 0, len(nodeList))
+	now := time.Now().Format(time.RFC3339)
+	for _, sql := range nodeList {
+		reqBody = append(reqBody, scanner.AuditPlanSQLReq{
+			Fingerprint:          sql.Fingerprint,
+			Counter:              fmt.Sprintf("%v", counterMap[sql.Fingerprint]),
+			LastReceiveText:      sql.RawText,
+			LastReceiveTimestamp: now,
+			SourceFile:           sql.SourceFile,
+			SourceLine:           strconv.Itoa(sql.SourceLine),
+		})
+	}
+
+	err := sc.c.UploadReq(scanner.FullUpload, sc.apName, reqBody)
+	if err != nil {
+		return err
+	}
+
+	reportID, err := sc.c.TriggerAuditReq(sc.apName)
+	if err != nil {
+		return err
+	}
+	return sc.c.GetAuditReportReq(sc.apName, reportID)
+}
+
+// SQL is a SQL extracted from source code.
+type SQL struct {
+	driver.Node
+
+	File    string
+	Line    int
+	EndLine int
+}
+
+// literal is a string expression in source code, the dynamic parts of the
+// expression are replaced by "?".
+type literal struct {
+	text    string
+	line    int
+	endLine int
+}
+
+// extractor extracts the string literals from the source files in a directory,
+// the constants defined in one file can be referred by other files.
+type extractor interface {
+	extract(files []string) (map[string][]literal, error)
+}
+
+var extractors = map[string]extractor{
+	".go":   &goExtractor{},
+	".java": &javaExtractor{},
+}
+
+// skipDirs are the directories which do not contain the code of application.
+var skipDirs = map[string]struct{}{
+	"vendor":       {},
+	"node_modules": {},
+	"testdata":     {},
+	"target":       {},
+	"build":        {},
+}
+
+// GetSQLFromPath extracts the SQLs from Java and Go files under the path. The
+// string literals which can not be parsed as SQL are ignored.
+func GetSQLFromPath(pathName string) ([]SQL, error) {
+	if !path.IsAbs(pathName) {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		pathName = path.Join(pwd, pathName)
+	}
+	return getSQLFromDir(pathName, pathName)
+}
+
+func getSQLFromDir(baseDir, dir string) ([]SQL, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var allSQL []SQL
+	filesByExt := map[string][]string{}
+	for _, fi := range fileInfos {
+		name := fi.Name()
+		if fi.IsDir() {
+			if _, ok := skipDirs[name]; ok || strings.HasPrefix(name, ".") {
+				continue
+			}
+			sqls, err := getSQLFromDir(baseDir, path.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			allSQL = append(allSQL, sqls...)
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		ext := filepath.Ext(name)
+		if _, ok := extractors[ext]; ok {
+			filesByExt[ext] = append(filesByExt[ext], path.Join(dir, name))
+		}
+	}
+
+	exts := make([]string, 0, len(filesByExt))
+	for ext := range filesByExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	for _, ext := range exts {
+		files := filesByExt[ext]
+		literals, err := extractors[ext].extract(files)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			name := file
+			if rel, err := filepath.Rel(baseDir, file); err == nil {
+				name = rel
+			}
+			for _, l := range literals[file] {
+				sqls, err := literalToSQLs(l)
+				if err != nil {
+					return nil, fmt.Errorf("parse SQL in %s:%d failed: %v", name, l.line, err)
+				}
+				for _, sql := range sqls {
+					sql.File = name
+					allSQL = append(allSQL, sql)
+				}
+			}
+		}
+	}
+	return allSQL, nil
+}
+
+// sqlPrefixRegexp matches the string which looks like a SQL.
+var sqlPrefixRegexp = regexp.MustCompile(`(?is)^\s*\(?\s*(select|insert|update|delete|replace|with|create|alter|drop|truncate)\s`)
+
+// the placeholders which are not supported by parser, e.g. "?1" of JPA,
+// "$1" of PostgreSQL driver and ":name" of sqlx and JdbcTemplate.
+var (
+	positionalPlaceholderRegexp = regexp.MustCompile(`[?$]\d+`)
+	namedPlaceholderRegexp      = regexp.MustCompile(`([^:\w]):[A-Za-z_]\w*`)
+)
+
+func literalToSQLs(l literal) ([]SQL, error) {
+	text := strings.TrimSpace(l.text)
+	text = positionalPlaceholderRegexp.ReplaceAllString(text, "?")
+	text = namedPlaceholderRegexp.ReplaceAllString(text, "$1?")
+	if !sqlPrefixRegexp.MatchString(text) || !mybatis.IsParsable(text) {
+		return nil, nil
+	}
+	nodes, err := mybatis.Parse(context.TODO(), text)
+	if err != nil {
+		return nil, err
+	}
+	sqls := make([]SQL, 0, len(nodes))
+	for _, n := range nodes {
+		sqls = append(sqls, SQL{Node: n, Line: l.line, EndLine: l.endLine})
+	}
+	return sqls, nil
+}
+
+func readFiles(files []string) (map[string][]byte, error) {
+	contents := make(map[string][]byte, len(files))
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, err
+		}
+		contents[file] = content
+	}
+	return contents, nil
+}
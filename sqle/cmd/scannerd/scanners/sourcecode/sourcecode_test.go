@@ -0,0 +1,54 @@
+package sourcecode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSQLFromPath(t *testing.T) {
+	sqls, err := GetSQLFromPath("./testdata")
+	assert.NoError(t, err)
+
+	type location struct {
+		file string
+		line int
+	}
+	got := map[string]location{}
+	for _, sql := range sqls {
+		got[sql.Text] = location{sql.File, sql.Line}
+	}
+	assert.Equal(t, map[string]location{
+		"SELECT id, name, email FROM users":                                                         {"dao/consts.go", 8},
+		"SELECT id, name, email FROM users WHERE id = ?":                                            {"dao/user.go", 16},
+		"DELETE FROM users\nWHERE id = ?":                                                           {"dao/user.go", 21},
+		"SELECT * FROM users WHERE status = ?":                                                      {"dao/user.go", 28},
+		"SELECT * FROM users WHERE name = ?":                                                        {"java/UserRepository.java", 10},
+		"SELECT COUNT(*) FROM users WHERE status = '?'":                                             {"java/UserRepository.java", 15},
+		"UPDATE users\n            SET archived = 1\n            WHERE last_login < \"2020-01-01\"": {"java/UserRepository.java", 21},
+	}, got)
+}
+
+func TestSourceCode(t *testing.T) {
+	sc, err := New(&Params{Dir: "./not-exist-directory/"}, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+	assert.Error(t, sc.Run(context.TODO()))
+
+	sc, err = New(&Params{Dir: "./testdata/java"}, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sc.Run(ctx)
+
+	var sqls []scanners.SQL
+	for sql := range sc.SQLs() {
+		sqls = append(sqls, sql)
+	}
+	assert.Len(t, sqls, 3)
+	assert.Equal(t, "UserRepository.java", sqls[0].SourceFile)
+	assert.NotEmpty(t, sqls[0].Fingerprint)
+}
@@ -0,0 +1,8 @@
+package dao
+
+const (
+	userTable   = "users"
+	userColumns = "id, name, email"
+)
+
+const selectUserSQL = "SELECT " + userColumns + " FROM " + userTable
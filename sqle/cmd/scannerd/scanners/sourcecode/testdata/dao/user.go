@@ -0,0 +1,37 @@
+package dao
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type UserDAO struct {
+	db   *sql.DB
+	gorm *gorm.DB
+}
+
+func (d *UserDAO) Get(id int) error {
+	_, err := d.db.Query(selectUserSQL+" WHERE id = ?", id)
+	return err
+}
+
+func (d *UserDAO) Delete(id int) error {
+	_, err := d.db.Exec(`DELETE FROM users
+WHERE id = ?`, id)
+	return err
+}
+
+func (d *UserDAO) ListByStatus(status string) error {
+	// the dynamic part is replaced by placeholder.
+	return d.gorm.Raw("SELECT * FROM users WHERE status = " + quote(status)).Error
+}
+
+func (d *UserDAO) Log(name string) {
+	fmt.Println("update user " + name + " failed")
+}
+
+func quote(s string) string {
+	return "'" + s + "'"
+}
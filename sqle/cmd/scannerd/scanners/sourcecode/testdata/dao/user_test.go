@@ -0,0 +1,3 @@
+package dao
+
+const testSQL = "SELECT * FROM test_users"
@@ -0,0 +1,5 @@
+package com.example;
+
+public final class Constants {
+    public static final String USER_TABLE = "users";
+}
@@ -0,0 +1,27 @@
+package com.example;
+
+import org.springframework.data.jpa.repository.Query;
+
+public interface UserRepository {
+    // JPQL is not SQL, it is ignored.
+    @Query("select u from User u where u.email = ?1")
+    User findByEmail(String email);
+
+    @Query(value = "SELECT * FROM users WHERE name = ?1", nativeQuery = true)
+    User findByName(String name);
+
+    /* "SELECT * FROM comments" in comment is ignored. */
+    default int count(JdbcTemplate jdbcTemplate, String status) {
+        String sql = "SELECT COUNT(*) FROM " + Constants.USER_TABLE
+                + " WHERE status = '" + status + "'";
+        return jdbcTemplate.queryForObject(sql, Integer.class);
+    }
+
+    default void archive(JdbcTemplate jdbcTemplate) {
+        jdbcTemplate.update("""
+            UPDATE users
+            SET archived = 1
+            WHERE last_login < \"2020-01-01\"
+            """);
+    }
+}
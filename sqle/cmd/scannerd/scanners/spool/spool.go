@@ -0,0 +1,157 @@
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const batchFileSuffix = ".batch.json"
+
+// Spool is a local on-disk FIFO queue of SQL batches which are failed to upload.
+// It is goroutine safe.
+type Spool struct {
+	l        *logrus.Entry
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	files []string
+	size  map[string]int64
+	seq   uint64
+}
+
+// New opens the spool in dir, the batches left by the last run are loaded.
+// The oldest batches are dropped if the total size exceeds maxBytes, 0 means unlimited.
+func New(l *logrus.Entry, dir string, maxBytes int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed to create spool directory %s", dir)
+	}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read spool directory %s", dir)
+	}
+
+	s := &Spool{
+		l:        l,
+		dir:      dir,
+		maxBytes: maxBytes,
+		size:     map[string]int64{},
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), batchFileSuffix) {
+			continue
+		}
+		s.files = append(s.files, fi.Name())
+		s.size[fi.Name()] = fi.Size()
+	}
+	// file name is started with timestamp, so the order is the order of writing.
+	sort.Strings(s.files)
+	if len(s.files) > 0 {
+		l.Infof("%d batches are left in spool %s", len(s.files), dir)
+	}
+	return s, nil
+}
+
+// Put writes a batch to the tail of the spool.
+func (s *Spool) Put(sqls []scanners.SQL) error {
+	data, err := json.Marshal(sqls)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, batchFileSuffix)
+	// write to a temporary file and rename it, so a half-written batch is never loaded.
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0640); err != nil {
+		return errors.Wrap(err, "failed to write spool")
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return errors.Wrap(err, "failed to write spool")
+	}
+	s.files = append(s.files, name)
+	s.size[name] = int64(len(data))
+	s.shrink()
+	return nil
+}
+
+// Peek reads the batch at the head of the spool, it returns false if the spool is empty.
+func (s *Spool) Peek() ([]scanners.SQL, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.files) == 0 {
+		return nil, false, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, s.files[0]))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read spool")
+	}
+	var sqls []scanners.SQL
+	if err := json.Unmarshal(data, &sqls); err != nil {
+		// the batch is corrupted, it can never be uploaded.
+		s.l.Errorf("drop corrupted batch %s in spool, error: %v", s.files[0], err)
+		s.removeHead()
+		return nil, false, err
+	}
+	return sqls, true, nil
+}
+
+// Pop removes the batch at the head of the spool, it should be called after
+// the batch returned by Peek is uploaded.
+func (s *Spool) Pop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.files) > 0 {
+		s.removeHead()
+	}
+}
+
+// Len returns the number of batches in the spool.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.files)
+}
+
+func (s *Spool) removeHead() {
+	name := s.files[0]
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+		s.l.Errorf("failed to remove batch %s in spool, error: %v", name, err)
+	}
+	s.files = s.files[1:]
+	delete(s.size, name)
+}
+
+func (s *Spool) shrink() {
+	if s.maxBytes <= 0 {
+		return
+	}
+	var total int64
+	for _, size := range s.size {
+		total += size
+	}
+	// keep the newest batch even if it exceeds the limit.
+	for total > s.maxBytes && len(s.files) > 1 {
+		name := s.files[0]
+		total -= s.size[name]
+		s.l.Warnf("spool exceeds %d bytes, drop the oldest batch %s", s.maxBytes, name)
+		s.removeHead()
+	}
+}
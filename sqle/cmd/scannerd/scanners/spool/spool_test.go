@@ -0,0 +1,62 @@
+package spool
+
+import (
+	"os"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpool(t *testing.T) {
+	dir, err := os.MkdirTemp("", "spool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	l := logrus.New().WithField("test", "test")
+
+	s, err := New(l, dir, 0)
+	assert.NoError(t, err)
+	_, ok, err := s.Peek()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, s.Put([]scanners.SQL{{RawText: "select 1"}}))
+	assert.NoError(t, s.Put([]scanners.SQL{{RawText: "select 2"}, {RawText: "select 3"}}))
+	assert.Equal(t, 2, s.Len())
+
+	// the batches are kept after restart.
+	s, err = New(l, dir, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Len())
+
+	batch, ok, err := s.Peek()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []scanners.SQL{{RawText: "select 1"}}, batch)
+	s.Pop()
+
+	batch, ok, err = s.Peek()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, batch, 2)
+	s.Pop()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSpoolMaxBytes(t *testing.T) {
+	dir, err := os.MkdirTemp("", "spool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := New(logrus.New().WithField("test", "test"), dir, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Put([]scanners.SQL{{RawText: "select 1"}}))
+	assert.NoError(t, s.Put([]scanners.SQL{{RawText: "select 2"}}))
+	assert.Equal(t, 1, s.Len())
+
+	batch, ok, err := s.Peek()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "select 2", batch[0].RawText)
+}
@@ -2,15 +2,56 @@ package supervisor
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/spool"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushBufferSize int) error {
+const (
+	defaultMinRetryInterval = time.Second
+	defaultMaxRetryInterval = 5 * time.Minute
+)
+
+type options struct {
+	spool            *spool.Spool
+	minRetryInterval time.Duration
+	maxRetryInterval time.Duration
+}
+
+type Option func(*options)
+
+// WithSpool buffers the SQLs failed to upload in spool and retries them with
+// exponential backoff, instead of returning the upload error.
+func WithSpool(s *spool.Spool) Option {
+	return func(o *options) {
+		o.spool = s
+	}
+}
+
+// WithRetryInterval sets the min and max interval of retrying the SQLs in spool.
+func WithRetryInterval(min, max time.Duration) Option {
+	return func(o *options) {
+		o.minRetryInterval = min
+		o.maxRetryInterval = max
+	}
+}
+
+func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushBufferSize int, opts ...Option) error {
+	o := &options{
+		minRetryInterval: defaultMinRetryInterval,
+		maxRetryInterval: defaultMaxRetryInterval,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	u := &uploader{scanner: scanner, options: o}
+
 	runErrCh := make(chan error)
 	runCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -18,6 +59,9 @@ func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushB
 		err := scanner.Run(runCtx)
 		runErrCh <- err
 	}()
+	if o.spool != nil {
+		go u.retry(runCtx)
+	}
 
 	logrus.StandardLogger().Infoln("scanner started...")
 
@@ -38,7 +82,7 @@ func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushB
 		case sql, ok := <-sqlCh:
 			if !ok {
 				if len(batch) != 0 {
-					err := scanner.Upload(context.TODO(), batch)
+					err := u.upload(batch)
 					if err != nil {
 						return errors.Wrap(err, "failed to upload sql")
 					}
@@ -57,7 +101,7 @@ func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushB
 			}
 		}
 		logrus.StandardLogger().Infof("start uploading %d sql\n", len(batch))
-		err := scanner.Upload(context.TODO(), batch)
+		err := u.upload(batch)
 		if err != nil {
 			return errors.Wrap(err, "failed to upload sql")
 		}
@@ -65,3 +109,110 @@ func Start(ctx context.Context, scanner scanners.Scanner, leastPushSecond, pushB
 		batch = make([]scanners.SQL, 0, pushBufferSize)
 	}
 }
+
+// uploader uploads SQLs in order. If spool is enabled, the batch is buffered
+// in spool when the upload fails or the spool is not empty.
+type uploader struct {
+	// mu makes sure scanner.Upload is not called concurrently.
+	mu      sync.Mutex
+	scanner scanners.Scanner
+	*options
+}
+
+func (u *uploader) upload(batch []scanners.SQL) error {
+	if u.spool != nil && u.spool.Len() > 0 {
+		// keep the order of batches, the batch is uploaded by retry loop.
+		return u.spoolBatch(batch)
+	}
+
+	u.mu.Lock()
+	err := u.scanner.Upload(context.TODO(), batch)
+	u.mu.Unlock()
+	if err != nil {
+		if u.spool == nil {
+			return err
+		}
+		if scanner.IsRejected(err) {
+			// retrying the rejected batch never succeeds and blocks the batches after it.
+			logrus.StandardLogger().Errorf("%d sql is rejected by sqle, drop it, error: %v", len(batch), err)
+			return u.saveCheckpoint(batch)
+		}
+		logrus.StandardLogger().Warnf("failed to upload sql, buffer it in spool, error: %v", err)
+		return u.spoolBatch(batch)
+	}
+	return u.saveCheckpoint(batch)
+}
+
+func (u *uploader) spoolBatch(batch []scanners.SQL) error {
+	if err := u.spool.Put(batch); err != nil {
+		return err
+	}
+	return u.saveCheckpoint(batch)
+}
+
+func (u *uploader) saveCheckpoint(batch []scanners.SQL) error {
+	cp, ok := u.scanner.(scanners.Checkpointer)
+	if !ok {
+		return nil
+	}
+	for i := len(batch) - 1; i >= 0; i-- {
+		if batch[i].Checkpoint != "" {
+			return cp.SaveCheckpoint(batch[i].Checkpoint)
+		}
+	}
+	return nil
+}
+
+// retry uploads the batches in spool with exponential backoff until ctx is canceled.
+func (u *uploader) retry(ctx context.Context) {
+	interval := u.minRetryInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		uploaded, err := u.uploadSpool()
+		switch {
+		case err != nil:
+			interval *= 2
+			if interval > u.maxRetryInterval {
+				interval = u.maxRetryInterval
+			}
+			logrus.StandardLogger().Warnf("failed to upload sql in spool, retry after %v, error: %v", interval, err)
+		case uploaded > 0:
+			logrus.StandardLogger().Infof("finish uploading %d batches in spool", uploaded)
+			interval = u.minRetryInterval
+		default:
+			interval = u.minRetryInterval
+		}
+	}
+}
+
+// uploadSpool uploads the batches in spool until it's empty or any upload fails.
+// The batch rejected by SQLE is dropped instead of being retried.
+func (u *uploader) uploadSpool() (int, error) {
+	var uploaded int
+	for {
+		batch, ok, err := u.spool.Peek()
+		if err != nil {
+			return uploaded, err
+		}
+		if !ok {
+			return uploaded, nil
+		}
+		u.mu.Lock()
+		err = u.scanner.Upload(context.TODO(), batch)
+		u.mu.Unlock()
+		switch {
+		case err == nil:
+			uploaded++
+		case scanner.IsRejected(err):
+			logrus.StandardLogger().Errorf("%d sql in spool is rejected by sqle, drop it, error: %v", len(batch), err)
+		default:
+			return uploaded, err
+		}
+		u.spool.Pop()
+	}
+}
@@ -3,10 +3,14 @@ package supervisor
 import (
 	"context"
 	"fmt"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/spool"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,6 +23,12 @@ type mockScanner struct {
 	generateSQLCnt int
 
 	runFailed bool
+
+	mu          sync.Mutex
+	uploadFails int
+	rejectAll   bool
+	rejected    int
+	checkpoint  string
 }
 
 func getMockScanner() *mockScanner {
@@ -35,7 +45,7 @@ func (mc *mockScanner) Run(ctx context.Context) error {
 	mc.isRunning = true
 
 	for i := 0; i < mc.generateSQLCnt; i++ {
-		mc.testSQLCh <- scanners.SQL{RawText: fmt.Sprintf("select * from t1 where id = %v", i)}
+		mc.testSQLCh <- scanners.SQL{RawText: fmt.Sprintf("select * from t1 where id = %v", i), Checkpoint: fmt.Sprintf("%v", i)}
 	}
 
 	ticker := time.NewTicker(2 * time.Second)
@@ -62,10 +72,33 @@ func (mc *mockScanner) SQLs() <-chan scanners.SQL {
 }
 
 func (mc *mockScanner) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.uploadFails > 0 {
+		mc.uploadFails--
+		return fmt.Errorf("mock upload failed")
+	}
+	if mc.rejectAll {
+		mc.rejected++
+		return &scanner.RejectedError{Err: fmt.Errorf("audit plan is not exist")}
+	}
 	mc.uploadSQLCnt += len(sqls)
 	return nil
 }
 
+func (mc *mockScanner) SaveCheckpoint(checkpoint string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.checkpoint = checkpoint
+	return nil
+}
+
+func (mc *mockScanner) uploaded() int {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.uploadSQLCnt
+}
+
 func Test_start(t *testing.T) {
 	errCh := make(chan error, 1)
 	leastPushSecond := 1
@@ -106,3 +139,67 @@ func Test_start(t *testing.T) {
 	err := Start(context.TODO(), mc, leastPushSecond, pushBufferSize)
 	assert.Error(t, err)
 }
+
+func Test_startWithSpool(t *testing.T) {
+	dir, err := os.MkdirTemp("", "spool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	sp, err := spool.New(logrus.New().WithField("test", "test"), dir, 0)
+	assert.NoError(t, err)
+
+	// upload error is not returned, the sql is buffered in spool and retried.
+	mc := getMockScanner()
+	mc.generateSQLCnt = 10
+	mc.uploadFails = 3
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Start(ctx, mc, 1, 10, WithSpool(sp), WithRetryInterval(10*time.Millisecond, 50*time.Millisecond))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return mc.uploaded() == 10 && sp.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	mc.mu.Lock()
+	assert.Equal(t, "9", mc.checkpoint)
+	mc.mu.Unlock()
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+func Test_startWithSpoolRejected(t *testing.T) {
+	dir, err := os.MkdirTemp("", "spool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	sp, err := spool.New(logrus.New().WithField("test", "test"), dir, 0)
+	assert.NoError(t, err)
+
+	// the first batch is buffered in spool by the network error, then all
+	// batches are rejected, they are dropped instead of being retried forever.
+	mc := getMockScanner()
+	mc.generateSQLCnt = 10
+	mc.uploadFails = 1
+	mc.rejectAll = true
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Start(ctx, mc, 1, 5, WithSpool(sp), WithRetryInterval(10*time.Millisecond, 50*time.Millisecond))
+	}()
+
+	assert.Eventually(t, func() bool {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+		return mc.rejected == 2 && sp.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	// the rejected batches are not retried.
+	time.Sleep(100 * time.Millisecond)
+	mc.mu.Lock()
+	assert.Equal(t, 2, mc.rejected)
+	assert.Equal(t, "9", mc.checkpoint)
+	mc.mu.Unlock()
+	assert.Equal(t, 0, mc.uploaded())
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/pkg/driver/conformance"
+
+	"github.com/spf13/cobra"
+)
+
+func pluginCheckCmd() *cobra.Command {
+	var specPath string
+	var timeout time.Duration
+
+	run := func(binary string) error {
+		spec := &conformance.Spec{}
+		if specPath != "" {
+			var err error
+			spec, err = conformance.LoadSpec(specPath)
+			if err != nil {
+				return err
+			}
+		}
+		if timeout > 0 {
+			spec.RPCTimeout = timeout
+		}
+
+		report := conformance.Check(binary, spec)
+		report.Print(os.Stdout)
+		if !report.Passed() {
+			return fmt.Errorf("plugin %v doesn't conform to the protocol", binary)
+		}
+		return nil
+	}
+
+	cmd := &cobra.Command{
+		Use:   "plugin-check <binary>",
+		Short: "launch the plugin binary and check its RPCs against the protocol",
+		Long: `launch the plugin binary and check its RPCs against the protocol.
+
+The checks which require a database are skipped unless the dsn is set in the
+spec file. The plugin connects to the dsn in its own process, so it must be a
+real database of the plugin's type, such as a local test instance.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := run(args[0]); nil != err {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&specPath, "spec", "", "", "spec file path, the expectations of the plugin in YAML")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", 0, fmt.Sprintf("max time of each RPC call (default %v)", conformance.DefaultRPCTimeout))
+	return cmd
+}
@@ -19,6 +19,24 @@ type SqleConfig struct {
 	LogPath          string `yaml:"log_path"`
 	PluginPath       string `yaml:"plugin_path"`
 	SecretKey        string `yaml:"secret_key"`
+	// PluginPoolSize is the max number of processes of each plugin.
+	PluginPoolSize int `yaml:"plugin_pool_size"`
+	// PluginHealthCheckInterval is the interval in seconds to ping the idle plugin processes.
+	PluginHealthCheckInterval int `yaml:"plugin_health_check_interval"`
+	// PluginAcquireTimeout is the max time in seconds to wait for an idle plugin process.
+	PluginAcquireTimeout int `yaml:"plugin_acquire_timeout"`
+	// PluginAuditTimeout, PluginExecTimeout, PluginQueryTimeout and PluginExplainTimeout
+	// are the max time in seconds of each plugin RPC call, negative means no deadline.
+	PluginAuditTimeout   int `yaml:"plugin_audit_timeout"`
+	PluginExecTimeout    int `yaml:"plugin_exec_timeout"`
+	PluginQueryTimeout   int `yaml:"plugin_query_timeout"`
+	PluginExplainTimeout int `yaml:"plugin_explain_timeout"`
+	// PluginMemoryLimit is the max memory in MB of each plugin process.
+	PluginMemoryLimit int `yaml:"plugin_memory_limit"`
+	// PluginCPULimit is the max CPU cores of each plugin process, it requires PluginCgroupPath.
+	PluginCPULimit float64 `yaml:"plugin_cpu_limit"`
+	// PluginCgroupPath is the cgroup v2 directory which the plugin processes are put into.
+	PluginCgroupPath string `yaml:"plugin_cgroup_path"`
 }
 
 type DatabaseConfig struct {
@@ -377,6 +377,38 @@ var doc = `{
                 }
             }
         },
+        "/v1/audit_plans/{audit_plan_name}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "pause audit plan, the collected SQLs and reports are kept",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "暂停审核计划",
+                "operationId": "pauseAuditPlanV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/audit_plans/{audit_plan_name}/report/{audit_plan_report_id}/": {
             "get": {
                 "security": [
@@ -512,6 +544,142 @@ var doc = `{
                 }
             }
         },
+        "/v1/audit_plans/{audit_plan_name}/reports/{audit_plan_report_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "export audit plan report with the summary and SQLs",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "导出指定审核计划的SQL审核报告",
+                "operationId": "exportAuditPlanReportV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan report id",
+                        "name": "audit_plan_report_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "export format, default is csv",
+                        "name": "export_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "audit plan report file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/audit_plans/{audit_plan_name}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "resume audit plan",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "恢复审核计划",
+                "operationId": "resumeAuditPlanV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/audit_plans/{audit_plan_name}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get audit plan run history",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "获取指定审核计划的运行记录",
+                "operationId": "getAuditPlanRunsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "filter run status",
+                        "name": "filter_run_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetAuditPlanRunsResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/audit_plans/{audit_plan_name}/sqls": {
             "get": {
                 "security": [
@@ -829,53 +997,565 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLEInfoResV1"
+                            "$ref": "#/definitions/v1.GetSQLEInfoResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/ding_talk": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get DingTalk configuration",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取 钉钉 配置",
+                "operationId": "getDingTalkConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetDingTalkConfigurationResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update DingTalk configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "添加 钉钉 配置",
+                "operationId": "updateDingTalkConfigurationV1",
+                "parameters": [
+                    {
+                        "description": "update DingTalk configuration req",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateDingTalkConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/ding_talk/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test DingTalk configuration, the test message is sent even if the DingTalk notify is disabled",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "测试 钉钉 配置",
+                "operationId": "testDingTalkConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatConfigurationResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get drivers and their capabilities",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取当前 server 支持的审核类型",
+                "operationId": "getDriversV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetDriversResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers/plugin_pools": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the usage of the process pools of plugins",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取插件进程池使用情况",
+                "operationId": "getPluginPoolsStatsV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetPluginPoolsStatsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers/plugins": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get driver plugins loaded by SQLE",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取已加载的插件列表",
+                "operationId": "getPluginsV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetPluginsResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "upload and load a driver plugin, the loaded plugin with the same name is replaced after the running audits are finished",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "上传并加载插件",
+                "operationId": "installPluginV1",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "plugin binary file",
+                        "name": "plugin_file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.InstallPluginResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers/plugins/{plugin_name}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "unload the driver plugin and remove its binary, the running audits are finished at first",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "卸载插件",
+                "operationId": "unloadPluginV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "plugin name",
+                        "name": "plugin_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers/plugins/{plugin_name}/reload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "reload the driver plugin from its binary, the running audits are finished by the old one",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "重新加载插件",
+                "operationId": "reloadPluginV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "plugin name",
+                        "name": "plugin_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.InstallPluginResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/drivers/plugins/{plugin_name}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the version history of driver plugin",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取插件版本历史",
+                "operationId": "getPluginVersionsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "plugin name",
+                        "name": "plugin_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetPluginVersionsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/feishu": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get Feishu configuration",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取 飞书 配置",
+                "operationId": "getFeishuConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetFeishuConfigurationResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update Feishu configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "添加 飞书 配置",
+                "operationId": "updateFeishuConfigurationV1",
+                "parameters": [
+                    {
+                        "description": "update Feishu configuration req",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateFeishuConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/feishu/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test Feishu configuration, the test message is sent even if the Feishu notify is disabled",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "测试 飞书 配置",
+                "operationId": "testFeishuConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatConfigurationResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/ldap": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get LDAP configuration",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取 LDAP 配置",
+                "operationId": "getLDAPConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetLDAPConfigurationResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update LDAP configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "添加 LDAP 配置",
+                "operationId": "updateLDAPConfigurationV1",
+                "parameters": [
+                    {
+                        "description": "update LDAP configuration req",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LDAPConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/license": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sqle license",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取 sqle license",
+                "operationId": "getSQLELicenseV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetLicenseResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "set sqle license",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "导入 sqle license",
+                "operationId": "setSQLELicenseV1",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "SQLE license file",
+                        "name": "license_file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/license/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "parse and check sqle license",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "解析和校验 sqle license",
+                "operationId": "checkSQLELicenseV1",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "SQLE license file",
+                        "name": "license_file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CheckLicenseResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/configurations/drivers": {
+        "/v1/configurations/license/info": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get drivers",
+                "description": "get the information needed to generate the sqle license",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取当前 server 支持的审核类型",
-                "operationId": "getDriversV1",
+                "summary": "获取生成 sqle license需要的的信息",
+                "operationId": "GetSQLELicenseInfoV1",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "server info",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetDriversResV1"
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/v1/configurations/ldap": {
+        "/v1/configurations/mattermost": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get LDAP configuration",
+                "description": "get Mattermost configuration",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取 LDAP 配置",
-                "operationId": "getLDAPConfigurationV1",
+                "summary": "获取 Mattermost 配置",
+                "operationId": "getMattermostConfigurationV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetLDAPConfigurationResV1"
+                            "$ref": "#/definitions/v1.GetMattermostConfigurationResV1"
                         }
                     }
                 }
@@ -886,23 +1566,23 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update LDAP configuration",
+                "description": "update Mattermost configuration",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "configuration"
                 ],
-                "summary": "添加 LDAP 配置",
-                "operationId": "updateLDAPConfigurationV1",
+                "summary": "添加 Mattermost 配置",
+                "operationId": "updateMattermostConfigurationV1",
                 "parameters": [
                     {
-                        "description": "update LDAP configuration req",
+                        "description": "update Mattermost configuration req",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.LDAPConfigurationReqV1"
+                            "$ref": "#/definitions/v1.UpdateMattermostConfigurationReqV1"
                         }
                     }
                 ],
@@ -916,138 +1596,123 @@ var doc = `{
                 }
             }
         },
-        "/v1/configurations/license": {
-            "get": {
+        "/v1/configurations/mattermost/test": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sqle license",
+                "description": "test Mattermost configuration, the test message is sent even if the Mattermost notify is disabled",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取 sqle license",
-                "operationId": "getSQLELicenseV1",
+                "summary": "测试 Mattermost 配置",
+                "operationId": "testMattermostConfigurationV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetLicenseResV1"
+                            "$ref": "#/definitions/v1.TestChatConfigurationResV1"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/configurations/oauth2": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "set sqle license",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "get Oauth2 configuration",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "导入 sqle license",
-                "operationId": "setSQLELicenseV1",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "SQLE license file",
-                        "name": "license_file",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
+                "summary": "获取 Oauth2 配置",
+                "operationId": "getOauth2ConfigurationV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetOauth2ConfigurationResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/configurations/license/check": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "parse and check sqle license",
+                "description": "update Oauth2 configuration",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "tags": [
                     "configuration"
                 ],
-                "summary": "解析和校验 sqle license",
-                "operationId": "checkSQLELicenseV1",
+                "summary": "修改 Oauth2 配置",
+                "operationId": "updateOauth2ConfigurationV1",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "SQLE license file",
-                        "name": "license_file",
-                        "in": "formData",
-                        "required": true
+                        "description": "update Oauth2 configuration req",
+                        "name": "conf",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.Oauth2ConfigurationReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CheckLicenseResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/configurations/license/info": {
+        "/v1/configurations/oauth2/tips": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "get the information needed to generate the sqle license",
+                "description": "get Oauth2 tips",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取生成 sqle license需要的的信息",
-                "operationId": "GetSQLELicenseInfoV1",
+                "summary": "获取 Oauth2 基本信息",
+                "operationId": "getOauth2Tips",
                 "responses": {
                     "200": {
-                        "description": "server info",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/v1.GetOauth2TipsResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/configurations/oauth2": {
+        "/v1/configurations/slack": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get Oauth2 configuration",
+                "description": "get Slack configuration",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取 Oauth2 配置",
-                "operationId": "getOauth2ConfigurationV1",
+                "summary": "获取 Slack 配置",
+                "operationId": "getSlackConfigurationV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOauth2ConfigurationResV1"
+                            "$ref": "#/definitions/v1.GetSlackConfigurationResV1"
                         }
                     }
                 }
@@ -1058,23 +1723,23 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update Oauth2 configuration",
+                "description": "update Slack configuration",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "configuration"
                 ],
-                "summary": "修改 Oauth2 配置",
-                "operationId": "updateOauth2ConfigurationV1",
+                "summary": "添加 Slack 配置",
+                "operationId": "updateSlackConfigurationV1",
                 "parameters": [
                     {
-                        "description": "update Oauth2 configuration req",
-                        "name": "conf",
+                        "description": "update Slack configuration req",
+                        "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.Oauth2ConfigurationReqV1"
+                            "$ref": "#/definitions/v1.UpdateSlackConfigurationReqV1"
                         }
                     }
                 ],
@@ -1088,19 +1753,24 @@ var doc = `{
                 }
             }
         },
-        "/v1/configurations/oauth2/tips": {
-            "get": {
-                "description": "get Oauth2 tips",
+        "/v1/configurations/slack/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test Slack configuration, the test message is sent even if the Slack notify is disabled",
                 "tags": [
                     "configuration"
                 ],
-                "summary": "获取 Oauth2 基本信息",
-                "operationId": "getOauth2Tips",
+                "summary": "测试 Slack 配置",
+                "operationId": "testSlackConfigurationV1",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOauth2TipsResV1"
+                            "$ref": "#/definitions/v1.TestChatConfigurationResV1"
                         }
                     }
                 }
@@ -3188,11 +3858,22 @@ var doc = `{
                         "description": "select unique (fingerprint and audit result) for task sql",
                         "name": "no_duplicate",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "export format, default is csv",
+                        "name": "export_format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "sql report csv file",
+                        "description": "sql report file",
                         "schema": {
                             "type": "file"
                         }
@@ -3436,6 +4117,67 @@ var doc = `{
                 }
             }
         },
+        "/v1/user/notify_setting": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get notify setting of current user, the event which is not in preferences is received on all channels",
+                "tags": [
+                    "user"
+                ],
+                "summary": "获取个人通知设置",
+                "operationId": "getCurrentUserNotifySettingV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetCurrentUserNotifySettingResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update notify setting of current user, the preferences and subscribed instances are overridden if they are set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "更新个人通知设置",
+                "operationId": "updateCurrentUserNotifySettingV1",
+                "parameters": [
+                    {
+                        "description": "update notify setting",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateCurrentUserNotifySettingReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/user/password": {
             "put": {
                 "security": [
@@ -4914,6 +5656,30 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditPlanNotifyRecordResV1": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "send_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "failed"
+                    ]
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.AuditPlanParamReqV1": {
             "type": "object",
             "properties": {
@@ -4968,9 +5734,18 @@ var doc = `{
                     "type": "string",
                     "example": "RFC3339"
                 },
+                "new_finding_count": {
+                    "type": "integer"
+                },
                 "pass_rate": {
                     "type": "number"
                 },
+                "persisting_finding_count": {
+                    "type": "integer"
+                },
+                "resolved_finding_count": {
+                    "type": "integer"
+                },
                 "score": {
                     "type": "integer"
                 }
@@ -5002,35 +5777,117 @@ var doc = `{
             "properties": {
                 "audit_plan_cron": {
                     "type": "string",
-                    "example": "0 */2 * * *"
+                    "example": "0 */2 * * *"
+                },
+                "audit_plan_db_type": {
+                    "type": "string",
+                    "example": "mysql"
+                },
+                "audit_plan_health": {
+                    "type": "string",
+                    "enum": [
+                        "healthy",
+                        "unhealthy",
+                        "unknown"
+                    ]
+                },
+                "audit_plan_instance_database": {
+                    "type": "string",
+                    "example": "app1"
+                },
+                "audit_plan_instance_name": {
+                    "type": "string",
+                    "example": "test_mysql"
+                },
+                "audit_plan_last_run_at": {
+                    "type": "string",
+                    "example": "RFC3339"
+                },
+                "audit_plan_last_run_status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "failed",
+                        ""
+                    ]
+                },
+                "audit_plan_meta": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditPlanMetaV1"
+                },
+                "audit_plan_name": {
+                    "type": "string",
+                    "example": "audit_for_java_app1"
+                },
+                "audit_plan_status": {
+                    "type": "string",
+                    "enum": [
+                        "enabled",
+                        "disabled"
+                    ]
+                },
+                "audit_plan_token": {
+                    "type": "string",
+                    "example": "it's a JWT Token for scanner"
+                },
+                "rule_template_name": {
+                    "type": "string",
+                    "example": "default_MySQL"
+                }
+            }
+        },
+        "v1.AuditPlanRunResV1": {
+            "type": "object",
+            "properties": {
+                "audit_plan_report_id": {
+                    "type": "string",
+                    "example": "1"
                 },
-                "audit_plan_db_type": {
-                    "type": "string",
-                    "example": "mysql"
+                "audit_plan_run_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "audit_plan_instance_database": {
+                "audited_sql_count": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "end_at": {
                     "type": "string",
-                    "example": "app1"
+                    "example": "RFC3339"
                 },
-                "audit_plan_instance_name": {
+                "error_message": {
+                    "type": "string"
+                },
+                "run_type": {
                     "type": "string",
-                    "example": "test_mysql"
+                    "enum": [
+                        "audit",
+                        "collect"
+                    ]
                 },
-                "audit_plan_meta": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.AuditPlanMetaV1"
+                "sql_count": {
+                    "type": "integer"
                 },
-                "audit_plan_name": {
+                "start_at": {
                     "type": "string",
-                    "example": "audit_for_java_app1"
+                    "example": "RFC3339"
                 },
-                "audit_plan_token": {
+                "status": {
                     "type": "string",
-                    "example": "it's a JWT Token for scanner"
+                    "enum": [
+                        "success",
+                        "failed"
+                    ]
                 },
-                "rule_template_name": {
+                "trigger_type": {
                     "type": "string",
-                    "example": "default_MySQL"
+                    "enum": [
+                        "schedule",
+                        "manual"
+                    ]
                 }
             }
         },
@@ -5053,9 +5910,33 @@ var doc = `{
                     "type": "string",
                     "example": "RFC3339"
                 },
+                "audit_plan_sql_mapper_namespace": {
+                    "type": "string",
+                    "example": "com.example.UserMapper"
+                },
+                "audit_plan_sql_mapper_statement_id": {
+                    "type": "string",
+                    "example": "getUser"
+                },
+                "audit_plan_sql_query_time_avg": {
+                    "type": "string",
+                    "example": "0.56"
+                },
+                "audit_plan_sql_query_time_max": {
+                    "type": "string",
+                    "example": "1.2"
+                },
                 "audit_plan_sql_schema": {
                     "type": "string",
                     "example": "db1"
+                },
+                "audit_plan_sql_source_file": {
+                    "type": "string",
+                    "example": "mapper/UserMapper.xml"
+                },
+                "audit_plan_sql_source_line": {
+                    "type": "string",
+                    "example": "12"
                 }
             }
         },
@@ -5607,6 +6488,26 @@ var doc = `{
                 }
             }
         },
+        "v1.CurrentUserNotifySettingResV1": {
+            "type": "object",
+            "properties": {
+                "enable_daily_digest": {
+                    "type": "boolean"
+                },
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.NotifyPreferenceV1"
+                    }
+                },
+                "subscribed_instance_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "v1.DashboardResV1": {
             "type": "object",
             "properties": {
@@ -5616,6 +6517,20 @@ var doc = `{
                 }
             }
         },
+        "v1.DingTalkConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "enable_ding_talk_notify": {
+                    "type": "boolean"
+                },
+                "is_secret_set": {
+                    "type": "boolean"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.DirectAuditReqV1": {
             "type": "object",
             "properties": {
@@ -5647,9 +6562,44 @@ var doc = `{
                 }
             }
         },
+        "v1.DriverCapabilitiesResV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string"
+                },
+                "explain_formats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "offline_audit": {
+                    "type": "boolean"
+                },
+                "rollback": {
+                    "type": "boolean"
+                },
+                "sql_analysis": {
+                    "type": "boolean"
+                },
+                "sql_query": {
+                    "type": "boolean"
+                },
+                "transactional_ddl": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v1.DriversResV1": {
             "type": "object",
             "properties": {
+                "driver_capabilities_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DriverCapabilitiesResV1"
+                    }
+                },
                 "driver_name_list": {
                     "type": "array",
                     "items": {
@@ -5678,6 +6628,20 @@ var doc = `{
                 }
             }
         },
+        "v1.FeishuConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "enable_feishu_notify": {
+                    "type": "boolean"
+                },
+                "is_secret_set": {
+                    "type": "boolean"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.FullSyncAuditPlanSQLsReqV1": {
             "type": "object",
             "properties": {
@@ -5734,12 +6698,19 @@ var doc = `{
                 "enable_web_hook_notify": {
                     "type": "boolean"
                 },
+                "last_web_hook_delivery": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditPlanNotifyRecordResV1"
+                },
                 "notify_interval": {
                     "type": "integer"
                 },
                 "notify_level": {
                     "type": "string"
                 },
+                "notify_only_new_findings": {
+                    "type": "boolean"
+                },
                 "web_hook_template": {
                     "type": "string"
                 },
@@ -5843,6 +6814,28 @@ var doc = `{
                 }
             }
         },
+        "v1.GetAuditPlanRunsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanRunResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.GetAuditPlanSQLsResV1": {
             "type": "object",
             "properties": {
@@ -5965,6 +6958,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetCurrentUserNotifySettingResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.CurrentUserNotifySettingResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetDashboardResV1": {
             "type": "object",
             "properties": {
@@ -5982,6 +6992,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetDingTalkConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.DingTalkConfigurationResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetDriversResV1": {
             "type": "object",
             "properties": {
@@ -5999,6 +7026,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetFeishuConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.FeishuConfigurationResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetInstanceAdditionalMetasResV1": {
             "type": "object",
             "properties": {
@@ -6231,6 +7275,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetMattermostConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.MattermostConfigurationResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetOauth2ConfigurationResDataV1": {
             "type": "object",
             "properties": {
@@ -6286,18 +7347,73 @@ var doc = `{
                 }
             }
         },
-        "v1.GetOauth2TipsResDataV1": {
-            "type": "object",
-            "properties": {
-                "enable_oauth2": {
-                    "type": "boolean"
-                },
-                "login_tip": {
-                    "type": "string"
-                }
-            }
-        },
-        "v1.GetOauth2TipsResV1": {
+        "v1.GetOauth2TipsResDataV1": {
+            "type": "object",
+            "properties": {
+                "enable_oauth2": {
+                    "type": "boolean"
+                },
+                "login_tip": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.GetOauth2TipsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.GetOauth2TipsResDataV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetOperationsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.OperationResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetPluginPoolsStatsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PluginPoolStatsResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetPluginVersionsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -6305,8 +7421,10 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.GetOauth2TipsResDataV1"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PluginVersionResV1"
+                    }
                 },
                 "message": {
                     "type": "string",
@@ -6314,7 +7432,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetOperationsResV1": {
+        "v1.GetPluginsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -6324,7 +7442,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.OperationResV1"
+                        "$ref": "#/definitions/v1.PluginResV1"
                     }
                 },
                 "message": {
@@ -6598,6 +7716,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetSlackConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SlackConfigurationResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetSqlExplainReqV1": {
             "type": "object",
             "properties": {
@@ -7064,6 +8199,23 @@ var doc = `{
                 }
             }
         },
+        "v1.InstallPluginResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.PluginResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.InstanceAdditionalMetaV1": {
             "type": "object",
             "properties": {
@@ -7407,6 +8559,52 @@ var doc = `{
                 }
             }
         },
+        "v1.MattermostConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "enable_mattermost_notify": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.NotifyPreferenceV1": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "email",
+                            "slack",
+                            "ding_talk",
+                            "feishu",
+                            "mattermost"
+                        ]
+                    }
+                },
+                "event_type": {
+                    "type": "string",
+                    "enum": [
+                        "workflow_create",
+                        "workflow_approve",
+                        "workflow_reject",
+                        "workflow_execute_success",
+                        "workflow_execute_fail",
+                        "audit_plan_report"
+                    ]
+                }
+            }
+        },
         "v1.Oauth2ConfigurationReqV1": {
             "type": "object",
             "properties": {
@@ -7494,6 +8692,97 @@ var doc = `{
                 }
             }
         },
+        "v1.PluginPoolStatsResV1": {
+            "type": "object",
+            "properties": {
+                "acquire_failed": {
+                    "type": "integer"
+                },
+                "acquired": {
+                    "type": "integer"
+                },
+                "idle": {
+                    "type": "integer"
+                },
+                "in_use": {
+                    "type": "integer"
+                },
+                "plugin_name": {
+                    "type": "string"
+                },
+                "restarted": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "started": {
+                    "type": "integer"
+                },
+                "waited": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.PluginResV1": {
+            "type": "object",
+            "properties": {
+                "capabilities_declared": {
+                    "description": "CapabilitiesDeclared is false for the old plugin, its capabilities are\nguessed by SQLE.",
+                    "type": "boolean"
+                },
+                "checksum": {
+                    "type": "string"
+                },
+                "min_sqle_version": {
+                    "type": "string"
+                },
+                "plugin_name": {
+                    "type": "string"
+                },
+                "rule_count": {
+                    "type": "integer"
+                },
+                "support_analysis": {
+                    "type": "boolean"
+                },
+                "support_query": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.PluginVersionResV1": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "startup",
+                        "install",
+                        "reload",
+                        "unload"
+                    ]
+                },
+                "checksum": {
+                    "type": "string"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "operator_user_name": {
+                    "type": "string"
+                },
+                "rule_count": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.PrepareSQLQueryReqV1": {
             "type": "object",
             "properties": {
@@ -7839,9 +9128,23 @@ var doc = `{
                 }
             }
         },
+        "v1.SlackConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "enable_slack_notify": {
+                    "type": "boolean"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SystemVariablesResV1": {
             "type": "object",
             "properties": {
+                "url": {
+                    "type": "string"
+                },
                 "workflow_expired_hours": {
                     "type": "integer"
                 }
@@ -7939,6 +9242,11 @@ var doc = `{
                 },
                 "send_error_message": {
                     "type": "string"
+                },
+                "web_hook_delivery": {
+                    "description": "WebHookDelivery is the delivery result of web hook if web hook notify is enabled.",
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditPlanNotifyRecordResV1"
                 }
             }
         },
@@ -7959,6 +9267,34 @@ var doc = `{
                 }
             }
         },
+        "v1.TestChatConfigurationResDataV1": {
+            "type": "object",
+            "properties": {
+                "is_message_send_normal": {
+                    "type": "boolean"
+                },
+                "send_error_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TestChatConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TestChatConfigurationResDataV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.TestSMTPConfigurationReqV1": {
             "type": "object",
             "properties": {
@@ -8093,6 +9429,10 @@ var doc = `{
                         "error"
                     ]
                 },
+                "notify_only_new_findings": {
+                    "description": "NotifyOnlyNewFindings notifies only for the findings which are not in the previous report.",
+                    "type": "boolean"
+                },
                 "web_hook_template": {
                     "type": "string"
                 },
@@ -8157,6 +9497,26 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateCurrentUserNotifySettingReqV1": {
+            "type": "object",
+            "properties": {
+                "enable_daily_digest": {
+                    "type": "boolean"
+                },
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.NotifyPreferenceV1"
+                    }
+                },
+                "subscribed_instance_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "v1.UpdateCurrentUserPasswordReqV1": {
             "type": "object",
             "properties": {
@@ -8180,6 +9540,34 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateDingTalkConfigurationReqV1": {
+            "type": "object",
+            "properties": {
+                "enable_ding_talk_notify": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UpdateFeishuConfigurationReqV1": {
+            "type": "object",
+            "properties": {
+                "enable_feishu_notify": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateInstanceReqV1": {
             "type": "object",
             "properties": {
@@ -8240,6 +9628,23 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateMattermostConfigurationReqV1": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "enable_mattermost_notify": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateOtherUserPasswordReqV1": {
             "type": "object",
             "properties": {
@@ -8315,9 +9720,24 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateSlackConfigurationReqV1": {
+            "type": "object",
+            "properties": {
+                "enable_slack_notify": {
+                    "type": "boolean"
+                },
+                "web_hook_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateSystemVariablesReqV1": {
             "type": "object",
             "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "http://10.186.61.32:10000"
+                },
                 "workflow_expired_hours": {
                     "type": "integer",
                     "example": 720
@@ -9014,6 +10434,23 @@ var doc = `{
                     "type": "string",
                     "example": "same format as task audit result"
                 },
+                "audit_plan_report_sql_finding_status": {
+                    "description": "FindingStatus compares the SQL with the previous report, it's empty if the SQL passes the audit.",
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "persisting",
+                        ""
+                    ]
+                },
+                "audit_plan_report_sql_fingerprint": {
+                    "type": "string",
+                    "example": "select * from t1 where id = ?"
+                },
+                "audit_plan_report_sql_location": {
+                    "type": "string",
+                    "example": "mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)"
+                },
                 "number": {
                     "type": "integer",
                     "example": 1
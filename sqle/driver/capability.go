@@ -0,0 +1,179 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/actiontech/sqle/sqle/driver/proto"
+)
+
+const (
+	ExplainFormatTraditional = "traditional"
+	ExplainFormatJSON        = "json"
+)
+
+// Capabilities is declared by the driver, SQLE enables the features of the
+// instance type based on it.
+type Capabilities struct {
+	// Rollback means the driver generates rollback SQL.
+	Rollback bool
+	// TransactionalDDL means DDL can be executed in a transaction with DML.
+	TransactionalDDL bool
+	// ExplainFormats is the formats of the execution plan the driver supports.
+	ExplainFormats []string
+	// OfflineAudit means the driver audits SQL without connecting to instance.
+	OfflineAudit bool
+	// Query and Analysis mean the query driver and the analysis driver exist.
+	Query    bool
+	Analysis bool
+}
+
+// CapabilitiesRegisterer is the optional interface of Registerer. The plugin
+// which implements it declares its capabilities and the minimum version of
+// SQLE it requires, the capabilities of the others are guessed by SQLE.
+type CapabilitiesRegisterer interface {
+	Capabilities() Capabilities
+
+	// MinSQLEVersion returns the minimum version of SQLE, such as "1.2209.0".
+	// Empty means no requirement.
+	MinSQLEVersion() string
+}
+
+// guessedCapabilities is used for the driver which doesn't declare its
+// capabilities, it keeps the features enabled before capabilities are
+// declared.
+var guessedCapabilities = Capabilities{
+	Rollback:     true,
+	OfflineAudit: true,
+}
+
+var (
+	// capabilities store the declared capabilities for each driver.
+	capabilities   = make(map[string]Capabilities)
+	capabilitiesMu sync.RWMutex
+)
+
+// RegisterCapabilities declares the capabilities of the built-in driver, it
+// should be called with RegisterAuditDriver.
+func RegisterCapabilities(name string, c Capabilities) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilities[name] = c
+}
+
+// setCapabilities sets the capabilities of the plugin, nil means they are not
+// declared.
+func setCapabilities(name string, c *Capabilities) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	if c == nil {
+		delete(capabilities, name)
+		return
+	}
+	capabilities[name] = *c
+}
+
+// GetCapabilities returns the capabilities of the driver, it returns false if
+// the driver doesn't exist.
+func GetCapabilities(name string) (Capabilities, bool) {
+	if !hasAuditDriver(name) {
+		return Capabilities{}, false
+	}
+	capabilitiesMu.RLock()
+	c, declared := capabilities[name]
+	capabilitiesMu.RUnlock()
+	if !declared {
+		c = guessedCapabilities
+	}
+	// the query driver and the analysis driver are checked when the plugin is
+	// loaded, so they are always the real ones.
+	c.Query = hasQueryDriver(name)
+	c.Analysis = hasAnalysisDriver(name)
+	return c, true
+}
+
+// AllCapabilities returns the capabilities of all drivers.
+func AllCapabilities() map[string] /*driver name*/ Capabilities {
+	all := map[string]Capabilities{}
+	for _, name := range AllDrivers() {
+		if c, exist := GetCapabilities(name); exist {
+			all[name] = c
+		}
+	}
+	return all
+}
+
+func convertCapabilitiesFromProto(c *proto.Capabilities) *Capabilities {
+	if c == nil {
+		return nil
+	}
+	return &Capabilities{
+		Rollback:         c.GetRollback(),
+		TransactionalDDL: c.GetTransactionalDDL(),
+		ExplainFormats:   c.GetExplainFormats(),
+		OfflineAudit:     c.GetOfflineAudit(),
+		Query:            c.GetQuery(),
+		Analysis:         c.GetAnalysis(),
+	}
+}
+
+func convertCapabilitiesToProto(c Capabilities) *proto.Capabilities {
+	return &proto.Capabilities{
+		Rollback:         c.Rollback,
+		TransactionalDDL: c.TransactionalDDL,
+		ExplainFormats:   c.ExplainFormats,
+		OfflineAudit:     c.OfflineAudit,
+		Query:            c.Query,
+		Analysis:         c.Analysis,
+	}
+}
+
+var versionRegexp = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+func parseVersion(version string) []int {
+	match := versionRegexp.FindStringSubmatch(version)
+	if match == nil {
+		return nil
+	}
+	segments := []int{}
+	for _, s := range match[1:] {
+		if s == "" {
+			break
+		}
+		i, _ := strconv.Atoi(s)
+		segments = append(segments, i)
+	}
+	return segments
+}
+
+// IsValidSQLEVersion returns true if the minimum SQLE version declared by
+// plugin can be parsed by SQLE, such as "1.2209.0" and "v1.2209".
+func IsValidSQLEVersion(version string) bool {
+	return parseVersion(version) != nil
+}
+
+// checkSQLEVersion checks whether the current version of SQLE satisfies the
+// version required by plugin. The check is skipped if the current version is
+// unknown, e.g. the development build, and the segments which are unknown in
+// current version are not compared, e.g. "1.2209.x".
+func checkSQLEVersion(required, current string) error {
+	if required == "" {
+		return nil
+	}
+	requiredSegments := parseVersion(required)
+	if requiredSegments == nil {
+		return fmt.Errorf("invalid minimum SQLE version %v", required)
+	}
+	currentSegments := parseVersion(current)
+	for i := 0; i < len(requiredSegments) && i < len(currentSegments); i++ {
+		if currentSegments[i] > requiredSegments[i] {
+			return nil
+		}
+		if currentSegments[i] < requiredSegments[i] {
+			return fmt.Errorf("plugin requires SQLE %v or later, current version is %v", required, current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/proto"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSQLEVersion(t *testing.T) {
+	for _, c := range []struct {
+		required string
+		current  string
+		ok       bool
+	}{
+		{"", "v1.2209.0", true},
+		{"1.2209.0", "v1.2209.0", true},
+		{"1.2209.0", "v1.2210.0", true},
+		{"1.2209.1", "v2.2201.0", true},
+		{"1.2209.1", "v1.2209.0", false},
+		{"1.2210.0", "release-1.2209.x-ee 8c0a6f2", false},
+		{"1.2209.3", "release-1.2209.x-ee 8c0a6f2", true},
+		{"1.2209.0", "main-ce 8c0a6f2", true},
+		{"1.2209.0", "", true},
+	} {
+		err := checkSQLEVersion(c.required, c.current)
+		assert.Equal(t, c.ok, err == nil, "required %v, current %v", c.required, c.current)
+	}
+	assert.Error(t, checkSQLEVersion("latest", "v1.2209.0"))
+	assert.True(t, IsValidSQLEVersion("v1.2209"))
+	assert.False(t, IsValidSQLEVersion("latest"))
+}
+
+func TestGetCapabilities(t *testing.T) {
+	_, exist := GetCapabilities("not_exist_driver")
+	assert.False(t, exist)
+
+	meta := &pluginMeta{
+		name:         "test_capabilities",
+		supportQuery: true,
+	}
+	setPluginDrivers(meta, driverManagerHandler{meta: meta})
+	defer unsetPluginDrivers(meta.name)
+
+	// the capabilities of old plugin are guessed.
+	c, exist := GetCapabilities(meta.name)
+	assert.True(t, exist)
+	assert.Equal(t, Capabilities{Rollback: true, OfflineAudit: true, Query: true}, c)
+
+	meta.capabilities = &Capabilities{
+		TransactionalDDL: true,
+		ExplainFormats:   []string{ExplainFormatJSON},
+		Query:            true,
+	}
+	setPluginDrivers(meta, driverManagerHandler{meta: meta})
+	c, exist = GetCapabilities(meta.name)
+	assert.True(t, exist)
+	assert.Equal(t, Capabilities{TransactionalDDL: true, ExplainFormats: []string{ExplainFormatJSON}, Query: true}, c)
+	assert.Contains(t, AllCapabilities(), meta.name)
+
+	unsetPluginDrivers(meta.name)
+	_, exist = GetCapabilities(meta.name)
+	assert.False(t, exist)
+	assert.NotContains(t, AllCapabilities(), meta.name)
+}
+
+type testRegisterer struct{}
+
+func (r *testRegisterer) Name() string                    { return "test" }
+func (r *testRegisterer) Rules() []*Rule                  { return nil }
+func (r *testRegisterer) AdditionalParams() params.Params { return nil }
+
+type testCapabilitiesRegisterer struct {
+	testRegisterer
+}
+
+func (r *testCapabilitiesRegisterer) Capabilities() Capabilities {
+	return Capabilities{Rollback: true, ExplainFormats: []string{ExplainFormatTraditional}}
+}
+
+func (r *testCapabilitiesRegisterer) MinSQLEVersion() string {
+	return "1.2209.0"
+}
+
+func TestMetasCapabilities(t *testing.T) {
+	srv := &auditDriverGRPCServer{r: &testRegisterer{}, supportQuery: true}
+	resp, err := srv.Metas(context.TODO(), &proto.Empty{})
+	assert.NoError(t, err)
+	assert.Nil(t, resp.GetCapabilities())
+	assert.Nil(t, convertCapabilitiesFromProto(resp.GetCapabilities()))
+
+	srv = &auditDriverGRPCServer{r: &testCapabilitiesRegisterer{}, supportQuery: true}
+	resp, err = srv.Metas(context.TODO(), &proto.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2209.0", resp.GetMinSQLEVersion())
+	assert.Equal(t, &Capabilities{
+		Rollback:       true,
+		ExplainFormats: []string{ExplainFormatTraditional},
+		Query:          true,
+	}, convertCapabilitiesFromProto(resp.GetCapabilities()))
+}
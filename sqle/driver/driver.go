@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/driver/proto"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/pkg/params"
@@ -57,53 +58,123 @@ func (p *PluginClient) Client() (goPlugin.ClientProtocol, error) {
 }
 
 func (p *PluginClient) RegisterDrivers(c *PluginClient) (pluginName string, err error) {
-	gRPCClient, err := c.Client()
+	meta, err := c.readMeta()
 	if err != nil {
 		return "", err
 	}
+	registerPluginDrivers(meta)
+	return meta.name, nil
+}
 
-	pluginName, version, drvClient, err := registerAuditDriver(gRPCClient)
+// readMeta starts the plugin process to read the metas, the process is killed
+// after reading.
+func (p *PluginClient) readMeta() (*pluginMeta, error) {
+	gRPCClient, err := p.Client()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer p.Kill()
 
-	if err := registerQueryDriver(pluginName, gRPCClient); err != nil {
-		log.Logger().WithFields(logrus.Fields{
-			"plugin_name": pluginName,
-			"plugin_type": PluginNameQueryDriver,
-		}).Infof("plugin not exist or failed to load. err: %v", err)
+	meta, drvClient, err := loadAuditDriverMeta(gRPCClient)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := registerAnalysisDriver(pluginName, gRPCClient); err != nil {
-		log.Logger().WithFields(logrus.Fields{
-			"plugin_name": pluginName,
-			"plugin_type": PluginNameQueryDriver,
-		}).Infof("plugin not exist or failed to load. err: %v", err)
+	if err := checkSQLEVersion(meta.minSQLEVersion, config.Version); err != nil {
+		return nil, err
+	}
+
+	if meta.capabilities != nil {
+		// the declared drivers must be loaded successfully.
+		if meta.capabilities.Query {
+			if err := checkQueryDriver(gRPCClient); err != nil {
+				return nil, fmt.Errorf("load declared query driver failed: %v", err)
+			}
+			meta.supportQuery = true
+		}
+		if meta.capabilities.Analysis {
+			if err := checkAnalysisDriver(gRPCClient); err != nil {
+				return nil, fmt.Errorf("load declared analysis driver failed: %v", err)
+			}
+			meta.supportAnalysis = true
+		}
+	} else {
+		// the plugin doesn't declare capabilities, try to load the drivers.
+		if err := checkQueryDriver(gRPCClient); err != nil {
+			log.Logger().WithFields(logrus.Fields{
+				"plugin_name": meta.name,
+				"plugin_type": PluginNameQueryDriver,
+			}).Infof("plugin not exist or failed to load. err: %v", err)
+		} else {
+			meta.supportQuery = true
+		}
+
+		if err := checkAnalysisDriver(gRPCClient); err != nil {
+			log.Logger().WithFields(logrus.Fields{
+				"plugin_name": meta.name,
+				"plugin_type": PluginNameAnalysisDriver,
+			}).Infof("plugin not exist or failed to load. err: %v", err)
+		} else {
+			meta.supportAnalysis = true
+		}
 	}
 
 	// to be compatible with old plugins
 	// the old plugin will panic if it call close() here
-	if version >= DefaultPluginVersion {
+	if meta.version >= DefaultPluginVersion {
 		_, err = drvClient.Close(context.TODO(), &proto.Empty{})
 		if err != nil {
 			log.Logger().Errorf("gracefully close plugins failed, will force kill the sub progress. err: %v", err)
 		}
 	}
-	c.Kill()
-	return pluginName, nil
+
+	meta.path = p.path
+	meta.checksum, err = fileChecksum(p.path)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
 }
 
 func (p *PluginClient) resetClient() {
 	if p.c != nil {
 		p.c.Kill()
 	}
-	p.c = goPlugin.NewClient(&goPlugin.ClientConfig{
+	p.c = p.newGoPluginClient()
+}
+
+func (p *PluginClient) newGoPluginClient() *goPlugin.Client {
+	return goPlugin.NewClient(NewPluginClientConfig(p.path))
+}
+
+// NewPluginClientConfig returns the config which SQLE starts the plugin binary
+// with, it's used by the tools which check the plugin outside SQLE too.
+func NewPluginClientConfig(path string) *goPlugin.ClientConfig {
+	return &goPlugin.ClientConfig{
 		HandshakeConfig:  handshakeConfig,
 		VersionedPlugins: defaultPluginSet,
-		Cmd:              exec.Command(p.path),
+		Cmd:              exec.Command(path),
 		AllowedProtocols: []goPlugin.Protocol{goPlugin.ProtocolGRPC},
 		GRPCDialOptions:  SQLEGRPCDialOptions,
-	})
+	}
+}
+
+// startProcess starts a new plugin process with the resource limit for the
+// process pool.
+func (p *PluginClient) startProcess(pluginName string, limit PluginResourceLimit) (*pluginProcess, error) {
+	cfg := NewPluginClientConfig(p.path)
+	c := goPlugin.NewClient(cfg)
+	protocol, err := c.Client()
+	if err != nil {
+		c.Kill()
+		return nil, err
+	}
+	cleanup, err := limitPluginProcess(pluginName, cfg.Cmd.Process.Pid, limit)
+	if err != nil {
+		c.Kill()
+		return nil, err
+	}
+	return &pluginProcess{client: c, protocol: protocol, cleanup: cleanup}, nil
 }
 
 var SQLEGRPCDialOptions = []grpc.DialOption{}
@@ -129,53 +200,94 @@ var driverManagers = make(map[string]driverManagerHandler)
 type driverManagerHandler struct {
 	pluginClient         *PluginClient
 	newDriverManagerFunc newDriverManagerHandler
+	// pool and meta are nil for the built-in driver.
+	pool *pluginPool
+	meta *pluginMeta
 }
 
 type newDriverManagerHandler func(log *logrus.Entry, dbType string, config *Config, client *PluginClient) (DriverManager, error)
 
-func RegisterDriverFromClient(client *PluginClient) error {
-	pluginName, err := client.RegisterDrivers(client)
+func RegisterDriverFromClient(client *PluginClient, poolCfg *PluginPoolConfig) error {
+	meta, err := client.readMeta()
 	if err != nil {
 		return fmt.Errorf("register plugin failed: %v", err)
 	}
+	registerPluginDrivers(meta)
+
+	h := newPluginDriverManagerHandler(client, meta, poolCfg)
+	registerDriverManger(meta.name, h)
+	go h.pool.run()
+	return nil
+}
 
+func newPluginDriverManagerHandler(client *PluginClient, meta *pluginMeta, poolCfg *PluginPoolConfig) driverManagerHandler {
+	poolCfg = poolCfg.withDefault()
+	pool := newPluginPool(meta.name, poolCfg, func() (*pluginProcess, error) {
+		return client.startProcess(meta.name, poolCfg.ResourceLimit)
+	})
 	handler := func(log *logrus.Entry, dbType string, config *Config, client *PluginClient) (DriverManager, error) {
-		gRPCClient, err := client.Client()
+		proc, err := pool.acquire()
 		if err != nil {
 			return nil, err
 		}
+		// the process is put back to the pool after the driver manager is closed.
 		closeCh := make(chan struct{})
 		go func() {
 			<-closeCh
-			client.Kill()
+			pool.release(proc)
 		}()
+		// the process which fails to init may keep the partial state, so
+		// it's killed instead of being put back.
+		discard := func(err error) {
+			log.Warnf("init plugin driver failed, kill the process: %v", err)
+			proc.kill()
+			close(closeCh)
+		}
 
 		drvMgr := &PluginDriverManager{
-			grpcClient:    gRPCClient,
+			grpcClient:    proc.protocol,
 			pluginCloseCh: closeCh,
 			config:        config,
 			dbType:        dbType,
 			log:           log,
+			caller: &pluginRPCCaller{
+				pluginName: meta.name,
+				timeout:    poolCfg.RPCTimeout,
+				proc:       proc,
+			},
 		}
 
 		if err = drvMgr.initAuditDriver(); err != nil {
+			discard(err)
 			return nil, err
 		}
 		if err = drvMgr.initSQLQueryDriver(); err != nil {
+			discard(err)
 			return nil, err
 		}
 		if err = drvMgr.initAnalysisDriver(); err != nil {
+			discard(err)
 			return nil, err
 		}
 
 		return drvMgr, nil
 	}
-
-	RegisterDriverManger(client, pluginName, handler)
-	return nil
+	return driverManagerHandler{
+		pluginClient:         client,
+		newDriverManagerFunc: handler,
+		pool:                 pool,
+		meta:                 meta,
+	}
 }
 
 func RegisterDriverManger(client *PluginClient, pluginName string, handler newDriverManagerHandler) {
+	registerDriverManger(pluginName, driverManagerHandler{
+		pluginClient:         client,
+		newDriverManagerFunc: handler,
+	})
+}
+
+func registerDriverManger(pluginName string, h driverManagerHandler) {
 	driverManagerMu.RLock()
 	_, exist := driverManagers[pluginName]
 	driverManagerMu.RUnlock()
@@ -184,10 +296,7 @@ func RegisterDriverManger(client *PluginClient, pluginName string, handler newDr
 	}
 
 	driverManagerMu.Lock()
-	driverManagers[pluginName] = driverManagerHandler{
-		pluginClient:         client,
-		newDriverManagerFunc: handler,
-	}
+	driverManagers[pluginName] = h
 	driverManagerMu.Unlock()
 }
 
@@ -206,6 +315,7 @@ type PluginDriverManager struct {
 	dbType               string
 	log                  *logrus.Entry
 	config               *Config
+	caller               *pluginRPCCaller
 	auditPluginClient    proto.DriverClient
 	queryPluginClient    proto.QueryDriverClient
 	analysisPluginClient proto.AnalysisDriverClient
@@ -215,12 +325,11 @@ func (d *PluginDriverManager) GetAuditDriver() (Driver, error) {
 	if d.auditPluginClient == nil {
 		return nil, fmt.Errorf("audit driver type %v is not supported", d.dbType)
 	}
-	return &driverImpl{d.auditPluginClient, d.pluginCloseCh}, nil
+	return &driverImpl{plugin: d.auditPluginClient, driverQuitCh: d.pluginCloseCh, caller: d.caller}, nil
 }
 
 func (d *PluginDriverManager) initAuditDriver() error {
-	_, exist := auditDrivers[d.dbType]
-	if !exist {
+	if !hasAuditDriver(d.dbType) {
 		return nil
 	}
 
@@ -258,7 +367,10 @@ func (d *PluginDriverManager) initAuditDriver() error {
 		}
 	}
 
-	_, err = pluginInst.Init(context.TODO(), initRequest)
+	err = d.caller.call(context.TODO(), "init audit driver", pluginRPCAudit, func(ctx context.Context) error {
+		_, err := pluginInst.Init(ctx, initRequest)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("init audit driver failed: %v", err)
 	}
@@ -270,12 +382,11 @@ func (d *PluginDriverManager) GetSQLQueryDriver() (SQLQueryDriver, error) {
 	if d.queryPluginClient == nil {
 		return nil, fmt.Errorf("SQL query driver type %v is not supported", d.dbType)
 	}
-	return &queryDriverImpl{d.queryPluginClient}, nil
+	return &queryDriverImpl{plugin: d.queryPluginClient, caller: d.caller}, nil
 }
 
 func (d *PluginDriverManager) initSQLQueryDriver() error {
-	_, exist := queryDrivers[d.dbType]
-	if !exist {
+	if !hasQueryDriver(d.dbType) {
 		return nil
 	}
 
@@ -306,7 +417,10 @@ func (d *PluginDriverManager) initSQLQueryDriver() error {
 			Database: d.config.DSN.DatabaseName,
 		}
 	}
-	_, err = pluginInst.Init(context.TODO(), initRequest)
+	err = d.caller.call(context.TODO(), "init SQL query driver", pluginRPCQuery, func(ctx context.Context) error {
+		_, err := pluginInst.Init(ctx, initRequest)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("init SQL query driver failed: %v", err)
 	}
@@ -318,12 +432,11 @@ func (d *PluginDriverManager) GetAnalysisDriver() (AnalysisDriver, error) {
 	if d.analysisPluginClient == nil {
 		return nil, fmt.Errorf("analysis driver type %v is not supported", d.dbType)
 	}
-	return &analysisDriverImpl{d.analysisPluginClient}, nil
+	return &analysisDriverImpl{plugin: d.analysisPluginClient, caller: d.caller}, nil
 }
 
 func (d *PluginDriverManager) initAnalysisDriver() error {
-	_, exist := analysisDrivers[d.dbType]
-	if !exist {
+	if !hasAnalysisDriver(d.dbType) {
 		return nil
 	}
 
@@ -351,7 +464,10 @@ func (d *PluginDriverManager) initAnalysisDriver() error {
 			Database: d.config.DSN.DatabaseName,
 		}
 	}
-	_, err = pluginInst.Init(context.TODO(), initRequest)
+	err = d.caller.call(context.TODO(), "init analysis driver", pluginRPCExplain, func(ctx context.Context) error {
+		_, err := pluginInst.Init(ctx, initRequest)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("init analysis driver failed: %v", err)
 	}
@@ -360,26 +476,44 @@ func (d *PluginDriverManager) initAnalysisDriver() error {
 }
 
 func (d *PluginDriverManager) Close(ctx context.Context) {
-	impl := &driverImpl{d.auditPluginClient, d.pluginCloseCh}
+	impl := &driverImpl{plugin: d.auditPluginClient, driverQuitCh: d.pluginCloseCh, caller: d.caller}
 	impl.Close(ctx)
 }
 
 func NewDriverManger(log *logrus.Entry, dbType string, config *Config) (DriverManager, error) {
+	// the lock is not held while creating driver manager, because it may wait
+	// for an idle plugin process.
 	driverManagerMu.RLock()
-	defer driverManagerMu.RUnlock()
 	h, exist := driverManagers[dbType]
+	driverManagerMu.RUnlock()
 	if !exist {
 		return nil, fmt.Errorf("driver type %v is not supported", dbType)
 	}
 
-	return h.newDriverManagerFunc(log, dbType, config, h.pluginClient)
+	drvMgr, err := h.newDriverManagerFunc(log, dbType, config, h.pluginClient)
+	if err == errPluginPoolClosed {
+		// the plugin is reloaded after the handler is got, retry with the new one.
+		driverManagerMu.RLock()
+		h, exist = driverManagers[dbType]
+		driverManagerMu.RUnlock()
+		if !exist {
+			return nil, fmt.Errorf("driver type %v is not supported", dbType)
+		}
+		return h.newDriverManagerFunc(log, dbType, config, h.pluginClient)
+	}
+	return drvMgr, err
 }
 
 // InitPlugins init plugins at plugins directory. It should be called on host process.
-func InitPlugins(pluginDir string) error {
+// Each plugin has a process pool configured by poolCfg, nil means the default.
+func InitPlugins(pluginDir string, poolCfg *PluginPoolConfig) error {
 	if pluginDir == "" {
 		return nil
 	}
+	pluginManagerMu.Lock()
+	pluginPath = pluginDir
+	pluginPoolCfg = poolCfg
+	pluginManagerMu.Unlock()
 
 	// read plugin file
 	var plugins []os.FileInfo
@@ -406,7 +540,7 @@ func InitPlugins(pluginDir string) error {
 		if !testConnClient(client) {
 			return fmt.Errorf("unable to load plugin: %v", binaryPath)
 		}
-		if err := RegisterDriverFromClient(client); err != nil {
+		if err := RegisterDriverFromClient(client, poolCfg); err != nil {
 			return err
 		}
 
@@ -6,10 +6,8 @@ import (
 	"sync"
 
 	"github.com/actiontech/sqle/sqle/driver/proto"
-	"github.com/actiontech/sqle/sqle/log"
 
 	goPlugin "github.com/hashicorp/go-plugin"
-	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/status"
 )
 
@@ -119,6 +117,13 @@ type ExplainResult struct {
 var analysisDriverMu = &sync.RWMutex{}
 var analysisDrivers = make(map[string]struct{})
 
+func hasAnalysisDriver(name string) bool {
+	analysisDriverMu.RLock()
+	defer analysisDriverMu.RUnlock()
+	_, exist := analysisDrivers[name]
+	return exist
+}
+
 // RegisterAnalysisDriver like sql.RegisterAuditDriver.
 //
 // RegisterAnalysisDriver makes a database driver available by the provided driver name.
@@ -139,13 +144,18 @@ func RegisterAnalysisDriver(name string) {
 // analysisDriverImpl implement AnalysisDriver. It use for hide gRPC detail, just like DriverGRPCServer.
 type analysisDriverImpl struct {
 	plugin proto.AnalysisDriverClient
+	caller *pluginRPCCaller
 }
 
 func (a *analysisDriverImpl) ListTablesInSchema(ctx context.Context, conf *ListTablesInSchemaConf) (*ListTablesInSchemaResult, error) {
 	req := &proto.ListTablesInSchemaRequest{
 		Schema: conf.Schema,
 	}
-	res, err := a.plugin.ListTablesInSchema(ctx, req)
+	var res *proto.ListTablesInSchemaResponse
+	err := a.caller.call(ctx, "ListTablesInSchema", pluginRPCExplain, func(ctx context.Context) (err error) {
+		res, err = a.plugin.ListTablesInSchema(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +174,11 @@ func (a *analysisDriverImpl) GetTableMetaByTableName(ctx context.Context, conf *
 		Schema: conf.Schema,
 		Table:  conf.Table,
 	}
-	res, err := a.plugin.GetTableMetaByTableName(ctx, req)
+	var res *proto.GetTableMetaByTableNameResponse
+	err := a.caller.call(ctx, "GetTableMetaByTableName", pluginRPCExplain, func(ctx context.Context) (err error) {
+		res, err = a.plugin.GetTableMetaByTableName(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +230,11 @@ func (a *analysisDriverImpl) GetTableMetaBySQL(ctx context.Context, conf *GetTab
 	req := &proto.GetTableMetaBySQLRequest{
 		Sql: conf.Sql,
 	}
-	res, err := a.plugin.GetTableMetaBySQL(ctx, req)
+	var res *proto.GetTableMetaBySQLResponse
+	err := a.caller.call(ctx, "GetTableMetaBySQL", pluginRPCExplain, func(ctx context.Context) (err error) {
+		res, err = a.plugin.GetTableMetaBySQL(ctx, req)
+		return err
+	})
 	if err != nil && status.Code(err) == grpcErrSQLIsNotSupported {
 		return nil, ErrSQLIsNotSupported
 	} else if err != nil {
@@ -257,7 +275,11 @@ func (a *analysisDriverImpl) Explain(ctx context.Context, conf *ExplainConf) (*E
 	req := &proto.ExplainRequest{
 		Sql: conf.Sql,
 	}
-	res, err := a.plugin.Explain(ctx, req)
+	var res *proto.ExplainResponse
+	err := a.caller.call(ctx, "Explain", pluginRPCExplain, func(ctx context.Context) (err error) {
+		res, err = a.plugin.Explain(ctx, req)
+		return err
+	})
 	if err != nil && status.Code(err) == grpcErrSQLIsNotSupported {
 		return nil, ErrSQLIsNotSupported
 	} else if err != nil {
@@ -273,7 +295,8 @@ func (a *analysisDriverImpl) Explain(ctx context.Context, conf *ExplainConf) (*E
 	}, nil
 }
 
-func registerAnalysisDriver(pluginName string, gRPCClient goPlugin.ClientProtocol) error {
+// checkAnalysisDriver checks whether the plugin implements the analysis driver.
+func checkAnalysisDriver(gRPCClient goPlugin.ClientProtocol) error {
 	rawI, err := gRPCClient.Dispense(PluginNameAnalysisDriver)
 	if err != nil {
 		return err
@@ -283,14 +306,5 @@ func registerAnalysisDriver(pluginName string, gRPCClient goPlugin.ClientProtoco
 
 	// The test target plugin implements the AnalysisDriver plugin
 	_, err = s.Init(context.TODO(), &proto.AnalysisDriverInitRequest{})
-	if err != nil {
-		return err
-	}
-
-	RegisterAnalysisDriver(pluginName)
-	log.Logger().WithFields(logrus.Fields{
-		"plugin_name": pluginName,
-		"plugin_type": PluginNameAnalysisDriver,
-	}).Infoln("plugin inited")
-	return nil
+	return err
 }
@@ -10,12 +10,12 @@ import (
 	"sync"
 
 	"github.com/actiontech/sqle/sqle/driver/proto"
-	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/pkg/params"
 
 	goPlugin "github.com/hashicorp/go-plugin"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -143,8 +143,7 @@ func NewConfig(dsn *DSN, rules []*Rule) (*Config, error) {
 // RegisterAuditDriver makes a database driver available by the provided driver name.
 // Driver's initialize handler and audit rules register by RegisterAuditDriver.
 func RegisterAuditDriver(name string, rs []*Rule, ap params.Params) {
-	_, exist := auditDrivers[name]
-	if exist {
+	if hasAuditDriver(name) {
 		panic("duplicated driver name")
 	}
 
@@ -167,6 +166,13 @@ func RegisterAuditDriver(name string, rs []*Rule, ap params.Params) {
 	additionalParamsMu.Unlock()
 }
 
+func hasAuditDriver(name string) bool {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	_, exist := auditDrivers[name]
+	return exist
+}
+
 type DriverNotSupportedError struct {
 	DriverTyp string
 }
@@ -178,12 +184,17 @@ func (e *DriverNotSupportedError) Error() string {
 func AllRules() map[string][]*Rule {
 	rulesMu.RLock()
 	defer rulesMu.RUnlock()
-	return rules
+	// the rules of plugin may be changed at runtime, so return a copy.
+	allRules := make(map[string][]*Rule, len(rules))
+	for name, rs := range rules {
+		allRules[name] = rs
+	}
+	return allRules
 }
 
 func AllDrivers() []string {
-	rulesMu.RLock()
-	defer rulesMu.RUnlock()
+	driversMu.RLock()
+	defer driversMu.RUnlock()
 
 	driverNames := make([]string, 0, len(auditDrivers))
 	for n := range auditDrivers {
@@ -243,6 +254,36 @@ type Driver interface {
 	GenRollbackSQL(ctx context.Context, sql string) (string, string, error)
 }
 
+// BatchAuditor is the optional interface of Driver. The Driver implements it
+// audits many SQLs in one call, e.g. the plugin driver reduces the round trips
+// to the plugin process.
+type BatchAuditor interface {
+	// AuditBatch audits the SQLs in order in the same context, like calling
+	// Audit one by one. The results are in the order of sqls.
+	AuditBatch(ctx context.Context, sqls []string) ([]*AuditResult, error)
+}
+
+// AuditBatch audits the SQLs in batch if the driver implements BatchAuditor,
+// otherwise it calls Audit one by one.
+func AuditBatch(ctx context.Context, d Driver, sqls []string) ([]*AuditResult, error) {
+	if ba, ok := d.(BatchAuditor); ok {
+		return ba.AuditBatch(ctx, sqls)
+	}
+	return auditOneByOne(ctx, d, sqls)
+}
+
+func auditOneByOne(ctx context.Context, d Driver, sqls []string) ([]*AuditResult, error) {
+	results := make([]*AuditResult, 0, len(sqls))
+	for _, sql := range sqls {
+		result, err := d.Audit(ctx, sql)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // Registerer is the interface that all SQLe plugins must support.
 type Registerer interface {
 	// Name returns plugin name.
@@ -356,16 +397,29 @@ type driverImpl struct {
 
 	// driverQuitCh produce a singal for telling caller that it's time to Client.Kill() plugin process.
 	driverQuitCh chan struct{}
+
+	// batchAuditUnsupported is set if the plugin is built before AuditBatch
+	// is added.
+	batchAuditUnsupported bool
+
+	caller *pluginRPCCaller
 }
 
 func (s *driverImpl) Close(ctx context.Context) {
-	s.plugin.Close(ctx, &proto.Empty{})
+	// the plugin process is released even if it's failed to close the driver,
+	// the hung process is killed by the caller.
+	_ = s.caller.call(ctx, "Close", pluginRPCAudit, func(ctx context.Context) (err error) {
+		_, err = s.plugin.Close(ctx, &proto.Empty{})
+		return err
+	})
 	close(s.driverQuitCh)
 }
 
 func (s *driverImpl) Ping(ctx context.Context) error {
-	_, err := s.plugin.Ping(ctx, &proto.Empty{})
-	return err
+	return s.caller.call(ctx, "Ping", pluginRPCAudit, func(ctx context.Context) (err error) {
+		_, err = s.plugin.Ping(ctx, &proto.Empty{})
+		return err
+	})
 }
 
 type dbDriverResult struct {
@@ -390,7 +444,11 @@ func (s *dbDriverResult) RowsAffected() (int64, error) {
 }
 
 func (s *driverImpl) Exec(ctx context.Context, query string) (driver.Result, error) {
-	resp, err := s.plugin.Exec(ctx, &proto.ExecRequest{Query: query})
+	var resp *proto.ExecResponse
+	err := s.caller.call(ctx, "Exec", pluginRPCExec, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.Exec(ctx, &proto.ExecRequest{Query: query})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +461,11 @@ func (s *driverImpl) Exec(ctx context.Context, query string) (driver.Result, err
 }
 
 func (s *driverImpl) Tx(ctx context.Context, queries ...string) ([]driver.Result, error) {
-	resp, err := s.plugin.Tx(ctx, &proto.TxRequest{Queries: queries})
+	var resp *proto.TxResponse
+	err := s.caller.call(ctx, "Tx", pluginRPCExec, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.Tx(ctx, &proto.TxRequest{Queries: queries})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +483,11 @@ func (s *driverImpl) Tx(ctx context.Context, queries ...string) ([]driver.Result
 }
 
 func (s *driverImpl) Schemas(ctx context.Context) ([]string, error) {
-	resp, err := s.plugin.Databases(ctx, &proto.Empty{})
+	var resp *proto.DatabasesResponse
+	err := s.caller.call(ctx, "Databases", pluginRPCAudit, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.Databases(ctx, &proto.Empty{})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -429,7 +495,11 @@ func (s *driverImpl) Schemas(ctx context.Context) ([]string, error) {
 }
 
 func (s *driverImpl) Parse(ctx context.Context, sqlText string) ([]Node, error) {
-	resp, err := s.plugin.Parse(ctx, &proto.ParseRequest{SqlText: sqlText})
+	var resp *proto.ParseResponse
+	err := s.caller.call(ctx, "Parse", pluginRPCAudit, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.Parse(ctx, &proto.ParseRequest{SqlText: sqlText})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -446,23 +516,109 @@ func (s *driverImpl) Parse(ctx context.Context, sqlText string) ([]Node, error)
 }
 
 func (s *driverImpl) Audit(ctx context.Context, sql string) (*AuditResult, error) {
-	resp, err := s.plugin.Audit(ctx, &proto.AuditRequest{Sql: sql})
+	var resp *proto.AuditResponse
+	err := s.caller.call(ctx, "Audit", pluginRPCAudit, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.Audit(ctx, &proto.AuditRequest{Sql: sql})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	return convertAuditResultFromProto(resp), nil
+}
+
+const (
+	// auditBatchMaxCount and auditBatchMaxSize limit the SQLs sent in one
+	// AuditBatch call, the message size is limited by gRPC.
+	auditBatchMaxCount = 1000
+	auditBatchMaxSize  = 1024 * 1024
+)
+
+// splitAuditBatches splits the SQLs into batches, the SQL which exceeds the
+// max size is sent alone.
+func splitAuditBatches(sqls []string) [][]string {
+	batches := [][]string{}
+	start, size := 0, 0
+	for i, sql := range sqls {
+		if i > start && (i-start >= auditBatchMaxCount || size+len(sql) > auditBatchMaxSize) {
+			batches = append(batches, sqls[start:i])
+			start, size = i, 0
+		}
+		size += len(sql)
+	}
+	if start < len(sqls) {
+		batches = append(batches, sqls[start:])
+	}
+	return batches
+}
+
+func (s *driverImpl) AuditBatch(ctx context.Context, sqls []string) ([]*AuditResult, error) {
+	results := make([]*AuditResult, 0, len(sqls))
+	for _, batch := range splitAuditBatches(sqls) {
+		if s.batchAuditUnsupported {
+			batchResults, err := auditOneByOne(ctx, s, batch)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, batchResults...)
+			continue
+		}
+
+		var resp *proto.AuditBatchResponse
+		err := s.caller.callBatch(ctx, "AuditBatch", pluginRPCAudit, len(batch), func(ctx context.Context) (err error) {
+			resp, err = s.plugin.AuditBatch(ctx, &proto.AuditBatchRequest{Sqls: batch})
+			return err
+		})
+		if status.Code(err) == codes.Unimplemented {
+			s.batchAuditUnsupported = true
+			batchResults, err := auditOneByOne(ctx, s, batch)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, batchResults...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != len(batch) {
+			return nil, fmt.Errorf("plugin returns %v audit results for %v SQLs", len(resp.Results), len(batch))
+		}
+		for _, result := range resp.Results {
+			results = append(results, convertAuditResultFromProto(result))
+		}
+	}
+	return results, nil
+}
 
+func convertAuditResultFromProto(resp *proto.AuditResponse) *AuditResult {
 	ret := &AuditResult{}
-	for _, result := range resp.Results {
+	for _, result := range resp.GetResults() {
 		ret.results = append(ret.results, &auditResult{
 			level:   RuleLevel(result.Level),
 			message: result.Message,
 		})
 	}
-	return ret, nil
+	return ret
+}
+
+func convertAuditResultToProto(result *AuditResult) *proto.AuditResponse {
+	resp := &proto.AuditResponse{}
+	for _, r := range result.results {
+		resp.Results = append(resp.Results, &proto.AuditResult{
+			Level:   string(r.level),
+			Message: r.message,
+		})
+	}
+	return resp
 }
 
 func (s *driverImpl) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
-	resp, err := s.plugin.GenRollbackSQL(ctx, &proto.GenRollbackSQLRequest{Sql: sql})
+	var resp *proto.GenRollbackSQLResponse
+	err := s.caller.call(ctx, "GenRollbackSQL", pluginRPCAudit, func(ctx context.Context) (err error) {
+		resp, err = s.plugin.GenRollbackSQL(ctx, &proto.GenRollbackSQLRequest{Sql: sql})
+		return err
+	})
 	if err != nil {
 		return "", "", err
 	}
@@ -508,36 +664,38 @@ func convertRuleFromDriverToProto(rule *Rule) *proto.Rule {
 	}
 }
 
-func registerAuditDriver(gRPCClient goPlugin.ClientProtocol) (pluginName string, pluginVersion int32, drvClient proto.DriverClient, err error) {
+// loadAuditDriverMeta reads the metas of the audit driver from plugin, the
+// driver is not registered.
+func loadAuditDriverMeta(gRPCClient goPlugin.ClientProtocol) (*pluginMeta, proto.DriverClient, error) {
 	rawI, err := gRPCClient.Dispense(PluginNameAuditDriver)
 	if err != nil {
-		return "", 0, nil, err
+		return nil, nil, err
 	}
 	// client can only be proto.DriverClient
 	//nolint:forcetypeassert
 	client := rawI.(proto.DriverClient)
 
-	pluginMeta, err := client.Metas(context.TODO(), &proto.Empty{})
+	metas, err := client.Metas(context.TODO(), &proto.Empty{})
 	if err != nil {
-		return "", 0, nil, err
+		return nil, nil, err
 	}
 	// init audit driver, so that we can use Close to inform all plugins with the same progress to recycle resource
 	_, err = client.Init(context.TODO(), &proto.InitRequest{})
 	if err != nil {
-		return "", 0, nil, err
+		return nil, nil, err
 	}
 
 	// driverRules get from plugin when plugin initialize.
-	var driverRules = make([]*Rule, 0, len(pluginMeta.Rules))
-	for _, rule := range pluginMeta.Rules {
+	var driverRules = make([]*Rule, 0, len(metas.Rules))
+	for _, rule := range metas.Rules {
 		driverRules = append(driverRules, convertRuleFromProtoToDriver(rule))
 	}
-
-	RegisterAuditDriver(pluginMeta.Name, driverRules, proto.ConvertProtoParamToParam(pluginMeta.GetAdditionalParams()))
-
-	log.Logger().WithFields(logrus.Fields{
-		"plugin_name": pluginMeta.Name,
-		"plugin_type": PluginNameAuditDriver,
-	}).Infoln("plugin inited")
-	return pluginMeta.Name, pluginMeta.GetVersion(), client, nil
+	return &pluginMeta{
+		name:             metas.Name,
+		version:          metas.GetVersion(),
+		rules:            driverRules,
+		additionalParams: proto.ConvertProtoParamToParam(metas.GetAdditionalParams()),
+		capabilities:     convertCapabilitiesFromProto(metas.GetCapabilities()),
+		minSQLEVersion:   metas.GetMinSQLEVersion(),
+	}, client, nil
 }
@@ -0,0 +1,158 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/proto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSplitAuditBatches(t *testing.T) {
+	assert.Len(t, splitAuditBatches(nil), 0)
+
+	sqls := make([]string, auditBatchMaxCount*2+1)
+	batches := splitAuditBatches(sqls)
+	assert.Len(t, batches, 3)
+	assert.Len(t, batches[0], auditBatchMaxCount)
+	assert.Len(t, batches[2], 1)
+
+	half := strings.Repeat("a", auditBatchMaxSize/2)
+	batches = splitAuditBatches([]string{"a", half, half, "b"})
+	assert.Equal(t, [][]string{{"a", half}, {half, "b"}}, batches)
+
+	// the SQL which exceeds the max size is sent alone.
+	large := strings.Repeat("a", auditBatchMaxSize+1)
+	batches = splitAuditBatches([]string{large, "a"})
+	assert.Equal(t, [][]string{{large}, {"a"}}, batches)
+}
+
+type mockBatchDriverClient struct {
+	proto.DriverClient
+	unimplemented bool
+	auditCalls    int
+	batchCalls    int
+	// auditDelay is the time to audit each SQL.
+	auditDelay time.Duration
+}
+
+func (m *mockBatchDriverClient) Audit(ctx context.Context, in *proto.AuditRequest, opts ...grpc.CallOption) (*proto.AuditResponse, error) {
+	m.auditCalls++
+	return &proto.AuditResponse{Results: []*proto.AuditResult{{Level: string(RuleLevelNotice), Message: in.Sql}}}, nil
+}
+
+func (m *mockBatchDriverClient) AuditBatch(ctx context.Context, in *proto.AuditBatchRequest, opts ...grpc.CallOption) (*proto.AuditBatchResponse, error) {
+	m.batchCalls++
+	if m.unimplemented {
+		return nil, status.Error(codes.Unimplemented, "unknown method AuditBatch")
+	}
+	resp := &proto.AuditBatchResponse{}
+	for _, sql := range in.Sqls {
+		select {
+		case <-time.After(m.auditDelay):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		resp.Results = append(resp.Results, &proto.AuditResponse{
+			Results: []*proto.AuditResult{{Level: string(RuleLevelNotice), Message: sql}},
+		})
+	}
+	return resp, nil
+}
+
+func TestDriverImplAuditBatch(t *testing.T) {
+	sqls := make([]string, auditBatchMaxCount+1)
+	for i := range sqls {
+		sqls[i] = fmt.Sprintf("select %v", i)
+	}
+
+	client := &mockBatchDriverClient{}
+	results, err := AuditBatch(context.TODO(), &driverImpl{plugin: client}, sqls)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(sqls))
+	assert.Equal(t, "[notice]select 1000", results[auditBatchMaxCount].Message())
+	assert.Equal(t, 2, client.batchCalls)
+	assert.Equal(t, 0, client.auditCalls)
+
+	// the old plugin doesn't implement AuditBatch.
+	client = &mockBatchDriverClient{unimplemented: true}
+	results, err = AuditBatch(context.TODO(), &driverImpl{plugin: client}, sqls)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(sqls))
+	assert.Equal(t, "[notice]select 0", results[0].Message())
+	assert.Equal(t, 1, client.batchCalls)
+	assert.Equal(t, len(sqls), client.auditCalls)
+}
+
+func TestDriverImplAuditBatchTimeout(t *testing.T) {
+	proc, _ := newMockProcess()
+	caller := &pluginRPCCaller{
+		pluginName: "test",
+		timeout:    PluginRPCTimeout{Audit: 50 * time.Millisecond}.withDefault(),
+		proc:       proc,
+	}
+	sqls := []string{"select 1", "select 2", "select 3", "select 4"}
+
+	// the batch takes longer than the timeout of single audit, but each SQL
+	// doesn't.
+	client := &mockBatchDriverClient{auditDelay: 20 * time.Millisecond}
+	results, err := AuditBatch(context.TODO(), &driverImpl{plugin: client, caller: caller}, sqls)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(sqls))
+
+	client = &mockBatchDriverClient{auditDelay: time.Second}
+	_, err = AuditBatch(context.TODO(), &driverImpl{plugin: client, caller: caller}, sqls)
+	assert.True(t, errors.Is(err, ErrPluginRPCTimeout))
+	assert.Contains(t, err.Error(), "AuditBatch of plugin test doesn't finish in 200ms")
+}
+
+// mockHungDriverClient blocks Ping and Close until the context is done.
+type mockHungDriverClient struct {
+	proto.DriverClient
+}
+
+func (m *mockHungDriverClient) Ping(ctx context.Context, in *proto.Empty, opts ...grpc.CallOption) (*proto.Empty, error) {
+	return nil, blockingRPC(ctx)
+}
+
+func (m *mockHungDriverClient) Close(ctx context.Context, in *proto.Empty, opts ...grpc.CallOption) (*proto.Empty, error) {
+	return nil, blockingRPC(ctx)
+}
+
+func TestDriverImplPingAndCloseTimeout(t *testing.T) {
+	proc, _ := newMockProcess()
+	d := &driverImpl{
+		plugin:       &mockHungDriverClient{},
+		driverQuitCh: make(chan struct{}),
+		caller: &pluginRPCCaller{
+			pluginName: "test",
+			timeout:    PluginRPCTimeout{Audit: 10 * time.Millisecond}.withDefault(),
+			proc:       proc,
+		},
+	}
+
+	err := d.Ping(context.TODO())
+	assert.True(t, errors.Is(err, ErrPluginRPCTimeout))
+	assert.Contains(t, err.Error(), "Ping of plugin test doesn't finish in 10ms")
+
+	done := make(chan struct{})
+	go func() {
+		d.Close(context.TODO())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close is blocked by the hung plugin")
+	}
+	_, ok := <-d.driverQuitCh
+	assert.False(t, ok)
+}
@@ -6,11 +6,9 @@ import (
 	"sync"
 
 	"github.com/actiontech/sqle/sqle/driver/proto"
-	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/pkg/params"
 
 	goPlugin "github.com/hashicorp/go-plugin"
-	"github.com/sirupsen/logrus"
 )
 
 // SQLQueryDriver is a SQL rewrite and execute driver
@@ -66,6 +64,7 @@ type QueryResultValue struct {
 // queryDriverImpl implement SQLQueryDriver. It use for hide gRPC detail, just like DriverGRPCServer.
 type queryDriverImpl struct {
 	plugin proto.QueryDriverClient
+	caller *pluginRPCCaller
 }
 
 func (q *queryDriverImpl) QueryPrepare(ctx context.Context, sql string, conf *QueryPrepareConf) (*QueryPrepareResult, error) {
@@ -76,7 +75,11 @@ func (q *queryDriverImpl) QueryPrepare(ctx context.Context, sql string, conf *Qu
 			Offset: conf.Offset,
 		},
 	}
-	res, err := q.plugin.QueryPrepare(ctx, req)
+	var res *proto.QueryPrepareResponse
+	err := q.caller.call(ctx, "QueryPrepare", pluginRPCQuery, func(ctx context.Context) (err error) {
+		res, err = q.plugin.QueryPrepare(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +97,11 @@ func (q *queryDriverImpl) Query(ctx context.Context, sql string, conf *QueryConf
 			TimeOutSecond: conf.TimeOutSecond,
 		},
 	}
-	res, err := q.plugin.Query(ctx, req)
+	var res *proto.QueryResponse
+	err := q.caller.call(ctx, "Query", pluginRPCQuery, func(ctx context.Context) (err error) {
+		res, err = q.plugin.Query(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +145,13 @@ func GetQueryDriverNames() []string {
 	return names
 }
 
+func hasQueryDriver(name string) bool {
+	queryDriverMu.RLock()
+	defer queryDriverMu.RUnlock()
+	_, exist := queryDrivers[name]
+	return exist
+}
+
 // RegisterSQLQueryDriver like sql.RegisterAuditDriver.
 //
 // RegisterSQLQueryDriver makes a database driver available by the provided driver name.
@@ -155,7 +169,8 @@ func RegisterSQLQueryDriver(name string) {
 	queryDriverMu.Unlock()
 }
 
-func registerQueryDriver(pluginName string, gRPCClient goPlugin.ClientProtocol) error {
+// checkQueryDriver checks whether the plugin implements the query driver.
+func checkQueryDriver(gRPCClient goPlugin.ClientProtocol) error {
 	rawI, err := gRPCClient.Dispense(PluginNameQueryDriver)
 	if err != nil {
 		return err
@@ -166,14 +181,5 @@ func registerQueryDriver(pluginName string, gRPCClient goPlugin.ClientProtocol)
 
 	// The test target plugin implements the QueryDriver plugin
 	_, err = s.Init(context.TODO(), &proto.InitRequest{})
-	if err != nil {
-		return err
-	}
-
-	RegisterSQLQueryDriver(pluginName)
-	log.Logger().WithFields(logrus.Fields{
-		"plugin_name": pluginName,
-		"plugin_type": PluginNameQueryDriver,
-	}).Infoln("plugin inited")
-	return nil
+	return err
 }
@@ -4097,3 +4097,104 @@ func Test_DDLDisableTypeTimestamp(t *testing.T) {
 			DefaultMysqlInspect(), sql, newTestResult())
 	}
 }
+
+func DefaultTiDBInspect() *MysqlDriverImpl {
+	i := DefaultMysqlInspect()
+	i.isTiDB = true
+	i.cnf.DDLOSCMinSize = -1
+	return i
+}
+
+func TestTiDBCheckAutoIncrementHotspot(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.TiDBCheckAutoIncrementHotspot].Rule
+
+	for _, sql := range []string{
+		`create table t1(id bigint unsigned auto_increment primary key, v1 int);`,
+		`create table t1(id bigint unsigned auto_increment, v1 int, primary key(id)) shard_row_id_bits=4;`,
+		`create table t1(id bigint unsigned auto_increment, v1 int, key idx_1(id));`,
+	} {
+		runSingleRuleInspectCase(rule, t, "", DefaultTiDBInspect(), sql,
+			newTestResult().addResult(rulepkg.TiDBCheckAutoIncrementHotspot, "t1", "id"))
+	}
+
+	for _, sql := range []string{
+		`create table t1(id bigint auto_random primary key, v1 int);`,
+		`create table t1(id bigint unsigned auto_increment, v1 int, key idx_1(id)) shard_row_id_bits=4;`,
+		`create table t1(id bigint unsigned, v1 int, primary key(id, v1));`,
+	} {
+		runSingleRuleInspectCase(rule, t, "", DefaultTiDBInspect(), sql, newTestResult())
+	}
+}
+
+func TestTiDBCheckUnsupportedFeature(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.TiDBCheckUnsupportedFeature].Rule
+
+	runSingleRuleInspectCase(rule, t, "create table with foreign key", DefaultTiDBInspect(),
+		`create table t1(id bigint unsigned primary key, v1 bigint unsigned, foreign key (v1) references exist_tb_1(id));`,
+		newTestResult().addResult(rulepkg.TiDBCheckUnsupportedFeature, "外键"))
+	runSingleRuleInspectCase(rule, t, "alter table add foreign key", DefaultTiDBInspect(),
+		`alter table exist_tb_2 add constraint fk_1 foreign key (user_id) references exist_tb_1(id);`,
+		newTestResult().addResult(rulepkg.TiDBCheckUnsupportedFeature, "外键"))
+	runSingleRuleInspectCase(rule, t, "create table with fulltext index", DefaultTiDBInspect(),
+		`create table t1(id bigint unsigned primary key, v1 text, fulltext index ft_1(v1));`,
+		newTestResult().addResult(rulepkg.TiDBCheckUnsupportedFeature, "全文索引"))
+	runSingleRuleInspectCase(rule, t, "create spatial index", DefaultTiDBInspect(),
+		`create spatial index idx_2 on exist_tb_1(v1);`,
+		newTestResult().addResult(rulepkg.TiDBCheckUnsupportedFeature, "空间索引"))
+	runSingleRuleInspectCase(rule, t, "create table with spatial type", DefaultTiDBInspect(),
+		`create table t1(id bigint unsigned primary key, location point not null, spatial index idx_1(location));`,
+		newTestResult().addResult(rulepkg.TiDBCheckUnsupportedFeature, "空间类型字段, 空间索引").
+			add(driver.RuleLevelWarn, "语法错误或者解析器不支持，请人工确认SQL正确性"))
+	runSingleRuleInspectCase(rule, t, "create table without unsupported feature", DefaultTiDBInspect(),
+		`create table t1(id bigint unsigned primary key, point int, key idx_1(point));`,
+		newTestResult())
+}
+
+func TestTiDBCheckLargeTransaction(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.TiDBCheckLargeTransaction].Rule
+	rule.Params.SetParamValue(rulepkg.DefaultSingleParamKeyName, "16")
+
+	for _, sql := range []string{
+		`update exist_db.exist_tb_4 set v1 = "1";`,
+		`delete from exist_db.exist_tb_4;`,
+		`insert into exist_db.exist_tb_1 select * from exist_db.exist_tb_4;`,
+	} {
+		runSingleRuleInspectCase(rule, t, "", DefaultTiDBInspect(), sql,
+			newTestResult().addResult(rulepkg.TiDBCheckLargeTransaction, "exist_tb_4", 16))
+	}
+
+	for _, sql := range []string{
+		`update exist_db.exist_tb_1 set v1 = "1";`,
+		`update exist_db.exist_tb_4 set v1 = "1" where id = 1;`,
+		`delete from exist_db.exist_tb_4 limit 1000;`,
+		`insert into exist_db.exist_tb_4 values (1, "1", "1", 1);`,
+	} {
+		runSingleRuleInspectCase(rule, t, "", DefaultTiDBInspect(), sql, newTestResult())
+	}
+}
+
+func TestTiDBCheckDDLBehavior(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.TiDBCheckDDLBehavior].Rule
+
+	runSingleRuleInspectCase(rule, t, "multiple changes", DefaultTiDBInspect(),
+		`alter table exist_db.exist_tb_1 add column v3 int, add index idx_2(v2);`,
+		newTestResult().addResult(rulepkg.TiDBCheckDDLBehavior, "TiDB v6.2 之前的版本不支持在一条 ALTER TABLE 语句中进行多个变更, 建议拆分"))
+	runSingleRuleInspectCase(rule, t, "algorithm and lock", DefaultTiDBInspect(),
+		`alter table exist_db.exist_tb_1 add column v3 int, algorithm=copy, lock=none;`,
+		newTestResult().addResult(rulepkg.TiDBCheckDDLBehavior, "TiDB 不支持 ALGORITHM=COPY, DDL 均为在线执行").
+			addResult(rulepkg.TiDBCheckDDLBehavior, "TiDB 会忽略 LOCK 选项, DDL 执行期间不锁表"))
+	runSingleRuleInspectCase(rule, t, "narrow column", DefaultTiDBInspect(),
+		`alter table exist_db.exist_tb_1 modify column v1 varchar(100) not null default "v1" comment "unit test";`,
+		newTestResult().addResult(rulepkg.TiDBCheckDDLBehavior, "字段 v1 由 varchar(255) 修改为 varchar(100) 时, TiDB 会重写整表数据, 耗时与表数据量相关"))
+	runSingleRuleInspectCase(rule, t, "change column type", DefaultTiDBInspect(),
+		`alter table exist_db.exist_tb_1 change column v2 v3 int;`,
+		newTestResult().addResult(rulepkg.TiDBCheckDDLBehavior, "字段 v2 由 varchar(255) 修改为 int(11) 时, TiDB 会重写整表数据, 耗时与表数据量相关"))
+
+	for _, sql := range []string{
+		`alter table exist_db.exist_tb_1 modify column v1 varchar(1024) not null default "v1" comment "unit test";`,
+		`alter table exist_db.exist_tb_1 modify column id bigint(20) unsigned not null auto_increment comment "unit test";`,
+		`alter table exist_db.exist_tb_1 add column v3 int, algorithm=inplace;`,
+	} {
+		runSingleRuleInspectCase(rule, t, "", DefaultTiDBInspect(), sql, newTestResult())
+	}
+}
@@ -28,8 +28,33 @@ func init() {
 	}
 
 	driver.RegisterAuditDriver(driver.DriverTypeMySQL, allRules, params.Params{})
+	driver.RegisterCapabilities(driver.DriverTypeMySQL, driver.Capabilities{
+		Rollback:       true,
+		ExplainFormats: []string{driver.ExplainFormatTraditional, driver.ExplainFormatJSON},
+		OfflineAudit:   true,
+	})
 	driver.RegisterDriverManger(nil, driver.DriverTypeMySQL, NewDriverManagerFunc)
 
+	// TiDB is compatible with MySQL protocol, it reuses the MySQL rules except
+	// the MySQL only rules, and has its own rules.
+	tidbRules := make([]*driver.Rule, 0, len(rulepkg.RuleHandlers)+len(rulepkg.TiDBRuleHandlers))
+	for i := range rulepkg.RuleHandlers {
+		if _, ok := rulepkg.MySQLOnlyRules[rulepkg.RuleHandlers[i].Rule.Name]; ok {
+			continue
+		}
+		tidbRules = append(tidbRules, &rulepkg.RuleHandlers[i].Rule)
+	}
+	for i := range rulepkg.TiDBRuleHandlers {
+		tidbRules = append(tidbRules, &rulepkg.TiDBRuleHandlers[i].Rule)
+	}
+
+	driver.RegisterAuditDriver(driver.DriverTypeTiDB, tidbRules, params.Params{})
+	driver.RegisterCapabilities(driver.DriverTypeTiDB, driver.Capabilities{
+		ExplainFormats: []string{driver.ExplainFormatTraditional},
+		OfflineAudit:   true,
+	})
+	driver.RegisterDriverManger(nil, driver.DriverTypeTiDB, NewDriverManagerFunc)
+
 	if err := LoadPtTemplateFromFile("./scripts/pt-online-schema-change.template"); err != nil {
 		panic(err)
 	}
@@ -59,9 +84,15 @@ type MysqlDriverImpl struct {
 	isConnected bool
 	// isOfflineAudit represent Audit without instance.
 	isOfflineAudit bool
+	// isTiDB represent the instance is TiDB, rollback and online DDL tools
+	// are disabled for it.
+	isTiDB bool
+	// hasAudited represent the SQL session has been changed by Audit, it's
+	// reset before generating rollback SQLs.
+	hasAudited bool
 }
 
-func NewInspect(log *logrus.Entry, cfg *driver.Config) (*MysqlDriverImpl, error) {
+func NewInspect(log *logrus.Entry, dbType string, cfg *driver.Config) (*MysqlDriverImpl, error) {
 	var inspect = &MysqlDriverImpl{}
 
 	if cfg.DSN != nil {
@@ -86,6 +117,7 @@ func NewInspect(log *logrus.Entry, cfg *driver.Config) (*MysqlDriverImpl, error)
 	inspect.rules = cfg.Rules
 	inspect.result = driver.NewInspectResults()
 	inspect.isOfflineAudit = cfg.DSN == nil
+	inspect.isTiDB = dbType == driver.DriverTypeTiDB
 
 	inspect.cnf = &Config{
 		DMLRollbackMaxRows: -1,
@@ -93,6 +125,9 @@ func NewInspect(log *logrus.Entry, cfg *driver.Config) (*MysqlDriverImpl, error)
 		DDLGhostMinSize:    -1,
 	}
 	for _, rule := range cfg.Rules {
+		if _, ok := rulepkg.MySQLOnlyRules[rule.Name]; ok && inspect.isTiDB {
+			continue
+		}
 		if rule.Name == rulepkg.ConfigDMLRollbackMaxRows {
 			max := rule.Params.GetParam(rulepkg.DefaultSingleParamKeyName).Int()
 			inspect.cnf.DMLRollbackMaxRows = int64(max)
@@ -354,6 +389,7 @@ func (i *MysqlDriverImpl) Audit(ctx context.Context, sql string) (*driver.AuditR
 		i.result.Add(driver.RuleLevelNotice, fmt.Sprintf("[osc]%s", oscCommandLine))
 	}
 	i.Ctx.UpdateContext(nodes[0])
+	i.hasAudited = true
 	return i.result, nil
 }
 
@@ -361,6 +397,16 @@ func (i *MysqlDriverImpl) GenRollbackSQL(ctx context.Context, sql string) (strin
 	if i.IsOfflineAudit() {
 		return "", "", nil
 	}
+	if i.isTiDB {
+		return "", "TiDB 不支持生成回滚语句", nil
+	}
+	// The rollback SQLs are generated by the same driver after all SQLs are
+	// audited, so the SQL session is reset to the state before auditing.
+	if i.hasAudited {
+		if err := i.resetContext(); err != nil {
+			return "", "", err
+		}
+	}
 	if i.HasInvalidSql {
 		return "", "", nil
 	}
@@ -380,6 +426,20 @@ func (i *MysqlDriverImpl) GenRollbackSQL(ctx context.Context, sql string) (strin
 	return rollback, reason, nil
 }
 
+func (i *MysqlDriverImpl) resetContext() error {
+	conn, err := i.getDbConn()
+	if err != nil {
+		return err
+	}
+	ctx := session.NewContext(nil, session.WithExecutor(conn))
+	ctx.SetCurrentSchema(i.inst.DatabaseName)
+
+	i.Ctx = ctx
+	i.HasInvalidSql = false
+	i.hasAudited = false
+	return nil
+}
+
 func (i *MysqlDriverImpl) Close(ctx context.Context) {
 	i.closeDbConn()
 }
@@ -508,7 +568,7 @@ func (d *DriverManager) Close(ctx context.Context) {
 }
 
 func NewDriverManagerFunc(log *logrus.Entry, dbType string, config *driver.Config, client *driver.PluginClient) (driver.DriverManager, error) {
-	inspect, err := NewInspect(log, config)
+	inspect, err := NewInspect(log, dbType, config)
 	if err != nil {
 		return nil, err
 	}
@@ -2,9 +2,14 @@ package mysql
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -162,3 +167,58 @@ func TestInspect_GenRollbackSQL(t *testing.T) {
 	assert.Equal(t, "", reason)
 	assert.Equal(t, "ALTER TABLE `exist_db`.`t1`\nDROP COLUMN `c1`;", rollback)
 }
+
+func TestInspect_GenRollbackSQLAfterAudit(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW GLOBAL VARIABLES LIKE 'lower_case_table_names'")).
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("lower_case_table_names", "0"))
+	handler.ExpectQuery(regexp.QuoteMeta("show databases")).
+		WillReturnRows(sqlmock.NewRows([]string{"Database"}).AddRow("mysql"))
+	handler.ExpectQuery(regexp.QuoteMeta("select TABLE_NAME from information_schema.tables where table_schema='mysql'")).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}))
+
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	_, err = i.Audit(context.TODO(), "use no_exist_db")
+	assert.NoError(t, err)
+	assert.True(t, i.HasInvalidSql)
+
+	// the session changed by Audit is reset before generating rollback SQLs.
+	rollback, reason, err := i.GenRollbackSQL(context.TODO(), "create table t1(id int, c1 int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "DROP TABLE IF EXISTS `mysql`.`t1`", rollback)
+	assert.False(t, i.HasInvalidSql)
+	assert.Equal(t, "mysql", i.Ctx.CurrentSchema())
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestInspect_GenRollbackSQLForTiDB(t *testing.T) {
+	i := DefaultMysqlInspect()
+	i.isTiDB = true
+
+	rollback, reason, err := i.GenRollbackSQL(context.TODO(), "create table t1(id int, c1 int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "TiDB 不支持生成回滚语句", reason)
+	assert.Equal(t, "", rollback)
+}
+
+func TestNewInspectForTiDB(t *testing.T) {
+	ghostRule := rulepkg.RuleHandlerMap[rulepkg.ConfigDDLGhostMinSize].Rule
+	oscRule := rulepkg.RuleHandlerMap[rulepkg.ConfigDDLOSCMinSize].Rule
+	cfg := &driver.Config{Rules: []*driver.Rule{&ghostRule, &oscRule}}
+
+	i, err := NewInspect(log.NewEntry(), driver.DriverTypeMySQL, cfg)
+	assert.NoError(t, err)
+	assert.False(t, i.isTiDB)
+	assert.Equal(t, int64(16), i.cnf.DDLGhostMinSize)
+	assert.Equal(t, int64(16), i.cnf.DDLOSCMinSize)
+
+	i, err = NewInspect(log.NewEntry(), driver.DriverTypeTiDB, cfg)
+	assert.NoError(t, err)
+	assert.True(t, i.isTiDB)
+	assert.Equal(t, int64(-1), i.cnf.DDLGhostMinSize)
+	assert.Equal(t, int64(-1), i.cnf.DDLOSCMinSize)
+}
@@ -0,0 +1,321 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+)
+
+const RuleTypeTiDBConvention = "TiDB规范"
+
+// inspector TiDB rules, they are only registered for the TiDB driver.
+const (
+	TiDBCheckAutoIncrementHotspot = "tidb_check_auto_increment_hotspot"
+	TiDBCheckUnsupportedFeature   = "tidb_check_unsupported_feature"
+	TiDBCheckLargeTransaction     = "tidb_check_large_transaction"
+	TiDBCheckDDLBehavior          = "tidb_check_ddl_behavior"
+)
+
+// MySQLOnlyRules are the rules of MySQL which make no sense to TiDB, such as
+// the config of rollback and online DDL tools.
+var MySQLOnlyRules = map[string]struct{}{
+	ConfigDMLRollbackMaxRows: {},
+	ConfigDDLOSCMinSize:      {},
+	ConfigDDLGhostMinSize:    {},
+	// TiDB before v6.2 doesn't support multiple changes in one ALTER TABLE
+	// statement, see TiDBCheckDDLBehavior.
+	DDLCheckAlterTableNeedMerge: {},
+}
+
+var TiDBRuleHandlers = []RuleHandler{
+	{
+		Rule: driver.Rule{
+			Name:     TiDBCheckAutoIncrementHotspot,
+			Desc:     "TiDB 中不建议使用 AUTO_INCREMENT 字段, 连续写入会产生热点",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeTiDBConvention,
+		},
+		Message:      "表 %v 的自增字段 %v 会产生写入热点, 建议主键使用 AUTO_RANDOM, 或对非聚簇主键的表设置 SHARD_ROW_ID_BITS",
+		AllowOffline: true,
+		Func:         checkTiDBAutoIncrementHotspot,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     TiDBCheckUnsupportedFeature,
+			Desc:     "禁止使用 TiDB 不支持的外键, 空间类型, 空间索引和全文索引",
+			Level:    driver.RuleLevelError,
+			Category: RuleTypeTiDBConvention,
+		},
+		Message:      "TiDB 不支持%v",
+		AllowOffline: true,
+		Func:         checkTiDBUnsupportedFeature,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     TiDBCheckLargeTransaction,
+			Desc:     "全表 DML 的事务大小不能超过 txn-total-size-limit",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeTiDBConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "100",
+					Desc:  "txn-total-size-limit（MB）",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "DML 语句会修改表 %v 的全部数据, 事务大小可能超过 txn-total-size-limit(%vMB), 建议分批执行",
+		AllowOffline: false,
+		Func:         checkTiDBLargeTransaction,
+	},
+	{
+		Rule: driver.Rule{
+			Name:     TiDBCheckDDLBehavior,
+			Desc:     "提示 TiDB 与 MySQL 执行方式不同的 DDL",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeTiDBConvention,
+		},
+		Message:      "%v",
+		AllowOffline: true,
+		Func:         checkTiDBDDLBehavior,
+	},
+}
+
+func init() {
+	for _, rh := range TiDBRuleHandlers {
+		RuleHandlerMap[rh.Rule.Name] = rh
+	}
+}
+
+func checkTiDBAutoIncrementHotspot(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	stmt, ok := node.(*ast.CreateTableStmt)
+	if !ok {
+		return nil
+	}
+
+	hasShardRowID := false
+	for _, op := range stmt.Options {
+		if op.Tp == ast.TableOptionShardRowID && op.UintValue > 0 {
+			hasShardRowID = true
+		}
+	}
+	pkColumns, _ := util.GetPrimaryKey(stmt)
+
+	for _, col := range stmt.Cols {
+		if !util.HasOneInOptions(col.Options, ast.ColumnOptionAutoIncrement) {
+			continue
+		}
+		// SHARD_ROW_ID_BITS doesn't work if the auto increment column is the
+		// clustered primary key, the row id is the column itself.
+		_, isPK := pkColumns[col.Name.Name.L]
+		if (isPK && len(pkColumns) == 1) || !hasShardRowID {
+			addResult(res, rule, TiDBCheckAutoIncrementHotspot, stmt.Table.Name.String(), col.Name.Name.String())
+		}
+	}
+	return nil
+}
+
+var spatialTypeReg = regexp.MustCompile(`(?i)\s(geometry|point|linestring|polygon|multipoint|multilinestring|multipolygon|geometrycollection|geomcollection)[\s,)]`)
+var spatialIndexReg = regexp.MustCompile(`(?i)spatial\s+(index|key)`)
+
+// The parser doesn't support the spatial types, so the statement used them is
+// unparsed, we do character matching for it.
+func checkTiDBUnsupportedFeature(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	features := []string{}
+	addFeature := func(feature string) {
+		for _, f := range features {
+			if f == feature {
+				return
+			}
+		}
+		features = append(features, feature)
+	}
+	checkConstraint := func(constraint *ast.Constraint) {
+		switch constraint.Tp {
+		case ast.ConstraintForeignKey:
+			addFeature("外键")
+		case ast.ConstraintFulltext:
+			addFeature("全文索引")
+		}
+	}
+	checkColumn := func(col *ast.ColumnDef) {
+		if util.HasOneInOptions(col.Options, ast.ColumnOptionReference) {
+			addFeature("外键")
+		}
+	}
+
+	switch stmt := node.(type) {
+	case *ast.CreateTableStmt:
+		for _, col := range stmt.Cols {
+			checkColumn(col)
+		}
+		for _, constraint := range stmt.Constraints {
+			checkConstraint(constraint)
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range stmt.Specs {
+			for _, col := range spec.NewColumns {
+				checkColumn(col)
+			}
+			if spec.Constraint != nil {
+				checkConstraint(spec.Constraint)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		switch stmt.KeyType {
+		case ast.IndexKeyTypeSpatial:
+			addFeature("空间索引")
+		case ast.IndexKeyTypeFullText:
+			addFeature("全文索引")
+		}
+	case *ast.UnparsedStmt:
+		if spatialTypeReg.MatchString(node.Text()) {
+			addFeature("空间类型字段")
+		}
+		if spatialIndexReg.MatchString(node.Text()) {
+			addFeature("空间索引")
+		}
+	default:
+		return nil
+	}
+
+	if len(features) > 0 {
+		addResult(res, rule, TiDBCheckUnsupportedFeature, strings.Join(features, ", "))
+	}
+	return nil
+}
+
+// checkTiDBLargeTransaction checks the DML which modifies the whole table, the
+// size of the table is regarded as the size of the transaction.
+func checkTiDBLargeTransaction(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	max := rule.Params.GetParam(DefaultSingleParamKeyName).Int()
+	tables := []*ast.TableName{}
+	switch stmt := node.(type) {
+	case *ast.UpdateStmt:
+		if stmt.Where == nil && stmt.Limit == nil {
+			tables = append(tables, util.GetTables(stmt.TableRefs.TableRefs)...)
+		}
+	case *ast.DeleteStmt:
+		if stmt.Where == nil && stmt.Limit == nil {
+			if stmt.Tables != nil {
+				tables = append(tables, stmt.Tables.Tables...)
+			} else {
+				tables = append(tables, util.GetTables(stmt.TableRefs.TableRefs)...)
+			}
+		}
+	case *ast.InsertStmt:
+		sel, ok := stmt.Select.(*ast.SelectStmt)
+		if ok && sel.From != nil && sel.Where == nil && sel.Limit == nil {
+			tables = append(tables, util.GetTables(sel.From.TableRefs)...)
+		}
+	default:
+		return nil
+	}
+
+	beyond := []string{}
+	for _, table := range tables {
+		size, err := ctx.GetTableSize(table)
+		if err != nil {
+			return err
+		}
+		if float64(max) < size {
+			beyond = append(beyond, table.Name.String())
+		}
+	}
+
+	if len(beyond) > 0 {
+		addResult(res, rule, TiDBCheckLargeTransaction, strings.Join(beyond, " , "), max)
+	}
+	return nil
+}
+
+func checkTiDBDDLBehavior(ctx *session.Context, rule driver.Rule, res *driver.AuditResult, node ast.Node) error {
+	stmt, ok := node.(*ast.AlterTableStmt)
+	if !ok {
+		return nil
+	}
+
+	changes := 0
+	for _, spec := range stmt.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAlgorithm:
+			if spec.Algorithm == ast.AlgorithmTypeCopy {
+				addResult(res, rule, TiDBCheckDDLBehavior, "TiDB 不支持 ALGORITHM=COPY, DDL 均为在线执行")
+			}
+		case ast.AlterTableLock:
+			addResult(res, rule, TiDBCheckDDLBehavior, "TiDB 会忽略 LOCK 选项, DDL 执行期间不锁表")
+		default:
+			changes++
+		}
+	}
+	if changes > 1 {
+		addResult(res, rule, TiDBCheckDDLBehavior, "TiDB v6.2 之前的版本不支持在一条 ALTER TABLE 语句中进行多个变更, 建议拆分")
+	}
+
+	originTable, exist, err := ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil || !exist {
+		return err
+	}
+	for _, spec := range util.GetAlterTableSpecByTp(stmt.Specs, ast.AlterTableModifyColumn, ast.AlterTableChangeColumn) {
+		for _, newCol := range spec.NewColumns {
+			colName := newCol.Name.Name.L
+			if spec.OldColumnName != nil {
+				colName = spec.OldColumnName.Name.L
+			}
+			for _, originCol := range originTable.Cols {
+				if originCol.Name.Name.L != colName || newCol.Tp == nil || originCol.Tp == nil {
+					continue
+				}
+				if isTiDBReorgTypeChange(originCol.Tp, newCol.Tp) {
+					addResult(res, rule, TiDBCheckDDLBehavior,
+						fmt.Sprintf("字段 %v 由 %v 修改为 %v 时, TiDB 会重写整表数据, 耗时与表数据量相关", originCol.Name.Name.O, originCol.Tp, newCol.Tp))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isTiDBReorgTypeChange returns true if TiDB changes the column type by
+// rewriting the data instead of only changing the meta data. It's only the meta
+// data change when widening the integer or the string, but changing the
+// precision of decimal always rewrites the data.
+//
+// ref: https://docs.pingcap.com/tidb/stable/sql-statement-modify-column
+func isTiDBReorgTypeChange(origin, new *types.FieldType) bool {
+	if mysql.HasUnsignedFlag(origin.Flag) != mysql.HasUnsignedFlag(new.Flag) {
+		return true
+	}
+	if origin.Charset != "" && new.Charset != "" && origin.Charset != new.Charset {
+		return true
+	}
+	switch {
+	case mysql.IsIntegerType(origin.Tp) && mysql.IsIntegerType(new.Tp):
+		return integerTypeSize[new.Tp] < integerTypeSize[origin.Tp]
+	case origin.Tp == mysql.TypeNewDecimal && new.Tp == mysql.TypeNewDecimal:
+		return origin.Flen != new.Flen || origin.Decimal != new.Decimal
+	case origin.Tp == new.Tp:
+		if new.Flen != types.UnspecifiedLength && new.Flen < origin.Flen {
+			return true
+		}
+		return new.Decimal != origin.Decimal
+	}
+	return true
+}
+
+var integerTypeSize = map[byte]int{
+	mysql.TypeTiny:     1,
+	mysql.TypeShort:    2,
+	mysql.TypeInt24:    3,
+	mysql.TypeLong:     4,
+	mysql.TypeLonglong: 8,
+}
@@ -39,6 +39,14 @@ func NewPlugin() *PluginServer {
 func (p *PluginServer) Serve() {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
+	// the audit driver declares whether the query driver and the analysis
+	// driver are served in the same plugin.
+	for _, set := range p.plugins {
+		if ap, ok := set[PluginNameAuditDriver].(*auditDriverPlugin); ok && ap.Srv != nil {
+			_, ap.Srv.supportQuery = set[PluginNameQueryDriver]
+			_, ap.Srv.supportAnalysis = set[PluginNameAnalysisDriver]
+		}
+	}
 	goPlugin.Serve(&goPlugin.ServeConfig{
 		HandshakeConfig:  handshakeConfig,
 		VersionedPlugins: p.plugins,
@@ -22,6 +22,11 @@ type auditDriverGRPCServer struct {
 
 	// Registerer provide some plugin info to host process.
 	r Registerer
+
+	// supportQuery and supportAnalysis are set by PluginServer, they are
+	// declared in capabilities.
+	supportQuery    bool
+	supportAnalysis bool
 }
 
 func (d *auditDriverGRPCServer) Init(ctx context.Context, req *proto.InitRequest) (*proto.Empty, error) {
@@ -132,13 +137,17 @@ func (d *auditDriverGRPCServer) Audit(ctx context.Context, req *proto.AuditReque
 	if err != nil {
 		return &proto.AuditResponse{}, err
 	}
+	return convertAuditResultToProto(auditResults), nil
+}
 
-	resp := &proto.AuditResponse{}
-	for _, result := range auditResults.results {
-		resp.Results = append(resp.Results, &proto.AuditResult{
-			Level:   string(result.level),
-			Message: result.message,
-		})
+func (d *auditDriverGRPCServer) AuditBatch(ctx context.Context, req *proto.AuditBatchRequest) (*proto.AuditBatchResponse, error) {
+	auditResults, err := AuditBatch(ctx, d.impl, req.GetSqls())
+	if err != nil {
+		return &proto.AuditBatchResponse{}, err
+	}
+	resp := &proto.AuditBatchResponse{}
+	for _, result := range auditResults {
+		resp.Results = append(resp.Results, convertAuditResultToProto(result))
 	}
 	return resp, nil
 }
@@ -158,12 +167,20 @@ func (d *auditDriverGRPCServer) Metas(ctx context.Context, req *proto.Empty) (*p
 		protoRules[i] = convertRuleFromDriverToProto(r)
 	}
 
-	return &proto.MetasResponse{
+	resp := &proto.MetasResponse{
 		Name:             d.r.Name(),
 		Rules:            protoRules,
 		AdditionalParams: proto.ConvertParamToProtoParam(d.r.AdditionalParams()),
 		Version:          DefaultPluginVersion,
-	}, nil
+	}
+	if cr, ok := d.r.(CapabilitiesRegisterer); ok {
+		c := cr.Capabilities()
+		c.Query = d.supportQuery
+		c.Analysis = d.supportAnalysis
+		resp.Capabilities = convertCapabilitiesToProto(c)
+		resp.MinSQLEVersion = cr.MinSQLEVersion()
+	}
+	return resp, nil
 }
 
 // auditDriverPlugin implements goPlugin.GRPCPlugin
@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultPluginAuditTimeout   = 5 * time.Minute
+	DefaultPluginQueryTimeout   = 10 * time.Minute
+	DefaultPluginExplainTimeout = time.Minute
+	// DefaultPluginExecTimeout means no deadline, because the DDL may run for
+	// hours on the large table.
+	DefaultPluginExecTimeout time.Duration = -1
+)
+
+var (
+	ErrPluginRPCTimeout   = errors.New("plugin RPC timeout")
+	ErrPluginProcessCrash = errors.New("plugin process exited unexpectedly")
+)
+
+// PluginRPCTimeout is the deadline of each RPC call to the plugin, zero means
+// the default and negative means no deadline.
+type PluginRPCTimeout struct {
+	// Audit is used by Init, Parse, Audit, AuditBatch and GenRollbackSQL, it is
+	// multiplied by the count of SQLs for AuditBatch.
+	Audit time.Duration
+	// Exec is used by Exec and Tx.
+	Exec time.Duration
+	// Query is used by QueryPrepare and Query.
+	Query time.Duration
+	// Explain is used by Explain and the other RPCs of analysis driver.
+	Explain time.Duration
+}
+
+func (t PluginRPCTimeout) withDefault() PluginRPCTimeout {
+	if t.Audit == 0 {
+		t.Audit = DefaultPluginAuditTimeout
+	}
+	if t.Exec == 0 {
+		t.Exec = DefaultPluginExecTimeout
+	}
+	if t.Query == 0 {
+		t.Query = DefaultPluginQueryTimeout
+	}
+	if t.Explain == 0 {
+		t.Explain = DefaultPluginExplainTimeout
+	}
+	return t
+}
+
+// PluginResourceLimit caps the resource of each plugin process, zero means no
+// limit.
+type PluginResourceLimit struct {
+	// Memory is the max memory in bytes.
+	Memory uint64
+	// CPU is the max number of CPU cores, such as 0.5. It's only supported
+	// with cgroup.
+	CPU float64
+	// CgroupPath is the cgroup v2 directory delegated to SQLE, the process is
+	// put into its own sub cgroup, so the kernel kills it on memory limit
+	// breach. The memory is limited by rlimit if it's empty.
+	CgroupPath string
+}
+
+func (l PluginResourceLimit) enabled() bool {
+	return l.Memory > 0 || l.CPU > 0
+}
+
+// pluginRPCKind decides which timeout is used by the RPC.
+type pluginRPCKind int
+
+const (
+	pluginRPCAudit pluginRPCKind = iota
+	pluginRPCExec
+	pluginRPCQuery
+	pluginRPCExplain
+)
+
+func (t PluginRPCTimeout) of(kind pluginRPCKind) time.Duration {
+	switch kind {
+	case pluginRPCExec:
+		return t.Exec
+	case pluginRPCQuery:
+		return t.Query
+	case pluginRPCExplain:
+		return t.Explain
+	default:
+		return t.Audit
+	}
+}
+
+// pluginRPCCaller calls the RPC of a plugin process with deadline. The process
+// which times out is killed because it may hang or leak, then it's restarted
+// by the process pool when it's released.
+type pluginRPCCaller struct {
+	pluginName string
+	timeout    PluginRPCTimeout
+	proc       *pluginProcess
+}
+
+// call converts the timeout and crash into the clean error, the other errors
+// are returned as they are. The nil caller calls f without deadline.
+func (c *pluginRPCCaller) call(ctx context.Context, rpc string, kind pluginRPCKind, f func(ctx context.Context) error) error {
+	if c == nil {
+		return f(ctx)
+	}
+	return c.callWithTimeout(ctx, rpc, c.timeout.of(kind), f)
+}
+
+// callBatch is like call, but the timeout is scaled by the count of items in
+// the batch, because each item may take as long as the single call.
+func (c *pluginRPCCaller) callBatch(ctx context.Context, rpc string, kind pluginRPCKind, count int, f func(ctx context.Context) error) error {
+	if c == nil {
+		return f(ctx)
+	}
+	timeout := c.timeout.of(kind)
+	if timeout > 0 && count > 1 {
+		timeout *= time.Duration(count)
+	}
+	return c.callWithTimeout(ctx, rpc, timeout, f)
+}
+
+func (c *pluginRPCCaller) callWithTimeout(ctx context.Context, rpc string, timeout time.Duration, f func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rpcCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		rpcCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := f(rpcCtx)
+	if err == nil {
+		return nil
+	}
+
+	// the deadline of caller is not the timeout of plugin.
+	if ctx.Err() == nil && rpcCtx.Err() == context.DeadlineExceeded {
+		c.proc.kill()
+		return fmt.Errorf("%w: %v of plugin %v doesn't finish in %v, the plugin process is killed and will be restarted",
+			ErrPluginRPCTimeout, rpc, c.pluginName, timeout)
+	}
+	if code := status.Code(err); (code == codes.Unavailable || code == codes.Canceled) && c.waitExited() {
+		return fmt.Errorf("%w: %v of plugin %v failed, the plugin process may crash or exceed the resource limit, it will be restarted",
+			ErrPluginProcessCrash, rpc, c.pluginName)
+	}
+	return err
+}
+
+// waitExited waits for the process to exit for a moment, because the broken
+// connection may be found before the exit of the process.
+func (c *pluginRPCCaller) waitExited() bool {
+	for i := 0; i < 10; i++ {
+		if c.proc.exited() {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+//go:build linux
+// +build linux
+
+package driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// cgroupCPUPeriod is the period of cpu.max in microseconds.
+const cgroupCPUPeriod = 100000
+
+var cgroupNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// limitPluginProcess caps the resource of the started plugin process, it
+// returns the function to clean up after the process is killed. The process
+// runs without limit for a moment before it's called, it's fine because the
+// plugin does nothing before the first RPC.
+func limitPluginProcess(pluginName string, pid int, limit PluginResourceLimit) (func(), error) {
+	if !limit.enabled() {
+		return func() {}, nil
+	}
+	if limit.CgroupPath != "" {
+		return limitByCgroup(pluginName, pid, limit)
+	}
+	if limit.CPU > 0 {
+		return nil, fmt.Errorf("CPU limit of plugin process requires cgroup path")
+	}
+	// RLIMIT_DATA includes the heap of the plugin, the allocation fails when
+	// it exceeds the limit, and the Go runtime exits with "out of memory".
+	rlimit := &unix.Rlimit{Cur: limit.Memory, Max: limit.Memory}
+	if err := unix.Prlimit(pid, unix.RLIMIT_DATA, rlimit, nil); err != nil {
+		return nil, fmt.Errorf("set memory rlimit of plugin process failed: %v", err)
+	}
+	return func() {}, nil
+}
+
+func limitByCgroup(pluginName string, pid int, limit PluginResourceLimit) (func(), error) {
+	dir := filepath.Join(limit.CgroupPath,
+		fmt.Sprintf("%v-%v", cgroupNameRegexp.ReplaceAllString(pluginName, "_"), pid))
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create cgroup of plugin process failed: %v", err)
+	}
+	cleanup := func() {
+		// the cgroup can be removed only if the process has exited.
+		_ = os.Remove(dir)
+	}
+
+	files := [][2]string{}
+	if limit.Memory > 0 {
+		files = append(files, [2]string{"memory.max", strconv.FormatUint(limit.Memory, 10)})
+	}
+	if limit.CPU > 0 {
+		quota := int64(limit.CPU * cgroupCPUPeriod)
+		files = append(files, [2]string{"cpu.max", fmt.Sprintf("%v %v", quota, cgroupCPUPeriod)})
+	}
+	// the process is moved into the cgroup after the limits are set.
+	files = append(files, [2]string{"cgroup.procs", strconv.Itoa(pid)})
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f[0]), []byte(f[1]), 0644); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("write %v of plugin process cgroup failed: %v", f[0], err)
+		}
+	}
+	return cleanup, nil
+}
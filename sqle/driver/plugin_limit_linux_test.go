@@ -0,0 +1,74 @@
+//go:build linux
+// +build linux
+
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestLimitPluginProcess(t *testing.T) {
+	cleanup, err := limitPluginProcess("test", 1, PluginResourceLimit{})
+	assert.NoError(t, err)
+	cleanup()
+
+	_, err = limitPluginProcess("test", 1, PluginResourceLimit{CPU: 1})
+	assert.EqualError(t, err, "CPU limit of plugin process requires cgroup path")
+
+	cmd := exec.Command("sleep", "10")
+	assert.NoError(t, cmd.Start())
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	cleanup, err = limitPluginProcess("test", cmd.Process.Pid, PluginResourceLimit{Memory: 512 * 1024 * 1024})
+	assert.NoError(t, err)
+	defer cleanup()
+
+	rlimit := &unix.Rlimit{}
+	assert.NoError(t, unix.Prlimit(cmd.Process.Pid, unix.RLIMIT_DATA, nil, rlimit))
+	assert.Equal(t, uint64(512*1024*1024), rlimit.Cur)
+	assert.Equal(t, uint64(512*1024*1024), rlimit.Max)
+}
+
+func TestLimitPluginProcessByCgroup(t *testing.T) {
+	// the files of cgroup are regular files in the temporary directory.
+	cgroupPath, err := ioutil.TempDir("", "sqle-cgroup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cgroupPath)
+
+	limit := PluginResourceLimit{
+		Memory:     256 * 1024 * 1024,
+		CPU:        0.5,
+		CgroupPath: cgroupPath,
+	}
+	_, err = limitPluginProcess("SQL Server", 123, limit)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(cgroupPath, "SQL_Server-123")
+	for file, expect := range map[string]string{
+		"memory.max":   strconv.Itoa(256 * 1024 * 1024),
+		"cpu.max":      "50000 100000",
+		"cgroup.procs": "123",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, expect, string(b))
+	}
+
+	// the cgroup of the same process can't be created twice.
+	_, err = limitPluginProcess("SQL Server", 123, limit)
+	assert.Error(t, err)
+
+	// the cgroup path doesn't exist.
+	_, err = limitPluginProcess("test", 1, PluginResourceLimit{Memory: 1, CgroupPath: filepath.Join(cgroupPath, "not_exist")})
+	assert.Error(t, err)
+}
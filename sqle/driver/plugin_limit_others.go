@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package driver
+
+import "fmt"
+
+func limitPluginProcess(pluginName string, pid int, limit PluginResourceLimit) (func(), error) {
+	if !limit.enabled() {
+		return func() {}, nil
+	}
+	return nil, fmt.Errorf("resource limit of plugin process is only supported on linux")
+}
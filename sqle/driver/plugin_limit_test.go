@@ -0,0 +1,83 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPluginRPCTimeoutWithDefault(t *testing.T) {
+	timeout := PluginRPCTimeout{}.withDefault()
+	assert.Equal(t, DefaultPluginAuditTimeout, timeout.Audit)
+	assert.Equal(t, DefaultPluginExecTimeout, timeout.Exec)
+	assert.Equal(t, DefaultPluginQueryTimeout, timeout.Query)
+	assert.Equal(t, DefaultPluginExplainTimeout, timeout.Explain)
+
+	timeout = PluginRPCTimeout{Audit: time.Second, Exec: time.Hour}.withDefault()
+	assert.Equal(t, time.Second, timeout.Audit)
+	assert.Equal(t, time.Hour, timeout.Exec)
+	assert.Equal(t, time.Hour, timeout.of(pluginRPCExec))
+	assert.Equal(t, DefaultPluginQueryTimeout, timeout.of(pluginRPCQuery))
+
+	cfg := (&PluginPoolConfig{RPCTimeout: PluginRPCTimeout{Query: time.Second}}).withDefault()
+	assert.Equal(t, time.Second, cfg.RPCTimeout.Query)
+	assert.Equal(t, DefaultPluginAuditTimeout, cfg.RPCTimeout.Audit)
+}
+
+// blockingRPC returns the error like gRPC when the context is done.
+func blockingRPC(ctx context.Context) error {
+	<-ctx.Done()
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+func TestPluginRPCCallerTimeout(t *testing.T) {
+	proc, _ := newMockProcess()
+	caller := &pluginRPCCaller{
+		pluginName: "test",
+		timeout: PluginRPCTimeout{
+			Audit: 10 * time.Millisecond,
+			Exec:  -1,
+		}.withDefault(),
+		proc: proc,
+	}
+
+	err := caller.call(context.TODO(), "Audit", pluginRPCAudit, blockingRPC)
+	assert.True(t, errors.Is(err, ErrPluginRPCTimeout))
+	assert.Contains(t, err.Error(), "Audit of plugin test doesn't finish in 10ms")
+
+	// no deadline
+	err = caller.call(context.TODO(), "Exec", pluginRPCExec, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// the deadline of caller isn't regarded as the timeout of plugin.
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	err = caller.call(ctx, "Query", pluginRPCQuery, blockingRPC)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.False(t, errors.Is(err, ErrPluginRPCTimeout))
+
+	// the other errors are returned as they are.
+	rpcErr := status.Error(codes.Unavailable, "connection refused")
+	err = caller.call(context.TODO(), "Explain", pluginRPCExplain, func(ctx context.Context) error {
+		return rpcErr
+	})
+	assert.Equal(t, rpcErr, err)
+
+	// nil caller calls without deadline.
+	var nilCaller *pluginRPCCaller
+	err = nilCaller.call(context.TODO(), "Audit", pluginRPCAudit, func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,408 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// pluginManagerMu makes the plugins loaded and unloaded one by one.
+	pluginManagerMu = &sync.Mutex{}
+	pluginPath      string
+	pluginPoolCfg   *PluginPoolConfig
+)
+
+var (
+	ErrPluginPathNotConfigured = errors.New("plugin path is not configured")
+	ErrPluginNotExist          = errors.New("plugin is not exist")
+	ErrBuiltInDriver           = errors.New("built-in driver can't be replaced or unloaded")
+)
+
+// pluginMeta is read from the plugin binary before it's registered.
+type pluginMeta struct {
+	name             string
+	version          int32
+	path             string
+	checksum         string
+	rules            []*Rule
+	additionalParams params.Params
+	supportQuery     bool
+	supportAnalysis  bool
+	// capabilities is nil if the plugin doesn't declare it.
+	capabilities   *Capabilities
+	minSQLEVersion string
+}
+
+// PluginInfo is the info of the plugin loaded by SQLE.
+type PluginInfo struct {
+	Name            string
+	Version         int32
+	Path            string
+	Checksum        string
+	Rules           []*Rule
+	SupportQuery    bool
+	SupportAnalysis bool
+	// Capabilities is nil if the plugin doesn't declare it.
+	Capabilities   *Capabilities
+	MinSQLEVersion string
+}
+
+func (m *pluginMeta) info() *PluginInfo {
+	return &PluginInfo{
+		Name:            m.name,
+		Version:         m.version,
+		Path:            m.path,
+		Checksum:        m.checksum,
+		Rules:           m.rules,
+		SupportQuery:    m.supportQuery,
+		SupportAnalysis: m.supportAnalysis,
+		Capabilities:    m.capabilities,
+		MinSQLEVersion:  m.minSQLEVersion,
+	}
+}
+
+func logPluginInited(pluginName, pluginType string) {
+	log.Logger().WithFields(logrus.Fields{
+		"plugin_name": pluginName,
+		"plugin_type": pluginType,
+	}).Infoln("plugin inited")
+}
+
+// registerPluginDrivers registers the drivers of plugin at startup, it panics
+// if the driver name is duplicated.
+func registerPluginDrivers(meta *pluginMeta) {
+	RegisterAuditDriver(meta.name, meta.rules, meta.additionalParams)
+	setCapabilities(meta.name, meta.capabilities)
+	logPluginInited(meta.name, PluginNameAuditDriver)
+	if meta.supportQuery {
+		RegisterSQLQueryDriver(meta.name)
+		logPluginInited(meta.name, PluginNameQueryDriver)
+	}
+	if meta.supportAnalysis {
+		RegisterAnalysisDriver(meta.name)
+		logPluginInited(meta.name, PluginNameAnalysisDriver)
+	}
+}
+
+// setPluginDrivers registers the drivers of plugin at runtime, the drivers of
+// the plugin with the same name are overridden.
+func setPluginDrivers(meta *pluginMeta, h driverManagerHandler) {
+	rulesMu.Lock()
+	if rules == nil {
+		rules = make(map[string][]*Rule)
+	}
+	rules[meta.name] = meta.rules
+	rulesMu.Unlock()
+
+	additionalParamsMu.Lock()
+	if additionalParams == nil {
+		additionalParams = make(map[string]params.Params)
+	}
+	additionalParams[meta.name] = meta.additionalParams
+	additionalParamsMu.Unlock()
+
+	setCapabilities(meta.name, meta.capabilities)
+
+	driversMu.Lock()
+	auditDrivers[meta.name] = struct{}{}
+	driversMu.Unlock()
+
+	queryDriverMu.Lock()
+	if meta.supportQuery {
+		queryDrivers[meta.name] = struct{}{}
+	} else {
+		delete(queryDrivers, meta.name)
+	}
+	queryDriverMu.Unlock()
+
+	analysisDriverMu.Lock()
+	if meta.supportAnalysis {
+		analysisDrivers[meta.name] = struct{}{}
+	} else {
+		delete(analysisDrivers, meta.name)
+	}
+	analysisDriverMu.Unlock()
+
+	// the handler is set at last, so the new driver manager uses the new drivers.
+	driverManagerMu.Lock()
+	driverManagers[meta.name] = h
+	driverManagerMu.Unlock()
+}
+
+// unsetPluginDrivers removes the drivers of plugin.
+func unsetPluginDrivers(name string) {
+	// the handler is removed at first, so no more driver manager is created.
+	driverManagerMu.Lock()
+	delete(driverManagers, name)
+	driverManagerMu.Unlock()
+
+	driversMu.Lock()
+	delete(auditDrivers, name)
+	driversMu.Unlock()
+
+	queryDriverMu.Lock()
+	delete(queryDrivers, name)
+	queryDriverMu.Unlock()
+
+	analysisDriverMu.Lock()
+	delete(analysisDrivers, name)
+	analysisDriverMu.Unlock()
+
+	rulesMu.Lock()
+	delete(rules, name)
+	rulesMu.Unlock()
+
+	additionalParamsMu.Lock()
+	delete(additionalParams, name)
+	additionalParamsMu.Unlock()
+
+	setCapabilities(name, nil)
+}
+
+func getDriverManagerHandler(name string) (driverManagerHandler, bool) {
+	driverManagerMu.RLock()
+	defer driverManagerMu.RUnlock()
+	h, exist := driverManagers[name]
+	return h, exist
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AllPlugins returns the plugins loaded by SQLE.
+func AllPlugins() []*PluginInfo {
+	driverManagerMu.RLock()
+	defer driverManagerMu.RUnlock()
+	plugins := []*PluginInfo{}
+	for _, h := range driverManagers {
+		if h.meta != nil {
+			plugins = append(plugins, h.meta.info())
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// InstallPlugin saves the plugin binary to the plugin path and loads it, the
+// loaded plugin with the same name is replaced.
+func InstallPlugin(fileName string, r io.Reader) (*PluginInfo, error) {
+	info, old, err := installPlugin(fileName, r)
+	if err != nil {
+		return nil, err
+	}
+	// the old plugin is drained without the lock, the other plugins can be
+	// managed in the meantime.
+	if old != nil {
+		drainPool(old)
+	}
+	return info, nil
+}
+
+func installPlugin(fileName string, r io.Reader) (*PluginInfo, *pluginPool, error) {
+	pluginManagerMu.Lock()
+	defer pluginManagerMu.Unlock()
+	if pluginPath == "" {
+		return nil, nil, ErrPluginPathNotConfigured
+	}
+	fileName = filepath.Base(fileName)
+	if fileName == "" || fileName == "." || fileName == string(filepath.Separator) || strings.HasPrefix(fileName, ".") {
+		return nil, nil, fmt.Errorf("invalid plugin file name %v", fileName)
+	}
+
+	// the uploading file is not executable, so it's skipped by InitPlugins
+	// if SQLE exits before it's loaded.
+	tmp, err := ioutil.TempFile(pluginPath, ".upload-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := newClientFromFile(tmp.Name()).readMeta()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load plugin: %v", err)
+	}
+	target := filepath.Join(pluginPath, fileName)
+	oldPath := ""
+	if h, exist := getDriverManagerHandler(meta.name); exist {
+		if h.meta == nil {
+			return nil, nil, ErrBuiltInDriver
+		}
+		// the processes of the old plugin may be started from its binary
+		// until the new one is loaded, so the new binary is staged beside.
+		oldPath = h.meta.path
+		target = versionedPluginPath(oldPath, meta.checksum)
+	} else {
+		for _, p := range AllPlugins() {
+			if p.Path == target {
+				return nil, nil, fmt.Errorf("plugin file %v is used by plugin %v", fileName, p.Name)
+			}
+		}
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, nil, err
+	}
+	info, old, err := loadPlugin(target)
+	if err != nil {
+		if target != oldPath {
+			os.Remove(target)
+		}
+		return nil, nil, err
+	}
+	// keep one binary for one plugin, otherwise it's duplicated at startup.
+	// The running processes of the old plugin are not affected.
+	if oldPath != "" && oldPath != target {
+		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+			log.Logger().WithField("plugin_name", meta.name).
+				Warnf("remove the binary of old plugin failed, it should be removed manually: %v", err)
+		}
+	}
+	return info, old, nil
+}
+
+var pluginVersionSuffixRegexp = regexp.MustCompile(`\.[0-9a-f]{12}$`)
+
+// versionedPluginPath returns the path of the new binary which replaces the
+// binary of path, it's suffixed with the checksum of the new binary, such as
+// "oracle-plugin.2cf24dba5fb0". It's the same as path if the binary isn't
+// changed.
+func versionedPluginPath(path, checksum string) string {
+	if len(checksum) > 12 {
+		checksum = checksum[:12]
+	}
+	return pluginVersionSuffixRegexp.ReplaceAllString(path, "") + "." + checksum
+}
+
+// ReloadPlugin loads the plugin from its binary again, it's used after the
+// binary is replaced.
+func ReloadPlugin(name string) (*PluginInfo, error) {
+	info, old, err := reloadPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil {
+		drainPool(old)
+	}
+	return info, nil
+}
+
+func reloadPlugin(name string) (*PluginInfo, *pluginPool, error) {
+	pluginManagerMu.Lock()
+	defer pluginManagerMu.Unlock()
+	h, exist := getDriverManagerHandler(name)
+	if !exist {
+		return nil, nil, ErrPluginNotExist
+	}
+	if h.meta == nil {
+		return nil, nil, ErrBuiltInDriver
+	}
+	return loadPlugin(h.meta.path)
+}
+
+// loadPlugin loads the plugin from binary. If the plugin with the same name is
+// loaded, the new driver managers use the new plugin at once, and the pool of
+// the old one is returned, the caller drains it after releasing the lock.
+func loadPlugin(path string) (*PluginInfo, *pluginPool, error) {
+	client := newClientFromFile(path)
+	meta, err := client.readMeta()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load plugin: %v", err)
+	}
+	old, exist := getDriverManagerHandler(meta.name)
+	if exist && old.meta == nil {
+		return nil, nil, ErrBuiltInDriver
+	}
+
+	h := newPluginDriverManagerHandler(client, meta, pluginPoolCfg)
+	setPluginDrivers(meta, h)
+	go h.pool.run()
+	log.Logger().WithFields(logrus.Fields{
+		"plugin_name": meta.name,
+		"checksum":    meta.checksum,
+	}).Infoln("plugin loaded")
+
+	if exist {
+		return meta.info(), old.pool, nil
+	}
+	return meta.info(), nil, nil
+}
+
+// UnloadPlugin unloads the plugin and removes its binary, so it's not loaded
+// at next startup. checkUsage is called under the lock of plugin manager, the
+// plugin isn't unloaded if it returns error, e.g. the plugin is still used.
+func UnloadPlugin(name string, checkUsage func(name string) error) error {
+	pool, err := unloadPlugin(name, checkUsage)
+	// the plugin is unloaded even if its binary fails to be removed.
+	if pool != nil {
+		drainPool(pool)
+	}
+	return err
+}
+
+func unloadPlugin(name string, checkUsage func(name string) error) (*pluginPool, error) {
+	pluginManagerMu.Lock()
+	defer pluginManagerMu.Unlock()
+	h, exist := getDriverManagerHandler(name)
+	if !exist {
+		return nil, ErrPluginNotExist
+	}
+	if h.meta == nil {
+		return nil, ErrBuiltInDriver
+	}
+	if checkUsage != nil {
+		if err := checkUsage(name); err != nil {
+			return nil, err
+		}
+	}
+
+	unsetPluginDrivers(name)
+	log.Logger().WithField("plugin_name", name).Infoln("plugin unloaded")
+
+	if err := os.Remove(h.meta.path); err != nil && !os.IsNotExist(err) {
+		return h.pool, err
+	}
+	return h.pool, nil
+}
+
+func drainPool(pool *pluginPool) {
+	if !pool.drain() {
+		log.Logger().WithField("plugin_name", pool.name).
+			Warnln("plugin processes are still in use after drain timeout, they will be killed after released")
+	}
+}
@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAndUnsetPluginDrivers(t *testing.T) {
+	meta := &pluginMeta{
+		name:         "test_plugin",
+		version:      DefaultPluginVersion,
+		path:         "/opt/sqle/plugins/test_plugin",
+		rules:        []*Rule{{Name: "rule_1", Level: RuleLevelError}},
+		supportQuery: true,
+	}
+	newHandler := func(meta *pluginMeta) driverManagerHandler {
+		return driverManagerHandler{
+			newDriverManagerFunc: nil,
+			pool:                 newPluginPool(meta.name, nil, newMockProcess),
+			meta:                 meta,
+		}
+	}
+	setPluginDrivers(meta, newHandler(meta))
+	defer unsetPluginDrivers(meta.name)
+
+	assert.True(t, hasAuditDriver("test_plugin"))
+	assert.True(t, hasQueryDriver("test_plugin"))
+	assert.False(t, hasAnalysisDriver("test_plugin"))
+	assert.Len(t, AllRules()["test_plugin"], 1)
+	assert.Contains(t, AllPlugins(), meta.info())
+
+	// the new version doesn't support query and has more rules
+	newMeta := &pluginMeta{
+		name:            "test_plugin",
+		version:         DefaultPluginVersion,
+		path:            meta.path,
+		rules:           []*Rule{{Name: "rule_1"}, {Name: "rule_2"}},
+		supportAnalysis: true,
+	}
+	setPluginDrivers(newMeta, newHandler(newMeta))
+	assert.False(t, hasQueryDriver("test_plugin"))
+	assert.True(t, hasAnalysisDriver("test_plugin"))
+	assert.Len(t, AllRules()["test_plugin"], 2)
+
+	unsetPluginDrivers("test_plugin")
+	assert.False(t, hasAuditDriver("test_plugin"))
+	assert.False(t, hasAnalysisDriver("test_plugin"))
+	assert.NotContains(t, AllRules(), "test_plugin")
+	_, exist := getDriverManagerHandler("test_plugin")
+	assert.False(t, exist)
+}
+
+func TestPluginPoolDrain(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 2, DrainTimeout: time.Second}, newMockProcess)
+	proc, err := pool.acquire()
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.release(proc)
+	}()
+	assert.True(t, pool.drain())
+	assert.Equal(t, 0, pool.Stats().Idle)
+
+	// the closed pool can't be acquired
+	_, err = pool.acquire()
+	assert.Equal(t, errPluginPoolClosed, err)
+	assert.Len(t, pool.slots, 0)
+
+	// the process is still in use after timeout
+	pool = newPluginPool("test", &PluginPoolConfig{Size: 2, DrainTimeout: 10 * time.Millisecond}, newMockProcess)
+	_, err = pool.acquire()
+	assert.NoError(t, err)
+	assert.False(t, pool.drain())
+}
+
+func TestManagePluginErrors(t *testing.T) {
+	_, err := InstallPlugin("test_plugin", bytes.NewBufferString("binary"))
+	assert.Equal(t, ErrPluginPathNotConfigured, err)
+	_, err = ReloadPlugin("not_exist_plugin")
+	assert.Equal(t, ErrPluginNotExist, err)
+	assert.Equal(t, ErrPluginNotExist, UnloadPlugin("not_exist_plugin", nil))
+}
+
+func TestFileChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test_plugin")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("hello"), 0755))
+
+	checksum, err := fileChecksum(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", checksum)
+}
+
+func TestVersionedPluginPath(t *testing.T) {
+	checksum := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	assert.Equal(t, "/opt/sqle/plugins/oracle.2cf24dba5fb0", versionedPluginPath("/opt/sqle/plugins/oracle", checksum))
+	// the version of the old binary is replaced.
+	assert.Equal(t, "/opt/sqle/plugins/oracle.2cf24dba5fb0", versionedPluginPath("/opt/sqle/plugins/oracle.486ea46224d1", checksum))
+	// the binary isn't changed.
+	assert.Equal(t, "/opt/sqle/plugins/oracle.2cf24dba5fb0", versionedPluginPath("/opt/sqle/plugins/oracle.2cf24dba5fb0", checksum))
+	assert.Equal(t, "/opt/sqle/plugins/oracle.v1.2cf24dba5fb0", versionedPluginPath("/opt/sqle/plugins/oracle.v1", checksum))
+}
+
+func TestUnloadPluginDrainsWithoutLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test_plugin")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("binary"), 0755))
+
+	meta := &pluginMeta{name: "test_plugin", version: DefaultPluginVersion, path: path}
+	pool := newPluginPool(meta.name, &PluginPoolConfig{Size: 1, DrainTimeout: time.Second}, newMockProcess)
+	setPluginDrivers(meta, driverManagerHandler{pool: pool, meta: meta})
+	defer unsetPluginDrivers(meta.name)
+	proc, err := pool.acquire()
+	assert.NoError(t, err)
+
+	// the plugin in use isn't unloaded.
+	errInUse := errors.New("plugin is in use")
+	assert.Equal(t, errInUse, UnloadPlugin(meta.name, func(name string) error {
+		assert.Equal(t, meta.name, name)
+		return errInUse
+	}))
+	_, exist := getDriverManagerHandler(meta.name)
+	assert.True(t, exist)
+
+	done := make(chan error)
+	go func() {
+		done <- UnloadPlugin(meta.name, func(name string) error {
+			return nil
+		})
+	}()
+	// the other plugins can be managed while the unloaded plugin is drained.
+	time.Sleep(50 * time.Millisecond)
+	_, err = ReloadPlugin("not_exist_plugin")
+	assert.Equal(t, ErrPluginNotExist, err)
+	select {
+	case <-done:
+		t.Fatal("the plugin is drained before the process in use is released")
+	default:
+	}
+
+	pool.release(proc)
+	assert.NoError(t, <-done)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,348 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+)
+
+const (
+	DefaultPluginPoolSize            = 5
+	DefaultPluginHealthCheckInterval = 30 * time.Second
+	DefaultPluginAcquireTimeout      = 60 * time.Second
+	DefaultPluginDrainTimeout        = 60 * time.Second
+)
+
+// errPluginPoolClosed means the plugin is reloaded or unloaded.
+var errPluginPoolClosed = errors.New("plugin process pool is closed")
+
+// PluginPoolConfig is the configuration of the plugin process pool and its
+// processes, the zero value of each field means the default value.
+type PluginPoolConfig struct {
+	// Size is the max number of processes of each plugin.
+	Size int
+	// HealthCheckInterval is the interval to ping the idle processes.
+	HealthCheckInterval time.Duration
+	// AcquireTimeout is the max time to wait for an available process.
+	AcquireTimeout time.Duration
+	// DrainTimeout is the max time to wait for the processes in use to be
+	// released when the plugin is reloaded or unloaded.
+	DrainTimeout time.Duration
+	RPCTimeout   PluginRPCTimeout
+	// ResourceLimit is applied to each process, the process is killed and
+	// restarted if it exceeds the limit.
+	ResourceLimit PluginResourceLimit
+}
+
+func (c *PluginPoolConfig) withDefault() *PluginPoolConfig {
+	cfg := &PluginPoolConfig{
+		Size:                DefaultPluginPoolSize,
+		HealthCheckInterval: DefaultPluginHealthCheckInterval,
+		AcquireTimeout:      DefaultPluginAcquireTimeout,
+		DrainTimeout:        DefaultPluginDrainTimeout,
+		RPCTimeout:          PluginRPCTimeout{}.withDefault(),
+	}
+	if c == nil {
+		return cfg
+	}
+	if c.Size > 0 {
+		cfg.Size = c.Size
+	}
+	if c.HealthCheckInterval > 0 {
+		cfg.HealthCheckInterval = c.HealthCheckInterval
+	}
+	if c.AcquireTimeout > 0 {
+		cfg.AcquireTimeout = c.AcquireTimeout
+	}
+	if c.DrainTimeout > 0 {
+		cfg.DrainTimeout = c.DrainTimeout
+	}
+	cfg.RPCTimeout = c.RPCTimeout.withDefault()
+	cfg.ResourceLimit = c.ResourceLimit
+	return cfg
+}
+
+// PluginPoolStats is the usage of the plugin process pool.
+type PluginPoolStats struct {
+	PluginName string
+	Size       int
+	InUse      int
+	Idle       int
+	// Started is the number of processes started, include the restarted ones.
+	Started uint64
+	// Restarted is the number of processes started to replace the crashed or
+	// unhealthy ones.
+	Restarted uint64
+	Acquired  uint64
+	// Waited is the number of acquires which wait for an available process.
+	Waited        uint64
+	AcquireFailed uint64
+}
+
+// pluginProcess is a plugin process which is used by one driver manager at a
+// time, because the plugin keeps the driver initialized by the last Init.
+type pluginProcess struct {
+	client   *goPlugin.Client
+	protocol goPlugin.ClientProtocol
+	// cleanup releases the resource limit after the process is killed.
+	cleanup func()
+}
+
+func (p *pluginProcess) exited() bool {
+	return p.client.Exited()
+}
+
+func (p *pluginProcess) healthy() bool {
+	return !p.exited() && p.protocol.Ping() == nil
+}
+
+func (p *pluginProcess) kill() {
+	p.client.Kill()
+	if p.cleanup != nil {
+		p.cleanup()
+	}
+}
+
+type pluginPool struct {
+	name       string
+	cfg        *PluginPoolConfig
+	newProcess func() (*pluginProcess, error)
+
+	// slots limits the number of processes in use.
+	slots   chan struct{}
+	mutex   *sync.Mutex
+	idle    []*pluginProcess
+	stats   PluginPoolStats
+	closed  bool
+	closeCh chan struct{}
+}
+
+func newPluginPool(name string, cfg *PluginPoolConfig, newProcess func() (*pluginProcess, error)) *pluginPool {
+	cfg = cfg.withDefault()
+	return &pluginPool{
+		name:       name,
+		cfg:        cfg,
+		newProcess: newProcess,
+		slots:      make(chan struct{}, cfg.Size),
+		mutex:      &sync.Mutex{},
+		idle:       []*pluginProcess{},
+		stats:      PluginPoolStats{PluginName: name, Size: cfg.Size},
+		closeCh:    make(chan struct{}),
+	}
+}
+
+// acquire returns an idle process or starts a new one, it waits if all the
+// processes are in use.
+func (p *pluginPool) acquire() (*pluginProcess, error) {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		p.mutex.Lock()
+		p.stats.Waited++
+		p.mutex.Unlock()
+
+		timer := time.NewTimer(p.cfg.AcquireTimeout)
+		defer timer.Stop()
+		select {
+		case p.slots <- struct{}{}:
+		case <-timer.C:
+			p.mutex.Lock()
+			p.stats.AcquireFailed++
+			p.mutex.Unlock()
+			return nil, fmt.Errorf("no process of plugin %v is available in %v", p.name, p.cfg.AcquireTimeout)
+		}
+	}
+
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		<-p.slots
+		return nil, errPluginPoolClosed
+	}
+	var proc *pluginProcess
+	for len(p.idle) > 0 && proc == nil {
+		proc = p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if proc.exited() {
+			proc.kill()
+			proc = nil
+		}
+	}
+	p.mutex.Unlock()
+
+	if proc == nil {
+		var err error
+		proc, err = p.newProcess()
+		if err != nil {
+			<-p.slots
+			p.mutex.Lock()
+			p.stats.AcquireFailed++
+			p.mutex.Unlock()
+			return nil, fmt.Errorf("start process of plugin %v failed: %v", p.name, err)
+		}
+		p.mutex.Lock()
+		p.stats.Started++
+		p.mutex.Unlock()
+	}
+
+	p.mutex.Lock()
+	p.stats.InUse++
+	p.stats.Acquired++
+	p.mutex.Unlock()
+	return proc, nil
+}
+
+// release puts the process back to the pool, the crashed process is replaced
+// by a new one in background.
+func (p *pluginPool) release(proc *pluginProcess) {
+	p.mutex.Lock()
+	p.stats.InUse--
+	p.mutex.Unlock()
+	<-p.slots
+
+	if proc.exited() {
+		proc.kill()
+		log.NewEntry().Warnf("process of plugin %v has exited, restart it", p.name)
+		go p.restart()
+		return
+	}
+	if !p.putIdle(proc) {
+		proc.kill()
+	}
+}
+
+// putIdle adds the process to the idle list, it returns false if the pool is
+// closed or full.
+func (p *pluginPool) putIdle(proc *pluginProcess) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed || len(p.idle)+p.stats.InUse >= p.cfg.Size {
+		return false
+	}
+	p.idle = append(p.idle, proc)
+	return true
+}
+
+func (p *pluginPool) restart() {
+	p.mutex.Lock()
+	closed := p.closed
+	p.mutex.Unlock()
+	if closed {
+		return
+	}
+	proc, err := p.newProcess()
+	if err != nil {
+		log.NewEntry().Errorf("restart process of plugin %v failed: %v", p.name, err)
+		return
+	}
+	p.mutex.Lock()
+	p.stats.Started++
+	p.stats.Restarted++
+	p.mutex.Unlock()
+	if !p.putIdle(proc) {
+		proc.kill()
+	}
+}
+
+// healthCheck pings the idle processes, the unhealthy ones are killed and
+// restarted.
+func (p *pluginPool) healthCheck() {
+	p.mutex.Lock()
+	procs := p.idle
+	p.idle = []*pluginProcess{}
+	p.mutex.Unlock()
+
+	unhealthy := 0
+	for _, proc := range procs {
+		if !proc.healthy() {
+			log.NewEntry().Warnf("process of plugin %v is unhealthy, restart it", p.name)
+			proc.kill()
+			unhealthy++
+			continue
+		}
+		if !p.putIdle(proc) {
+			proc.kill()
+		}
+	}
+	for i := 0; i < unhealthy; i++ {
+		p.restart()
+	}
+}
+
+func (p *pluginPool) run() {
+	tick := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-p.closeCh:
+			return
+		case <-tick.C:
+			p.healthCheck()
+		}
+	}
+}
+
+// close kills the idle processes, the processes in use are killed when they
+// are released.
+func (p *pluginPool) close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, proc := range p.idle {
+		proc.kill()
+	}
+	p.idle = []*pluginProcess{}
+	close(p.closeCh)
+}
+
+// drain closes the pool and waits for the processes in use to be released, it
+// returns false if there are still processes in use after timeout.
+func (p *pluginPool) drain() bool {
+	p.close()
+	deadline := time.Now().Add(p.cfg.DrainTimeout)
+	for {
+		p.mutex.Lock()
+		inUse := p.stats.InUse
+		p.mutex.Unlock()
+		if inUse == 0 {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (p *pluginPool) Stats() PluginPoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	stats := p.stats
+	stats.Idle = len(p.idle)
+	return stats
+}
+
+// PluginPoolsStats returns the usage of the process pools of all plugins.
+func PluginPoolsStats() []PluginPoolStats {
+	driverManagerMu.RLock()
+	defer driverManagerMu.RUnlock()
+	stats := []PluginPoolStats{}
+	for _, h := range driverManagers {
+		if h.pool != nil {
+			stats = append(stats, h.pool.Stats())
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].PluginName < stats[j].PluginName
+	})
+	return stats
+}
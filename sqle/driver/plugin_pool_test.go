@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	goPlugin "github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockClientProtocol struct {
+	mutex   *sync.Mutex
+	pingErr error
+}
+
+func (m *mockClientProtocol) Close() error {
+	return nil
+}
+
+func (m *mockClientProtocol) Dispense(string) (interface{}, error) {
+	return nil, nil
+}
+
+func (m *mockClientProtocol) Ping() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.pingErr
+}
+
+func (m *mockClientProtocol) setPingErr(err error) {
+	m.mutex.Lock()
+	m.pingErr = err
+	m.mutex.Unlock()
+}
+
+// newMockProcess returns a process which is not started, so it's never exited
+// and killing it does nothing.
+func newMockProcess() (*pluginProcess, error) {
+	return &pluginProcess{
+		client: goPlugin.NewClient(&goPlugin.ClientConfig{
+			HandshakeConfig: handshakeConfig,
+			Cmd:             exec.Command("true"),
+		}),
+		protocol: &mockClientProtocol{mutex: &sync.Mutex{}},
+	}, nil
+}
+
+func TestPluginPoolConfigWithDefault(t *testing.T) {
+	var cfg *PluginPoolConfig
+	assert.Equal(t, DefaultPluginPoolSize, cfg.withDefault().Size)
+
+	cfg = &PluginPoolConfig{Size: 2}
+	assert.Equal(t, 2, cfg.withDefault().Size)
+	assert.Equal(t, DefaultPluginHealthCheckInterval, cfg.withDefault().HealthCheckInterval)
+	assert.Equal(t, DefaultPluginAcquireTimeout, cfg.withDefault().AcquireTimeout)
+}
+
+func TestPluginPoolAcquireAndRelease(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 2, AcquireTimeout: 50 * time.Millisecond}, newMockProcess)
+
+	p1, err := pool.acquire()
+	assert.NoError(t, err)
+	p2, err := pool.acquire()
+	assert.NoError(t, err)
+	assert.NotSame(t, p1, p2)
+	assert.Equal(t, 2, pool.Stats().InUse)
+
+	// all processes are in use
+	_, err = pool.acquire()
+	assert.Error(t, err)
+
+	// the released process is reused
+	pool.release(p1)
+	p3, err := pool.acquire()
+	assert.NoError(t, err)
+	assert.Same(t, p1, p3)
+
+	pool.release(p2)
+	pool.release(p3)
+	stats := pool.Stats()
+	assert.Equal(t, PluginPoolStats{
+		PluginName:    "test",
+		Size:          2,
+		InUse:         0,
+		Idle:          2,
+		Started:       2,
+		Acquired:      3,
+		Waited:        1,
+		AcquireFailed: 1,
+	}, stats)
+}
+
+func TestPluginPoolWaitForRelease(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 1, AcquireTimeout: time.Second}, newMockProcess)
+	p1, err := pool.acquire()
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.release(p1)
+	}()
+	p2, err := pool.acquire()
+	assert.NoError(t, err)
+	assert.Same(t, p1, p2)
+	assert.Equal(t, uint64(1), pool.Stats().Waited)
+}
+
+func TestPluginPoolStartFailed(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 1}, func() (*pluginProcess, error) {
+		return nil, fmt.Errorf("exec format error")
+	})
+	_, err := pool.acquire()
+	assert.EqualError(t, err, "start process of plugin test failed: exec format error")
+	// the slot is released
+	assert.Len(t, pool.slots, 0)
+	assert.Equal(t, uint64(1), pool.Stats().AcquireFailed)
+}
+
+func TestPluginPoolHealthCheck(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 2}, newMockProcess)
+	p1, err := pool.acquire()
+	assert.NoError(t, err)
+	p2, err := pool.acquire()
+	assert.NoError(t, err)
+	pool.release(p1)
+	pool.release(p2)
+
+	p1.protocol.(*mockClientProtocol).setPingErr(fmt.Errorf("connection refused"))
+	pool.healthCheck()
+
+	stats := pool.Stats()
+	assert.Equal(t, 2, stats.Idle)
+	assert.Equal(t, uint64(3), stats.Started)
+	assert.Equal(t, uint64(1), stats.Restarted)
+	for _, proc := range pool.idle {
+		assert.NotSame(t, p1, proc)
+	}
+}
+
+func TestPluginPoolClose(t *testing.T) {
+	pool := newPluginPool("test", &PluginPoolConfig{Size: 2}, newMockProcess)
+	p1, err := pool.acquire()
+	assert.NoError(t, err)
+	p2, err := pool.acquire()
+	assert.NoError(t, err)
+	pool.release(p1)
+
+	pool.close()
+	assert.Equal(t, 0, pool.Stats().Idle)
+	// the process in use is not put back after the pool is closed
+	pool.release(p2)
+	assert.Equal(t, 0, pool.Stats().Idle)
+	assert.Equal(t, 0, pool.Stats().InUse)
+}
@@ -47,6 +47,9 @@ It has these top-level messages:
 	GenRollbackSQLRequest
 	GenRollbackSQLResponse
 	MetasResponse
+	Capabilities
+	AuditBatchRequest
+	AuditBatchResponse
 	QueryPrepareRequest
 	QueryPrepareConf
 	QueryPrepareResponse
@@ -470,6 +470,9 @@ type MetasResponse struct {
 	Rules            []*Rule  `protobuf:"bytes,2,rep,name=rules" json:"rules,omitempty"`
 	AdditionalParams []*Param `protobuf:"bytes,3,rep,name=additionalParams" json:"additionalParams,omitempty"`
 	Version          int32    `protobuf:"varint,4,opt,name=version" json:"version,omitempty"`
+	// capabilities is nil for the plugins which don't declare it, they are guessed by SQLE.
+	Capabilities   *Capabilities `protobuf:"bytes,5,opt,name=capabilities" json:"capabilities,omitempty"`
+	MinSQLEVersion string        `protobuf:"bytes,6,opt,name=minSQLEVersion" json:"minSQLEVersion,omitempty"`
 }
 
 func (m *MetasResponse) Reset()                    { *m = MetasResponse{} }
@@ -505,6 +508,108 @@ func (m *MetasResponse) GetVersion() int32 {
 	return 0
 }
 
+func (m *MetasResponse) GetCapabilities() *Capabilities {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *MetasResponse) GetMinSQLEVersion() string {
+	if m != nil {
+		return m.MinSQLEVersion
+	}
+	return ""
+}
+
+type Capabilities struct {
+	Rollback         bool     `protobuf:"varint,1,opt,name=rollback" json:"rollback,omitempty"`
+	TransactionalDDL bool     `protobuf:"varint,2,opt,name=transactionalDDL" json:"transactionalDDL,omitempty"`
+	ExplainFormats   []string `protobuf:"bytes,3,rep,name=explainFormats" json:"explainFormats,omitempty"`
+	OfflineAudit     bool     `protobuf:"varint,4,opt,name=offlineAudit" json:"offlineAudit,omitempty"`
+	Query            bool     `protobuf:"varint,5,opt,name=query" json:"query,omitempty"`
+	Analysis         bool     `protobuf:"varint,6,opt,name=analysis" json:"analysis,omitempty"`
+}
+
+func (m *Capabilities) Reset()                    { *m = Capabilities{} }
+func (m *Capabilities) String() string            { return proto1.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()               {}
+func (*Capabilities) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *Capabilities) GetRollback() bool {
+	if m != nil {
+		return m.Rollback
+	}
+	return false
+}
+
+func (m *Capabilities) GetTransactionalDDL() bool {
+	if m != nil {
+		return m.TransactionalDDL
+	}
+	return false
+}
+
+func (m *Capabilities) GetExplainFormats() []string {
+	if m != nil {
+		return m.ExplainFormats
+	}
+	return nil
+}
+
+func (m *Capabilities) GetOfflineAudit() bool {
+	if m != nil {
+		return m.OfflineAudit
+	}
+	return false
+}
+
+func (m *Capabilities) GetQuery() bool {
+	if m != nil {
+		return m.Query
+	}
+	return false
+}
+
+func (m *Capabilities) GetAnalysis() bool {
+	if m != nil {
+		return m.Analysis
+	}
+	return false
+}
+
+type AuditBatchRequest struct {
+	Sqls []string `protobuf:"bytes,1,rep,name=sqls" json:"sqls,omitempty"`
+}
+
+func (m *AuditBatchRequest) Reset()                    { *m = AuditBatchRequest{} }
+func (m *AuditBatchRequest) String() string            { return proto1.CompactTextString(m) }
+func (*AuditBatchRequest) ProtoMessage()               {}
+func (*AuditBatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *AuditBatchRequest) GetSqls() []string {
+	if m != nil {
+		return m.Sqls
+	}
+	return nil
+}
+
+type AuditBatchResponse struct {
+	Results []*AuditResponse `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *AuditBatchResponse) Reset()                    { *m = AuditBatchResponse{} }
+func (m *AuditBatchResponse) String() string            { return proto1.CompactTextString(m) }
+func (*AuditBatchResponse) ProtoMessage()               {}
+func (*AuditBatchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *AuditBatchResponse) GetResults() []*AuditResponse {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*DSN)(nil), "proto.DSN")
 	proto1.RegisterType((*Rule)(nil), "proto.Rule")
@@ -525,6 +630,9 @@ func init() {
 	proto1.RegisterType((*GenRollbackSQLRequest)(nil), "proto.GenRollbackSQLRequest")
 	proto1.RegisterType((*GenRollbackSQLResponse)(nil), "proto.GenRollbackSQLResponse")
 	proto1.RegisterType((*MetasResponse)(nil), "proto.MetasResponse")
+	proto1.RegisterType((*Capabilities)(nil), "proto.Capabilities")
+	proto1.RegisterType((*AuditBatchRequest)(nil), "proto.AuditBatchRequest")
+	proto1.RegisterType((*AuditBatchResponse)(nil), "proto.AuditBatchResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -552,6 +660,9 @@ type DriverClient interface {
 	Databases(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatabasesResponse, error)
 	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
 	Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error)
+	// AuditBatch audits the SQLs in order in the same context, the results are
+	// in the order of SQLs.
+	AuditBatch(ctx context.Context, in *AuditBatchRequest, opts ...grpc.CallOption) (*AuditBatchResponse, error)
 	GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error)
 }
 
@@ -644,6 +755,15 @@ func (c *driverClient) Audit(ctx context.Context, in *AuditRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *driverClient) AuditBatch(ctx context.Context, in *AuditBatchRequest, opts ...grpc.CallOption) (*AuditBatchResponse, error) {
+	out := new(AuditBatchResponse)
+	err := grpc.Invoke(ctx, "/proto.Driver/AuditBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *driverClient) GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error) {
 	out := new(GenRollbackSQLResponse)
 	err := grpc.Invoke(ctx, "/proto.Driver/GenRollbackSQL", in, out, c.cc, opts...)
@@ -670,6 +790,9 @@ type DriverServer interface {
 	Databases(context.Context, *Empty) (*DatabasesResponse, error)
 	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
 	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	// AuditBatch audits the SQLs in order in the same context, the results are
+	// in the order of SQLs.
+	AuditBatch(context.Context, *AuditBatchRequest) (*AuditBatchResponse, error)
 	GenRollbackSQL(context.Context, *GenRollbackSQLRequest) (*GenRollbackSQLResponse, error)
 }
 
@@ -839,6 +962,24 @@ func _Driver_Audit_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_AuditBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).AuditBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Driver/AuditBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).AuditBatch(ctx, req.(*AuditBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Driver_GenRollbackSQL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GenRollbackSQLRequest)
 	if err := dec(in); err != nil {
@@ -897,6 +1038,10 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Audit",
 			Handler:    _Driver_Audit_Handler,
 		},
+		{
+			MethodName: "AuditBatch",
+			Handler:    _Driver_AuditBatch_Handler,
+		},
 		{
 			MethodName: "GenRollbackSQL",
 			Handler:    _Driver_GenRollbackSQL_Handler,
@@ -909,58 +1054,69 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 func init() { proto1.RegisterFile("driver.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 835 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdb, 0x6e, 0xdb, 0x46,
-	0x10, 0x85, 0x44, 0x52, 0x8e, 0x86, 0x72, 0x61, 0x6f, 0xdc, 0x80, 0x10, 0x5c, 0x40, 0xd9, 0xb4,
-	0x80, 0x82, 0xa6, 0x0e, 0xaa, 0xbc, 0x14, 0x08, 0xf2, 0x10, 0xd7, 0x46, 0x61, 0xa0, 0x31, 0x5c,
-	0xda, 0x40, 0x81, 0xbe, 0xad, 0xc5, 0xb1, 0x4b, 0x84, 0xe6, 0x52, 0xbb, 0x4b, 0x5f, 0x7e, 0xa5,
-	0x4f, 0xfd, 0x86, 0xf6, 0xc7, 0xfa, 0x09, 0xc5, 0xde, 0x78, 0xb1, 0xac, 0xa2, 0x4f, 0xdc, 0x39,
-	0x33, 0x3b, 0x7b, 0x66, 0x38, 0x67, 0x60, 0x92, 0x89, 0xfc, 0x16, 0xc5, 0x41, 0x25, 0xb8, 0xe2,
-	0x24, 0x32, 0x1f, 0xfa, 0xf7, 0x00, 0x82, 0xa3, 0xf3, 0x53, 0x42, 0x20, 0xfc, 0x9d, 0x4b, 0x95,
-	0x0c, 0x66, 0x83, 0xf9, 0x38, 0x35, 0x67, 0x8d, 0x55, 0x5c, 0xa8, 0x64, 0x68, 0x31, 0x7d, 0xd6,
-	0x58, 0x2d, 0x51, 0x24, 0x81, 0xc5, 0xf4, 0x99, 0x4c, 0xe1, 0x59, 0xc5, 0xa4, 0xbc, 0xe3, 0x22,
-	0x4b, 0x42, 0x83, 0x37, 0xb6, 0xf6, 0x65, 0x4c, 0xb1, 0x4b, 0x26, 0x31, 0x89, 0xac, 0xcf, 0xdb,
-	0xe4, 0x07, 0xd8, 0x61, 0x59, 0x96, 0xab, 0x9c, 0x97, 0xac, 0x38, 0x63, 0x82, 0xdd, 0xc8, 0x64,
-	0x34, 0x0b, 0xe6, 0xf1, 0x62, 0x62, 0x49, 0x1e, 0x18, 0x30, 0x5d, 0x8b, 0xa2, 0x7f, 0x0e, 0x20,
-	0x4c, 0xeb, 0x02, 0x35, 0x9d, 0x92, 0xdd, 0xa0, 0xa7, 0xad, 0xcf, 0x1a, 0xcb, 0x50, 0x2e, 0x3d,
-	0x6d, 0x7d, 0x26, 0x09, 0x44, 0xb7, 0xac, 0xa8, 0xd1, 0xf2, 0x3e, 0x1c, 0x26, 0x83, 0xd4, 0x02,
-	0x64, 0x0f, 0xa2, 0x02, 0x6f, 0xb1, 0x70, 0xcc, 0xad, 0xa1, 0x69, 0x2f, 0x99, 0xc2, 0x6b, 0x2e,
-	0x1e, 0x3c, 0x6d, 0x6f, 0x93, 0xaf, 0x61, 0x54, 0x6d, 0x26, 0xeb, 0x7c, 0xf4, 0x57, 0x88, 0x0c,
-	0x40, 0x76, 0x20, 0xf8, 0x8c, 0x0f, 0x8e, 0xa1, 0x3e, 0xea, 0x27, 0x2d, 0x19, 0xcb, 0xd0, 0x11,
-	0xf1, 0xb4, 0x83, 0x0e, 0x6d, 0x02, 0xa1, 0x7a, 0xa8, 0xd0, 0x71, 0x33, 0x67, 0x7a, 0x0a, 0xf1,
-	0x49, 0x99, 0xab, 0x14, 0x57, 0x35, 0x4a, 0x45, 0xf6, 0x21, 0xc8, 0x64, 0x69, 0xd2, 0xc7, 0x0b,
-	0x70, 0x54, 0x8e, 0xce, 0x4f, 0x53, 0x0d, 0x93, 0x97, 0x10, 0x89, 0xba, 0x40, 0x99, 0x04, 0x86,
-	0x6a, 0xec, 0xfc, 0xba, 0x77, 0xa9, 0xf5, 0xd0, 0x2d, 0x88, 0x8e, 0x6f, 0x2a, 0xf5, 0x40, 0x5f,
-	0x41, 0x7c, 0x7c, 0x8f, 0x4b, 0x9f, 0x78, 0x0f, 0xa2, 0x55, 0x8d, 0xc2, 0x33, 0xb7, 0x06, 0xfd,
-	0x6b, 0x00, 0x13, 0x1b, 0x25, 0x2b, 0x5e, 0x4a, 0x24, 0x14, 0x26, 0x05, 0x93, 0xea, 0xa4, 0x94,
-	0x28, 0xd4, 0x49, 0x66, 0xa2, 0x83, 0xb4, 0x87, 0x91, 0x37, 0xb0, 0xdb, 0xb5, 0x8f, 0x85, 0xe0,
-	0xc2, 0x15, 0xbf, 0xee, 0xd0, 0x19, 0x05, 0xbf, 0x93, 0x1f, 0xaf, 0xae, 0x70, 0xa9, 0x30, 0x33,
-	0x0d, 0x09, 0xd2, 0x1e, 0xa6, 0x33, 0x76, 0x6d, 0x9b, 0xd1, 0x76, 0x69, 0xdd, 0x41, 0xbf, 0x81,
-	0xf1, 0xc5, 0xbd, 0xaf, 0x2b, 0x81, 0x2d, 0x5d, 0x4a, 0x8e, 0x32, 0x19, 0xcc, 0x82, 0xf9, 0x38,
-	0xf5, 0x26, 0x7d, 0x0f, 0xa0, 0xc3, 0x5c, 0x61, 0xdf, 0xc1, 0x96, 0x40, 0x59, 0x17, 0xca, 0xc6,
-	0xc5, 0x8b, 0xe7, 0xae, 0x79, 0xdd, 0xf2, 0x53, 0x1f, 0x43, 0xbf, 0x87, 0xdd, 0x23, 0x37, 0xd8,
-	0xb2, 0xc9, 0xb1, 0x0f, 0x63, 0x3f, 0xed, 0xfe, 0xb5, 0x16, 0xa0, 0x73, 0x98, 0x9c, 0x31, 0x21,
-	0xb1, 0xc3, 0x4c, 0xae, 0x8a, 0x0b, 0xbc, 0xf7, 0x32, 0xf4, 0x26, 0x3d, 0x83, 0xf0, 0x94, 0x67,
-	0x66, 0x46, 0x54, 0xeb, 0x36, 0xe7, 0x66, 0x46, 0x86, 0xed, 0x8c, 0x90, 0x19, 0xc4, 0x57, 0x79,
-	0x79, 0x8d, 0xa2, 0x12, 0x79, 0xa9, 0xdc, 0x48, 0x75, 0x21, 0xba, 0x80, 0x6d, 0xf7, 0xb6, 0xa3,
-	0xfa, 0x12, 0xa2, 0x92, 0x67, 0xe8, 0x8b, 0xf5, 0x93, 0xa2, 0x9f, 0x4d, 0xad, 0x87, 0xce, 0x60,
-	0xf2, 0xb1, 0xce, 0xda, 0xd1, 0xdb, 0x81, 0x40, 0xae, 0x0a, 0x3f, 0xd9, 0x72, 0x55, 0xd0, 0x0f,
-	0x10, 0xbb, 0x08, 0xdd, 0x14, 0x5d, 0xd0, 0x0d, 0x4a, 0xc9, 0xae, 0xbd, 0x40, 0xbd, 0xd9, 0xaa,
-	0x6e, 0xd8, 0x51, 0x1d, 0xfd, 0x00, 0xdb, 0xfe, 0xba, 0x25, 0xf5, 0xe6, 0xf1, 0x3f, 0x20, 0x8e,
-	0x56, 0xe7, 0x95, 0xf6, 0x17, 0xbc, 0x86, 0x2f, 0x7f, 0xc2, 0x32, 0xe5, 0x45, 0x71, 0xc9, 0x96,
-	0x9f, 0xcf, 0x7f, 0xf9, 0x79, 0x33, 0xd1, 0x43, 0x78, 0xf1, 0x38, 0xd4, 0x3d, 0xb9, 0x16, 0x4b,
-	0x5e, 0xc0, 0x48, 0x20, 0x93, 0xbc, 0x74, 0x64, 0x9d, 0x45, 0xff, 0x18, 0xc0, 0xf6, 0x27, 0x54,
-	0xac, 0xfd, 0xdd, 0x4f, 0x6d, 0xa3, 0x46, 0x81, 0xc3, 0x4d, 0x0a, 0x7c, 0x72, 0x0f, 0x06, 0xff,
-	0x67, 0x0f, 0xea, 0x06, 0xdf, 0xa2, 0x90, 0x39, 0x2f, 0xcd, 0xf0, 0x47, 0xa9, 0x37, 0x17, 0xff,
-	0x04, 0x30, 0x3a, 0x32, 0xfb, 0x9e, 0x7c, 0x0b, 0x91, 0xa1, 0x49, 0x7c, 0x36, 0x23, 0xf7, 0xe9,
-	0x9e, 0xb3, 0xfa, 0x25, 0xcc, 0x21, 0xd4, 0xdb, 0x85, 0xf8, 0x46, 0x77, 0x56, 0xcd, 0xb4, 0x77,
-	0x9f, 0xbc, 0x82, 0xe8, 0xc7, 0x82, 0x4b, 0x7c, 0x94, 0xb6, 0x1f, 0x44, 0x21, 0x3c, 0xcb, 0xcb,
-	0xeb, 0xff, 0x8c, 0x79, 0x0b, 0xa1, 0x96, 0x54, 0xf3, 0x64, 0x67, 0x09, 0x4d, 0x9f, 0xd2, 0x1c,
-	0x79, 0x0d, 0xc3, 0x8b, 0x7b, 0xb2, 0xe3, 0x5c, 0x8d, 0xb2, 0xa7, 0xbb, 0x1d, 0xc4, 0x85, 0xbe,
-	0x83, 0x71, 0xa3, 0xca, 0x47, 0x24, 0x12, 0xbf, 0x2b, 0xd7, 0x54, 0xbb, 0x30, 0xab, 0x5b, 0x22,
-	0x79, 0xde, 0xb6, 0xbf, 0x51, 0x69, 0xd3, 0xb7, 0xbe, 0x7c, 0x16, 0x10, 0x99, 0x99, 0x6c, 0xee,
-	0x74, 0x95, 0xd2, 0xdc, 0xe9, 0x4f, 0xf7, 0x27, 0xf8, 0xa2, 0x3f, 0x84, 0x64, 0xdf, 0xc5, 0x3d,
-	0x39, 0xc6, 0xd3, 0xaf, 0x36, 0x78, 0x6d, 0xba, 0x43, 0xf8, 0xed, 0xd9, 0xc1, 0xdb, 0xf7, 0x26,
-	0xe4, 0x72, 0x64, 0x3e, 0xef, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xcb, 0xe5, 0x14, 0xee, 0xf4,
-	0x07, 0x00, 0x00,
+	// 1011 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xdd, 0x6e, 0xdb, 0x36,
+	0x14, 0x86, 0x2c, 0xc9, 0xb1, 0x8f, 0x9d, 0x22, 0x61, 0xb2, 0x42, 0x33, 0x32, 0xc0, 0x65, 0xf7,
+	0x93, 0x6e, 0x5d, 0x8a, 0xb9, 0x17, 0x1b, 0x50, 0xf4, 0x22, 0xa9, 0xb3, 0x21, 0x40, 0x1a, 0xa4,
+	0x4c, 0xb0, 0x01, 0xbb, 0x63, 0x2c, 0x26, 0x15, 0x2a, 0x4b, 0x32, 0x49, 0xa7, 0xf6, 0xdb, 0xec,
+	0x19, 0xd6, 0x57, 0xd9, 0xdb, 0xec, 0x66, 0xe0, 0x9f, 0x7e, 0x6c, 0x67, 0xe8, 0x95, 0x78, 0xbe,
+	0x73, 0x74, 0xf8, 0xf1, 0xf0, 0x3b, 0x87, 0xd0, 0x8f, 0x79, 0x72, 0xcf, 0xf8, 0x51, 0xc1, 0x73,
+	0x99, 0xa3, 0x50, 0x7f, 0xf0, 0x27, 0x0f, 0xfc, 0xf1, 0xd5, 0x05, 0x42, 0x10, 0xbc, 0xcf, 0x85,
+	0x8c, 0xbc, 0xa1, 0x77, 0xd8, 0x25, 0x7a, 0xad, 0xb0, 0x22, 0xe7, 0x32, 0x6a, 0x19, 0x4c, 0xad,
+	0x15, 0x36, 0x17, 0x8c, 0x47, 0xbe, 0xc1, 0xd4, 0x1a, 0x0d, 0xa0, 0x53, 0x50, 0x21, 0x3e, 0xe6,
+	0x3c, 0x8e, 0x02, 0x8d, 0x97, 0xb6, 0xf2, 0xc5, 0x54, 0xd2, 0x1b, 0x2a, 0x58, 0x14, 0x1a, 0x9f,
+	0xb3, 0xd1, 0x2f, 0xb0, 0x43, 0xe3, 0x38, 0x91, 0x49, 0x9e, 0xd1, 0xf4, 0x92, 0x72, 0x3a, 0x15,
+	0x51, 0x7b, 0xe8, 0x1f, 0xf6, 0x46, 0x7d, 0x43, 0xf2, 0x48, 0x83, 0x64, 0x2d, 0x0a, 0xff, 0xe5,
+	0x41, 0x40, 0xe6, 0x29, 0x53, 0x74, 0x32, 0x3a, 0x65, 0x8e, 0xb6, 0x5a, 0x2b, 0x2c, 0x66, 0x62,
+	0xe2, 0x68, 0xab, 0x35, 0x8a, 0x20, 0xbc, 0xa7, 0xe9, 0x9c, 0x19, 0xde, 0x27, 0xad, 0xc8, 0x23,
+	0x06, 0x40, 0xfb, 0x10, 0xa6, 0xec, 0x9e, 0xa5, 0x96, 0xb9, 0x31, 0x14, 0xed, 0x09, 0x95, 0xec,
+	0x2e, 0xe7, 0x4b, 0x47, 0xdb, 0xd9, 0xe8, 0x6b, 0x68, 0x17, 0x0f, 0x93, 0xb5, 0x3e, 0xfc, 0x07,
+	0x84, 0x1a, 0x40, 0x3b, 0xe0, 0x7f, 0x60, 0x4b, 0xcb, 0x50, 0x2d, 0xd5, 0x96, 0x86, 0x8c, 0x61,
+	0x68, 0x89, 0x38, 0xda, 0x7e, 0x8d, 0x36, 0x82, 0x40, 0x2e, 0x0b, 0x66, 0xb9, 0xe9, 0x35, 0xbe,
+	0x80, 0xde, 0x59, 0x96, 0x48, 0xc2, 0x66, 0x73, 0x26, 0x24, 0x3a, 0x00, 0x3f, 0x16, 0x99, 0x4e,
+	0xdf, 0x1b, 0x81, 0xa5, 0x32, 0xbe, 0xba, 0x20, 0x0a, 0x46, 0x4f, 0x20, 0xe4, 0xf3, 0x94, 0x89,
+	0xc8, 0xd7, 0x54, 0x7b, 0xd6, 0xaf, 0x6a, 0x47, 0x8c, 0x07, 0x6f, 0x41, 0x78, 0x3a, 0x2d, 0xe4,
+	0x12, 0x3f, 0x85, 0xde, 0xe9, 0x82, 0x4d, 0x5c, 0xe2, 0x7d, 0x08, 0x67, 0x73, 0xc6, 0x1d, 0x73,
+	0x63, 0xe0, 0xbf, 0x3d, 0xe8, 0x9b, 0x28, 0x51, 0xe4, 0x99, 0x60, 0x08, 0x43, 0x3f, 0xa5, 0x42,
+	0x9e, 0x65, 0x82, 0x71, 0x79, 0x16, 0xeb, 0x68, 0x9f, 0x34, 0x30, 0xf4, 0x1c, 0x76, 0xeb, 0xf6,
+	0x29, 0xe7, 0x39, 0xb7, 0x87, 0x5f, 0x77, 0xa8, 0x8c, 0x3c, 0xff, 0x28, 0x8e, 0x6f, 0x6f, 0xd9,
+	0x44, 0xb2, 0x58, 0x17, 0xc4, 0x27, 0x0d, 0x4c, 0x65, 0xac, 0xdb, 0x26, 0xa3, 0xa9, 0xd2, 0xba,
+	0x03, 0x7f, 0x03, 0xdd, 0xeb, 0x85, 0x3b, 0x57, 0x04, 0x5b, 0xea, 0x28, 0x09, 0x13, 0x91, 0x37,
+	0xf4, 0x0f, 0xbb, 0xc4, 0x99, 0xf8, 0x15, 0xc0, 0xf5, 0xa2, 0x3c, 0xd8, 0x8f, 0xb0, 0xc5, 0x99,
+	0x98, 0xa7, 0xd2, 0xc4, 0xf5, 0x46, 0x7b, 0xb6, 0x78, 0xf5, 0xe3, 0x13, 0x17, 0x83, 0x7f, 0x82,
+	0xdd, 0xb1, 0x15, 0xb6, 0x28, 0x73, 0x1c, 0x40, 0xd7, 0xa9, 0xdd, 0xed, 0x56, 0x01, 0xf8, 0x10,
+	0xfa, 0x97, 0x94, 0x0b, 0x56, 0x63, 0x26, 0x66, 0xe9, 0x35, 0x5b, 0xb8, 0x36, 0x74, 0x26, 0xbe,
+	0x84, 0xe0, 0x22, 0x8f, 0xb5, 0x46, 0x64, 0xe5, 0xd6, 0xeb, 0x52, 0x23, 0xad, 0x4a, 0x23, 0x68,
+	0x08, 0xbd, 0xdb, 0x24, 0xbb, 0x63, 0xbc, 0xe0, 0x49, 0x26, 0xad, 0xa4, 0xea, 0x10, 0x1e, 0xc1,
+	0xb6, 0xdd, 0xdb, 0x52, 0x7d, 0x02, 0x61, 0x96, 0xc7, 0xcc, 0x1d, 0xd6, 0x29, 0x45, 0x6d, 0x4b,
+	0x8c, 0x07, 0x0f, 0xa1, 0x7f, 0x3c, 0x8f, 0x2b, 0xe9, 0xed, 0x80, 0x2f, 0x66, 0xa9, 0x53, 0xb6,
+	0x98, 0xa5, 0xf8, 0x35, 0xf4, 0x6c, 0x84, 0x2a, 0x8a, 0x3a, 0xd0, 0x94, 0x09, 0x41, 0xef, 0x5c,
+	0x83, 0x3a, 0xb3, 0xea, 0xba, 0x56, 0xad, 0xeb, 0xf0, 0x6b, 0xd8, 0x76, 0xbf, 0x1b, 0x52, 0xcf,
+	0x57, 0xef, 0x00, 0x59, 0x5a, 0xb5, 0x5d, 0xaa, 0x2b, 0x78, 0x06, 0x5f, 0xfc, 0xc6, 0x32, 0x92,
+	0xa7, 0xe9, 0x0d, 0x9d, 0x7c, 0xb8, 0x7a, 0x77, 0xfe, 0x30, 0xd1, 0x13, 0x78, 0xbc, 0x1a, 0x6a,
+	0xb7, 0x5c, 0x8b, 0x45, 0x8f, 0xa1, 0xcd, 0x19, 0x15, 0x79, 0x66, 0xc9, 0x5a, 0x0b, 0xff, 0xeb,
+	0xc1, 0xf6, 0x5b, 0x26, 0x69, 0x75, 0xdd, 0x9b, 0xa6, 0x51, 0xd9, 0x81, 0xad, 0x87, 0x3a, 0x70,
+	0xe3, 0x1c, 0xf4, 0x3f, 0x67, 0x0e, 0xaa, 0x02, 0xdf, 0x33, 0x2e, 0x92, 0x3c, 0xd3, 0xe2, 0x0f,
+	0x89, 0x33, 0xd1, 0xcf, 0xd0, 0x9f, 0xd0, 0x82, 0xde, 0x24, 0x69, 0x22, 0x95, 0xd4, 0xc3, 0xa1,
+	0x57, 0x93, 0xf0, 0x9b, 0x9a, 0x8b, 0x34, 0x02, 0xd1, 0xb7, 0xf0, 0x68, 0x9a, 0x64, 0x57, 0xef,
+	0xce, 0x4f, 0x7f, 0xb7, 0x99, 0xdb, 0xfa, 0x34, 0x2b, 0x28, 0xfe, 0xc7, 0x83, 0x7e, 0x3d, 0x8d,
+	0x1a, 0x99, 0xdc, 0xd6, 0x53, 0x17, 0xa0, 0x43, 0x4a, 0x1b, 0x7d, 0x0f, 0x3b, 0x92, 0xd3, 0x4c,
+	0xd0, 0x89, 0xa1, 0x3f, 0x1e, 0x9f, 0xeb, 0x62, 0x76, 0xc8, 0x1a, 0xae, 0x08, 0xb0, 0x45, 0x91,
+	0xd2, 0x24, 0xfb, 0x35, 0xe7, 0x53, 0x2a, 0x4d, 0x2d, 0xba, 0x64, 0x05, 0x55, 0x63, 0x22, 0xbf,
+	0xbd, 0x4d, 0x93, 0x8c, 0x69, 0x31, 0xe8, 0x02, 0x74, 0x48, 0x03, 0xab, 0x66, 0x58, 0xa8, 0x9d,
+	0xc6, 0x50, 0x4c, 0x69, 0x46, 0xd3, 0xa5, 0x48, 0x84, 0x3e, 0x5c, 0x87, 0x94, 0x36, 0xfe, 0x0e,
+	0x76, 0xf5, 0xaf, 0x27, 0x54, 0x4e, 0xde, 0x3b, 0xfd, 0x20, 0x08, 0xc4, 0x2c, 0x75, 0x1d, 0xac,
+	0xd7, 0x78, 0x0c, 0xa8, 0x1e, 0x68, 0x15, 0x70, 0xb4, 0x2a, 0xd8, 0xfd, 0x15, 0xc1, 0x36, 0xa7,
+	0xc6, 0xe8, 0x53, 0x00, 0xed, 0xb1, 0x7e, 0x96, 0xd1, 0x0f, 0x10, 0x6a, 0x35, 0x21, 0x77, 0xe9,
+	0x7a, 0x2a, 0x0f, 0x5c, 0x82, 0xa6, 0xd2, 0x0e, 0x21, 0x50, 0x8f, 0x00, 0x72, 0xfd, 0x50, 0x7b,
+	0x11, 0x06, 0x8d, 0xff, 0xd1, 0x53, 0x08, 0xdf, 0xa4, 0xb9, 0x60, 0x2b, 0x69, 0x9b, 0x41, 0x18,
+	0x82, 0xcb, 0x24, 0xbb, 0xfb, 0xdf, 0x98, 0x17, 0x10, 0xa8, 0xc9, 0x57, 0x6e, 0x59, 0x7b, 0x2b,
+	0x06, 0x9b, 0x46, 0x23, 0x7a, 0x06, 0xad, 0xeb, 0x05, 0xda, 0xb1, 0xae, 0x72, 0x00, 0x0f, 0x76,
+	0x6b, 0x88, 0x0d, 0x7d, 0x09, 0xdd, 0x72, 0x78, 0xae, 0x90, 0x88, 0xdc, 0x93, 0xb6, 0x36, 0x5c,
+	0x47, 0xfa, 0x85, 0x15, 0x0c, 0xed, 0x55, 0x5d, 0x52, 0x0e, 0xd3, 0xc1, 0x7e, 0x13, 0xac, 0xfe,
+	0x31, 0xca, 0xd8, 0x6b, 0xde, 0x4b, 0xf3, 0x9f, 0xe6, 0x10, 0x3a, 0x06, 0xa8, 0x6e, 0x1a, 0x45,
+	0xf5, 0x98, 0xba, 0x4a, 0x06, 0x5f, 0x6e, 0xf0, 0xd8, 0x14, 0x6f, 0xe1, 0x51, 0x73, 0xdc, 0xa0,
+	0x03, 0x1b, 0xbc, 0x71, 0x60, 0x0d, 0xbe, 0x7a, 0xc0, 0x6b, 0xd2, 0x9d, 0xc0, 0x9f, 0x9d, 0xa3,
+	0x17, 0xaf, 0x74, 0xc8, 0x4d, 0x5b, 0x7f, 0x5e, 0xfe, 0x37, 0x00, 0x9c, 0x2d, 0x55, 0x4a, 0xde,
+	0x09, 0x00, 0x00,
 }
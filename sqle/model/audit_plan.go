@@ -22,19 +22,33 @@ type AuditPlan struct {
 	Type             string        `json:"type"`
 	RuleTemplateName string        `json:"rule_template_name"`
 	Params           params.Params `json:"params" gorm:"type:varchar(1000)"`
-
-	NotifyInterval      int    `json:"notify_interval" gorm:"default:10"`
-	NotifyLevel         string `json:"notify_level" gorm:"default:'warn'"`
-	EnableEmailNotify   bool   `json:"enable_email_notify"`
-	EnableWebHookNotify bool   `json:"enable_web_hook_notify"`
-	WebHookURL          string `json:"web_hook_url"`
-	WebHookTemplate     string `json:"web_hook_template"`
+	// Status is "disabled" if the audit plan is paused, the collected SQLs and
+	// reports are kept, but it is neither scheduled nor collecting SQLs.
+	Status string `json:"status" gorm:"type:varchar(32);default:'enabled'"`
+
+	NotifyInterval int    `json:"notify_interval" gorm:"default:10"`
+	NotifyLevel    string `json:"notify_level" gorm:"default:'warn'"`
+	// NotifyOnlyNewFindings notifies only if there are new findings at or above NotifyLevel.
+	NotifyOnlyNewFindings bool   `json:"notify_only_new_findings"`
+	EnableEmailNotify     bool   `json:"enable_email_notify"`
+	EnableWebHookNotify   bool   `json:"enable_web_hook_notify"`
+	WebHookURL            string `json:"web_hook_url"`
+	WebHookTemplate       string `json:"web_hook_template"`
 
 	CreateUser    *User             `gorm:"foreignkey:CreateUserId"`
 	Instance      *Instance         `gorm:"foreignkey:InstanceName;association_foreignkey:Name"`
 	AuditPlanSQLs []*AuditPlanSQLV2 `gorm:"foreignkey:AuditPlanID"`
 }
 
+const (
+	AuditPlanStatusEnabled  = "enabled"
+	AuditPlanStatusDisabled = "disabled"
+)
+
+func (a *AuditPlan) IsEnabled() bool {
+	return a.Status != AuditPlanStatusDisabled
+}
+
 type AuditPlanSQLV2 struct {
 	Model
 
@@ -102,6 +116,20 @@ func (s *Storage) GetAuditPlanSQLs(name string) ([]*AuditPlanSQLV2, error) {
 	return sqls, errors.New(errors.ConnectStorageError, err)
 }
 
+func (s *Storage) GetAuditPlanSQLCount(auditPlanID uint) (uint64, error) {
+	var count uint64
+	err := s.db.Model(AuditPlanSQLV2{}).Where("audit_plan_id = ?", auditPlanID).Count(&count).Error
+	return count, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetAuditPlanCountByDBType returns the count of audit plans of the DB type,
+// the audit plans which are deleted are not counted.
+func (s *Storage) GetAuditPlanCountByDBType(dbType string) (uint64, error) {
+	var count uint64
+	err := s.db.Model(AuditPlan{}).Where("db_type = ?", dbType).Count(&count).Error
+	return count, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) OverrideAuditPlanSQLs(apName string, sqls []*AuditPlanSQLV2) error {
 	ap, _, err := s.GetAuditPlanByName(apName)
 	if err != nil {
@@ -127,13 +155,24 @@ func (s *Storage) UpdateDefaultAuditPlanSQLs(apName string, sqls []*AuditPlanSQL
 
 	raw, args := getBatchInsertRawSQL(ap, sqls)
 	// counter column is a accumulate value when update.
+	// query_time_avg is weighted by counter, query_time_max is the max value.
 	raw += `
 ON DUPLICATE KEY UPDATE sql_content = VALUES(sql_content),
                         info        = JSON_SET(COALESCE(info, '{}'),
                                               '$.counter', CAST(COALESCE(JSON_EXTRACT(values(info), '$.counter'), 0) +
                                                                 COALESCE(JSON_EXTRACT(info, '$.counter'), 0) AS SIGNED),
                                               '$.last_receive_timestamp',
-                                              JSON_EXTRACT(values(info), '$.last_receive_timestamp'));`
+                                              JSON_EXTRACT(values(info), '$.last_receive_timestamp'),
+                                              '$.query_time_avg',
+                                              COALESCE((COALESCE(JSON_EXTRACT(values(info), '$.query_time_avg'), 0) *
+                                                        COALESCE(JSON_EXTRACT(values(info), '$.counter'), 0) +
+                                                        COALESCE(JSON_EXTRACT(info, '$.query_time_avg'), 0) *
+                                                        COALESCE(JSON_EXTRACT(info, '$.counter'), 0)) /
+                                                       NULLIF(COALESCE(JSON_EXTRACT(values(info), '$.counter'), 0) +
+                                                              COALESCE(JSON_EXTRACT(info, '$.counter'), 0), 0), 0),
+                                              '$.query_time_max',
+                                              GREATEST(COALESCE(JSON_EXTRACT(values(info), '$.query_time_max'), 0),
+                                                       COALESCE(JSON_EXTRACT(info, '$.query_time_max'), 0)));`
 
 	return errors.New(errors.ConnectStorageError, s.db.Exec(raw, args...).Error)
 }
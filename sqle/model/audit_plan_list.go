@@ -2,6 +2,8 @@ package model
 
 import (
 	"database/sql"
+	"time"
+
 	"github.com/actiontech/sqle/sqle/pkg/params"
 )
 
@@ -15,11 +17,15 @@ type AuditPlanListDetail struct {
 	RuleTemplateName sql.NullString `json:"rule_template_name"`
 	Type             sql.NullString `json:"type"`
 	Params           params.Params  `json:"params"`
+	Status           sql.NullString `json:"status"`
+	LastRunStatus    sql.NullString `json:"last_run_status"`
+	LastRunAt        *time.Time     `json:"last_run_at"`
 }
 
 var auditPlanQueryTpl = `
 SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token,
-audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params
+audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params,
+audit_plans.status, last_runs.status AS last_run_status, last_runs.end_at AS last_run_at
 
 {{- template "body" . -}} 
 
@@ -38,6 +44,10 @@ var auditPlanBodyTpl = `
 {{ define "body" }}
 FROM audit_plans
 LEFT JOIN users ON audit_plans.create_user_id = users.id
+LEFT JOIN audit_plan_runs AS last_runs ON last_runs.id = (
+SELECT MAX(runs.id) FROM audit_plan_runs AS runs
+WHERE runs.audit_plan_id = audit_plans.id AND runs.deleted_at IS NULL
+)
 
 WHERE audit_plans.deleted_at IS NULL
 AND users.deleted_at IS NULL
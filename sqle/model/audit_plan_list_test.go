@@ -13,6 +13,10 @@ func TestStorage_GetAuditPlansByReq(t *testing.T) {
 	tableAndRowOfSQL := `
 	FROM audit_plans
 	LEFT JOIN users ON audit_plans.create_user_id = users.id
+	LEFT JOIN audit_plan_runs AS last_runs ON last_runs.id = (
+	SELECT MAX(runs.id) FROM audit_plan_runs AS runs
+	WHERE runs.audit_plan_id = audit_plans.id AND runs.deleted_at IS NULL
+	)
 	WHERE audit_plans.deleted_at IS NULL 
 	AND users.deleted_at IS NULL
 	AND ( 
@@ -23,9 +27,9 @@ func TestStorage_GetAuditPlansByReq(t *testing.T) {
 	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	assert.NoError(t, err)
 	InitMockStorage(mockDB)
-	mock.ExpectPrepare(fmt.Sprintf(`SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token, audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params %v LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
-		ExpectQuery().WithArgs(1, "mysql", 100, 10).WillReturnRows(sqlmock.NewRows([]string{"name", "cron_expression", "db_type", "token", "instance_name", "instance_database", "rule_template_name", "type", "params"}).
-		AddRow("audit_plan_1", "* */2 * * *", "mysql", "fake token", "inst_1", "template_1", "db_1", "", nil))
+	mock.ExpectPrepare(fmt.Sprintf(`SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token, audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params, audit_plans.status, last_runs.status AS last_run_status, last_runs.end_at AS last_run_at %v LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
+		ExpectQuery().WithArgs(1, "mysql", 100, 10).WillReturnRows(sqlmock.NewRows([]string{"name", "cron_expression", "db_type", "token", "instance_name", "instance_database", "rule_template_name", "type", "params", "status", "last_run_status", "last_run_at"}).
+		AddRow("audit_plan_1", "* */2 * * *", "mysql", "fake token", "inst_1", "template_1", "db_1", "", nil, "enabled", "failed", nil))
 	mock.ExpectPrepare(fmt.Sprintf(`SELECT COUNT(*) %v`, tableAndRowOfSQL)).
 		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{
 		"COUNT(*)",
@@ -47,6 +51,10 @@ func TestStorage_GetAuditPlansByReq(t *testing.T) {
 	tableAndRowOfSQL1 := `
 	FROM audit_plans
 	LEFT JOIN users ON audit_plans.create_user_id = users.id
+	LEFT JOIN audit_plan_runs AS last_runs ON last_runs.id = (
+	SELECT MAX(runs.id) FROM audit_plan_runs AS runs
+	WHERE runs.audit_plan_id = audit_plans.id AND runs.deleted_at IS NULL
+	)
 	WHERE audit_plans.deleted_at IS NULL
 	AND users.deleted_at IS NULL
 	`
@@ -54,12 +62,12 @@ func TestStorage_GetAuditPlansByReq(t *testing.T) {
 	assert.NoError(t, err)
 	InitMockStorage(mockDB)
 	mock.ExpectPrepare(fmt.Sprintf(`
-	SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token, audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params
+	SELECT audit_plans.name, audit_plans.cron_expression, audit_plans.db_type, audit_plans.token, audit_plans.instance_name, audit_plans.instance_database, audit_plans.rule_template_name, audit_plans.type, audit_plans.params, audit_plans.status, last_runs.status AS last_run_status, last_runs.end_at AS last_run_at
 	%v
 	LIMIT ? OFFSET ?`, tableAndRowOfSQL1)).
 		ExpectQuery().WithArgs(100, 10).WillReturnRows(sqlmock.NewRows([]string{
-		"name", "cron_expression", "db_type", "token", "instance_name", "instance_database", "rule_template_name", "type", "params",
-	}).AddRow("audit_plan_1", "* */2 * * *", "mysql", "fake token", "inst_1", "template_1", "db_1", "", nil))
+		"name", "cron_expression", "db_type", "token", "instance_name", "instance_database", "rule_template_name", "type", "params", "status", "last_run_status", "last_run_at",
+	}).AddRow("audit_plan_1", "* */2 * * *", "mysql", "fake token", "inst_1", "template_1", "db_1", "", nil, "enabled", "failed", nil))
 	mock.ExpectPrepare(fmt.Sprintf(`SELECT COUNT(*) %v`, tableAndRowOfSQL1)).
 		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("2"))
 	nameFields = map[string]interface{}{
@@ -119,10 +127,10 @@ func TestStorage_GetAuditPlanReportsByReq(t *testing.T) {
 	assert.NoError(t, err)
 	defer mockDB.Close()
 	InitMockStorage(mockDB)
-	mock.ExpectPrepare(fmt.Sprintf(`SELECT reports.id, reports.score , reports.pass_rate, reports.audit_level, reports.created_at %v LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
+	mock.ExpectPrepare(fmt.Sprintf(`SELECT reports.id, reports.score , reports.pass_rate, reports.audit_level, reports.created_at, reports.new_finding_count, reports.persisting_finding_count, reports.resolved_finding_count %v LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
 		ExpectQuery().WithArgs("audit_plan_for_jave_repo", 100, 10).WillReturnRows(sqlmock.NewRows([]string{
-		"id", "score", "pass_rate", "audit_level", "created_at"}).
-		AddRow("1", 100, 1, "normal", "2021-09-01T13:46:13+08:00"))
+		"id", "score", "pass_rate", "audit_level", "created_at", "new_finding_count", "persisting_finding_count", "resolved_finding_count"}).
+		AddRow("1", 100, 1, "normal", "2021-09-01T13:46:13+08:00", 1, 2, 3))
 
 	mock.ExpectPrepare(fmt.Sprintf(`SELECT COUNT(*) %v`, tableAndRowOfSQL)).
 		ExpectQuery().WithArgs("audit_plan_for_jave_repo").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("2"))
@@ -149,10 +157,10 @@ func TestStorage_GetAuditPlanReportSQLsByReq(t *testing.T) {
 	assert.NoError(t, err)
 	defer mockDB.Close()
 	InitMockStorage(mockDB)
-	mock.ExpectPrepare(fmt.Sprintf(`SELECT report_sqls.sql, report_sqls.audit_result, report_sqls.number %v LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
+	mock.ExpectPrepare(fmt.Sprintf(`SELECT report_sqls.sql, report_sqls.fingerprint, report_sqls.audit_result, report_sqls.number, report_sqls.location, report_sqls.finding_status, report_sqls.audit_level %v ORDER BY report_sqls.number ASC, report_sqls.id ASC LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
 		ExpectQuery().WithArgs(1, 100, 10).WillReturnRows(sqlmock.NewRows([]string{
-		"sql", "audit_result", "number",
-	}).AddRow("select * from t1 where id = 1", "FAKE AUDIT RESULT", "1"))
+		"sql", "fingerprint", "audit_result", "number", "location", "finding_status", "audit_level",
+	}).AddRow("select * from t1 where id = 1", "select * from t1 where id = ?", "FAKE AUDIT RESULT", "1", "mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)", "new", "warn"))
 
 	mock.ExpectPrepare(fmt.Sprintf(`SELECT COUNT(*) %v`, tableAndRowOfSQL)).
 		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("2"))
@@ -168,3 +176,37 @@ func TestStorage_GetAuditPlanReportSQLsByReq(t *testing.T) {
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+
+func TestStorage_GetAuditPlanRunsByReq(t *testing.T) {
+	tableAndRowOfSQL := `
+	FROM audit_plan_runs AS runs
+	JOIN audit_plans ON audit_plans.id = runs.audit_plan_id
+	WHERE runs.deleted_at IS NULL
+	AND audit_plans.deleted_at IS NULL
+	AND audit_plans.name = ?
+	AND runs.status = ?
+	`
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	InitMockStorage(mockDB)
+	mock.ExpectPrepare(fmt.Sprintf(`SELECT runs.id, runs.run_type, runs.trigger_type, runs.start_at, runs.end_at, runs.sql_count, runs.audited_sql_count, runs.audit_plan_report_id, runs.status, runs.error_message %v ORDER BY runs.id DESC LIMIT ? OFFSET ?`, tableAndRowOfSQL)).
+		ExpectQuery().WithArgs("audit_plan_for_jave_repo", "failed", 100, 10).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "run_type", "trigger_type", "start_at", "end_at", "sql_count", "audited_sql_count", "audit_plan_report_id", "status", "error_message"}).
+		AddRow(1, "audit", "schedule", nil, nil, 0, 0, 0, "failed", "there is no SQLs in audit plan"))
+
+	mock.ExpectPrepare(fmt.Sprintf(`SELECT COUNT(*) %v`, tableAndRowOfSQL)).
+		ExpectQuery().WithArgs("audit_plan_for_jave_repo", "failed").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("2"))
+	nameFields := map[string]interface{}{
+		"audit_plan_name":   "audit_plan_for_jave_repo",
+		"filter_run_status": "failed",
+		"limit":             100,
+		"offset":            10}
+	result, count, err := GetStorage().GetAuditPlanRunsByReq(nameFields)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "there is no SQLs in audit plan", result[0].ErrorMessage.String)
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
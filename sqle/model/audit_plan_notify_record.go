@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	NotifyChannelWebHook = "web_hook"
+
+	NotifyRecordStatusSuccess = "success"
+	NotifyRecordStatusFailed  = "failed"
+)
+
+// AuditPlanNotifyRecord is the delivery result of audit plan notification
+// which is pushed to the external system, e.g. web hook.
+type AuditPlanNotifyRecord struct {
+	Model
+	AuditPlanID uint   `json:"audit_plan_id" gorm:"index"`
+	Channel     string `json:"channel" gorm:"type:varchar(32)"`
+	Status      string `json:"status" gorm:"type:varchar(32)"`
+	// Attempts is the count of sending, including the retries.
+	Attempts     int    `json:"attempts"`
+	StatusCode   int    `json:"status_code"`
+	ErrorMessage string `json:"error_message" gorm:"type:text"`
+}
+
+func (a AuditPlanNotifyRecord) TableName() string {
+	return "audit_plan_notify_records"
+}
+
+func (s *Storage) GetLatestAuditPlanNotifyRecord(auditPlanID uint, channel string) (*AuditPlanNotifyRecord, bool, error) {
+	record := &AuditPlanNotifyRecord{}
+	err := s.db.Where("audit_plan_id = ? AND channel = ?", auditPlanID, channel).Order("id DESC").First(record).Error
+	if err == gorm.ErrRecordNotFound {
+		return record, false, nil
+	}
+	return record, true, errors.New(errors.ConnectStorageError, err)
+}
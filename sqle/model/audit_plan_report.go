@@ -14,6 +14,12 @@ type AuditPlanReportV2 struct {
 	PassRate            float64                 `json:"pass_rate"`
 	Score               int32                   `json:"score"`
 	AuditLevel          string                  `json:"audit_level"`
+
+	// the findings are compared with the previous report by SQL fingerprint.
+	PreviousReportID       uint `json:"previous_report_id"`
+	NewFindingCount        uint `json:"new_finding_count"`
+	PersistingFindingCount uint `json:"persisting_finding_count"`
+	ResolvedFindingCount   uint `json:"resolved_finding_count"`
 }
 
 func (a AuditPlanReportV2) TableName() string {
@@ -26,6 +32,13 @@ type AuditPlanReportSQLV2 struct {
 	SQL               string `json:"sql" gorm:"type:text;not null"`
 	Number            uint   `json:"number"`
 	AuditResult       string `json:"audit_result" gorm:"type:text"`
+	// Location is the location of SQL in source code, it's empty if the SQL is not extracted from code.
+	Location    string `json:"location" gorm:"type:varchar(512)"`
+	Fingerprint string `json:"fingerprint" gorm:"type:text"`
+	AuditLevel  string `json:"audit_level"`
+	// FindingStatus is "new" or "persisting" if the SQL violates rules, it's
+	// empty if the SQL passes the audit.
+	FindingStatus string `json:"finding_status" gorm:"type:varchar(32)"`
 
 	AuditPlanReport *AuditPlanReportV2 `gorm:"foreignkey:AuditPlanReportID"`
 }
@@ -34,6 +47,22 @@ func (a AuditPlanReportSQLV2) TableName() string {
 	return "audit_plan_report_sqls_v2"
 }
 
+const (
+	FindingStatusNew        = "new"
+	FindingStatusPersisting = "persisting"
+)
+
+// GetLatestAuditPlanReport returns the latest report of audit plan with its SQLs.
+func (s *Storage) GetLatestAuditPlanReport(auditPlanID uint) (*AuditPlanReportV2, bool, error) {
+	report := &AuditPlanReportV2{}
+	err := s.db.Where("audit_plan_id = ?", auditPlanID).Order("id DESC").
+		Preload("AuditPlanReportSQLs").First(report).Error
+	if err == gorm.ErrRecordNotFound {
+		return report, false, nil
+	}
+	return report, true, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetAuditPlanReportSQLV2ByReportIDAndNumber(reportId, number uint) (
 	auditPlanReportSQLV2 *AuditPlanReportSQLV2, exist bool, err error) {
 
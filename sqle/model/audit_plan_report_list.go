@@ -8,10 +8,15 @@ type AuditPlanReportListDetail struct {
 	Score      sql.NullInt32   `json:"score"`
 	PassRate   sql.NullFloat64 `json:"pass_rate"`
 	CreateAt   string          `json:"created_at"`
+
+	NewFindingCount        uint `json:"new_finding_count"`
+	PersistingFindingCount uint `json:"persisting_finding_count"`
+	ResolvedFindingCount   uint `json:"resolved_finding_count"`
 }
 
 var auditPlanReportQueryTpl = `
-SELECT reports.id, reports.score , reports.pass_rate, reports.audit_level, reports.created_at
+SELECT reports.id, reports.score , reports.pass_rate, reports.audit_level, reports.created_at,
+reports.new_finding_count, reports.persisting_finding_count, reports.resolved_finding_count
 
 {{- template "body" . -}} 
 
@@ -56,16 +61,22 @@ func (s *Storage) GetAuditPlanReportsByReq(data map[string]interface{}) (
 }
 
 type AuditPlanReportSQLListDetail struct {
-	SQL         string `json:"sql"`
-	AuditResult string `json:"audit_result"`
-	Number      uint   `json:"number"`
+	SQL           string `json:"sql"`
+	Fingerprint   string `json:"fingerprint"`
+	AuditResult   string `json:"audit_result"`
+	Number        uint   `json:"number"`
+	Location      string `json:"location"`
+	FindingStatus string `json:"finding_status"`
+	AuditLevel    string `json:"audit_level"`
 }
 
 var auditPlanReportSQLQueryTpl = `
-SELECT report_sqls.sql, report_sqls.audit_result, report_sqls.number
+SELECT report_sqls.sql, report_sqls.fingerprint, report_sqls.audit_result, report_sqls.number, report_sqls.location, report_sqls.finding_status, report_sqls.audit_level
 
 {{- template "body" . -}} 
 
+ORDER BY report_sqls.number ASC, report_sqls.id ASC
+
 {{- if .limit }}
 LIMIT :limit OFFSET :offset
 {{- end -}}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	AuditPlanRunTypeAudit   = "audit"
+	AuditPlanRunTypeCollect = "collect"
+
+	AuditPlanRunTriggerTypeSchedule = "schedule"
+	AuditPlanRunTriggerTypeManual   = "manual"
+
+	AuditPlanRunStatusSuccess = "success"
+	AuditPlanRunStatusFailed  = "failed"
+)
+
+// AuditPlanRun is a run of audit plan, including the audit triggered by
+// scheduler or user and the SQL collection of the audit plan which collects
+// SQLs by itself.
+type AuditPlanRun struct {
+	Model
+	AuditPlanID uint   `json:"audit_plan_id" gorm:"index"`
+	RunType     string `json:"run_type" gorm:"type:varchar(32)"`
+	TriggerType string `json:"trigger_type" gorm:"type:varchar(32)"`
+	StartAt     time.Time
+	EndAt       time.Time
+	// SQLCount is the count of SQLs in audit plan when the run is finished.
+	SQLCount uint `json:"sql_count"`
+	// AuditedSQLCount is the count of SQLs in report, it's 0 for collection.
+	AuditedSQLCount   uint   `json:"audited_sql_count"`
+	AuditPlanReportID uint   `json:"audit_plan_report_id"`
+	Status            string `json:"status" gorm:"type:varchar(32)"`
+	ErrorMessage      string `json:"error_message" gorm:"type:text"`
+}
+
+func (a AuditPlanRun) TableName() string {
+	return "audit_plan_runs"
+}
+
+func (s *Storage) GetLatestAuditPlanRun(auditPlanID uint) (*AuditPlanRun, bool, error) {
+	run := &AuditPlanRun{}
+	err := s.db.Where("audit_plan_id = ?", auditPlanID).Order("id DESC").First(run).Error
+	if err == gorm.ErrRecordNotFound {
+		return run, false, nil
+	}
+	return run, true, errors.New(errors.ConnectStorageError, err)
+}
+
+type AuditPlanRunListDetail struct {
+	ID                uint           `json:"id"`
+	RunType           string         `json:"run_type"`
+	TriggerType       string         `json:"trigger_type"`
+	StartAt           *time.Time     `json:"start_at"`
+	EndAt             *time.Time     `json:"end_at"`
+	SQLCount          uint           `json:"sql_count"`
+	AuditedSQLCount   uint           `json:"audited_sql_count"`
+	AuditPlanReportID uint           `json:"audit_plan_report_id"`
+	Status            string         `json:"status"`
+	ErrorMessage      sql.NullString `json:"error_message"`
+}
+
+var auditPlanRunQueryTpl = `
+SELECT runs.id, runs.run_type, runs.trigger_type, runs.start_at, runs.end_at, runs.sql_count,
+runs.audited_sql_count, runs.audit_plan_report_id, runs.status, runs.error_message
+
+{{- template "body" . -}} 
+
+ORDER BY runs.id DESC
+
+{{- if .limit }}
+LIMIT :limit OFFSET :offset
+{{- end -}}
+`
+
+var auditPlanRunCountTpl = `
+SELECT COUNT(*)
+
+{{- template "body" . -}}
+`
+
+var auditPlanRunBodyTpl = `
+{{ define "body" }}
+
+FROM audit_plan_runs AS runs
+JOIN audit_plans ON audit_plans.id = runs.audit_plan_id
+
+WHERE runs.deleted_at IS NULL
+AND audit_plans.deleted_at IS NULL
+AND audit_plans.name = :audit_plan_name
+
+{{- if .filter_run_status }}
+AND runs.status = :filter_run_status
+{{- end }}
+
+{{ end }}
+`
+
+func (s *Storage) GetAuditPlanRunsByReq(data map[string]interface{}) (
+	list []*AuditPlanRunListDetail, count uint64, err error) {
+
+	err = s.getListResult(auditPlanRunBodyTpl, auditPlanRunQueryTpl, data, &list)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err = s.getCountResult(auditPlanRunBodyTpl, auditPlanRunCountTpl, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return
+}
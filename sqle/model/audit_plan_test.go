@@ -24,6 +24,22 @@ func TestStorage_GetAuditPlans(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestStorage_GetAuditPlanCountByDBType(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectQuery("SELECT count(*) FROM `audit_plans`  WHERE `audit_plans`.`deleted_at` IS NULL AND ((db_type = ?))").
+		WithArgs("Oracle").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectClose()
+	count, err := GetStorage().GetAuditPlanCountByDBType("Oracle")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	mockDB.Close()
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
 func TestStorage_GetAuditPlanByName(t *testing.T) {
 	// 1. test record exist
 	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
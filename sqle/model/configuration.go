@@ -144,6 +144,137 @@ func (s *Storage) GetWeChatConfiguration() (*WeChatConfiguration, bool, error) {
 	return wechatC, true, errors.New(errors.ConnectStorageError, err)
 }
 
+// SlackConfiguration store Slack incoming web hook configuration.
+type SlackConfiguration struct {
+	Model
+	EnableSlackNotify bool   `json:"enable_slack_notify" gorm:"not null"`
+	WebHookURL        string `json:"web_hook_url" gorm:"type:varchar(1024)"`
+}
+
+func (i *SlackConfiguration) TableName() string {
+	return fmt.Sprintf("%v_slack", globalConfigurationTablePrefix)
+}
+
+func (s *Storage) GetSlackConfiguration() (*SlackConfiguration, bool, error) {
+	slackC := new(SlackConfiguration)
+	err := s.db.Last(slackC).Error
+	if err == gorm.ErrRecordNotFound {
+		return slackC, false, nil
+	}
+	return slackC, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// DingTalkConfiguration store DingTalk robot configuration, the secret is used
+// to sign the message if the robot is protected by signature.
+type DingTalkConfiguration struct {
+	Model
+	EnableDingTalkNotify bool   `json:"enable_ding_talk_notify" gorm:"not null"`
+	WebHookURL           string `json:"web_hook_url" gorm:"type:varchar(1024)"`
+	Secret               string `json:"-" gorm:"-"`
+	EncryptedSecret      string `json:"encrypted_secret"`
+}
+
+func (i *DingTalkConfiguration) TableName() string {
+	return fmt.Sprintf("%v_ding_talk", globalConfigurationTablePrefix)
+}
+
+// BeforeSave is a hook implement gorm model before exec create.
+func (i *DingTalkConfiguration) BeforeSave() error {
+	data, err := utils.AesEncrypt(i.Secret)
+	if err != nil {
+		return err
+	}
+	i.EncryptedSecret = data
+	return nil
+}
+
+// AfterFind is a hook implement gorm model after query, ignore err if query from db.
+func (i *DingTalkConfiguration) AfterFind() error {
+	data, err := utils.AesDecrypt(i.EncryptedSecret)
+	if err != nil {
+		log.NewEntry().Errorf("decrypt secret for DingTalk configuration failed, error: %v", err)
+		return nil
+	}
+	i.Secret = data
+	return nil
+}
+
+func (s *Storage) GetDingTalkConfiguration() (*DingTalkConfiguration, bool, error) {
+	dingTalkC := new(DingTalkConfiguration)
+	err := s.db.Last(dingTalkC).Error
+	if err == gorm.ErrRecordNotFound {
+		return dingTalkC, false, nil
+	}
+	return dingTalkC, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// FeishuConfiguration store Feishu custom bot configuration, the secret is used
+// to sign the message if the bot is protected by signature.
+type FeishuConfiguration struct {
+	Model
+	EnableFeishuNotify bool   `json:"enable_feishu_notify" gorm:"not null"`
+	WebHookURL         string `json:"web_hook_url" gorm:"type:varchar(1024)"`
+	Secret             string `json:"-" gorm:"-"`
+	EncryptedSecret    string `json:"encrypted_secret"`
+}
+
+func (i *FeishuConfiguration) TableName() string {
+	return fmt.Sprintf("%v_feishu", globalConfigurationTablePrefix)
+}
+
+// BeforeSave is a hook implement gorm model before exec create.
+func (i *FeishuConfiguration) BeforeSave() error {
+	data, err := utils.AesEncrypt(i.Secret)
+	if err != nil {
+		return err
+	}
+	i.EncryptedSecret = data
+	return nil
+}
+
+// AfterFind is a hook implement gorm model after query, ignore err if query from db.
+func (i *FeishuConfiguration) AfterFind() error {
+	data, err := utils.AesDecrypt(i.EncryptedSecret)
+	if err != nil {
+		log.NewEntry().Errorf("decrypt secret for Feishu configuration failed, error: %v", err)
+		return nil
+	}
+	i.Secret = data
+	return nil
+}
+
+func (s *Storage) GetFeishuConfiguration() (*FeishuConfiguration, bool, error) {
+	feishuC := new(FeishuConfiguration)
+	err := s.db.Last(feishuC).Error
+	if err == gorm.ErrRecordNotFound {
+		return feishuC, false, nil
+	}
+	return feishuC, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// MattermostConfiguration store Mattermost incoming web hook configuration,
+// the channel and username override the default of the web hook if not empty.
+type MattermostConfiguration struct {
+	Model
+	EnableMattermostNotify bool   `json:"enable_mattermost_notify" gorm:"not null"`
+	WebHookURL             string `json:"web_hook_url" gorm:"type:varchar(1024)"`
+	Channel                string `json:"channel"`
+	Username               string `json:"username"`
+}
+
+func (i *MattermostConfiguration) TableName() string {
+	return fmt.Sprintf("%v_mattermost", globalConfigurationTablePrefix)
+}
+
+func (s *Storage) GetMattermostConfiguration() (*MattermostConfiguration, bool, error) {
+	mattermostC := new(MattermostConfiguration)
+	err := s.db.Last(mattermostC).Error
+	if err == gorm.ErrRecordNotFound {
+		return mattermostC, false, nil
+	}
+	return mattermostC, true, errors.New(errors.ConnectStorageError, err)
+}
+
 // LDAPConfiguration store ldap server configuration.
 type LDAPConfiguration struct {
 	Model
@@ -304,6 +435,9 @@ func (s *Storage) GetOauth2Configuration() (*Oauth2Configuration, bool, error) {
 
 const (
 	SystemVariableWorkflowExpiredHours = "system_variable_workflow_expired_hours"
+	// SystemVariableSqleUrl is the url of SQLE which is accessed by users, it's
+	// used to make the link of workflow in notification.
+	SystemVariableSqleUrl = "system_variable_sqle_url"
 )
 
 // SystemVariable store misc K-V.
@@ -331,3 +465,12 @@ func (s *Storage) GetWorkflowExpiredHoursOrDefault() (int64, error) {
 
 	return 30 * 24, nil
 }
+
+func (s *Storage) GetSqleUrl() (string, error) {
+	sv := &SystemVariable{}
+	err := s.db.Where("`key` = ?", SystemVariableSqleUrl).First(sv).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	return sv.Value, errors.New(errors.ConnectStorageError, err)
+}
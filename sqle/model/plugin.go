@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// PluginActionStartup means the plugin binary is changed when SQLE starts.
+	PluginActionStartup = "startup"
+	PluginActionInstall = "install"
+	PluginActionReload  = "reload"
+	PluginActionUnload  = "unload"
+)
+
+// PluginVersion is the history of the plugin binary loaded by SQLE.
+type PluginVersion struct {
+	Model
+	PluginName string `json:"plugin_name" gorm:"index;not null"`
+	// Version is the protocol version of plugin.
+	Version int32 `json:"version"`
+	// Checksum is the sha256 of plugin binary.
+	Checksum         string `json:"checksum" gorm:"type:varchar(64)"`
+	Action           string `json:"action" gorm:"type:varchar(32)"`
+	RuleCount        int    `json:"rule_count"`
+	OperatorUserName string `json:"operator_user_name"`
+}
+
+func (p PluginVersion) TableName() string {
+	return "plugin_versions"
+}
+
+func (s *Storage) GetPluginVersions(pluginName string) ([]*PluginVersion, error) {
+	versions := []*PluginVersion{}
+	err := s.db.Where("plugin_name = ?", pluginName).Order("id DESC").Find(&versions).Error
+	return versions, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetLatestPluginVersion(pluginName string) (*PluginVersion, bool, error) {
+	version := &PluginVersion{}
+	err := s.db.Where("plugin_name = ?", pluginName).Order("id DESC").First(version).Error
+	if err == gorm.ErrRecordNotFound {
+		return version, false, nil
+	}
+	return version, true, errors.New(errors.ConnectStorageError, err)
+}
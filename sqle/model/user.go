@@ -133,6 +133,12 @@ func (s *Storage) GetUsersByNames(names []string) ([]*User, error) {
 	return users, errors.New(errors.ConnectStorageError, err)
 }
 
+func (s *Storage) GetUsersByIDs(ids []uint) ([]*User, error) {
+	users := []*User{}
+	err := s.db.Where("id in (?)", ids).Find(&users).Error
+	return users, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetAllUserTip() ([]*User, error) {
 	users := []*User{}
 	err := s.db.Select("login_name").Where("stat=0").Find(&users).Error
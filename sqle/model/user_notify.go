@@ -0,0 +1,225 @@
+package model
+
+import (
+	sqlDriver "database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// the event types which can be chosen by user in notify setting.
+const (
+	NotifyEventWorkflowCreate         = "workflow_create"
+	NotifyEventWorkflowApprove        = "workflow_approve"
+	NotifyEventWorkflowReject         = "workflow_reject"
+	NotifyEventWorkflowExecuteSuccess = "workflow_execute_success"
+	NotifyEventWorkflowExecuteFail    = "workflow_execute_fail"
+	NotifyEventAuditPlanReport        = "audit_plan_report"
+)
+
+var NotifyEvents = []string{
+	NotifyEventWorkflowCreate,
+	NotifyEventWorkflowApprove,
+	NotifyEventWorkflowReject,
+	NotifyEventWorkflowExecuteSuccess,
+	NotifyEventWorkflowExecuteFail,
+	NotifyEventAuditPlanReport,
+}
+
+// the channels which send notifications to users.
+const (
+	NotifyChannelEmail      = "email"
+	NotifyChannelSlack      = "slack"
+	NotifyChannelDingTalk   = "ding_talk"
+	NotifyChannelFeishu     = "feishu"
+	NotifyChannelMattermost = "mattermost"
+)
+
+var NotifyChannels = []string{
+	NotifyChannelEmail,
+	NotifyChannelSlack,
+	NotifyChannelDingTalk,
+	NotifyChannelFeishu,
+	NotifyChannelMattermost,
+}
+
+// NotifyPreferences maps event type to the channels which the user receives
+// the event on. The user receives the event on all channels if the event type
+// is not in it, and receives nothing if the channels is empty.
+type NotifyPreferences map[string] /* event type */ []string /* channels */
+
+func (p NotifyPreferences) Value() (sqlDriver.Value, error) {
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+func (p *NotifyPreferences) Scan(input interface{}) error {
+	switch v := input.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported type %T for notify preferences", input)
+	}
+}
+
+// UserNotifySetting is the notify setting of user, the user without setting
+// receives all events on all channels instantly.
+type UserNotifySetting struct {
+	Model
+	UserID            uint              `json:"user_id" gorm:"unique_index"`
+	EnableDailyDigest bool              `json:"enable_daily_digest"`
+	Preferences       NotifyPreferences `json:"preferences" gorm:"type:text"`
+}
+
+func (u UserNotifySetting) TableName() string {
+	return "user_notify_settings"
+}
+
+// AcceptChannel checks whether the user receives the event on the channel.
+func (u *UserNotifySetting) AcceptChannel(event, channel string) bool {
+	if u == nil || event == "" {
+		return true
+	}
+	channels, ok := u.Preferences[event]
+	if !ok {
+		return true
+	}
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptEvent checks whether the user receives the event on any channel.
+func (u *UserNotifySetting) AcceptEvent(event string) bool {
+	if u == nil || event == "" {
+		return true
+	}
+	channels, ok := u.Preferences[event]
+	return !ok || len(channels) > 0
+}
+
+func (s *Storage) GetUserNotifySetting(userID uint) (*UserNotifySetting, bool, error) {
+	setting := &UserNotifySetting{}
+	err := s.db.Where("user_id = ?", userID).First(setting).Error
+	if err == gorm.ErrRecordNotFound {
+		return &UserNotifySetting{UserID: userID, Preferences: NotifyPreferences{}}, false, nil
+	}
+	if setting.Preferences == nil {
+		setting.Preferences = NotifyPreferences{}
+	}
+	return setting, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetUserNotifySettingsByUserIDs(userIDs []uint) (map[uint]*UserNotifySetting, error) {
+	settings := []*UserNotifySetting{}
+	if len(userIDs) > 0 {
+		err := s.db.Where("user_id IN (?)", userIDs).Find(&settings).Error
+		if err != nil {
+			return nil, errors.New(errors.ConnectStorageError, err)
+		}
+	}
+	settingMap := make(map[uint]*UserNotifySetting, len(settings))
+	for _, setting := range settings {
+		settingMap[setting.UserID] = setting
+	}
+	return settingMap, nil
+}
+
+// UserNotifySubscription is the instance which user subscribes, the user
+// receives the notifications of all workflows on the instance.
+type UserNotifySubscription struct {
+	Model
+	UserID       uint   `json:"user_id" gorm:"index"`
+	InstanceName string `json:"instance_name" gorm:"index"`
+}
+
+func (u UserNotifySubscription) TableName() string {
+	return "user_notify_subscriptions"
+}
+
+func (s *Storage) GetUserNotifySubscriptions(userID uint) ([]*UserNotifySubscription, error) {
+	subscriptions := []*UserNotifySubscription{}
+	err := s.db.Where("user_id = ?", userID).Order("id").Find(&subscriptions).Error
+	return subscriptions, errors.New(errors.ConnectStorageError, err)
+}
+
+// UpdateUserNotifySubscriptions overrides the subscribed instances of user.
+func (s *Storage) UpdateUserNotifySubscriptions(userID uint, instanceNames []string) error {
+	return errors.New(errors.ConnectStorageError, s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_id = ?", userID).Delete(&UserNotifySubscription{}).Error
+		if err != nil {
+			return err
+		}
+		for _, name := range instanceNames {
+			err := tx.Create(&UserNotifySubscription{UserID: userID, InstanceName: name}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Storage) GetUsersSubscribedInstance(instanceName string) ([]*User, error) {
+	users := []*User{}
+	err := s.db.Model(&User{}).
+		Joins("JOIN user_notify_subscriptions AS subscriptions ON subscriptions.user_id = users.id").
+		Where("subscriptions.instance_name = ? AND subscriptions.deleted_at IS NULL", instanceName).
+		Where("users.stat = ?", Enabled).
+		Find(&users).Error
+	return users, errors.New(errors.ConnectStorageError, err)
+}
+
+// UserNotifyDigestItem is the notification which is delayed to the daily
+// digest of user.
+type UserNotifyDigestItem struct {
+	Model
+	UserID  uint   `json:"user_id" gorm:"index"`
+	Subject string `json:"subject"`
+	Body    string `json:"body" gorm:"type:text"`
+}
+
+func (u UserNotifyDigestItem) TableName() string {
+	return "user_notify_digest_items"
+}
+
+func (s *Storage) GetUserNotifyDigestItems() ([]*UserNotifyDigestItem, error) {
+	items := []*UserNotifyDigestItem{}
+	err := s.db.Order("user_id, id").Find(&items).Error
+	return items, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) DeleteUserNotifyDigestItems(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := s.db.Unscoped().Where("id IN (?)", ids).Delete(&UserNotifyDigestItem{}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// CheckUserCanViewInstanceWorkflows checks whether the user can view the
+// workflows of others on the instance, it's required to subscribe the instance.
+func (s *Storage) CheckUserCanViewInstanceWorkflows(user *User, instName string) (bool, error) {
+	if user.Name == DefaultAdminUser {
+		return true, nil
+	}
+	instances, err := s.GetUserCanOpInstances(user, []uint{OP_WORKFLOW_VIEW_OTHERS})
+	if err != nil {
+		return false, err
+	}
+	for _, instance := range instances {
+		if instName == instance.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -106,12 +106,19 @@ var autoMigrateList = []interface{}{
 	&AuditPlanReportSQLV2{},
 	&AuditPlanReportV2{},
 	&AuditPlanSQLV2{},
+	&AuditPlanRun{},
+	&AuditPlanNotifyRecord{},
 	&AuditPlan{},
 	&ExecuteSQL{},
 	&Instance{},
 	&WeChatConfiguration{},
+	&SlackConfiguration{},
+	&DingTalkConfiguration{},
+	&FeishuConfiguration{},
+	&MattermostConfiguration{},
 	&LDAPConfiguration{},
 	&Oauth2Configuration{},
+	&PluginVersion{},
 	&RoleOperation{},
 	&Role{},
 	&RollbackSQL{},
@@ -123,6 +130,9 @@ var autoMigrateList = []interface{}{
 	&SystemVariable{},
 	&Task{},
 	&UserGroup{},
+	&UserNotifySetting{},
+	&UserNotifySubscription{},
+	&UserNotifyDigestItem{},
 	&User{},
 	&WorkflowRecord{},
 	&WorkflowStepTemplate{},
@@ -233,6 +243,54 @@ func (s *Storage) CreateDefaultTemplate(rules map[string][]*driver.Rule) error {
 	return nil
 }
 
+// SyncDriverRules creates the rules of driver which is loaded at runtime, and
+// adds the new rules with error level to the default rule template.
+func (s *Storage) SyncDriverRules(dbType string, rules []*driver.Rule) error {
+	newRules := []*driver.Rule{}
+	for _, rule := range rules {
+		_, exist, err := s.GetRule(rule.Name, dbType)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			newRules = append(newRules, rule)
+		}
+	}
+	if err := s.CreateRulesIfNotExist(map[string][]*driver.Rule{dbType: rules}); err != nil {
+		return err
+	}
+
+	t, exist, err := s.GetRuleTemplateByName(s.GetDefaultRuleTemplateName(dbType))
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return s.CreateDefaultTemplate(map[string][]*driver.Rule{dbType: rules})
+	}
+	// the rules in default template may be changed by user, so only the new
+	// rules are added.
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, rule := range newRules {
+			if rule.Level != driver.RuleLevelError {
+				continue
+			}
+			modelRule := GenerateRuleByDriverRule(rule, dbType)
+			err := tx.Omit("Rule").Create(&RuleTemplateRule{
+				RuleTemplateId: t.ID,
+				RuleName:       modelRule.Name,
+				RuleLevel:      modelRule.Level,
+				RuleParams:     modelRule.Params,
+				RuleDBType:     dbType,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetDefaultRuleTemplateName(dbType string) string {
 	return fmt.Sprintf("default_%v", dbType)
 }
@@ -0,0 +1,324 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+func init() {
+	Notifiers = append(Notifiers,
+		&SlackNotifier{},
+		&DingTalkNotifier{},
+		&FeishuNotifier{},
+		&MattermostNotifier{},
+	)
+}
+
+// chatMessage is the message which is sent to the chat platforms by incoming
+// web hook, the text is markdown.
+type chatMessage struct {
+	Title string
+	Text  string
+	Link  string
+}
+
+func newChatMessage(notification Notification, users []*model.User, sqleUrl string) *chatMessage {
+	msg := &chatMessage{
+		Title: notification.NotificationSubject(),
+		Text:  strings.TrimSpace(notification.NotificationBody()),
+	}
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		if user != nil {
+			names = append(names, user.Name)
+		}
+	}
+	if len(names) > 0 {
+		msg.Text = fmt.Sprintf("%v\n- 通知对象: %v", msg.Text, strings.Join(names, ", "))
+	}
+	msg.Link = workflowLink(notification, sqleUrl)
+	return msg
+}
+
+// workflowLink returns the link to the workflow page of SQLE, it's empty if
+// the SQLE url is not configured or the notification is not about workflow.
+func workflowLink(notification Notification, sqleUrl string) string {
+	w, ok := notification.(*WorkflowNotification)
+	if !ok || sqleUrl == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v/order/%v", strings.TrimRight(sqleUrl, "/"), w.workflow.ID)
+}
+
+func loadChatMessage(notification Notification, users []*model.User) (*chatMessage, error) {
+	sqleUrl, err := model.GetStorage().GetSqleUrl()
+	if err != nil {
+		return nil, err
+	}
+	return newChatMessage(notification, users, sqleUrl), nil
+}
+
+const chatLinkText = "查看详情"
+
+type SlackNotifier struct{}
+
+func (n *SlackNotifier) Channel() string {
+	return model.NotifyChannelSlack
+}
+
+func (n *SlackNotifier) Notify(notification Notification, users []*model.User) error {
+	slackC, exist, err := model.GetStorage().GetSlackConfiguration()
+	if err != nil {
+		return err
+	}
+	if !exist || !slackC.EnableSlackNotify {
+		return nil
+	}
+	return n.Send(slackC, notification, users)
+}
+
+// Send sends the notification whether the Slack notify is enabled or not.
+func (n *SlackNotifier) Send(slackC *model.SlackConfiguration, notification Notification, users []*model.User) error {
+	msg, err := loadChatMessage(notification, users)
+	if err != nil {
+		return err
+	}
+	payload, err := slackPayload(msg)
+	if err != nil {
+		return err
+	}
+	_, err = stdWebHookSender.Post(slackC.WebHookURL, "application/json", payload, nil)
+	return err
+}
+
+func slackPayload(msg *chatMessage) ([]byte, error) {
+	text := fmt.Sprintf("*%v*\n%v", msg.Title, msg.Text)
+	if msg.Link != "" {
+		text = fmt.Sprintf("%v\n<%v|%v>", text, msg.Link, chatLinkText)
+	}
+	return json.Marshal(map[string]interface{}{
+		"text": text,
+	})
+}
+
+type MattermostNotifier struct{}
+
+func (n *MattermostNotifier) Channel() string {
+	return model.NotifyChannelMattermost
+}
+
+func (n *MattermostNotifier) Notify(notification Notification, users []*model.User) error {
+	mattermostC, exist, err := model.GetStorage().GetMattermostConfiguration()
+	if err != nil {
+		return err
+	}
+	if !exist || !mattermostC.EnableMattermostNotify {
+		return nil
+	}
+	return n.Send(mattermostC, notification, users)
+}
+
+// Send sends the notification whether the Mattermost notify is enabled or not.
+func (n *MattermostNotifier) Send(mattermostC *model.MattermostConfiguration, notification Notification, users []*model.User) error {
+	msg, err := loadChatMessage(notification, users)
+	if err != nil {
+		return err
+	}
+	payload, err := mattermostPayload(mattermostC, msg)
+	if err != nil {
+		return err
+	}
+	_, err = stdWebHookSender.Post(mattermostC.WebHookURL, "application/json", payload, nil)
+	return err
+}
+
+func mattermostPayload(mattermostC *model.MattermostConfiguration, msg *chatMessage) ([]byte, error) {
+	payload := map[string]interface{}{
+		"text": markdownText(msg),
+	}
+	if mattermostC.Channel != "" {
+		payload["channel"] = mattermostC.Channel
+	}
+	if mattermostC.Username != "" {
+		payload["username"] = mattermostC.Username
+	}
+	return json.Marshal(payload)
+}
+
+type DingTalkNotifier struct{}
+
+func (n *DingTalkNotifier) Channel() string {
+	return model.NotifyChannelDingTalk
+}
+
+func (n *DingTalkNotifier) Notify(notification Notification, users []*model.User) error {
+	dingTalkC, exist, err := model.GetStorage().GetDingTalkConfiguration()
+	if err != nil {
+		return err
+	}
+	if !exist || !dingTalkC.EnableDingTalkNotify {
+		return nil
+	}
+	return n.Send(dingTalkC, notification, users)
+}
+
+// Send sends the notification whether the DingTalk notify is enabled or not.
+func (n *DingTalkNotifier) Send(dingTalkC *model.DingTalkConfiguration, notification Notification, users []*model.User) error {
+	msg, err := loadChatMessage(notification, users)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  markdownText(msg),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	webHookURL, err := signDingTalkURL(dingTalkC.WebHookURL, dingTalkC.Secret, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = stdWebHookSender.Post(webHookURL, "application/json", payload, checkDingTalkResponse)
+	return err
+}
+
+// signDingTalkURL adds the timestamp and signature to the url if the secret is
+// not empty, see https://open.dingtalk.com/document/robots/customize-robot-security-settings
+func signDingTalkURL(webHookURL, secret string, now time.Time) (string, error) {
+	if secret == "" {
+		return webHookURL, nil
+	}
+	u, err := url.Parse(webHookURL)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp + "\n" + secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func checkDingTalkResponse(body []byte) error {
+	resp := struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("error code %v: %v", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
+
+type FeishuNotifier struct{}
+
+func (n *FeishuNotifier) Channel() string {
+	return model.NotifyChannelFeishu
+}
+
+func (n *FeishuNotifier) Notify(notification Notification, users []*model.User) error {
+	feishuC, exist, err := model.GetStorage().GetFeishuConfiguration()
+	if err != nil {
+		return err
+	}
+	if !exist || !feishuC.EnableFeishuNotify {
+		return nil
+	}
+	return n.Send(feishuC, notification, users)
+}
+
+// Send sends the notification whether the Feishu notify is enabled or not.
+func (n *FeishuNotifier) Send(feishuC *model.FeishuConfiguration, notification Notification, users []*model.User) error {
+	msg, err := loadChatMessage(notification, users)
+	if err != nil {
+		return err
+	}
+	payload, err := feishuPayload(msg, feishuC.Secret, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = stdWebHookSender.Post(feishuC.WebHookURL, "application/json", payload, checkFeishuResponse)
+	return err
+}
+
+// feishuPayload makes the message card, the timestamp and signature are added
+// if the secret is not empty, see https://open.feishu.cn/document/ukTMukTMukTM/ucTM5YjL3ETO24yNxkjN
+func feishuPayload(msg *chatMessage, secret string, now time.Time) ([]byte, error) {
+	elements := []interface{}{
+		map[string]interface{}{
+			"tag":     "markdown",
+			"content": msg.Text,
+		},
+	}
+	if msg.Link != "" {
+		elements = append(elements, map[string]interface{}{
+			"tag": "action",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"tag":  "button",
+					"type": "primary",
+					"url":  msg.Link,
+					"text": map[string]string{"tag": "plain_text", "content": chatLinkText},
+				},
+			},
+		})
+	}
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title": map[string]string{"tag": "plain_text", "content": msg.Title},
+			},
+			"elements": elements,
+		},
+	}
+	if secret != "" {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		h := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+		payload["timestamp"] = timestamp
+		payload["sign"] = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	return json.Marshal(payload)
+}
+
+func checkFeishuResponse(body []byte) error {
+	resp := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("error code %v: %v", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+func markdownText(msg *chatMessage) string {
+	text := fmt.Sprintf("#### %v\n%v", msg.Title, msg.Text)
+	if msg.Link != "" {
+		text = fmt.Sprintf("%v\n\n[%v](%v)", text, chatLinkText, msg.Link)
+	}
+	return text
+}
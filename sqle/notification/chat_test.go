@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChatMessage(t *testing.T) {
+	users := []*model.User{{Name: "user1"}, {Name: "user2"}}
+
+	msg := newChatMessage(&TestNotify{}, users, "http://10.186.61.32:10000/")
+	assert.Equal(t, "SQLE notification test", msg.Title)
+	assert.Contains(t, msg.Text, "- 通知对象: user1, user2")
+	assert.Equal(t, "", msg.Link)
+}
+
+func TestWorkflowLink(t *testing.T) {
+	w := &WorkflowNotification{workflow: &model.Workflow{Model: model.Model{ID: 12}}, notifyType: WorkflowNotifyTypeReject}
+	assert.Equal(t, "http://10.186.61.32:10000/order/12", workflowLink(w, "http://10.186.61.32:10000/"))
+	// the link is empty if the SQLE url is not configured
+	assert.Equal(t, "", workflowLink(w, ""))
+	assert.Equal(t, "", workflowLink(&TestNotify{}, "http://10.186.61.32:10000"))
+}
+
+func TestChatPayload(t *testing.T) {
+	msg := &chatMessage{Title: "title", Text: "- a: 1", Link: "http://sqle/order/1"}
+
+	payload, err := slackPayload(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text": "*title*\n- a: 1\n<http://sqle/order/1|查看详情>"}`, string(payload))
+
+	payload, err = mattermostPayload(&model.MattermostConfiguration{Channel: "dba"}, msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text": "#### title\n- a: 1\n\n[查看详情](http://sqle/order/1)", "channel": "dba"}`, string(payload))
+
+	now := time.Unix(1600000000, 0)
+	payload, err = feishuPayload(msg, "secret", now)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(payload, &result))
+	assert.Equal(t, "interactive", result["msg_type"])
+	assert.Equal(t, "1600000000", result["timestamp"])
+	h := hmac.New(sha256.New, []byte("1600000000\nsecret"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(h.Sum(nil)), result["sign"])
+	assert.Len(t, result["card"].(map[string]interface{})["elements"], 2)
+
+	// no signature and button
+	payload, err = feishuPayload(&chatMessage{Title: "title", Text: "text"}, "", now)
+	assert.NoError(t, err)
+	result = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(payload, &result))
+	assert.NotContains(t, result, "sign")
+	assert.Len(t, result["card"].(map[string]interface{})["elements"], 1)
+}
+
+func TestSignDingTalkURL(t *testing.T) {
+	webHookURL := "https://oapi.dingtalk.com/robot/send?access_token=xxx"
+	signed, err := signDingTalkURL(webHookURL, "", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, webHookURL, signed)
+
+	signed, err = signDingTalkURL(webHookURL, "secret", time.Unix(1600000000, 0))
+	assert.NoError(t, err)
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "xxx", u.Query().Get("access_token"))
+	assert.Equal(t, "1600000000000", u.Query().Get("timestamp"))
+	h := hmac.New(sha256.New, []byte("secret"))
+	h.Write([]byte("1600000000000\nsecret"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(h.Sum(nil)), u.Query().Get("sign"))
+}
+
+func TestCheckChatResponse(t *testing.T) {
+	assert.NoError(t, checkDingTalkResponse([]byte(`{"errcode":0,"errmsg":"ok"}`)))
+	assert.EqualError(t, checkDingTalkResponse([]byte(`{"errcode":310000,"errmsg":"sign not match"}`)), "error code 310000: sign not match")
+	assert.NoError(t, checkFeishuResponse([]byte(`{"code":0,"msg":"success"}`)))
+	assert.EqualError(t, checkFeishuResponse([]byte(`{"code":19021,"msg":"sign match fail"}`)), "error code 19021: sign match fail")
+	assert.Error(t, checkFeishuResponse([]byte(`ok`)))
+}
+
+func TestDingTalkNotifierSend(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+	mock.ExpectQuery("SELECT \\* FROM `system_variables`").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "value"}).AddRow(model.SystemVariableSqleUrl, "http://sqle"))
+
+	var received map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.URL.Query().Get("sign"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer ts.Close()
+
+	notifier := &DingTalkNotifier{}
+	err = notifier.Send(&model.DingTalkConfiguration{WebHookURL: ts.URL + "?access_token=xxx", Secret: "secret"},
+		&TestNotify{}, []*model.User{{Name: "admin"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "markdown", received["msgtype"])
+	assert.Equal(t, "SQLE notification test", received["markdown"].(map[string]interface{})["title"])
+	assert.Contains(t, received["markdown"].(map[string]interface{})["text"], "- 通知对象: admin")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
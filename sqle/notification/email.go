@@ -16,6 +16,10 @@ func init() {
 
 type EmailNotifier struct{}
 
+func (n *EmailNotifier) Channel() string {
+	return model.NotifyChannelEmail
+}
+
 func (n *EmailNotifier) Notify(notification Notification, users []*model.User) error {
 	// workflow has been finished.
 	if len(users) == 0 {
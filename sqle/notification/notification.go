@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/pkg/errors"
 
 	"github.com/actiontech/sqle/sqle/model"
 )
@@ -19,18 +20,29 @@ type Notification interface {
 
 type Notifier interface {
 	Notify(Notification, []*model.User) error
+	// Channel is the channel name which users choose in notify setting.
+	Channel() string
 }
 
 var Notifiers = []Notifier{}
 
+// Notify sends the notification by all notifiers, the failure of one notifier
+// doesn't prevent the others. The users receive the notification on the
+// channels chosen in their notify setting, or in the daily digest.
 func Notify(notification Notification, users []*model.User) error {
+	recipients, err := splitRecipients(notification, users)
+	if err != nil {
+		return err
+	}
+	var errs []error
 	for _, n := range Notifiers {
-		err := n.Notify(notification, users)
-		if err != nil {
-			return err
+		channelUsers := recipients.forChannel(n.Channel())
+		if len(channelUsers) == 0 {
+			continue
 		}
+		errs = append(errs, n.Notify(notification, channelUsers))
 	}
-	return nil
+	return errors.Combine(errs...)
 }
 
 type WorkflowNotifyType int
@@ -191,6 +203,35 @@ func (w *WorkflowNotification) notifyUser() []*model.User {
 	}
 }
 
+// subscribedUser returns the users who subscribe the instance of workflow.
+func (w *WorkflowNotification) subscribedUser() ([]*model.User, error) {
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(strconv.Itoa(int(w.workflow.Record.TaskId)))
+	if err != nil || !exist {
+		return nil, err
+	}
+	return s.GetUsersSubscribedInstance(task.InstanceName())
+}
+
+// mergeUsers merges the users and removes the duplicated ones.
+func mergeUsers(users []*model.User, others []*model.User) []*model.User {
+	merged := make([]*model.User, 0, len(users)+len(others))
+	exist := map[uint]struct{}{}
+	for _, list := range [][]*model.User{users, others} {
+		for _, user := range list {
+			if user == nil {
+				continue
+			}
+			if _, ok := exist[user.ID]; ok {
+				continue
+			}
+			exist[user.ID] = struct{}{}
+			merged = append(merged, user)
+		}
+	}
+	return merged
+}
+
 func NotifyWorkflow(workflowId string, wt WorkflowNotifyType) {
 	s := model.GetStorage()
 	workflow, exist, err := s.GetWorkflowDetailById(workflowId)
@@ -202,8 +243,13 @@ func NotifyWorkflow(workflowId string, wt WorkflowNotifyType) {
 	}
 
 	wn := NewWorkflowNotification(workflow, wt)
-	users := wn.notifyUser()
-	// workflow has been finished.
+	subscribers, err := wn.subscribedUser()
+	if err != nil {
+		log.NewEntry().Errorf("get subscribed users of workflow error, %v", err)
+	}
+	// the subscribers are notified even if the workflow has been finished and
+	// no one needs to operate it.
+	users := mergeUsers(wn.notifyUser(), subscribers)
 	if len(users) == 0 {
 		return
 	}
@@ -239,6 +285,9 @@ func (a *AuditPlanNotification) NotificationBody() string {
 - 审核得分: %v
 - 审核通过率：%v
 - 审核结果等级: %v
+- 新增问题数: %v
+- 持续问题数: %v
+- 已解决问题数: %v
 `,
 		a.auditPlan.Name,
 		a.report.CreatedAt.Format(time.RFC3339),
@@ -248,6 +297,9 @@ func (a *AuditPlanNotification) NotificationBody() string {
 		a.report.Score,
 		a.report.PassRate,
 		a.report.AuditLevel,
+		a.report.NewFindingCount,
+		a.report.PersistingFindingCount,
+		a.report.ResolvedFindingCount,
 	)
 }
 
@@ -273,7 +325,11 @@ func NotifyAuditPlan(apName string, report *model.AuditPlanReportV2) error {
 		return err
 	}
 
-	if driver.RuleLevelLessOrEqual(ap.NotifyLevel, report.AuditLevel) {
+	level := report.AuditLevel
+	if ap.NotifyOnlyNewFindings {
+		level = newFindingLevel(report)
+	}
+	if level != "" && driver.RuleLevelLessOrEqual(ap.NotifyLevel, level) {
 		n := NewAuditPlanNotification(ap, report)
 		return GetAuditPlanNotifier().Notify(n, ap)
 	}
@@ -281,6 +337,18 @@ func NotifyAuditPlan(apName string, report *model.AuditPlanReportV2) error {
 	return nil
 }
 
+// newFindingLevel returns the highest audit level of the new findings in report,
+// it's empty if there is no new finding.
+func newFindingLevel(report *model.AuditPlanReportV2) string {
+	level := driver.RuleLevelNull
+	for _, sql := range report.AuditPlanReportSQLs {
+		if sql.FindingStatus == model.FindingStatusNew && driver.RuleLevel(sql.AuditLevel).More(level) {
+			level = driver.RuleLevel(sql.AuditLevel)
+		}
+	}
+	return string(level)
+}
+
 var stdAuditPlanNotifier = NewAuditPlanNotifier()
 
 func GetAuditPlanNotifier() *AuditPlanNotifier {
@@ -306,7 +374,15 @@ func (n *AuditPlanNotifier) Notify(notification Notification, auditPlan *model.A
 		return nil
 	}
 
-	err := n.Send(notification, auditPlan)
+	var emailUsers []*model.User
+	if auditPlan.EnableEmailNotify {
+		recipients, err := splitRecipients(notification, []*model.User{auditPlan.CreateUser})
+		if err != nil {
+			return err
+		}
+		emailUsers = recipients.forChannel(model.NotifyChannelEmail)
+	}
+	err := n.send(notification, auditPlan, emailUsers)
 	if err != nil {
 		return err
 	}
@@ -322,24 +398,27 @@ func (n *AuditPlanNotifier) shouldNotify(auditPlan *model.AuditPlan) bool {
 	return time.Now().After(last.Add(time.Duration(auditPlan.NotifyInterval) * time.Minute))
 }
 
-func (n *AuditPlanNotifier) Send(notification Notification, auditPlan *model.AuditPlan) (err error) {
+// Send sends the notification to all enabled channels, the failure of one
+// channel doesn't prevent sending to the others.
+func (n *AuditPlanNotifier) Send(notification Notification, auditPlan *model.AuditPlan) error {
+	var emailUsers []*model.User
 	if auditPlan.EnableEmailNotify {
-		err = n.sendEmail(notification, auditPlan.CreateUser)
-		if err != nil {
-			return err
-		}
-	}
-	if auditPlan.EnableWebHookNotify {
-		err = n.sendWebHook(notification, auditPlan.WebHookURL, auditPlan.WebHookTemplate)
-		if err != nil {
-			return err
-		}
+		emailUsers = []*model.User{auditPlan.CreateUser}
 	}
-	return nil
+	return n.send(notification, auditPlan, emailUsers)
 }
 
-func (n *AuditPlanNotifier) sendEmail(notification Notification, user *model.User) error {
-	return n.emailNotifier.Notify(notification, []*model.User{user})
+// send sends the email to the given users, they are the creator of audit plan
+// who doesn't turn off the email or enable the daily digest in notify setting.
+func (n *AuditPlanNotifier) send(notification Notification, auditPlan *model.AuditPlan, emailUsers []*model.User) error {
+	var errs []error
+	if len(emailUsers) > 0 {
+		errs = append(errs, n.emailNotifier.Notify(notification, emailUsers))
+	}
+	if auditPlan.EnableWebHookNotify {
+		errs = append(errs, n.sendWebHook(notification, auditPlan))
+	}
+	return errors.Combine(errs...)
 }
 
 func (n *AuditPlanNotifier) updateRecord(auditPlanName string) {
@@ -3,6 +3,17 @@
 
 package notification
 
-func (n *AuditPlanNotifier) sendWebHook(notification Notification, webHookUrl, webHookTemplate string) error {
-	return nil
+import (
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+func (n *AuditPlanNotifier) sendWebHook(notification Notification, auditPlan *model.AuditPlan) error {
+	payload, err := renderWebHookPayload(auditPlan.WebHookTemplate, newWebHookData(notification, auditPlan))
+	if err != nil {
+		saveNotifyRecord(auditPlan, model.NotifyChannelWebHook, nil, err)
+		return err
+	}
+	result, err := stdWebHookSender.Post(auditPlan.WebHookURL, "application/json", payload, nil)
+	saveNotifyRecord(auditPlan, model.NotifyChannelWebHook, result, err)
+	return err
 }
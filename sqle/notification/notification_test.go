@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/agiledragon/gomonkey"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotifier struct {
+	users []*model.User
+}
+
+func (n *mockNotifier) Notify(notification Notification, users []*model.User) error {
+	n.users = append(n.users, users...)
+	return nil
+}
+
+func (n *mockNotifier) Channel() string {
+	return model.NotifyChannelEmail
+}
+
+func TestNotifyWorkflowSubscribers(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	subscriber := &model.User{Model: model.Model{ID: 1}, Name: "subscriber"}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetWorkflowDetailById", func(_ *model.Storage, _ string) (*model.Workflow, bool, error) {
+		// the workflow is finished, there is no assignee.
+		return &model.Workflow{Record: &model.WorkflowRecord{TaskId: 1}}, true, nil
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetTaskById", func(_ *model.Storage, _ string) (*model.Task, bool, error) {
+		return &model.Task{Instance: &model.Instance{Name: "inst_1"}}, true, nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetUsersSubscribedInstance", func(_ *model.Storage, instanceName string) ([]*model.User, error) {
+		assert.Equal(t, "inst_1", instanceName)
+		return []*model.User{subscriber}, nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetUserNotifySettingsByUserIDs", func(_ *model.Storage, _ []uint) (map[uint]*model.UserNotifySetting, error) {
+		return map[uint]*model.UserNotifySetting{}, nil
+	})
+
+	notifier := &mockNotifier{}
+	oldNotifiers := Notifiers
+	Notifiers = []Notifier{notifier}
+	defer func() { Notifiers = oldNotifiers }()
+
+	NotifyWorkflow("1", WorkflowNotifyTypeApprove)
+	assert.Equal(t, []*model.User{subscriber}, notifier.users)
+}
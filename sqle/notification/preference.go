@@ -0,0 +1,169 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/errors"
+)
+
+// notifyEvent returns the event type of the notification which users choose in
+// notify setting, it's empty if the notification is not chosen by users, e.g.
+// the test notification.
+func notifyEvent(notification Notification) string {
+	switch n := notification.(type) {
+	case *WorkflowNotification:
+		switch n.notifyType {
+		case WorkflowNotifyTypeCreate:
+			return model.NotifyEventWorkflowCreate
+		case WorkflowNotifyTypeApprove:
+			return model.NotifyEventWorkflowApprove
+		case WorkflowNotifyTypeReject:
+			return model.NotifyEventWorkflowReject
+		case WorkflowNotifyTypeExecuteSuccess:
+			return model.NotifyEventWorkflowExecuteSuccess
+		case WorkflowNotifyTypeExecuteFail:
+			return model.NotifyEventWorkflowExecuteFail
+		}
+	case *AuditPlanNotification:
+		return model.NotifyEventAuditPlanReport
+	}
+	return ""
+}
+
+// notifyRecipients is the users who receive the notification instantly.
+type notifyRecipients struct {
+	event    string
+	users    []*model.User
+	settings map[uint]*model.UserNotifySetting
+}
+
+// splitRecipients splits the users by their notify settings, the notification
+// is saved to the daily digest of users who enable it, the others are returned
+// as the recipients.
+func splitRecipients(notification Notification, users []*model.User) (*notifyRecipients, error) {
+	r := &notifyRecipients{
+		event:    notifyEvent(notification),
+		users:    []*model.User{},
+		settings: map[uint]*model.UserNotifySetting{},
+	}
+	if r.event == "" {
+		r.users = users
+		return r, nil
+	}
+
+	userIDs := []uint{}
+	for _, user := range users {
+		if user != nil {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	s := model.GetStorage()
+	settings, err := s.GetUserNotifySettingsByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	r.settings = settings
+
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+		setting := settings[user.ID]
+		if !setting.AcceptEvent(r.event) {
+			continue
+		}
+		if setting == nil || !setting.EnableDailyDigest {
+			r.users = append(r.users, user)
+			continue
+		}
+		err := s.Save(&model.UserNotifyDigestItem{
+			UserID:  user.ID,
+			Subject: notification.NotificationSubject(),
+			Body:    strings.TrimSpace(notification.NotificationBody()),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// forChannel returns the recipients who receive the notification on the channel.
+func (r *notifyRecipients) forChannel(channel string) []*model.User {
+	users := []*model.User{}
+	for _, user := range r.users {
+		if user == nil || r.settings[user.ID].AcceptChannel(r.event, channel) {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+type digestNotification struct {
+	items []*model.UserNotifyDigestItem
+}
+
+func (d *digestNotification) NotificationSubject() string {
+	return fmt.Sprintf("SQLE每日通知摘要[%v条]", len(d.items))
+}
+
+func (d *digestNotification) NotificationBody() string {
+	sections := make([]string, 0, len(d.items))
+	for _, item := range d.items {
+		sections = append(sections, fmt.Sprintf("[%v] %v\n%v",
+			item.CreatedAt.Format(time.RFC3339), item.Subject, item.Body))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// SendNotifyDigests sends the daily digest to users by email, the items are
+// removed after they are sent.
+func SendNotifyDigests() error {
+	s := model.GetStorage()
+	items, err := s.GetUserNotifyDigestItems()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	userIDs := []uint{}
+	itemsByUser := map[uint][]*model.UserNotifyDigestItem{}
+	for _, item := range items {
+		if _, ok := itemsByUser[item.UserID]; !ok {
+			userIDs = append(userIDs, item.UserID)
+		}
+		itemsByUser[item.UserID] = append(itemsByUser[item.UserID], item)
+	}
+	users, err := s.GetUsersByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	userMap := make(map[uint]*model.User, len(users))
+	for _, user := range users {
+		userMap[user.ID] = user
+	}
+
+	var errs []error
+	notifier := &EmailNotifier{}
+	for _, userID := range userIDs {
+		// the items of deleted user are removed directly.
+		if user, ok := userMap[userID]; ok {
+			err := notifier.Notify(&digestNotification{items: itemsByUser[userID]}, []*model.User{user})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		ids := make([]uint, 0, len(itemsByUser[userID]))
+		for _, item := range itemsByUser[userID] {
+			ids = append(ids, item.ID)
+		}
+		errs = append(errs, s.DeleteUserNotifyDigestItems(ids))
+	}
+	return errors.Combine(errs...)
+}
@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyEvent(t *testing.T) {
+	w := &WorkflowNotification{workflow: &model.Workflow{}, notifyType: WorkflowNotifyTypeExecuteFail}
+	assert.Equal(t, model.NotifyEventWorkflowExecuteFail, notifyEvent(w))
+	assert.Equal(t, model.NotifyEventAuditPlanReport, notifyEvent(newTestAuditPlanNotification()))
+	assert.Equal(t, "", notifyEvent(&TestNotify{}))
+}
+
+func TestUserNotifySettingAccept(t *testing.T) {
+	var setting *model.UserNotifySetting
+	assert.True(t, setting.AcceptChannel(model.NotifyEventWorkflowCreate, model.NotifyChannelEmail))
+	assert.True(t, setting.AcceptEvent(model.NotifyEventWorkflowCreate))
+
+	setting = &model.UserNotifySetting{Preferences: model.NotifyPreferences{
+		model.NotifyEventWorkflowCreate: {model.NotifyChannelSlack},
+		model.NotifyEventWorkflowReject: {},
+	}}
+	assert.True(t, setting.AcceptChannel(model.NotifyEventWorkflowCreate, model.NotifyChannelSlack))
+	assert.False(t, setting.AcceptChannel(model.NotifyEventWorkflowCreate, model.NotifyChannelEmail))
+	assert.False(t, setting.AcceptEvent(model.NotifyEventWorkflowReject))
+	// the event which is not in preferences is received on all channels
+	assert.True(t, setting.AcceptChannel(model.NotifyEventWorkflowApprove, model.NotifyChannelEmail))
+	// the notification without event is not filtered
+	assert.True(t, setting.AcceptChannel("", model.NotifyChannelEmail))
+}
+
+func TestSplitRecipients(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	users := []*model.User{
+		{Model: model.Model{ID: 1}, Name: "user1"},
+		{Model: model.Model{ID: 2}, Name: "user2"},
+		{Model: model.Model{ID: 3}, Name: "user3"},
+		{Model: model.Model{ID: 4}, Name: "user4"},
+		nil,
+	}
+	mock.ExpectQuery("SELECT \\* FROM `user_notify_settings`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "enable_daily_digest", "preferences"}).
+			AddRow(1, 2, false, `{"audit_plan_report":["slack"]}`).
+			AddRow(2, 3, false, `{"audit_plan_report":[]}`).
+			AddRow(3, 4, true, `{}`))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `user_notify_digest_items`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 4, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	r, err := splitRecipients(newTestAuditPlanNotification(), users)
+	assert.NoError(t, err)
+	// user3 mutes the event and user4 receives it in daily digest
+	assert.Len(t, r.users, 2)
+	email := r.forChannel(model.NotifyChannelEmail)
+	assert.Len(t, email, 1)
+	assert.Equal(t, "user1", email[0].Name)
+	assert.Len(t, r.forChannel(model.NotifyChannelSlack), 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// the test notification is sent to all users without querying settings
+	r, err = splitRecipients(&TestNotify{}, users[:2])
+	assert.NoError(t, err)
+	assert.Len(t, r.forChannel(model.NotifyChannelEmail), 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeUsers(t *testing.T) {
+	user1 := &model.User{Model: model.Model{ID: 1}, Name: "user1"}
+	user2 := &model.User{Model: model.Model{ID: 2}, Name: "user2"}
+	merged := mergeUsers([]*model.User{user1, nil}, []*model.User{{Model: model.Model{ID: 1}, Name: "user1"}, user2})
+	assert.Equal(t, []*model.User{user1, user2}, merged)
+}
+
+func TestDigestNotification(t *testing.T) {
+	d := &digestNotification{items: []*model.UserNotifyDigestItem{
+		{Subject: "SQL工单已被驳回", Body: "- 工单主题: a"},
+		{Subject: "SQL工单上线成功", Body: "- 工单主题: b"},
+	}}
+	assert.Equal(t, "SQLE每日通知摘要[2条]", d.NotificationSubject())
+	assert.Contains(t, d.NotificationBody(), "SQL工单已被驳回\n- 工单主题: a")
+	assert.Contains(t, d.NotificationBody(), "SQL工单上线成功\n- 工单主题: b")
+}
@@ -0,0 +1,204 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// WebHookData is the data to render the web hook template of audit plan, the
+// report fields are empty if the notification is not an audit plan report, e.g.
+// the test notification.
+type WebHookData struct {
+	Subject       string            `json:"subject"`
+	Body          string            `json:"body"`
+	AuditPlanName string            `json:"audit_plan_name"`
+	ReportID      uint              `json:"report_id"`
+	Score         int32             `json:"score"`
+	PassRate      float64           `json:"pass_rate"`
+	AuditLevel    string            `json:"audit_level"`
+	TopFindings   []*WebHookFinding `json:"top_findings"`
+}
+
+type WebHookFinding struct {
+	Number        uint   `json:"number"`
+	SQL           string `json:"sql"`
+	AuditLevel    string `json:"audit_level"`
+	AuditResult   string `json:"audit_result"`
+	FindingStatus string `json:"finding_status"`
+}
+
+// webHookTopFindingsLimit is the max count of findings in web hook data.
+const webHookTopFindingsLimit = 5
+
+func newWebHookData(notification Notification, auditPlan *model.AuditPlan) *WebHookData {
+	data := &WebHookData{
+		Subject:       notification.NotificationSubject(),
+		Body:          notification.NotificationBody(),
+		AuditPlanName: auditPlan.Name,
+		TopFindings:   []*WebHookFinding{},
+	}
+	n, ok := notification.(*AuditPlanNotification)
+	if !ok {
+		return data
+	}
+	data.ReportID = n.report.ID
+	data.Score = n.report.Score
+	data.PassRate = n.report.PassRate
+	data.AuditLevel = n.report.AuditLevel
+
+	for _, sql := range n.report.AuditPlanReportSQLs {
+		if !driver.RuleLevel(sql.AuditLevel).More(driver.RuleLevelNormal) {
+			continue
+		}
+		data.TopFindings = append(data.TopFindings, &WebHookFinding{
+			Number:        sql.Number,
+			SQL:           sql.SQL,
+			AuditLevel:    sql.AuditLevel,
+			AuditResult:   sql.AuditResult,
+			FindingStatus: sql.FindingStatus,
+		})
+	}
+	// the findings with higher level come first, then the SQL number.
+	sort.SliceStable(data.TopFindings, func(i, j int) bool {
+		return driver.RuleLevel(data.TopFindings[i].AuditLevel).More(driver.RuleLevel(data.TopFindings[j].AuditLevel))
+	})
+	if len(data.TopFindings) > webHookTopFindingsLimit {
+		data.TopFindings = data.TopFindings[:webHookTopFindingsLimit]
+	}
+	return data
+}
+
+const defaultWebHookTemplate = `{
+    "subject": {{ json .Subject }},
+    "body": {{ json .Body }},
+    "audit_plan_name": {{ json .AuditPlanName }},
+    "report_id": {{ .ReportID }},
+    "score": {{ .Score }},
+    "pass_rate": {{ .PassRate }},
+    "audit_level": {{ json .AuditLevel }},
+    "top_findings": {{ json .TopFindings }}
+}`
+
+var webHookTemplateFuncs = template.FuncMap{
+	// json is used to quote the string in JSON template, e.g. {"text": {{ json .Body }}}.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ValidateWebHookTemplate checks whether the web hook template can be rendered.
+func ValidateWebHookTemplate(tpl string) error {
+	_, err := renderWebHookPayload(tpl, &WebHookData{TopFindings: []*WebHookFinding{}})
+	return err
+}
+
+// renderWebHookPayload renders the web hook template by text/template, the
+// default template is used if tpl is empty.
+func renderWebHookPayload(tpl string, data *WebHookData) ([]byte, error) {
+	if tpl == "" {
+		tpl = defaultWebHookTemplate
+	}
+	t, err := template.New("web_hook").Funcs(webHookTemplateFuncs).Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("parse web hook template failed: %v", err)
+	}
+	buff := &bytes.Buffer{}
+	if err := t.Execute(buff, data); err != nil {
+		return nil, fmt.Errorf("render web hook template failed: %v", err)
+	}
+	return buff.Bytes(), nil
+}
+
+type webHookSender struct {
+	client        *http.Client
+	maxAttempts   int
+	retryInterval time.Duration
+}
+
+var stdWebHookSender = &webHookSender{
+	client:        &http.Client{Timeout: 10 * time.Second},
+	maxAttempts:   3,
+	retryInterval: 2 * time.Second,
+}
+
+// webHookResult is the delivery result of web hook.
+type webHookResult struct {
+	Attempts   int
+	StatusCode int
+}
+
+// Post posts the payload to url, it retries if the request failed or the
+// response status is not 2xx. The retry interval is doubled after every retry.
+// checkBody is used to check the response body if the platform responses error
+// with 2xx status, it can be nil.
+func (s *webHookSender) Post(url, contentType string, payload []byte, checkBody func(body []byte) error) (*webHookResult, error) {
+	result := &webHookResult{}
+	interval := s.retryInterval
+	var err error
+	for result.Attempts < s.maxAttempts {
+		if result.Attempts > 0 {
+			time.Sleep(interval)
+			interval *= 2
+		}
+		result.Attempts++
+		result.StatusCode, err = s.post(url, contentType, payload, checkBody)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return result, err
+}
+
+// webHookResponseBodyLimit is the max length of response body in the error message.
+const webHookResponseBodyLimit = 512
+
+func (s *webHookSender) post(url, contentType string, payload []byte, checkBody func(body []byte) error) (int, error) {
+	resp, err := s.client.Post(url, contentType, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("post web hook failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webHookResponseBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("post web hook failed, status code: %v, response: %s", resp.StatusCode, body)
+	}
+	if checkBody != nil {
+		if err := checkBody(body); err != nil {
+			return resp.StatusCode, fmt.Errorf("post web hook failed: %v, response: %s", err, body)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// saveNotifyRecord saves the delivery result, the failure of saving is only
+// logged, it should not break the notification.
+func saveNotifyRecord(auditPlan *model.AuditPlan, channel string, result *webHookResult, sendErr error) {
+	record := &model.AuditPlanNotifyRecord{
+		AuditPlanID: auditPlan.ID,
+		Channel:     channel,
+		Status:      model.NotifyRecordStatusSuccess,
+	}
+	if result != nil {
+		record.Attempts = result.Attempts
+		record.StatusCode = result.StatusCode
+	}
+	if sendErr != nil {
+		record.Status = model.NotifyRecordStatusFailed
+		record.ErrorMessage = sendErr.Error()
+	}
+	if err := model.GetStorage().Save(record); err != nil {
+		log.NewEntry().WithField("name", auditPlan.Name).Errorf("save audit plan notify record failed, error: %v", err)
+	}
+}
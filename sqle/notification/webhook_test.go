@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAuditPlanNotification() *AuditPlanNotification {
+	ap := &model.AuditPlan{Name: "test_ap", Type: "default"}
+	report := &model.AuditPlanReportV2{
+		Model:      model.Model{ID: 2},
+		Score:      60,
+		PassRate:   0.5,
+		AuditLevel: "error",
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{Number: 1, SQL: "select 1", AuditLevel: "normal"},
+			{Number: 2, SQL: "select * from t1", AuditLevel: "warn", AuditResult: "[warn]xxx", FindingStatus: model.FindingStatusNew},
+			{Number: 3, SQL: "delete from t1", AuditLevel: "error", AuditResult: "[error]\"yyy\"", FindingStatus: model.FindingStatusPersisting},
+		},
+	}
+	return NewAuditPlanNotification(ap, report)
+}
+
+func TestRenderWebHookPayload(t *testing.T) {
+	n := newTestAuditPlanNotification()
+	data := newWebHookData(n, n.auditPlan)
+	assert.Len(t, data.TopFindings, 2)
+	assert.Equal(t, uint(3), data.TopFindings[0].Number)
+	assert.Equal(t, uint(2), data.TopFindings[1].Number)
+
+	// default template is a JSON
+	payload, err := renderWebHookPayload("", data)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(payload, &result))
+	assert.Equal(t, "test_ap", result["audit_plan_name"])
+	assert.Equal(t, float64(60), result["score"])
+	assert.Equal(t, "error", result["audit_level"])
+	assert.Len(t, result["top_findings"], 2)
+
+	payload, err = renderWebHookPayload(`{"text": {{ json (index .TopFindings 0).AuditResult }}, "name": "{{ .AuditPlanName }}"}`, data)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text": "[error]\"yyy\"", "name": "test_ap"}`, string(payload))
+
+	// the test notification has no report data
+	payload, err = renderWebHookPayload("", newWebHookData(&TestNotify{}, n.auditPlan))
+	assert.NoError(t, err)
+	assert.True(t, json.Valid(payload))
+
+	_, err = renderWebHookPayload("{{ .Unknown }}", data)
+	assert.Error(t, err)
+	assert.Error(t, ValidateWebHookTemplate("{{ .Subject "))
+	assert.NoError(t, ValidateWebHookTemplate(""))
+}
+
+func TestWebHookSenderPost(t *testing.T) {
+	requestCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		if requestCount < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := &webHookSender{client: ts.Client(), maxAttempts: 3}
+	result, err := sender.Post(ts.URL, "application/json", []byte("payload"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Attempts)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	// all attempts are failed
+	requestCount = -10
+	result, err = sender.Post(ts.URL, "application/json", []byte("payload"), nil)
+	assert.Error(t, err)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+}
+
+func TestAuditPlanNotifierSendWebHook(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	var received map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	n := newTestAuditPlanNotification()
+	n.auditPlan.ID = 1
+	n.auditPlan.EnableWebHookNotify = true
+	n.auditPlan.WebHookURL = ts.URL
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `audit_plan_notify_records`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 1, model.NotifyChannelWebHook, model.NotifyRecordStatusSuccess, 1, http.StatusOK, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = NewAuditPlanNotifier().Send(n, n.auditPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_ap", received["audit_plan_name"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,6 +9,7 @@ import (
 
 	hclog "github.com/hashicorp/go-hclog"
 	goPlugin "github.com/hashicorp/go-plugin"
+	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 // AuditAdaptor is a wrapper for the sqle driver layer. It
@@ -26,21 +27,43 @@ type AuditAdaptor struct {
 
 	additionalParams params.Params
 
+	rollbackFunc RollbackFunc
+
 	ao *adaptorOptions
 }
 
 type adaptorOptions struct {
-	sqlParser func(string) (interface{}, error)
+	sqlParser      func(string) (interface{}, error)
+	sqlSplitter    SQLSplitter
+	sqlClassifier  SQLClassifier
+	capabilities   driver.Capabilities
+	minSQLEVersion string
 }
 
+// The handlers get the session by SessionFromContext(ctx).
 type rawSQLRuleHandler func(ctx context.Context, rule *driver.Rule, rawSQL string) (string, error)
 type astSQLRuleHandler func(ctx context.Context, rule *driver.Rule, astSQL interface{}) (string, error)
 
+// RollbackFunc generates the rollback SQL of the SQL, the reason is returned
+// if it can't be rolled back.
+type RollbackFunc func(ctx context.Context, sql string) (rollbackSQL string, reason string, err error)
+
+// SQLSplitter splits the SQL script into statements.
+type SQLSplitter func(sql string) ([]string, error)
+
+// SQLClassifier returns the type of the SQL, driver.SQLTypeDML or driver.SQLTypeDDL.
+type SQLClassifier func(sql string) (sqlType string)
+
 // NewAdaptor create a database plugin AuditAdaptor with dialector.
 // NewAdaptor is actually NewAuditAdaptor, but the method name cannot be changed for historical reasons
 func NewAdaptor(dt Dialector) *AuditAdaptor {
 	return &AuditAdaptor{
-		ao: &adaptorOptions{},
+		ao: &adaptorOptions{
+			sqlSplitter:   sqlparser.SplitStatementToPieces,
+			sqlClassifier: ClassifySQL,
+			// the adaptor audits SQL without DSN, but doesn't generate rollback SQL.
+			capabilities: driver.Capabilities{OfflineAudit: true},
+		},
 
 		dt: dt,
 		l: hclog.New(&hclog.LoggerOptions{
@@ -68,6 +91,12 @@ func (a *AuditAdaptor) AddRuleWithSQLParser(r *driver.Rule, h astSQLRuleHandler)
 	a.ruleToASTHandler[r.Name] = h
 }
 
+// AddRollbackFunc registers the handler to generate rollback SQL, the plugin
+// declares the rollback capability with it.
+func (a *AuditAdaptor) AddRollbackFunc(f RollbackFunc) {
+	a.rollbackFunc = f
+}
+
 func (a *AuditAdaptor) Serve(opts ...AdaptorOption) {
 	plugin := a.GeneratePlugin(opts...)
 	a.l.Info("start serve plugin", "name", a.dt)
@@ -95,10 +124,16 @@ func (a *AuditAdaptor) GeneratePlugin(opts ...AdaptorOption) goPlugin.Plugin {
 		panic("Add rule by AddRuleWithSQLParser(), but no SQL parser provided.")
 	}
 
+	if a.rollbackFunc != nil {
+		a.ao.capabilities.Rollback = true
+	}
+
 	r := &auditRegistererImpl{
 		dt:               a.dt,
 		rules:            a.rules,
 		additionalParams: a.additionalParams,
+		capabilities:     a.ao.capabilities,
+		minSQLEVersion:   a.ao.minSQLEVersion,
 	}
 
 	newDriver := func(cfg *driver.Config) driver.Driver {
@@ -111,6 +146,7 @@ func (a *AuditAdaptor) GeneratePlugin(opts ...AdaptorOption) goPlugin.Plugin {
 		di := &pluginImpl{auditAdaptor: a}
 
 		if cfg.DSN == nil {
+			di.session = newSession(nil, nil)
 			pluginImpls[driver.PluginNameAuditDriver] = di
 			return di
 		}
@@ -119,6 +155,7 @@ func (a *AuditAdaptor) GeneratePlugin(opts ...AdaptorOption) goPlugin.Plugin {
 		db, conn := getDbConn(driverName, dsnDetail)
 		di.db = db
 		di.conn = conn
+		di.session = newSession(db, conn)
 		pluginImpls[driver.PluginNameAuditDriver] = di
 		return di
 	}
@@ -154,13 +191,51 @@ func WithSQLParser(parser func(sql string) (ast interface{}, err error)) Adaptor
 	})
 }
 
+// WithSQLSplitter defines custom SQL splitter. If set, the adaptor uses it
+// instead of the MySQL-like splitter to split the SQL script, such as the
+// splitter which keeps the semicolons in the stored procedure.
+func WithSQLSplitter(splitter SQLSplitter) AdaptorOption {
+	return newOptionFunc(func(a *adaptorOptions) {
+		a.sqlSplitter = splitter
+	})
+}
+
+// WithSQLClassifier defines custom SQL classifier. If set, the adaptor uses
+// it instead of ClassifySQL to decide the type of the SQL, SQLE executes the
+// SQL by its type.
+func WithSQLClassifier(classifier SQLClassifier) AdaptorOption {
+	return newOptionFunc(func(a *adaptorOptions) {
+		a.sqlClassifier = classifier
+	})
+}
+
+// WithCapabilities declares the capabilities of the plugin, SQLE enables the
+// features of the instance type based on it. The plugin is regarded as
+// supporting offline audit only by default.
+func WithCapabilities(c driver.Capabilities) AdaptorOption {
+	return newOptionFunc(func(a *adaptorOptions) {
+		a.capabilities = c
+	})
+}
+
+// WithMinSQLEVersion declares the minimum version of SQLE, such as "1.2209.0",
+// the plugin is refused by the older SQLE.
+func WithMinSQLEVersion(version string) AdaptorOption {
+	return newOptionFunc(func(a *adaptorOptions) {
+		a.minSQLEVersion = version
+	})
+}
+
 var _ driver.Driver = (*pluginImpl)(nil)
 var _ driver.Registerer = (*auditRegistererImpl)(nil)
+var _ driver.CapabilitiesRegisterer = (*auditRegistererImpl)(nil)
 
 type auditRegistererImpl struct {
 	dt               Dialector
 	rules            []*driver.Rule
 	additionalParams params.Params
+	capabilities     driver.Capabilities
+	minSQLEVersion   string
 }
 
 func (r *auditRegistererImpl) Name() string {
@@ -174,3 +249,11 @@ func (r *auditRegistererImpl) Rules() []*driver.Rule {
 func (r *auditRegistererImpl) AdditionalParams() params.Params {
 	return r.additionalParams
 }
+
+func (r *auditRegistererImpl) Capabilities() driver.Capabilities {
+	return r.capabilities
+}
+
+func (r *auditRegistererImpl) MinSQLEVersion() string {
+	return r.minSQLEVersion
+}
@@ -0,0 +1,109 @@
+// Package astrule is the shared plumbing of the plugins which check the rules
+// with the AST and keep the state of the audit session, such as Oracle and
+// SQL Server. It doesn't depend on the plugin adaptor, so the parser packages
+// of the plugins can be used by SQLE too.
+package astrule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// AuditContext keeps the state of the audit session for the plugin which
+// checks the rules with the AST, it's shared by the SQLs audited by the same
+// driver. The plugin embeds it to keep its own state.
+type AuditContext struct {
+	// Conn is nil if the SQL is audited offline.
+	Conn   *sql.Conn
+	Logger hclog.Logger
+
+	// createdTables are the tables created by the previous SQLs, which don't
+	// exist in the database before the SQLs are executed.
+	createdTables map[string]struct{}
+	// current is the AST being audited.
+	current interface{}
+	// update records the effect of the audited AST.
+	update func(ast interface{})
+}
+
+// NewAuditContext creates the audit context, update is called with the
+// previous AST when the next AST begins.
+func NewAuditContext(conn *sql.Conn, logger hclog.Logger, update func(ast interface{})) *AuditContext {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+	return &AuditContext{
+		Conn:          conn,
+		Logger:        logger,
+		createdTables: map[string]struct{}{},
+		update:        update,
+	}
+}
+
+// Begin is called by each rule before checking the AST, the effect of the
+// previous AST is recorded when the next AST begins, so all rules check the
+// AST with the same context.
+func (c *AuditContext) Begin(ast interface{}) {
+	if c.current == ast {
+		return
+	}
+	if c.current != nil && c.update != nil {
+		c.update(c.current)
+	}
+	c.current = ast
+}
+
+// Current returns the AST being audited, it's nil before Begin is called.
+func (c *AuditContext) Current() interface{} {
+	return c.current
+}
+
+// AddCreatedTable records the table created by the audited SQL, the name is
+// normalized by the plugin.
+func (c *AuditContext) AddCreatedTable(name string) {
+	c.createdTables[name] = struct{}{}
+}
+
+func (c *AuditContext) RemoveCreatedTable(name string) {
+	delete(c.createdTables, name)
+}
+
+func (c *AuditContext) IsCreatedTable(name string) bool {
+	_, ok := c.createdTables[name]
+	return ok
+}
+
+// AuditState is the audit context of the plugin, it embeds *AuditContext.
+type AuditState interface {
+	Begin(ast interface{})
+}
+
+// NewAuditStateFunc creates the audit context of the plugin for the session,
+// conn is nil if the SQL is audited offline.
+type NewAuditStateFunc func(conn *sql.Conn, logger hclog.Logger) AuditState
+
+// RuleFunc returns the arguments of the message if the AST breaks the rule,
+// and nil otherwise.
+type RuleFunc func(ctx context.Context, state AuditState, rule *driver.Rule, ast interface{}) ([]interface{}, error)
+
+type RuleHandler struct {
+	Rule driver.Rule
+	// Message is the audit result, it's formatted by the arguments returned by Func.
+	Message string
+	Func    RuleFunc
+}
+
+// Check returns the audit result of the AST, it's empty if the AST follows
+// the rule.
+func (h *RuleHandler) Check(ctx context.Context, state AuditState, rule *driver.Rule, ast interface{}) (string, error) {
+	args, err := h.Func(ctx, state, rule, ast)
+	if err != nil || args == nil {
+		return "", err
+	}
+	return fmt.Sprintf(h.Message, args...), nil
+}
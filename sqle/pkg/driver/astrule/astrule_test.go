@@ -0,0 +1,65 @@
+package astrule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditContext_Begin(t *testing.T) {
+	updated := []string{}
+	var c *AuditContext
+	c = NewAuditContext(nil, nil, func(ast interface{}) {
+		table := *ast.(*string)
+		updated = append(updated, table)
+		c.AddCreatedTable(table)
+	})
+	assert.Nil(t, c.Current())
+
+	t1, t2 := "t1", "t2"
+	c.Begin(&t1)
+	c.Begin(&t1)
+	assert.Equal(t, &t1, c.Current())
+	assert.Len(t, updated, 0)
+
+	// the effect of the previous AST is recorded when the next AST begins.
+	c.Begin(&t2)
+	assert.Equal(t, []string{"t1"}, updated)
+	assert.True(t, c.IsCreatedTable("t1"))
+	assert.False(t, c.IsCreatedTable("t2"))
+
+	c.RemoveCreatedTable("t1")
+	assert.False(t, c.IsCreatedTable("t1"))
+}
+
+func TestRuleHandler_Check(t *testing.T) {
+	h := &RuleHandler{
+		Rule:    driver.Rule{Name: "table_name"},
+		Message: "table %v is not allowed",
+		Func: func(ctx context.Context, state AuditState, rule *driver.Rule, ast interface{}) ([]interface{}, error) {
+			if ast == "t1" {
+				return []interface{}{ast}, nil
+			}
+			if ast == "t2" {
+				return nil, assert.AnError
+			}
+			return nil, nil
+		},
+	}
+	c := NewAuditContext(nil, nil, nil)
+
+	msg, err := h.Check(context.TODO(), c, &h.Rule, "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "table t1 is not allowed", msg)
+
+	msg, err = h.Check(context.TODO(), c, &h.Rule, "t2")
+	assert.Error(t, err)
+	assert.Equal(t, "", msg)
+
+	msg, err = h.Check(context.TODO(), c, &h.Rule, "t3")
+	assert.NoError(t, err)
+	assert.Equal(t, "", msg)
+}
@@ -0,0 +1,46 @@
+package driver
+
+import (
+	"context"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+type auditStateKey struct{}
+
+// getAuditState returns the audit context kept in the session, it's created
+// on the first call of the session.
+func getAuditState(ctx context.Context, newState astrule.NewAuditStateFunc, logger hclog.Logger) astrule.AuditState {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return newState(nil, logger)
+	}
+	if v, ok := session.Get(auditStateKey{}); ok {
+		return v.(astrule.AuditState)
+	}
+	var state astrule.AuditState
+	if conf, err := session.DbConf(); err == nil {
+		state = newState(conf.Conn, logger)
+	} else {
+		state = newState(nil, logger)
+	}
+	session.Set(auditStateKey{}, state)
+	return state
+}
+
+// AddRuleHandlers adds the rules which are checked with the AST and the audit
+// context, the SQL parser must be provided by WithSQLParser.
+func (a *AuditAdaptor) AddRuleHandlers(handlers []astrule.RuleHandler, newState astrule.NewAuditStateFunc) {
+	for i := range handlers {
+		h := handlers[i]
+		rule := h.Rule
+		a.AddRuleWithSQLParser(&rule, func(ctx context.Context, rule *driver.Rule, ast interface{}) (string, error) {
+			state := getAuditState(ctx, newState, a.l)
+			state.Begin(ast)
+			return h.Check(ctx, state, rule, ast)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAuditState is the audit context of which the AST is the name of the
+// created table.
+type mockAuditState struct {
+	*astrule.AuditContext
+}
+
+func newMockAuditState(conn *sql.Conn, logger hclog.Logger) astrule.AuditState {
+	s := &mockAuditState{}
+	s.AuditContext = astrule.NewAuditContext(conn, logger, func(ast interface{}) {
+		s.AddCreatedTable(*ast.(*string))
+	})
+	return s
+}
+
+func TestAuditAdaptor_AddRuleHandlers(t *testing.T) {
+	a := NewAdaptor(&PostgresDialector{})
+	a.AddRuleHandlers([]astrule.RuleHandler{
+		{
+			Rule:    driver.Rule{Name: "created_table", Level: driver.RuleLevelWarn},
+			Message: "table %v is created by the previous SQL",
+			Func: func(ctx context.Context, state astrule.AuditState, rule *driver.Rule, ast interface{}) ([]interface{}, error) {
+				table := *ast.(*string)
+				if state.(*mockAuditState).IsCreatedTable(table) {
+					return []interface{}{table}, nil
+				}
+				return nil, nil
+			},
+		},
+	}, newMockAuditState)
+	assert.Len(t, a.rules, 1)
+	// the AST is a new pointer for each audit like the real parser.
+	a.ao.sqlParser = func(sql string) (interface{}, error) {
+		return &sql, nil
+	}
+	a.cfg = &driver.Config{Rules: a.rules}
+	p := &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+
+	result, err := p.Audit(context.TODO(), "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", result.Message())
+
+	// the audit context is kept in the session.
+	result, err = p.Audit(context.TODO(), "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "[warn]table t1 is created by the previous SQL", result.Message())
+
+	// the audit context isn't shared by the other session.
+	p = &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+	result, err = p.Audit(context.TODO(), "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", result.Message())
+}
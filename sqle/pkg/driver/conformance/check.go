@@ -0,0 +1,517 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/proto"
+
+	hclog "github.com/hashicorp/go-hclog"
+	goPlugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lockedBuffer keeps the stderr of plugin process, which is written in the
+// goroutine of go-plugin.
+type lockedBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+// crashOutput returns the stderr from the panic, or the last lines of stderr
+// if there is no panic.
+func (b *lockedBuffer) crashOutput() string {
+	b.mutex.Lock()
+	output := b.buf.String()
+	b.mutex.Unlock()
+
+	if i := strings.Index(output, "panic:"); i >= 0 {
+		output = output[i:]
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > 20 {
+		lines = lines[:20]
+	}
+	return strings.Join(lines, "\n")
+}
+
+type checker struct {
+	spec   *Spec
+	report *Report
+	stderr *lockedBuffer
+
+	client   *goPlugin.Client
+	protocol goPlugin.ClientProtocol
+	// exited is set if the plugin process exits, the left checks are skipped.
+	exited bool
+
+	metas *proto.MetasResponse
+}
+
+// Check launches the plugin binary like SQLE does, and checks the RPCs of the
+// plugin against the spec. The nil spec means only the checks without
+// expectations and database are run.
+func Check(binary string, spec *Spec) *Report {
+	if spec == nil {
+		spec = &Spec{}
+	}
+	c := &checker{
+		spec:   spec,
+		report: &Report{Binary: binary},
+		stderr: &lockedBuffer{},
+	}
+
+	cfg := driver.NewPluginClientConfig(binary)
+	cfg.Stderr = c.stderr
+	cfg.StartTimeout = spec.rpcTimeout()
+	cfg.Logger = hclog.New(&hclog.LoggerOptions{
+		Output: ioutil.Discard,
+		Level:  hclog.Error,
+	})
+	c.client = goPlugin.NewClient(cfg)
+	defer c.client.Kill()
+
+	protocol, err := c.client.Client()
+	if err != nil {
+		c.report.add("Handshake", StatusFailed, fmt.Sprintf("start plugin failed: %v\n%v", err, c.stderr.crashOutput()))
+		return c.report
+	}
+	c.report.add("Handshake", StatusPassed)
+	c.protocol = protocol
+
+	c.checkAuditDriver()
+	c.checkQueryDriver()
+	c.checkAnalysisDriver()
+	return c.report
+}
+
+// call calls the RPC with timeout, the error is described for the report.
+func (c *checker) call(f func(ctx context.Context) error) error {
+	timeout := c.spec.rpcTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := f(ctx)
+	if err == nil {
+		return nil
+	}
+	code := status.Code(err)
+	if code == codes.DeadlineExceeded {
+		return fmt.Errorf("timeout after %v", timeout)
+	}
+	if code == codes.Unavailable || code == codes.Internal || code == codes.Canceled {
+		// the process exits asynchronously after the connection is closed.
+		for i := 0; i < 20 && !c.client.Exited(); i++ {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if c.client.Exited() {
+			c.exited = true
+			return fmt.Errorf("plugin process exited, it may panic: %v\n%v", err, c.stderr.crashOutput())
+		}
+	}
+	return err
+}
+
+// result adds the result of check, it fails if there are violations.
+func (c *checker) result(check string, violations []string) {
+	if len(violations) > 0 {
+		c.report.add(check, StatusFailed, violations...)
+		return
+	}
+	c.report.add(check, StatusPassed)
+}
+
+func (c *checker) fail(check string, err error) {
+	c.report.add(check, StatusFailed, err.Error())
+}
+
+func (c *checker) skip(check, reason string) {
+	c.report.add(check, StatusSkipped, reason)
+}
+
+// skipExited skips the check if the plugin process exited.
+func (c *checker) skipExited(check string) bool {
+	if c.exited {
+		c.skip(check, "plugin process exited")
+	}
+	return c.exited
+}
+
+func (c *checker) protoDSN() *proto.DSN {
+	if c.spec.DSN == nil {
+		return nil
+	}
+	return &proto.DSN{
+		Host:     c.spec.DSN.Host,
+		Port:     c.spec.DSN.Port,
+		User:     c.spec.DSN.User,
+		Password: c.spec.DSN.Password,
+		Database: c.spec.DSN.Database,
+	}
+}
+
+func (c *checker) checkAuditDriver() {
+	raw, err := c.protocol.Dispense(driver.PluginNameAuditDriver)
+	if err != nil {
+		c.fail("Metas", fmt.Errorf("dispense audit driver failed: %v", err))
+		return
+	}
+	//nolint:forcetypeassert
+	client := raw.(proto.DriverClient)
+
+	err = c.call(func(ctx context.Context) (err error) {
+		c.metas, err = client.Metas(ctx, &proto.Empty{})
+		return err
+	})
+	if err != nil {
+		c.fail("Metas", err)
+		return
+	}
+	c.result("Metas", checkMetas(c.metas, c.spec.PluginName))
+
+	capabilities := c.metas.GetCapabilities()
+	offline := capabilities == nil || capabilities.GetOfflineAudit()
+	online := c.spec.DSN != nil
+	if !offline && !online {
+		c.skip("Audit", "offline audit is not declared and dsn is not provided")
+		return
+	}
+
+	// audit the cases in the session with DSN if it's provided.
+	initRequest := &proto.InitRequest{Dsn: c.protoDSN(), Rules: c.metas.GetRules()}
+	c.checkAuditCases(client, initRequest)
+
+	if capabilities != nil && !capabilities.GetRollback() {
+		c.skip("GenRollbackSQL", "rollback is not declared")
+	} else if !online {
+		c.skip("GenRollbackSQL", "dsn is not provided")
+	} else {
+		c.checkGenRollbackSQL(client, initRequest)
+	}
+}
+
+func (c *checker) initAudit(check string, client proto.DriverClient, req *proto.InitRequest) bool {
+	if c.skipExited(check) {
+		return false
+	}
+	err := c.call(func(ctx context.Context) error {
+		_, err := client.Init(ctx, req)
+		return err
+	})
+	if err != nil {
+		c.fail(check, fmt.Errorf("init audit driver failed: %v", err))
+		return false
+	}
+	return true
+}
+
+// closeAudit closes the driver, the plugin before version 1 panics on Close.
+func (c *checker) closeAudit(client proto.DriverClient) error {
+	if c.exited || c.metas.GetVersion() < driver.DefaultPluginVersion {
+		return nil
+	}
+	err := c.call(func(ctx context.Context) error {
+		_, err := client.Close(ctx, &proto.Empty{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("close audit driver failed: %v", err)
+	}
+	return nil
+}
+
+func (c *checker) checkAuditCases(client proto.DriverClient, initRequest *proto.InitRequest) {
+	if len(c.spec.Cases) == 0 {
+		c.skip("Audit", "no case in spec")
+		return
+	}
+	if !c.initAudit("Init", client, initRequest) {
+		return
+	}
+	c.report.add("Init", StatusPassed)
+
+	results := make([]*proto.AuditResponse, len(c.spec.Cases))
+	for i, cs := range c.spec.Cases {
+		parseCheck := fmt.Sprintf("Parse #%v", i+1)
+		if c.skipExited(parseCheck) {
+			return
+		}
+		var parseResp *proto.ParseResponse
+		err := c.call(func(ctx context.Context) (err error) {
+			parseResp, err = client.Parse(ctx, &proto.ParseRequest{SqlText: cs.SQL})
+			return err
+		})
+		if err != nil {
+			c.fail(parseCheck, err)
+		} else {
+			c.result(parseCheck, checkParse(parseResp, cs))
+		}
+
+		auditCheck := fmt.Sprintf("Audit #%v", i+1)
+		if c.skipExited(auditCheck) {
+			return
+		}
+		err = c.call(func(ctx context.Context) (err error) {
+			results[i], err = client.Audit(ctx, &proto.AuditRequest{Sql: cs.SQL})
+			return err
+		})
+		if err != nil {
+			c.fail(auditCheck, err)
+			continue
+		}
+		c.result(auditCheck, checkAudit(results[i], cs))
+	}
+	if c.skipExited("Close") {
+		return
+	}
+	if c.metas.GetVersion() < driver.DefaultPluginVersion {
+		c.skip("Close", "the plugin before version 1 doesn't support Close")
+	} else if err := c.closeAudit(client); err != nil {
+		c.fail("Close", err)
+	} else {
+		c.report.add("Close", StatusPassed)
+	}
+
+	// the batch audit is checked in a new session, its results should be the
+	// same as auditing one by one.
+	if !c.initAudit("AuditBatch", client, initRequest) {
+		return
+	}
+	defer func() {
+		if err := c.closeAudit(client); err != nil {
+			c.fail("AuditBatch", err)
+		}
+	}()
+	sqls := make([]string, 0, len(c.spec.Cases))
+	for _, cs := range c.spec.Cases {
+		sqls = append(sqls, cs.SQL)
+	}
+	var batchResp *proto.AuditBatchResponse
+	err := c.call(func(ctx context.Context) (err error) {
+		batchResp, err = client.AuditBatch(ctx, &proto.AuditBatchRequest{Sqls: sqls})
+		return err
+	})
+	if status.Code(err) == codes.Unimplemented {
+		c.skip("AuditBatch", "AuditBatch is not implemented, the plugin is built with the old SDK")
+		return
+	}
+	if err != nil {
+		c.fail("AuditBatch", err)
+		return
+	}
+	c.result("AuditBatch", checkAuditBatch(batchResp, results))
+}
+
+func (c *checker) checkGenRollbackSQL(client proto.DriverClient, initRequest *proto.InitRequest) {
+	if len(c.spec.Cases) == 0 {
+		c.skip("GenRollbackSQL", "no case in spec")
+		return
+	}
+	if !c.initAudit("GenRollbackSQL", client, initRequest) {
+		return
+	}
+	defer func() {
+		if err := c.closeAudit(client); err != nil {
+			c.fail("GenRollbackSQL", err)
+		}
+	}()
+	for i, cs := range c.spec.Cases {
+		check := fmt.Sprintf("GenRollbackSQL #%v", i+1)
+		if c.skipExited(check) {
+			return
+		}
+		var resp *proto.GenRollbackSQLResponse
+		err := c.call(func(ctx context.Context) (err error) {
+			resp, err = client.GenRollbackSQL(ctx, &proto.GenRollbackSQLRequest{Sql: cs.SQL})
+			return err
+		})
+		if err != nil {
+			c.fail(check, err)
+			continue
+		}
+		var violations []string
+		if cs.RollbackSQL != "" && strings.TrimSpace(resp.GetSql()) != strings.TrimSpace(cs.RollbackSQL) {
+			violations = append(violations, fmt.Sprintf("expect rollback SQL %q, got %q", cs.RollbackSQL, resp.GetSql()))
+		}
+		c.result(check, violations)
+	}
+}
+
+// dispense dispenses the optional driver, it returns false if the driver is
+// not served or the plugin exited.
+func (c *checker) dispense(check, pluginName string, declared bool) (interface{}, bool) {
+	if c.skipExited(check) {
+		return nil, false
+	}
+	capabilities := c.metas.GetCapabilities()
+	if capabilities != nil && !declared {
+		c.skip(check, "it is not declared in capabilities")
+		return nil, false
+	}
+	raw, err := c.protocol.Dispense(pluginName)
+	if err != nil {
+		if capabilities != nil {
+			c.fail(check, fmt.Errorf("it is declared in capabilities, but dispense failed: %v", err))
+		} else {
+			c.skip(check, fmt.Sprintf("it is not served: %v", err))
+		}
+		return nil, false
+	}
+	if c.spec.DSN == nil {
+		c.skip(check, "dsn is not provided")
+		return nil, false
+	}
+	return raw, true
+}
+
+func (c *checker) checkQueryDriver() {
+	if c.metas == nil {
+		return
+	}
+	raw, ok := c.dispense("QueryDriver", driver.PluginNameQueryDriver, c.metas.GetCapabilities().GetQuery())
+	if !ok {
+		return
+	}
+	//nolint:forcetypeassert
+	client := raw.(proto.QueryDriverClient)
+	if c.spec.Query == nil || c.spec.Query.SQL == "" {
+		c.skip("QueryDriver", "no query case in spec")
+		return
+	}
+	err := c.call(func(ctx context.Context) error {
+		_, err := client.Init(ctx, &proto.InitRequest{Dsn: c.protoDSN()})
+		return err
+	})
+	if err != nil {
+		c.fail("QueryDriver", fmt.Errorf("init query driver failed: %v", err))
+		return
+	}
+
+	var prepareResp *proto.QueryPrepareResponse
+	err = c.call(func(ctx context.Context) (err error) {
+		prepareResp, err = client.QueryPrepare(ctx, &proto.QueryPrepareRequest{
+			Sql:  c.spec.Query.SQL,
+			Conf: &proto.QueryPrepareConf{Limit: 10},
+		})
+		return err
+	})
+	if err != nil {
+		c.fail("QueryPrepare", err)
+		return
+	}
+	violations := checkQueryPrepare(prepareResp)
+	c.result("QueryPrepare", violations)
+	if len(violations) > 0 || c.skipExited("Query") {
+		return
+	}
+
+	var queryResp *proto.QueryResponse
+	err = c.call(func(ctx context.Context) (err error) {
+		queryResp, err = client.Query(ctx, &proto.QueryRequest{
+			Sql:  prepareResp.GetNewSql(),
+			Conf: &proto.QueryConf{TimeOutSecond: uint32(c.spec.rpcTimeout().Seconds())},
+		})
+		return err
+	})
+	if err != nil {
+		c.fail("Query", err)
+		return
+	}
+	c.result("Query", checkQuery(queryResp))
+}
+
+func (c *checker) checkAnalysisDriver() {
+	if c.metas == nil {
+		return
+	}
+	raw, ok := c.dispense("AnalysisDriver", driver.PluginNameAnalysisDriver, c.metas.GetCapabilities().GetAnalysis())
+	if !ok {
+		return
+	}
+	//nolint:forcetypeassert
+	client := raw.(proto.AnalysisDriverClient)
+	spec := c.spec.Analysis
+	if spec == nil || spec.Schema == "" {
+		c.skip("AnalysisDriver", "no analysis case in spec")
+		return
+	}
+	err := c.call(func(ctx context.Context) error {
+		_, err := client.Init(ctx, &proto.AnalysisDriverInitRequest{Dsn: c.protoDSN()})
+		return err
+	})
+	if err != nil {
+		c.fail("AnalysisDriver", fmt.Errorf("init analysis driver failed: %v", err))
+		return
+	}
+
+	var tablesResp *proto.ListTablesInSchemaResponse
+	err = c.call(func(ctx context.Context) (err error) {
+		tablesResp, err = client.ListTablesInSchema(ctx, &proto.ListTablesInSchemaRequest{Schema: spec.Schema})
+		return err
+	})
+	if err != nil {
+		c.fail("ListTablesInSchema", err)
+	} else {
+		c.result("ListTablesInSchema", checkListTables(tablesResp, spec.Table))
+	}
+
+	if spec.Table == "" {
+		c.skip("GetTableMetaByTableName", "no table in spec")
+	} else if !c.skipExited("GetTableMetaByTableName") {
+		var metaResp *proto.GetTableMetaByTableNameResponse
+		err = c.call(func(ctx context.Context) (err error) {
+			metaResp, err = client.GetTableMetaByTableName(ctx, &proto.GetTableMetaByTableNameRequest{
+				Schema: spec.Schema,
+				Table:  spec.Table,
+			})
+			return err
+		})
+		if err != nil {
+			c.fail("GetTableMetaByTableName", err)
+		} else {
+			c.result("GetTableMetaByTableName", checkTableMeta(metaResp.GetTableMeta(), spec.Table))
+		}
+	}
+
+	if spec.ExplainSQL == "" {
+		c.skip("Explain", "no explain SQL in spec")
+		return
+	}
+	if !c.skipExited("GetTableMetaBySQL") {
+		var metaResp *proto.GetTableMetaBySQLResponse
+		err = c.call(func(ctx context.Context) (err error) {
+			metaResp, err = client.GetTableMetaBySQL(ctx, &proto.GetTableMetaBySQLRequest{Sql: spec.ExplainSQL})
+			return err
+		})
+		if err != nil {
+			c.fail("GetTableMetaBySQL", err)
+		} else {
+			c.result("GetTableMetaBySQL", checkTableMetasBySQL(metaResp))
+		}
+	}
+	if !c.skipExited("Explain") {
+		var explainResp *proto.ExplainResponse
+		err = c.call(func(ctx context.Context) (err error) {
+			explainResp, err = client.Explain(ctx, &proto.ExplainRequest{Sql: spec.ExplainSQL})
+			return err
+		})
+		if err != nil {
+			c.fail("Explain", err)
+		} else {
+			c.result("Explain", checkExplain(explainResp))
+		}
+	}
+}
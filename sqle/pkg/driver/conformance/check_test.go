@@ -0,0 +1,84 @@
+package conformance
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildTestPlugin builds the plugin in testdata, which is served by the driver
+// adaptor.
+func buildTestPlugin(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "conformance")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	binary := filepath.Join(dir, "plugin")
+	cmd := exec.Command("go", "build", "-o", binary, "./testdata/plugin")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build test plugin failed: %v\n%s", err, output)
+	}
+	return binary
+}
+
+func findResult(report *Report, check string) *Result {
+	for _, result := range report.Results {
+		if result.Check == check {
+			return result
+		}
+	}
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	binary := buildTestPlugin(t)
+
+	report := Check(binary, &Spec{
+		PluginName: "PostgreSQL",
+		Cases: []*Case{
+			{SQL: "select * from t1", SQLType: "dml", AuditLevel: "error"},
+			{SQL: "select id from t1", SQLType: "dml", AuditLevel: "normal"},
+		},
+	})
+	assert.True(t, report.Passed())
+	for _, check := range []string{"Handshake", "Metas", "Init", "Parse #1", "Audit #1", "Audit #2", "Close", "AuditBatch"} {
+		result := findResult(report, check)
+		if assert.NotNil(t, result, check) {
+			assert.Equal(t, StatusPassed, result.Status, check)
+		}
+	}
+	// the checks which require the database are skipped.
+	assert.Equal(t, StatusSkipped, findResult(report, "GenRollbackSQL").Status)
+
+	// the audit result doesn't meet the spec.
+	report = Check(binary, &Spec{
+		Cases: []*Case{{SQL: "select * from t1", AuditLevel: "warn"}},
+	})
+	assert.False(t, report.Passed())
+	assert.Equal(t, StatusFailed, findResult(report, "Audit #1").Status)
+	assert.Equal(t, []string{`expect audit level "warn", got "error"`}, findResult(report, "Audit #1").Messages)
+
+	// the plugin panics, the left checks are skipped.
+	report = Check(binary, &Spec{
+		Cases: []*Case{{SQL: "select 'panic'"}, {SQL: "select 1"}},
+	})
+	assert.False(t, report.Passed())
+	result := findResult(report, "Audit #1")
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.True(t, strings.Contains(result.Messages[0], "plugin process exited"), result.Messages[0])
+	assert.True(t, strings.Contains(result.Messages[0], "audit panic"), result.Messages[0])
+	assert.Equal(t, StatusSkipped, findResult(report, "Parse #2").Status)
+
+	// the plugin hangs.
+	report = Check(binary, &Spec{
+		RPCTimeout: time.Second,
+		Cases:      []*Case{{SQL: "select 'sleep'"}},
+	})
+	assert.False(t, report.Passed())
+	assert.Equal(t, []string{"timeout after 1s"}, findResult(report, "Audit #1").Messages)
+}
@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Status string
+
+const (
+	StatusPassed  Status = "PASS"
+	StatusFailed  Status = "FAIL"
+	StatusSkipped Status = "SKIP"
+)
+
+// Result is the result of one check, e.g. "Metas" or "Audit #1".
+type Result struct {
+	Check  string
+	Status Status
+	// Messages are the protocol violations of the failed check, or the reason
+	// of the skipped check.
+	Messages []string
+}
+
+type Report struct {
+	Binary  string
+	Results []*Result
+}
+
+func (r *Report) add(check string, status Status, messages ...string) {
+	r.Results = append(r.Results, &Result{
+		Check:    check,
+		Status:   status,
+		Messages: messages,
+	})
+}
+
+// Passed returns true if no check fails.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFailed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) count(status Status) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Print writes the report in text.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "checking plugin %v\n", r.Binary)
+	for _, result := range r.Results {
+		fmt.Fprintf(w, "%v\t%v\n", result.Status, result.Check)
+		for _, message := range result.Messages {
+			fmt.Fprintf(w, "\t- %v\n", strings.ReplaceAll(message, "\n", "\n\t  "))
+		}
+	}
+	fmt.Fprintf(w, "%v passed, %v failed, %v skipped\n",
+		r.count(StatusPassed), r.count(StatusFailed), r.count(StatusSkipped))
+}
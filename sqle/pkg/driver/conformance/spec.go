@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const DefaultRPCTimeout = 10 * time.Second
+
+// Spec is the expectations of the plugin. The checks which require a database
+// are skipped if the DSN is not provided.
+type Spec struct {
+	// PluginName is the expected name returned by Metas, empty means not checked.
+	PluginName string `yaml:"plugin_name"`
+	// RPCTimeout is the max time of each RPC call.
+	RPCTimeout time.Duration `yaml:"rpc_timeout"`
+	// DSN is the database which the plugin connects to, it must be a real
+	// database of the plugin's type, because the plugin connects to it in its
+	// own process. The online audit, rollback, query and analysis are checked
+	// with it.
+	DSN *DSN `yaml:"dsn"`
+
+	// Cases are parsed, audited and rolled back in order in the same context.
+	Cases    []*Case       `yaml:"cases"`
+	Query    *QueryCase    `yaml:"query"`
+	Analysis *AnalysisCase `yaml:"analysis"`
+}
+
+type DSN struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+}
+
+type Case struct {
+	SQL string `yaml:"sql"`
+	// SQLType is the expected type returned by Parse, such as "dml" and "ddl".
+	SQLType string `yaml:"sql_type"`
+	// AuditLevel is the expected highest level of the audit results.
+	AuditLevel string `yaml:"audit_level"`
+	// RollbackSQL is the expected rollback SQL, it's checked with DSN only.
+	RollbackSQL string `yaml:"rollback_sql"`
+}
+
+type QueryCase struct {
+	SQL string `yaml:"sql"`
+}
+
+type AnalysisCase struct {
+	Schema string `yaml:"schema"`
+	Table  string `yaml:"table"`
+	// ExplainSQL is explained if it's not empty.
+	ExplainSQL string `yaml:"explain_sql"`
+}
+
+// LoadSpec loads the spec from the YAML file.
+func LoadSpec(path string) (*Spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec file failed: %v", err)
+	}
+	spec := &Spec{}
+	if err := yaml.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("unmarshal spec file failed: %v", err)
+	}
+	return spec, nil
+}
+
+func (s *Spec) rpcTimeout() time.Duration {
+	if s.RPCTimeout <= 0 {
+		return DefaultRPCTimeout
+	}
+	return s.RPCTimeout
+}
@@ -0,0 +1,34 @@
+// The plugin is built by the test of conformance, it audits the SQLs offline.
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	adaptor "github.com/actiontech/sqle/sqle/pkg/driver"
+)
+
+func main() {
+	plugin := adaptor.NewAdaptor(&adaptor.PostgresDialector{})
+	rule := &driver.Rule{
+		Name:     "dml_disable_select_all_column",
+		Desc:     "禁止使用SELECT *",
+		Level:    driver.RuleLevelError,
+		Category: "DML规范",
+	}
+	plugin.AddRule(rule, func(ctx context.Context, rule *driver.Rule, sql string) (string, error) {
+		switch strings.ToLower(strings.TrimSpace(sql)) {
+		case "select 'panic'":
+			panic("audit panic")
+		case "select 'sleep'":
+			time.Sleep(time.Hour)
+		}
+		if strings.Contains(sql, "*") {
+			return rule.Desc, nil
+		}
+		return "", nil
+	})
+	plugin.Serve(adaptor.WithCapabilities(driver.Capabilities{OfflineAudit: true}))
+}
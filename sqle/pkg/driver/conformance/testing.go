@@ -0,0 +1,30 @@
+package conformance
+
+// TestingT is the subset of *testing.T used by Run.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// Run checks the plugin binary in the go test of the plugin, the failed checks
+// are reported as the test errors, e.g.
+//
+//	func TestConformance(t *testing.T) {
+//		spec, err := conformance.LoadSpec("testdata/spec.yaml")
+//		...
+//		conformance.Run(t, "./bin/plugin", spec)
+//	}
+func Run(t TestingT, binary string, spec *Spec) *Report {
+	t.Helper()
+	report := Check(binary, spec)
+	for _, result := range report.Results {
+		switch result.Status {
+		case StatusFailed:
+			t.Errorf("%v: %v", result.Check, result.Messages)
+		case StatusSkipped:
+			t.Logf("%v is skipped: %v", result.Check, result.Messages)
+		}
+	}
+	return report
+}
@@ -0,0 +1,239 @@
+package conformance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/proto"
+)
+
+// the validators return the protocol violations of the RPC responses.
+
+var ruleLevels = map[string]struct{}{
+	string(driver.RuleLevelNormal): {},
+	string(driver.RuleLevelNotice): {},
+	string(driver.RuleLevelWarn):   {},
+	string(driver.RuleLevelError):  {},
+}
+
+func checkMetas(metas *proto.MetasResponse, expectName string) []string {
+	violations := []string{}
+	if metas.GetName() == "" {
+		violations = append(violations, "plugin name is empty")
+	}
+	if expectName != "" && metas.GetName() != expectName {
+		violations = append(violations, fmt.Sprintf("expect plugin name %q, got %q", expectName, metas.GetName()))
+	}
+	if metas.GetVersion() < driver.DefaultPluginVersion {
+		violations = append(violations, fmt.Sprintf("plugin version %v is less than %v, the plugin is built with the old SDK",
+			metas.GetVersion(), driver.DefaultPluginVersion))
+	}
+	if v := metas.GetMinSQLEVersion(); v != "" && !driver.IsValidSQLEVersion(v) {
+		violations = append(violations, fmt.Sprintf("invalid minimum SQLE version %q", v))
+	}
+
+	ruleNames := map[string]struct{}{}
+	for _, rule := range metas.GetRules() {
+		if rule.GetName() == "" {
+			violations = append(violations, "rule name is empty")
+			continue
+		}
+		if _, ok := ruleNames[rule.GetName()]; ok {
+			violations = append(violations, fmt.Sprintf("rule %v is duplicated", rule.GetName()))
+		}
+		ruleNames[rule.GetName()] = struct{}{}
+		if rule.GetDesc() == "" {
+			violations = append(violations, fmt.Sprintf("desc of rule %v is empty", rule.GetName()))
+		}
+		if _, ok := ruleLevels[rule.GetLevel()]; !ok {
+			violations = append(violations, fmt.Sprintf("level %q of rule %v is invalid", rule.GetLevel(), rule.GetName()))
+		}
+	}
+	return violations
+}
+
+func checkParse(resp *proto.ParseResponse, cs *Case) []string {
+	violations := []string{}
+	nodes := resp.GetNodes()
+	if len(nodes) == 0 {
+		return append(violations, "no node is returned")
+	}
+	if len(nodes) > 1 {
+		violations = append(violations, fmt.Sprintf("the case is single SQL, but %v nodes are returned", len(nodes)))
+	}
+	for i, node := range nodes {
+		if strings.TrimSpace(node.GetText()) == "" {
+			violations = append(violations, fmt.Sprintf("text of node #%v is empty", i+1))
+		}
+		if node.GetFingerprint() == "" {
+			violations = append(violations, fmt.Sprintf("fingerprint of node #%v is empty, the fingerprint whitelist doesn't work", i+1))
+		}
+		// SQLE executes the SQL by its type, the other types can't be executed.
+		if node.GetType() != driver.SQLTypeDML && node.GetType() != driver.SQLTypeDDL {
+			violations = append(violations, fmt.Sprintf("type %q of node #%v is neither %v nor %v",
+				node.GetType(), i+1, driver.SQLTypeDML, driver.SQLTypeDDL))
+		}
+	}
+	if cs.SQLType != "" && nodes[0].GetType() != cs.SQLType {
+		violations = append(violations, fmt.Sprintf("expect SQL type %q, got %q", cs.SQLType, nodes[0].GetType()))
+	}
+	return violations
+}
+
+// auditLevel returns the highest level of the audit results, the SQL without
+// audit result is regarded as normal.
+func auditLevel(resp *proto.AuditResponse) string {
+	result := driver.NewInspectResults()
+	for _, r := range resp.GetResults() {
+		result.Add(driver.RuleLevel(r.GetLevel()), r.GetMessage())
+	}
+	if result.Level() == driver.RuleLevelNull {
+		return string(driver.RuleLevelNormal)
+	}
+	return string(result.Level())
+}
+
+func checkAudit(resp *proto.AuditResponse, cs *Case) []string {
+	violations := []string{}
+	for i, r := range resp.GetResults() {
+		if _, ok := ruleLevels[r.GetLevel()]; !ok {
+			violations = append(violations, fmt.Sprintf("level %q of result #%v is invalid", r.GetLevel(), i+1))
+		}
+		if r.GetMessage() == "" {
+			violations = append(violations, fmt.Sprintf("message of result #%v is empty", i+1))
+		}
+	}
+	if cs.AuditLevel != "" {
+		if level := auditLevel(resp); level != cs.AuditLevel {
+			violations = append(violations, fmt.Sprintf("expect audit level %q, got %q", cs.AuditLevel, level))
+		}
+	}
+	return violations
+}
+
+func formatAuditResults(resp *proto.AuditResponse) string {
+	results := []string{}
+	for _, r := range resp.GetResults() {
+		results = append(results, fmt.Sprintf("[%v]%v", r.GetLevel(), r.GetMessage()))
+	}
+	return strings.Join(results, " ")
+}
+
+// checkAuditBatch compares the batch results with the results of auditing one
+// by one, the nil expected result means the audit failed and is skipped.
+func checkAuditBatch(resp *proto.AuditBatchResponse, expects []*proto.AuditResponse) []string {
+	violations := []string{}
+	results := resp.GetResults()
+	if len(results) != len(expects) {
+		return append(violations, fmt.Sprintf("expect %v results, got %v", len(expects), len(results)))
+	}
+	for i, expect := range expects {
+		if expect == nil {
+			continue
+		}
+		if got, want := formatAuditResults(results[i]), formatAuditResults(expect); got != want {
+			violations = append(violations, fmt.Sprintf("result #%v is different from Audit, expect %q, got %q", i+1, want, got))
+		}
+	}
+	return violations
+}
+
+func checkQueryPrepare(resp *proto.QueryPrepareResponse) []string {
+	violations := []string{}
+	switch resp.GetErrorType() {
+	case driver.ErrorTypeNotError:
+		if resp.GetNewSql() == "" {
+			violations = append(violations, "new SQL is empty")
+		}
+	case driver.ErrorTypeNotQuery:
+		violations = append(violations, fmt.Sprintf("the query case is regarded as not query: %v", resp.GetError()))
+	default:
+		violations = append(violations, fmt.Sprintf("error type %q is neither %q nor %q",
+			resp.GetErrorType(), driver.ErrorTypeNotError, driver.ErrorTypeNotQuery))
+	}
+	return violations
+}
+
+func checkQuery(resp *proto.QueryResponse) []string {
+	violations := []string{}
+	if len(resp.GetColumn()) == 0 {
+		violations = append(violations, "no column is returned")
+	}
+	for i, row := range resp.GetRows() {
+		if len(row.GetValues()) != len(resp.GetColumn()) {
+			violations = append(violations, fmt.Sprintf("row #%v has %v values, but there are %v columns",
+				i+1, len(row.GetValues()), len(resp.GetColumn())))
+		}
+	}
+	return violations
+}
+
+func checkListTables(resp *proto.ListTablesInSchemaResponse, expectTable string) []string {
+	violations := []string{}
+	found := false
+	for i, table := range resp.GetTables() {
+		if table.GetName() == "" {
+			violations = append(violations, fmt.Sprintf("name of table #%v is empty", i+1))
+		}
+		if table.GetName() == expectTable {
+			found = true
+		}
+	}
+	if expectTable != "" && !found {
+		violations = append(violations, fmt.Sprintf("table %v is not listed", expectTable))
+	}
+	return violations
+}
+
+// checkTableFormat checks the table which is displayed on the analysis page.
+func checkTableFormat(name string, table *proto.AnalysisInfoInTableFormat) []string {
+	violations := []string{}
+	if table == nil {
+		return append(violations, fmt.Sprintf("%v is nil", name))
+	}
+	if len(table.GetColumns()) == 0 {
+		violations = append(violations, fmt.Sprintf("%v has no column", name))
+	}
+	for i, row := range table.GetRows() {
+		if len(row.GetItems()) != len(table.GetColumns()) {
+			violations = append(violations, fmt.Sprintf("row #%v of %v has %v items, but there are %v columns",
+				i+1, name, len(row.GetItems()), len(table.GetColumns())))
+		}
+	}
+	return violations
+}
+
+func checkTableMeta(meta *proto.TableItem, expectTable string) []string {
+	violations := []string{}
+	if meta == nil {
+		return append(violations, "table meta is nil")
+	}
+	if meta.GetName() != expectTable {
+		violations = append(violations, fmt.Sprintf("expect table %q, got %q", expectTable, meta.GetName()))
+	}
+	violations = append(violations, checkTableFormat("columns", meta.GetColumnsInfo().GetAnalysisInfoInTableFormat())...)
+	violations = append(violations, checkTableFormat("indexes", meta.GetIndexesInfo().GetAnalysisInfoInTableFormat())...)
+	return violations
+}
+
+func checkTableMetasBySQL(resp *proto.GetTableMetaBySQLResponse) []string {
+	violations := []string{}
+	if len(resp.GetTableMetas()) == 0 {
+		violations = append(violations, "no table meta is returned")
+	}
+	for _, meta := range resp.GetTableMetas() {
+		// the error message is displayed instead of the meta.
+		if meta.GetErrMessage() != "" {
+			continue
+		}
+		name := fmt.Sprintf("table %v", meta.GetName())
+		violations = append(violations, checkTableFormat(name+" columns", meta.GetColumnsInfo().GetAnalysisInfoInTableFormat())...)
+		violations = append(violations, checkTableFormat(name+" indexes", meta.GetIndexesInfo().GetAnalysisInfoInTableFormat())...)
+	}
+	return violations
+}
+
+func checkExplain(resp *proto.ExplainResponse) []string {
+	return checkTableFormat("explain result", resp.GetClassicResult().GetAnalysisInfoInTableFormat())
+}
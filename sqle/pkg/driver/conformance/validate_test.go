@@ -0,0 +1,181 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMetas(t *testing.T) {
+	metas := &proto.MetasResponse{
+		Name:           "Oracle",
+		Version:        1,
+		MinSQLEVersion: "1.2209.0",
+		Rules: []*proto.Rule{
+			{Name: "rule_1", Desc: "desc 1", Level: "error"},
+			{Name: "rule_2", Desc: "desc 2", Level: "notice"},
+		},
+	}
+	assert.Empty(t, checkMetas(metas, "Oracle"))
+	assert.Empty(t, checkMetas(metas, ""))
+	assert.Equal(t, []string{`expect plugin name "PostgreSQL", got "Oracle"`}, checkMetas(metas, "PostgreSQL"))
+
+	metas = &proto.MetasResponse{
+		Version:        1,
+		MinSQLEVersion: "main",
+		Rules: []*proto.Rule{
+			{Name: "rule_1", Desc: "desc 1", Level: "error"},
+			{Name: "rule_1", Desc: "", Level: "fatal"},
+			{Name: ""},
+		},
+	}
+	assert.Equal(t, []string{
+		"plugin name is empty",
+		`invalid minimum SQLE version "main"`,
+		"rule rule_1 is duplicated",
+		"desc of rule rule_1 is empty",
+		`level "fatal" of rule rule_1 is invalid`,
+		"rule name is empty",
+	}, checkMetas(metas, ""))
+}
+
+func TestCheckParse(t *testing.T) {
+	cs := &Case{SQL: "select 1", SQLType: "dml"}
+	resp := &proto.ParseResponse{
+		Nodes: []*proto.Node{{Text: "select 1", Type: "dml", Fingerprint: "select ?"}},
+	}
+	assert.Empty(t, checkParse(resp, cs))
+	assert.Empty(t, checkParse(resp, &Case{SQL: "select 1"}))
+	assert.Equal(t, []string{`expect SQL type "ddl", got "dml"`}, checkParse(resp, &Case{SQL: "select 1", SQLType: "ddl"}))
+
+	assert.Equal(t, []string{"no node is returned"}, checkParse(&proto.ParseResponse{}, cs))
+
+	resp = &proto.ParseResponse{
+		Nodes: []*proto.Node{{Text: " ", Type: "query"}, {Text: "select 2", Type: "dml", Fingerprint: "select ?"}},
+	}
+	assert.Equal(t, []string{
+		"the case is single SQL, but 2 nodes are returned",
+		"text of node #1 is empty",
+		"fingerprint of node #1 is empty, the fingerprint whitelist doesn't work",
+		`type "query" of node #1 is neither dml nor ddl`,
+		`expect SQL type "dml", got "query"`,
+	}, checkParse(resp, cs))
+}
+
+func TestCheckAudit(t *testing.T) {
+	resp := &proto.AuditResponse{
+		Results: []*proto.AuditResult{
+			{Level: "notice", Message: "message 1"},
+			{Level: "warn", Message: "message 2"},
+		},
+	}
+	assert.Empty(t, checkAudit(resp, &Case{}))
+	assert.Empty(t, checkAudit(resp, &Case{AuditLevel: "warn"}))
+	assert.Equal(t, []string{`expect audit level "error", got "warn"`}, checkAudit(resp, &Case{AuditLevel: "error"}))
+	assert.Empty(t, checkAudit(&proto.AuditResponse{}, &Case{AuditLevel: "normal"}))
+
+	resp = &proto.AuditResponse{
+		Results: []*proto.AuditResult{{Level: "fatal", Message: ""}},
+	}
+	assert.Equal(t, []string{
+		`level "fatal" of result #1 is invalid`,
+		"message of result #1 is empty",
+	}, checkAudit(resp, &Case{}))
+}
+
+func TestCheckAuditBatch(t *testing.T) {
+	expects := []*proto.AuditResponse{
+		{Results: []*proto.AuditResult{{Level: "warn", Message: "message 1"}}},
+		nil,
+		{},
+	}
+	resp := &proto.AuditBatchResponse{
+		Results: []*proto.AuditResponse{
+			{Results: []*proto.AuditResult{{Level: "warn", Message: "message 1"}}},
+			{Results: []*proto.AuditResult{{Level: "error", Message: "message 2"}}},
+			{},
+		},
+	}
+	assert.Empty(t, checkAuditBatch(resp, expects))
+
+	assert.Equal(t, []string{"expect 3 results, got 2"}, checkAuditBatch(&proto.AuditBatchResponse{
+		Results: resp.Results[:2],
+	}, expects))
+
+	resp.Results[2] = &proto.AuditResponse{Results: []*proto.AuditResult{{Level: "notice", Message: "message 3"}}}
+	assert.Equal(t, []string{
+		`result #3 is different from Audit, expect "", got "[notice]message 3"`,
+	}, checkAuditBatch(resp, expects))
+}
+
+func TestCheckQuery(t *testing.T) {
+	assert.Empty(t, checkQueryPrepare(&proto.QueryPrepareResponse{NewSql: "select 1 limit 10", ErrorType: "not error"}))
+	assert.Equal(t, []string{"new SQL is empty"}, checkQueryPrepare(&proto.QueryPrepareResponse{ErrorType: "not error"}))
+	assert.Equal(t, []string{"the query case is regarded as not query: not select"},
+		checkQueryPrepare(&proto.QueryPrepareResponse{ErrorType: "not query", Error: "not select"}))
+	assert.Equal(t, []string{`error type "" is neither "not error" nor "not query"`},
+		checkQueryPrepare(&proto.QueryPrepareResponse{}))
+
+	resp := &proto.QueryResponse{
+		Column: []*proto.Param{{Key: "a"}, {Key: "b"}},
+		Rows: []*proto.QueryResultRow{
+			{Values: []*proto.QueryResultValue{{Value: "1"}, {Value: "2"}}},
+			{Values: []*proto.QueryResultValue{{Value: "3"}}},
+		},
+	}
+	assert.Equal(t, []string{"row #2 has 1 values, but there are 2 columns"}, checkQuery(resp))
+	assert.Equal(t, []string{"no column is returned"}, checkQuery(&proto.QueryResponse{}))
+}
+
+func newTableFormat(columns int, items ...int) *proto.AnalysisInfoInTableFormat {
+	table := &proto.AnalysisInfoInTableFormat{}
+	for i := 0; i < columns; i++ {
+		table.Columns = append(table.Columns, &proto.AnalysisInfoHead{Name: "c"})
+	}
+	for _, n := range items {
+		row := &proto.Row{}
+		for i := 0; i < n; i++ {
+			row.Items = append(row.Items, "v")
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func TestCheckAnalysis(t *testing.T) {
+	tables := &proto.ListTablesInSchemaResponse{Tables: []*proto.Table{{Name: "t1"}, {Name: ""}}}
+	assert.Equal(t, []string{"name of table #2 is empty"}, checkListTables(tables, "t1"))
+	assert.Equal(t, []string{"name of table #2 is empty", "table t2 is not listed"}, checkListTables(tables, "t2"))
+
+	meta := &proto.TableItem{
+		Name:        "t1",
+		ColumnsInfo: &proto.ColumnsInfo{AnalysisInfoInTableFormat: newTableFormat(2, 2, 2)},
+		IndexesInfo: &proto.IndexesInfo{AnalysisInfoInTableFormat: newTableFormat(3, 3, 2)},
+	}
+	assert.Equal(t, []string{"row #2 of indexes has 2 items, but there are 3 columns"}, checkTableMeta(meta, "t1"))
+	assert.Equal(t, []string{"table meta is nil"}, checkTableMeta(nil, "t1"))
+
+	resp := &proto.GetTableMetaBySQLResponse{
+		TableMetas: []*proto.TableMetaItemBySQL{
+			{Name: "t1", ColumnsInfo: meta.ColumnsInfo},
+			{Name: "t2", ErrMessage: "table not exist"},
+		},
+	}
+	assert.Equal(t, []string{"table t1 indexes is nil"}, checkTableMetasBySQL(resp))
+	assert.Equal(t, []string{"no table meta is returned"}, checkTableMetasBySQL(&proto.GetTableMetaBySQLResponse{}))
+
+	explain := &proto.ExplainResponse{
+		ClassicResult: &proto.ExplainClassicResult{AnalysisInfoInTableFormat: newTableFormat(0)},
+	}
+	assert.Equal(t, []string{"explain result has no column"}, checkExplain(explain))
+	assert.Equal(t, []string{"explain result is nil"}, checkExplain(&proto.ExplainResponse{}))
+}
+
+func TestCheckNotExistBinary(t *testing.T) {
+	report := Check("./testdata/not_exist_plugin", nil)
+	assert.False(t, report.Passed())
+	assert.Len(t, report.Results, 1)
+	assert.Equal(t, "Handshake", report.Results[0].Check)
+	assert.Equal(t, StatusFailed, report.Results[0].Status)
+}
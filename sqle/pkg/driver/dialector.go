@@ -48,12 +48,20 @@ func (d *PostgresDialector) ShowDatabaseSQL() string {
 type OracleDialector struct {
 }
 
+// OracleParamServiceName is the additional param of Oracle instance, it's
+// used instead of the database name to connect if it's set.
+const OracleParamServiceName = "service_name"
+
 func (d *OracleDialector) Dialect(dsn *driver.DSN) (string, string) {
-	if dsn.DatabaseName == "" {
-		dsn.DatabaseName = "xe"
+	serviceName := dsn.AdditionalParams.GetParam(OracleParamServiceName).String()
+	if serviceName == "" {
+		serviceName = dsn.DatabaseName
+	}
+	if serviceName == "" {
+		serviceName = "xe"
 	}
 	return "oracle", fmt.Sprintf("oracle://%s:%s@%s:%s/%s",
-		dsn.User, dsn.Password, dsn.Host, dsn.Port, dsn.DatabaseName)
+		dsn.User, dsn.Password, dsn.Host, dsn.Port, serviceName)
 }
 
 func (d *OracleDialector) String() string {
@@ -4,16 +4,17 @@ import (
 	"context"
 	"database/sql"
 	_driver "database/sql/driver"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/pkg/params"
-	"github.com/actiontech/sqle/sqle/utils"
 
 	"github.com/percona/go-mysql/query"
 	"github.com/pkg/errors"
-	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 var pluginImpls = make(map[string]*pluginImpl)
@@ -25,6 +26,11 @@ type pluginImpl struct {
 	analysisAdaptor *AnalysisAdaptor
 	db              *sql.DB
 	conn            *sql.Conn
+	// session is nil for the query driver and the analysis driver.
+	session *Session
+	// hasAudited represents the session has been changed by Audit, it's reset
+	// before generating rollback SQLs.
+	hasAudited bool
 }
 
 func (p *pluginImpl) Close(ctx context.Context) {
@@ -124,19 +130,16 @@ func (p *pluginImpl) Schemas(ctx context.Context) ([]string, error) {
 }
 
 func (p *pluginImpl) Parse(ctx context.Context, sql string) ([]driver.Node, error) {
-	sqls, err := sqlparser.SplitStatementToPieces(sql)
+	sqls, err := p.auditAdaptor.ao.sqlSplitter(sql)
 	if err != nil {
 		return nil, errors.Wrap(err, "split sql")
 	}
-	if err != nil {
-		return nil, errors.Wrapf(err, "split sql %s error", sql)
-	}
 
 	nodes := make([]driver.Node, 0, len(sqls))
 	for _, sql := range sqls {
 		n := driver.Node{
 			Text:        sql,
-			Type:        classifySQL(sql),
+			Type:        p.auditAdaptor.ao.sqlClassifier(sql),
 			Fingerprint: query.Fingerprint(sql),
 		}
 		nodes = append(nodes, n)
@@ -144,17 +147,32 @@ func (p *pluginImpl) Parse(ctx context.Context, sql string) ([]driver.Node, erro
 	return nodes, nil
 }
 
-func classifySQL(sql string) (sqlType string) {
-	if utils.HasPrefix(sql, "update", false) ||
-		utils.HasPrefix(sql, "insert", false) ||
-		utils.HasPrefix(sql, "delete", false) {
-		return driver.SQLTypeDML
-	}
+var dmlKeywords = []string{"select", "insert", "update", "delete", "replace", "merge", "with", "upsert"}
 
+// leadingCommentRegexp matches the comments and the brackets before the
+// first keyword of SQL, e.g. "/* hint */ (select 1)".
+var leadingCommentRegexp = regexp.MustCompile(`^(\s|\(|--[^\n]*(\n|$)|/\*(.|\n)*?\*/)*`)
+
+// ClassifySQL is the default SQL classifier, it classifies the SQL by the
+// first keyword. The SQL is regarded as DDL if it isn't DML.
+func ClassifySQL(sql string) (sqlType string) {
+	sql = leadingCommentRegexp.ReplaceAllString(sql, "")
+	// the first keyword ends with the first non-letter character, e.g.
+	// "selection" isn't "select".
+	if end := strings.IndexFunc(sql, func(r rune) bool { return !unicode.IsLetter(r) }); end >= 0 {
+		sql = sql[:end]
+	}
+	for _, keyword := range dmlKeywords {
+		if strings.EqualFold(sql, keyword) {
+			return driver.SQLTypeDML
+		}
+	}
 	return driver.SQLTypeDDL
 }
 
 func (p *pluginImpl) Audit(ctx context.Context, sql string) (*driver.AuditResult, error) {
+	ctx = contextWithSession(ctx, p.session)
+
 	var err error
 	var ast interface{}
 	if p.auditAdaptor.ao.sqlParser != nil {
@@ -185,11 +203,25 @@ func (p *pluginImpl) Audit(ctx context.Context, sql string) (*driver.AuditResult
 		}
 	}
 
+	p.hasAudited = true
 	return result, nil
 }
 
 func (p *pluginImpl) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
-	return "", "", nil
+	if p.auditAdaptor.rollbackFunc == nil {
+		return "", "", nil
+	}
+	// the SQLs are audited before generating rollback SQLs with the same
+	// driver, the state kept by the rule handlers is dropped.
+	if p.hasAudited {
+		p.session.reset()
+		p.hasAudited = false
+	}
+	rollbackSQL, reason, err := p.auditAdaptor.rollbackFunc(contextWithSession(ctx, p.session), sql)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "generate rollback SQL %s in driver adaptor", sql)
+	}
+	return rollbackSQL, reason, nil
 }
 
 func (p *pluginImpl) QueryPrepare(ctx context.Context, sql string, conf *driver.QueryPrepareConf) (*driver.QueryPrepareResult, error) {
@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifySQL(t *testing.T) {
+	cases := []struct {
+		sql    string
+		expect string
+	}{
+		{"select * from t1", driver.SQLTypeDML},
+		{"SELECT * FROM t1", driver.SQLTypeDML},
+		{"Insert into t1 values (1)", driver.SQLTypeDML},
+		{"update t1 set a = 1", driver.SQLTypeDML},
+		{"DELETE FROM t1", driver.SQLTypeDML},
+		{"replace into t1 values (1)", driver.SQLTypeDML},
+		{"with cte as (select 1) select * from cte", driver.SQLTypeDML},
+		{"MERGE INTO t1 USING t2 ON (t1.id = t2.id) WHEN MATCHED THEN UPDATE SET t1.a = t2.a", driver.SQLTypeDML},
+		{"(select 1) union (select 2)", driver.SQLTypeDML},
+		{"/* hint */ select 1", driver.SQLTypeDML},
+		{"/* multi\nline\ncomment */\nupdate t1 set a = 1", driver.SQLTypeDML},
+		{"-- comment\ninsert into t1 values (1)", driver.SQLTypeDML},
+		{"-- comment 1\n  -- comment 2\n/* comment 3 */ delete from t1", driver.SQLTypeDML},
+		{"create table t1 (id int)", driver.SQLTypeDDL},
+		{"ALTER TABLE t1 ADD COLUMN a int", driver.SQLTypeDDL},
+		{"/* select */ drop table t1", driver.SQLTypeDDL},
+		{"-- select\ntruncate table t1", driver.SQLTypeDDL},
+		{"selection", driver.SQLTypeDDL},
+		{"select*from t1", driver.SQLTypeDML},
+		{"-- select", driver.SQLTypeDDL},
+		{"", driver.SQLTypeDDL},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expect, ClassifySQL(c.sql), c.sql)
+	}
+}
+
+func TestPluginImpl_Parse(t *testing.T) {
+	a := NewAdaptor(&PostgresDialector{})
+	WithSQLClassifier(func(sql string) string {
+		return driver.SQLTypeDDL
+	}).apply(a.ao)
+	p := &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+
+	nodes, err := p.Parse(context.TODO(), "select 1; insert into t1 values (1);")
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+	for _, n := range nodes {
+		assert.Equal(t, driver.SQLTypeDDL, n.Type, n.Text)
+	}
+}
+
+func TestPluginImpl_GenRollbackSQL(t *testing.T) {
+	a := NewAdaptor(&PostgresDialector{})
+	p := &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+
+	// no rollback handler
+	rollbackSQL, reason, err := p.GenRollbackSQL(context.TODO(), "insert into t1 values (1)")
+	assert.NoError(t, err)
+	assert.Equal(t, "", rollbackSQL)
+	assert.Equal(t, "", reason)
+
+	a.AddRollbackFunc(func(ctx context.Context, sql string) (string, string, error) {
+		s := SessionFromContext(ctx)
+		if s == nil {
+			return "", "", assert.AnError
+		}
+		if sql == "drop table t1" {
+			return "", "can't rollback DROP TABLE", nil
+		}
+		if sql == "bad sql" {
+			return "", "", assert.AnError
+		}
+		s.Set("rollback", sql)
+		return "delete from t1 where id = 1", "", nil
+	})
+	a.GeneratePlugin()
+	assert.True(t, a.ao.capabilities.Rollback)
+	assert.True(t, a.ao.capabilities.OfflineAudit)
+
+	rollbackSQL, reason, err = p.GenRollbackSQL(context.TODO(), "insert into t1 values (1)")
+	assert.NoError(t, err)
+	assert.Equal(t, "delete from t1 where id = 1", rollbackSQL)
+	assert.Equal(t, "", reason)
+	v, ok := p.session.Get("rollback")
+	assert.True(t, ok)
+	assert.Equal(t, "insert into t1 values (1)", v)
+
+	rollbackSQL, reason, err = p.GenRollbackSQL(context.TODO(), "drop table t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", rollbackSQL)
+	assert.Equal(t, "can't rollback DROP TABLE", reason)
+
+	_, _, err = p.GenRollbackSQL(context.TODO(), "bad sql")
+	assert.Error(t, err)
+}
+
+func TestPluginImpl_AuditWithSession(t *testing.T) {
+	a := NewAdaptor(&PostgresDialector{})
+	rule := &driver.Rule{Name: "table_is_created", Level: driver.RuleLevelWarn}
+	a.AddRule(rule, func(ctx context.Context, rule *driver.Rule, sql string) (string, error) {
+		s := SessionFromContext(ctx)
+		if _, ok := s.Get("t1"); ok {
+			return "table t1 is created by the previous SQL", nil
+		}
+		s.Set("t1", struct{}{})
+		return "", nil
+	})
+	a.cfg = &driver.Config{Rules: []*driver.Rule{rule}}
+	p := &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+
+	result, err := p.Audit(context.TODO(), "create table t1 (id int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "", result.Message())
+
+	result, err = p.Audit(context.TODO(), "create table t1 (id int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "[warn]table t1 is created by the previous SQL", result.Message())
+}
+
+func TestPluginImpl_GenRollbackSQLAfterAudit(t *testing.T) {
+	a := NewAdaptor(&PostgresDialector{})
+	rule := &driver.Rule{Name: "table_is_created", Level: driver.RuleLevelWarn}
+	a.AddRule(rule, func(ctx context.Context, rule *driver.Rule, sql string) (string, error) {
+		SessionFromContext(ctx).Set("t1", struct{}{})
+		return "", nil
+	})
+	a.AddRollbackFunc(func(ctx context.Context, sql string) (string, string, error) {
+		s := SessionFromContext(ctx)
+		// the table created by the audited SQL isn't visible to rollback.
+		if _, ok := s.Get("t1"); ok {
+			return "", "table t1 is created", nil
+		}
+		s.Set("t1", struct{}{})
+		return "drop table t1", "", nil
+	})
+	a.cfg = &driver.Config{Rules: []*driver.Rule{rule}}
+	p := &pluginImpl{auditAdaptor: a, session: newSession(nil, nil)}
+
+	_, err := p.Audit(context.TODO(), "create table t1 (id int)")
+	assert.NoError(t, err)
+	rollbackSQL, reason, err := p.GenRollbackSQL(context.TODO(), "create table t1 (id int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "drop table t1", rollbackSQL)
+	assert.Equal(t, "", reason)
+
+	// the state of rollback is kept across GenRollbackSQL calls.
+	_, reason, err = p.GenRollbackSQL(context.TODO(), "create table t1 (id int)")
+	assert.NoError(t, err)
+	assert.Equal(t, "table t1 is created", reason)
+}
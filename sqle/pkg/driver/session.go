@@ -0,0 +1,106 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var ErrSessionOffline = errors.New("session is offline, no database is connected")
+
+type sessionKey struct{}
+
+// Session is created when SQLE inits the audit driver, and it's kept until
+// the driver is closed. The rule handlers and the rollback handler keep the
+// state across Audit calls in it, such as the tables created by the previous
+// SQLs, and query the target database through its connection.
+type Session struct {
+	db   *sql.DB
+	conn *sql.Conn
+
+	mu     *sync.Mutex
+	values map[interface{}]interface{}
+}
+
+func newSession(db *sql.DB, conn *sql.Conn) *Session {
+	return &Session{
+		db:     db,
+		conn:   conn,
+		mu:     &sync.Mutex{},
+		values: map[interface{}]interface{}{},
+	}
+}
+
+// SessionFromContext returns the session of the handler, it's nil if the
+// context isn't passed by the adaptor.
+func SessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+func contextWithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// Online returns true if the session connects to the database, the SQL is
+// audited offline otherwise.
+func (s *Session) Online() bool {
+	return s.conn != nil
+}
+
+// DbConf returns the connection opened by the Dialector.
+func (s *Session) DbConf() (DbConf, error) {
+	if !s.Online() {
+		return DbConf{}, ErrSessionOffline
+	}
+	return DbConf{Db: s.db, Conn: s.conn}, nil
+}
+
+func (s *Session) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !s.Online() {
+		return nil, ErrSessionOffline
+	}
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "query in session")
+	}
+	return rows, nil
+}
+
+func (s *Session) QueryRow(ctx context.Context, query string, args ...interface{}) (*sql.Row, error) {
+	if !s.Online() {
+		return nil, ErrSessionOffline
+	}
+	return s.conn.QueryRowContext(ctx, query, args...), nil
+}
+
+// Get returns the value stored by Set.
+func (s *Session) Get(key interface{}) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores the value, it's visible to the following Audit calls in the
+// session.
+func (s *Session) Set(key, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *Session) Delete(key interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// reset drops all values stored by Set.
+func (s *Session) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = map[interface{}]interface{}{}
+}
@@ -0,0 +1,90 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_Offline(t *testing.T) {
+	s := newSession(nil, nil)
+	assert.False(t, s.Online())
+
+	_, err := s.DbConf()
+	assert.Equal(t, ErrSessionOffline, err)
+	_, err = s.Query(context.TODO(), "select 1")
+	assert.Equal(t, ErrSessionOffline, err)
+	_, err = s.QueryRow(context.TODO(), "select 1")
+	assert.Equal(t, ErrSessionOffline, err)
+}
+
+func TestSession_Online(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.TODO())
+	assert.NoError(t, err)
+
+	s := newSession(db, conn)
+	assert.True(t, s.Online())
+
+	conf, err := s.DbConf()
+	assert.NoError(t, err)
+	assert.Equal(t, db, conf.Db)
+	assert.Equal(t, conn, conf.Conn)
+
+	mock.ExpectQuery("select name from t1").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+	rows, err := s.Query(context.TODO(), "select name from t1")
+	assert.NoError(t, err)
+	names := []string{}
+	for rows.Next() {
+		var name string
+		assert.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	mock.ExpectQuery("select count").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	row, err := s.QueryRow(context.TODO(), "select count(*) from t1 where id > ?", 1)
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, row.Scan(&count))
+	assert.Equal(t, 10, count)
+
+	mock.ExpectQuery("select 1").WillReturnError(assert.AnError)
+	_, err = s.Query(context.TODO(), "select 1")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSession_Values(t *testing.T) {
+	s := newSession(nil, nil)
+
+	_, ok := s.Get("t1")
+	assert.False(t, ok)
+
+	s.Set("t1", 1)
+	v, ok := s.Get("t1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	s.Set("t1", 2)
+	v, ok = s.Get("t1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	s.Delete("t1")
+	_, ok = s.Get("t1")
+	assert.False(t, ok)
+}
+
+func TestSessionFromContext(t *testing.T) {
+	assert.Nil(t, SessionFromContext(context.TODO()))
+
+	s := newSession(nil, nil)
+	assert.Equal(t, s, SessionFromContext(contextWithSession(context.TODO(), s)))
+}
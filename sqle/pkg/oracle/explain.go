@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PlanStep is a row of PLAN_TABLE, ref to https://docs.oracle.com/en/database/oracle/oracle-database/19/refrn/PLAN_TABLE.html
+type PlanStep struct {
+	ID         int64
+	Operation  string
+	Options    string
+	ObjectName string
+}
+
+// IsFullTableScan returns true if the step reads the whole table, such as
+// "TABLE ACCESS FULL" and "TABLE ACCESS STORAGE FULL" on Exadata.
+func (s *PlanStep) IsFullTableScan() bool {
+	return s.Operation == "TABLE ACCESS" && (s.Options == "FULL" || s.Options == "STORAGE FULL")
+}
+
+const planStepsQuery = `SELECT id, operation, options, object_name FROM plan_table WHERE statement_id = :1 ORDER BY id`
+
+// ExplainPlan explains the SQL by "EXPLAIN PLAN", the plan is removed from
+// PLAN_TABLE after it's read. The SQL must be DML.
+func ExplainPlan(ctx context.Context, conn *sql.Conn, query string) ([]*PlanStep, error) {
+	// the statement id is at most 30 bytes.
+	statementID := fmt.Sprintf("SQLE_%v", time.Now().UnixNano())
+	_, err := conn.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%v' FOR %v", statementID, query))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to explain %s", query)
+	}
+	defer func() {
+		// the plan is kept in the session if it fails to delete, it's harmless.
+		_, _ = conn.ExecContext(ctx, "DELETE FROM plan_table WHERE statement_id = :1", statementID)
+	}()
+
+	rows, err := conn.QueryContext(ctx, planStepsQuery, statementID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query plan of %s", query)
+	}
+	defer rows.Close()
+
+	var steps []*PlanStep
+	for rows.Next() {
+		var operation, options, objectName sql.NullString
+		step := &PlanStep{}
+		if err := rows.Scan(&step.ID, &operation, &options, &objectName); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan plan of %s", query)
+		}
+		step.Operation = operation.String
+		step.Options = options.String
+		step.ObjectName = objectName.String
+		steps = append(steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate plan of %s", query)
+	}
+	return steps, nil
+}
@@ -0,0 +1,219 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenBind
+	tokenSymbol
+)
+
+type token struct {
+	typ tokenType
+	// text is the raw text, the quotes are kept.
+	text string
+	// start and end are the offsets of the token in SQL.
+	start int
+	end   int
+}
+
+// upper returns the keyword form of the word.
+func (t token) upper() string {
+	if t.typ != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+func (t token) is(keyword string) bool {
+	return t.upper() == keyword
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.typ == tokenSymbol && t.text == symbol
+}
+
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '_' || c == '$' || c == '#'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+var multiCharSymbols = []string{":=", "=>", "||", "<=", ">=", "<>", "!=", "^=", "..", "**"}
+
+// qQuoteClosers maps the opening delimiter of the q-quoted string to the
+// closing one, the other delimiters are closed by themselves.
+var qQuoteClosers = map[byte]byte{'[': ']', '{': '}', '(': ')', '<': '>'}
+
+// tokenize splits the SQL into tokens, the comments and whitespaces are
+// skipped. It returns error only if the string, quoted identifier or comment
+// is not closed.
+func tokenize(sql string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 1
+			}
+			continue
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("comment at offset %v is not closed", start)
+			}
+			i += end + 4
+			continue
+		case (c == 'q' || c == 'Q') && i+2 < len(sql) && sql[i+1] == '\'':
+			end, err := scanQQuote(sql, i+2)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case (c == 'n' || c == 'N') && i+3 < len(sql) && (sql[i+1] == 'q' || sql[i+1] == 'Q') && sql[i+2] == '\'':
+			end, err := scanQQuote(sql, i+3)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case (c == 'n' || c == 'N') && i+1 < len(sql) && sql[i+1] == '\'':
+			end, err := scanQuoted(sql, i+1, '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case c == '\'':
+			end, err := scanQuoted(sql, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case c == '"':
+			end, err := scanQuoted(sql, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenQuotedIdentifier, text: sql[start:i], start: start, end: i})
+		case isWordStart(c):
+			for i < len(sql) && isWordChar(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenWord, text: sql[start:i], start: start, end: i})
+		case isDigit(c) || c == '.' && i+1 < len(sql) && isDigit(sql[i+1]):
+			i = scanNumber(sql, i)
+			tokens = append(tokens, token{typ: tokenNumber, text: sql[start:i], start: start, end: i})
+		case c == ':' && i+1 < len(sql) && (isWordChar(sql[i+1]) || sql[i+1] == '"'):
+			i++
+			if sql[i] == '"' {
+				end, err := scanQuoted(sql, i, '"')
+				if err != nil {
+					return nil, err
+				}
+				i = end
+			} else {
+				for i < len(sql) && isWordChar(sql[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{typ: tokenBind, text: sql[start:i], start: start, end: i})
+		default:
+			i++
+			for _, symbol := range multiCharSymbols {
+				if strings.HasPrefix(sql[start:], symbol) {
+					i = start + len(symbol)
+					break
+				}
+			}
+			tokens = append(tokens, token{typ: tokenSymbol, text: sql[start:i], start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted returns the end of the quoted text which starts at i, the quote
+// is escaped by doubling it.
+func scanQuoted(sql string, i int, quote byte) (int, error) {
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] != quote {
+			continue
+		}
+		if j+1 < len(sql) && sql[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1, nil
+	}
+	return 0, fmt.Errorf("quoted text at offset %v is not closed", i)
+}
+
+// scanQQuote returns the end of the q-quoted string, such as q'[it's]', i is
+// the offset of the opening delimiter.
+func scanQQuote(sql string, i int) (int, error) {
+	if i >= len(sql) {
+		return 0, fmt.Errorf("q-quoted string at offset %v is not closed", i)
+	}
+	closer, ok := qQuoteClosers[sql[i]]
+	if !ok {
+		closer = sql[i]
+	}
+	end := strings.Index(sql[i+1:], string([]byte{closer, '\''}))
+	if end < 0 {
+		return 0, fmt.Errorf("q-quoted string at offset %v is not closed", i)
+	}
+	return i + 1 + end + 2, nil
+}
+
+func scanNumber(sql string, i int) int {
+	for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.') {
+		// the range operator of PL/SQL, such as "1..10".
+		if sql[i] == '.' && i+1 < len(sql) && sql[i+1] == '.' {
+			return i
+		}
+		i++
+	}
+	if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+		j := i + 1
+		if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+			j++
+		}
+		if j < len(sql) && isDigit(sql[j]) {
+			i = j
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+		}
+	}
+	// the suffix of BINARY_FLOAT and BINARY_DOUBLE literals.
+	if i < len(sql) && (sql[i] == 'f' || sql[i] == 'F' || sql[i] == 'd' || sql[i] == 'D') &&
+		(i+1 == len(sql) || !isWordChar(sql[i+1])) {
+		i++
+	}
+	return i
+}
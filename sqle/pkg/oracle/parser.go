@@ -0,0 +1,327 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+)
+
+type StatementKind string
+
+const (
+	KindSelect      StatementKind = "SELECT"
+	KindInsert      StatementKind = "INSERT"
+	KindUpdate      StatementKind = "UPDATE"
+	KindDelete      StatementKind = "DELETE"
+	KindMerge       StatementKind = "MERGE"
+	KindCreateTable StatementKind = "CREATE TABLE"
+	KindCreateIndex StatementKind = "CREATE INDEX"
+	KindAlterTable  StatementKind = "ALTER TABLE"
+	KindAlterIndex  StatementKind = "ALTER INDEX"
+	KindDropTable   StatementKind = "DROP TABLE"
+	KindPLSQL       StatementKind = "PL/SQL"
+	KindOther       StatementKind = "OTHER"
+)
+
+// ObjectName is the name of table or index. The unquoted name is converted to
+// upper case like Oracle.
+type ObjectName struct {
+	Schema string
+	Name   string
+	// Quoted is true if the name is quoted by double quotes.
+	Quoted bool
+}
+
+func (n ObjectName) String() string {
+	if n.Schema == "" {
+		return n.Name
+	}
+	return fmt.Sprintf("%v.%v", n.Schema, n.Name)
+}
+
+// Statement is the result of parsing one Oracle statement, it keeps the
+// information which the rules need instead of the full syntax tree.
+type Statement struct {
+	Text string
+	Kind StatementKind
+
+	// Table is the table created, altered or dropped by DDL, or the table of
+	// the created index.
+	Table ObjectName
+	// Index is the index created or altered.
+	Index       ObjectName
+	UniqueIndex bool
+
+	// Tables are the tables referenced by DML.
+	Tables []ObjectName
+	// SelectStar is true if "*" is in the select list.
+	SelectStar bool
+	// Literals and Binds are the number of literals and bind variables in DML.
+	Literals int
+	Binds    int
+
+	// Move, UpdateIndexes and Online are the options of ALTER TABLE ... MOVE.
+	Move          bool
+	UpdateIndexes bool
+	Online        bool
+}
+
+// IsDML returns true if the statement can be explained.
+func (s *Statement) IsDML() bool {
+	switch s.Kind {
+	case KindSelect, KindInsert, KindUpdate, KindDelete, KindMerge:
+		return true
+	}
+	return false
+}
+
+// Parse parses the statement split by SplitStatements.
+func Parse(sql string) (*Statement, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &Statement{Text: sql, Kind: KindOther}
+	if len(tokens) == 0 {
+		return stmt, nil
+	}
+	if isPLSQL(tokens) {
+		stmt.Kind = KindPLSQL
+		return stmt, nil
+	}
+
+	p := &parser{tokens: tokens}
+	switch p.next().upper() {
+	case "SELECT", "WITH":
+		stmt.Kind = KindSelect
+	case "INSERT":
+		stmt.Kind = KindInsert
+	case "UPDATE":
+		stmt.Kind = KindUpdate
+	case "DELETE":
+		stmt.Kind = KindDelete
+	case "MERGE":
+		stmt.Kind = KindMerge
+	case "CREATE":
+		p.parseCreate(stmt)
+	case "ALTER":
+		p.parseAlter(stmt)
+	case "DROP":
+		if p.accept("TABLE") {
+			stmt.Kind = KindDropTable
+			stmt.Table = p.objectName()
+		}
+	}
+	if stmt.IsDML() {
+		parseDML(stmt, tokens)
+	}
+	return stmt, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{typ: tokenSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the keyword if it's the next token.
+func (p *parser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func identifier(t token) (string, bool, bool) {
+	switch t.typ {
+	case tokenWord:
+		return strings.ToUpper(t.text), false, true
+	case tokenQuotedIdentifier:
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], `""`, `"`), true, true
+	}
+	return "", false, false
+}
+
+// objectName parses the name like "schema.table", the name is empty if the
+// next token isn't identifier.
+func (p *parser) objectName() ObjectName {
+	name, quoted, ok := identifier(p.peek())
+	if !ok {
+		return ObjectName{}
+	}
+	p.pos++
+	if !p.peek().isSymbol(".") {
+		return ObjectName{Name: name, Quoted: quoted}
+	}
+	p.pos++
+	second, secondQuoted, ok := identifier(p.peek())
+	if !ok {
+		return ObjectName{Name: name, Quoted: quoted}
+	}
+	p.pos++
+	return ObjectName{Schema: name, Name: second, Quoted: secondQuoted}
+}
+
+func (p *parser) parseCreate(stmt *Statement) {
+	for {
+		switch p.peek().upper() {
+		case "OR", "REPLACE", "GLOBAL", "PRIVATE", "TEMPORARY", "SHARDED", "DUPLICATED", "BLOCKCHAIN", "IMMUTABLE":
+			p.pos++
+			continue
+		case "TABLE":
+			p.pos++
+			stmt.Kind = KindCreateTable
+			stmt.Table = p.objectName()
+			return
+		case "UNIQUE":
+			p.pos++
+			stmt.UniqueIndex = true
+			continue
+		case "BITMAP", "MULTIVALUE":
+			p.pos++
+			continue
+		case "INDEX":
+			p.pos++
+			stmt.Kind = KindCreateIndex
+			stmt.Index = p.objectName()
+			if p.accept("ON") {
+				stmt.Table = p.objectName()
+			}
+			return
+		}
+		return
+	}
+}
+
+func (p *parser) parseAlter(stmt *Statement) {
+	switch {
+	case p.accept("TABLE"):
+		stmt.Kind = KindAlterTable
+		stmt.Table = p.objectName()
+		depth := 0
+		for p.pos < len(p.tokens) {
+			t := p.next()
+			switch {
+			case t.isSymbol("("):
+				depth++
+			case t.isSymbol(")"):
+				depth--
+			case depth > 0:
+			case t.is("MOVE"):
+				stmt.Move = true
+			case t.is("ONLINE"):
+				stmt.Online = true
+			case t.is("UPDATE"):
+				// UPDATE [GLOBAL] INDEXES
+				p.accept("GLOBAL")
+				if p.accept("INDEXES") {
+					stmt.UpdateIndexes = true
+				}
+			}
+		}
+	case p.accept("INDEX"):
+		stmt.Kind = KindAlterIndex
+		stmt.Index = p.objectName()
+	}
+}
+
+// tableClauseEnds are the keywords which end the table list in FROM clause.
+var tableClauseEnds = map[string]struct{}{
+	"WHERE": {}, "GROUP": {}, "ORDER": {}, "HAVING": {}, "CONNECT": {}, "START": {}, "UNION": {},
+	"INTERSECT": {}, "MINUS": {}, "FETCH": {}, "FOR": {}, "ON": {}, "SET": {}, "VALUES": {},
+	"RETURNING": {}, "WHEN": {}, "MODEL": {}, "WINDOW": {}, "OFFSET": {}, "LOG": {},
+}
+
+func parseDML(stmt *Statement, tokens []token) {
+	// inTableList means the following names after "," are tables, and it's
+	// kept for each depth of brackets.
+	inTableList := map[int]bool{}
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch t.typ {
+		case tokenString, tokenNumber:
+			stmt.Literals++
+			continue
+		case tokenBind:
+			stmt.Binds++
+			continue
+		}
+
+		var prev token
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		switch {
+		case t.isSymbol("("):
+			depth++
+			continue
+		case t.isSymbol(")"):
+			inTableList[depth] = false
+			depth--
+			continue
+		case t.isSymbol("*"):
+			switch {
+			case prev.is("SELECT"), prev.is("DISTINCT"), prev.is("UNIQUE"), prev.is("ALL"),
+				prev.isSymbol("."), prev.isSymbol(","):
+				stmt.SelectStar = true
+			}
+			continue
+		}
+
+		readTable := false
+		switch t.upper() {
+		case "FROM":
+			inTableList[depth] = true
+			readTable = true
+		case "JOIN", "INTO", "USING":
+			readTable = true
+		case "UPDATE":
+			// UPDATE of "FOR UPDATE" and "WHEN MATCHED THEN UPDATE" isn't followed by table.
+			readTable = !prev.is("FOR") && !prev.is("THEN")
+		case "DELETE":
+			readTable = !prev.is("THEN")
+			if readTable && i+1 < len(tokens) && tokens[i+1].is("FROM") {
+				readTable = false
+			}
+		default:
+			if _, ok := tableClauseEnds[t.upper()]; ok {
+				inTableList[depth] = false
+			} else if t.isSymbol(",") && inTableList[depth] {
+				readTable = true
+			}
+		}
+		if !readTable {
+			continue
+		}
+		p := &parser{tokens: tokens, pos: i + 1}
+		// the DML of the table, such as "DELETE FROM t", or "UPDATE ONLY (t)".
+		p.accept("ONLY")
+		if name := p.objectName(); name.Name != "" {
+			if _, ok := tableClauseEnds[name.Name]; !ok || name.Quoted {
+				stmt.Tables = appendTable(stmt.Tables, name)
+			}
+		}
+	}
+}
+
+func appendTable(tables []ObjectName, name ObjectName) []ObjectName {
+	for _, t := range tables {
+		if t == name {
+			return tables
+		}
+	}
+	return append(tables, name)
+}
@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_DML(t *testing.T) {
+	stmt, err := Parse(`SELECT a.*, b.id FROM scott.emp a, "Dept" b JOIN t3 ON a.id = t3.id WHERE a.id = :id AND b.name = 'x' FOR UPDATE`)
+	assert.NoError(t, err)
+	assert.Equal(t, KindSelect, stmt.Kind)
+	assert.True(t, stmt.SelectStar)
+	assert.Equal(t, 1, stmt.Binds)
+	assert.Equal(t, 1, stmt.Literals)
+	assert.Equal(t, []ObjectName{
+		{Schema: "SCOTT", Name: "EMP"},
+		{Name: "Dept", Quoted: true},
+		{Name: "T3"},
+	}, stmt.Tables)
+
+	stmt, err = Parse("select count(*) from (select id from t1 where id in (1, 2)) x, t2")
+	assert.NoError(t, err)
+	assert.False(t, stmt.SelectStar)
+	assert.Equal(t, 2, stmt.Literals)
+	assert.Equal(t, []ObjectName{{Name: "T1"}, {Name: "T2"}}, stmt.Tables)
+
+	stmt, err = Parse("delete from t1 where id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, KindDelete, stmt.Kind)
+	assert.Equal(t, []ObjectName{{Name: "T1"}}, stmt.Tables)
+
+	stmt, err = Parse("update t1 set a = :1")
+	assert.NoError(t, err)
+	assert.Equal(t, KindUpdate, stmt.Kind)
+	assert.Equal(t, []ObjectName{{Name: "T1"}}, stmt.Tables)
+	assert.Equal(t, 0, stmt.Literals)
+
+	stmt, err = Parse("merge into t1 using t2 on (t1.id = t2.id) when matched then update set t1.a = t2.a when not matched then insert (id) values (t2.id)")
+	assert.NoError(t, err)
+	assert.Equal(t, KindMerge, stmt.Kind)
+	assert.Equal(t, []ObjectName{{Name: "T1"}, {Name: "T2"}}, stmt.Tables)
+}
+
+func TestParse_DDL(t *testing.T) {
+	stmt, err := Parse(`create global temporary table "app"."tmp_Order" (id number)`)
+	assert.NoError(t, err)
+	assert.Equal(t, KindCreateTable, stmt.Kind)
+	assert.Equal(t, ObjectName{Schema: "app", Name: "tmp_Order", Quoted: true}, stmt.Table)
+
+	stmt, err = Parse("create unique index uk_t1_a on t1 (a)")
+	assert.NoError(t, err)
+	assert.Equal(t, KindCreateIndex, stmt.Kind)
+	assert.True(t, stmt.UniqueIndex)
+	assert.Equal(t, ObjectName{Name: "UK_T1_A"}, stmt.Index)
+	assert.Equal(t, ObjectName{Name: "T1"}, stmt.Table)
+
+	stmt, err = Parse("alter table t1 move tablespace users")
+	assert.NoError(t, err)
+	assert.Equal(t, KindAlterTable, stmt.Kind)
+	assert.True(t, stmt.Move)
+	assert.False(t, stmt.UpdateIndexes)
+	assert.False(t, stmt.Online)
+
+	stmt, err = Parse("alter table t1 move partition p1 online update global indexes")
+	assert.NoError(t, err)
+	assert.True(t, stmt.Move)
+	assert.True(t, stmt.UpdateIndexes)
+	assert.True(t, stmt.Online)
+
+	stmt, err = Parse("drop table t1 purge")
+	assert.NoError(t, err)
+	assert.Equal(t, KindDropTable, stmt.Kind)
+	assert.Equal(t, ObjectName{Name: "T1"}, stmt.Table)
+
+	stmt, err = Parse("create or replace trigger trg before insert on t1 begin null; end;")
+	assert.NoError(t, err)
+	assert.Equal(t, KindPLSQL, stmt.Kind)
+
+	stmt, err = Parse("grant select on t1 to u1")
+	assert.NoError(t, err)
+	assert.Equal(t, KindOther, stmt.Kind)
+}
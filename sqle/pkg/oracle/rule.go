@@ -0,0 +1,259 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	RuleTypeNamingConvention = "命名规范"
+	RuleTypeDDLConvention    = "DDL规范"
+	RuleTypeDMLConvention    = "DML规范"
+)
+
+const (
+	DMLCheckBindVariable        = "dml_check_bind_variable"
+	DMLCheckFullTableScan       = "dml_check_full_table_scan"
+	DMLDisableSelectAllColumn   = "dml_disable_select_all_column"
+	DDLCheckTableName           = "ddl_check_table_name"
+	DDLCheckIndexName           = "ddl_check_index_name"
+	DDLCheckAlterTableMoveIndex = "ddl_check_alter_table_move"
+)
+
+const (
+	ParamKeyNamePattern       = "name_pattern"
+	ParamKeyNameMaxLength     = "name_max_length"
+	ParamKeyIndexPrefix       = "index_prefix"
+	ParamKeyUniqueIndexPrefix = "unique_index_prefix"
+)
+
+// AuditContext keeps the state of the audit session, it's shared by the SQLs
+// audited by the same driver.
+type AuditContext struct {
+	*astrule.AuditContext
+}
+
+func NewAuditContext(conn *sql.Conn, logger hclog.Logger) *AuditContext {
+	c := &AuditContext{}
+	c.AuditContext = astrule.NewAuditContext(conn, logger, func(ast interface{}) {
+		if stmt, ok := ast.(*Statement); ok {
+			c.Update(stmt)
+		}
+	})
+	return c
+}
+
+// Update records the effect of the audited statement.
+func (c *AuditContext) Update(stmt *Statement) {
+	switch stmt.Kind {
+	case KindCreateTable:
+		c.AddCreatedTable(stmt.Table.Name)
+	case KindDropTable:
+		c.RemoveCreatedTable(stmt.Table.Name)
+	}
+}
+
+type RuleHandler = astrule.RuleHandler
+
+// statementRule converts the rule function of statement to the rule function
+// of the astrule.
+func statementRule(f func(ctx context.Context, actx *AuditContext, rule *driver.Rule, stmt *Statement) ([]interface{}, error)) astrule.RuleFunc {
+	return func(ctx context.Context, state astrule.AuditState, rule *driver.Rule, ast interface{}) ([]interface{}, error) {
+		actx, ok := state.(*AuditContext)
+		if !ok {
+			return nil, fmt.Errorf("unexpected audit context %T", state)
+		}
+		stmt, ok := ast.(*Statement)
+		if !ok {
+			return nil, fmt.Errorf("unexpected AST %T", ast)
+		}
+		return f(ctx, actx, rule, stmt)
+	}
+}
+
+var RuleHandlers = []RuleHandler{
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckBindVariable,
+			Desc:     "DML 语句建议使用绑定变量",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeDMLConvention,
+		},
+		Message: "DML 语句使用了字面量而没有使用绑定变量，会导致硬解析过多",
+		Func:    statementRule(checkBindVariable),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckFullTableScan,
+			Desc:     "DML 语句不建议进行全表扫描",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+		},
+		Message: "执行计划中存在对表 %v 的全表扫描",
+		Func:    statementRule(checkFullTableScan),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLDisableSelectAllColumn,
+			Desc:     "不建议使用 SELECT *",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeDMLConvention,
+		},
+		Message: "不建议使用 SELECT *",
+		Func:    statementRule(checkSelectAllColumn),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckTableName,
+			Desc:     "表名需要符合命名规范",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   ParamKeyNamePattern,
+					Value: "^[A-Z][A-Z0-9_]*$",
+					Desc:  "表名正则表达式",
+					Type:  params.ParamTypeString,
+				},
+				&params.Param{
+					Key:   ParamKeyNameMaxLength,
+					Value: "30",
+					Desc:  "表名最大长度",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message: "表名 %v 不符合命名规范：%v",
+		Func:    statementRule(checkTableName),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckIndexName,
+			Desc:     "索引名需要使用固定前缀",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   ParamKeyIndexPrefix,
+					Value: "IDX_",
+					Desc:  "普通索引前缀",
+					Type:  params.ParamTypeString,
+				},
+				&params.Param{
+					Key:   ParamKeyUniqueIndexPrefix,
+					Value: "UK_",
+					Desc:  "唯一索引前缀",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message: "索引 %v 的名称需要以 %v 开头",
+		Func:    statementRule(checkIndexName),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckAlterTableMoveIndex,
+			Desc:     "ALTER TABLE ... MOVE 需要同时维护索引",
+			Level:    driver.RuleLevelError,
+			Category: RuleTypeDDLConvention,
+		},
+		Message: "ALTER TABLE %v MOVE 会导致表上的索引失效，建议使用 UPDATE INDEXES 或 ONLINE",
+		Func:    statementRule(checkAlterTableMove),
+	},
+}
+
+func checkBindVariable(_ context.Context, _ *AuditContext, _ *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if stmt.IsDML() && stmt.Literals > 0 && stmt.Binds == 0 {
+		return []interface{}{}, nil
+	}
+	return nil, nil
+}
+
+func checkFullTableScan(ctx context.Context, actx *AuditContext, _ *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if actx.Conn == nil || !stmt.IsDML() {
+		return nil, nil
+	}
+	// the tables created by the previous SQLs can't be explained.
+	for _, table := range stmt.Tables {
+		if actx.IsCreatedTable(table.Name) {
+			return nil, nil
+		}
+	}
+	steps, err := ExplainPlan(ctx, actx.Conn, stmt.Text)
+	if err != nil {
+		// the SQL may depend on the objects which don't exist now, it's not
+		// the problem of the SQL.
+		actx.Logger.Warn("failed to explain SQL, skip checking full table scan", "sql", stmt.Text, "err", err)
+		return nil, nil
+	}
+	tables := []string{}
+	for _, step := range steps {
+		if step.IsFullTableScan() && step.ObjectName != "" {
+			tables = append(tables, step.ObjectName)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	return []interface{}{strings.Join(tables, ", ")}, nil
+}
+
+func checkSelectAllColumn(_ context.Context, _ *AuditContext, _ *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if stmt.IsDML() && stmt.SelectStar {
+		return []interface{}{}, nil
+	}
+	return nil, nil
+}
+
+func checkTableName(_ context.Context, _ *AuditContext, rule *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if stmt.Kind != KindCreateTable || stmt.Table.Name == "" {
+		return nil, nil
+	}
+	name := stmt.Table.Name
+	if maxLength := rule.Params.GetParam(ParamKeyNameMaxLength).Int(); maxLength > 0 && len(name) > maxLength {
+		return []interface{}{name, fmt.Sprintf("长度超过 %v", maxLength)}, nil
+	}
+	pattern := rule.Params.GetParam(ParamKeyNamePattern).String()
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %v of rule %v: %v", pattern, rule.Name, err)
+	}
+	if !re.MatchString(name) {
+		return []interface{}{name, fmt.Sprintf("不匹配 %v", pattern)}, nil
+	}
+	return nil, nil
+}
+
+func checkIndexName(_ context.Context, _ *AuditContext, rule *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if stmt.Kind != KindCreateIndex || stmt.Index.Name == "" {
+		return nil, nil
+	}
+	key := ParamKeyIndexPrefix
+	if stmt.UniqueIndex {
+		key = ParamKeyUniqueIndexPrefix
+	}
+	prefix := rule.Params.GetParam(key).String()
+	if prefix == "" || strings.HasPrefix(stmt.Index.Name, strings.ToUpper(prefix)) {
+		return nil, nil
+	}
+	return []interface{}{stmt.Index.Name, strings.ToUpper(prefix)}, nil
+}
+
+func checkAlterTableMove(_ context.Context, _ *AuditContext, _ *driver.Rule, stmt *Statement) ([]interface{}, error) {
+	if stmt.Kind == KindAlterTable && stmt.Move && !stmt.UpdateIndexes && !stmt.Online {
+		return []interface{}{stmt.Table}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func getRuleHandler(name string) *RuleHandler {
+	for i := range RuleHandlers {
+		if RuleHandlers[i].Rule.Name == name {
+			return &RuleHandlers[i]
+		}
+	}
+	return nil
+}
+
+func checkRule(t *testing.T, actx *AuditContext, name, sql string) string {
+	h := getRuleHandler(name)
+	if !assert.NotNil(t, h, name) {
+		return ""
+	}
+	stmt, err := Parse(sql)
+	assert.NoError(t, err)
+	rule := h.Rule
+	msg, err := h.Check(context.Background(), actx, &rule, stmt)
+	assert.NoError(t, err)
+	return msg
+}
+
+func TestRules_Offline(t *testing.T) {
+	actx := NewAuditContext(nil, nil)
+
+	assert.NotEmpty(t, checkRule(t, actx, DMLCheckBindVariable, "select a from t1 where id = 1"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckBindVariable, "select a from t1 where id = :id"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckBindVariable, "create table t1 (a varchar2(10) default 'x')"))
+
+	assert.NotEmpty(t, checkRule(t, actx, DMLDisableSelectAllColumn, "select * from t1"))
+	assert.Empty(t, checkRule(t, actx, DMLDisableSelectAllColumn, "select count(*) from t1"))
+
+	// it's skipped offline.
+	assert.Empty(t, checkRule(t, actx, DMLCheckFullTableScan, "select a from t1"))
+
+	assert.Empty(t, checkRule(t, actx, DDLCheckTableName, "create table order_item (id number)"))
+	assert.Equal(t, "表名 order_item 不符合命名规范：不匹配 ^[A-Z][A-Z0-9_]*$",
+		checkRule(t, actx, DDLCheckTableName, `create table "order_item" (id number)`))
+	assert.Equal(t, "表名 A1234567890123456789012345678901 不符合命名规范：长度超过 30",
+		checkRule(t, actx, DDLCheckTableName, "create table a1234567890123456789012345678901 (id number)"))
+
+	assert.Empty(t, checkRule(t, actx, DDLCheckIndexName, "create index idx_t1_a on t1 (a)"))
+	assert.Equal(t, "索引 T1_A 的名称需要以 UK_ 开头", checkRule(t, actx, DDLCheckIndexName, "create unique index t1_a on t1 (a)"))
+
+	assert.NotEmpty(t, checkRule(t, actx, DDLCheckAlterTableMoveIndex, "alter table t1 move"))
+	assert.Empty(t, checkRule(t, actx, DDLCheckAlterTableMoveIndex, "alter table t1 move update indexes"))
+	assert.Empty(t, checkRule(t, actx, DDLCheckAlterTableMoveIndex, "alter table t1 add (b number)"))
+}
+
+func TestCheckFullTableScan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	actx := NewAuditContext(conn, nil)
+
+	mock.ExpectExec("EXPLAIN PLAN SET STATEMENT_ID = '.*' FOR select a from t1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, operation, options, object_name FROM plan_table").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "operation", "options", "object_name"}).
+			AddRow(0, "SELECT STATEMENT", nil, nil).
+			AddRow(1, "TABLE ACCESS", "FULL", "T1"))
+	mock.ExpectExec("DELETE FROM plan_table").WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.Equal(t, "执行计划中存在对表 T1 的全表扫描", checkRule(t, actx, DMLCheckFullTableScan, "select a from t1"))
+
+	mock.ExpectExec("EXPLAIN PLAN").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, operation, options, object_name FROM plan_table").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "operation", "options", "object_name"}).
+			AddRow(0, "SELECT STATEMENT", nil, nil).
+			AddRow(1, "TABLE ACCESS", "BY INDEX ROWID", "T1").
+			AddRow(2, "INDEX", "UNIQUE SCAN", "PK_T1"))
+	mock.ExpectExec("DELETE FROM plan_table").WillReturnResult(sqlmock.NewResult(0, 3))
+	assert.Empty(t, checkRule(t, actx, DMLCheckFullTableScan, "select a from t1 where id = :1"))
+
+	// the error of explain is ignored.
+	mock.ExpectExec("EXPLAIN PLAN").WillReturnError(fmt.Errorf("ORA-00942: table or view does not exist"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckFullTableScan, "select a from t2"))
+
+	// the table created in the session is not explained.
+	stmt, err := Parse("create table t3 (a number)")
+	assert.NoError(t, err)
+	actx.Update(stmt)
+	assert.Empty(t, checkRule(t, actx, DMLCheckFullTableScan, "select a from t3"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleHandlers(t *testing.T) {
+	names := map[string]struct{}{}
+	for _, h := range RuleHandlers {
+		assert.NotEmpty(t, h.Rule.Desc, h.Rule.Name)
+		assert.Contains(t, []driver.RuleLevel{driver.RuleLevelNotice, driver.RuleLevelWarn, driver.RuleLevelError}, h.Rule.Level)
+		_, ok := names[h.Rule.Name]
+		assert.False(t, ok, "duplicated rule %v", h.Rule.Name)
+		names[h.Rule.Name] = struct{}{}
+	}
+}
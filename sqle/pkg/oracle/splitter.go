@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"strings"
+)
+
+// plsqlObjects are the objects whose CREATE statement contains PL/SQL.
+var plsqlObjects = map[string]struct{}{
+	"PROCEDURE": {},
+	"FUNCTION":  {},
+	"PACKAGE":   {},
+	"TRIGGER":   {},
+	"TYPE":      {},
+	"LIBRARY":   {},
+}
+
+// isPLSQL returns true if the statement which starts with the tokens is a
+// PL/SQL block, whose inner semicolons don't end the statement.
+func isPLSQL(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch tokens[0].upper() {
+	case "DECLARE", "BEGIN":
+		return true
+	case "CREATE":
+	default:
+		return false
+	}
+	for _, t := range tokens[1:] {
+		switch t.upper() {
+		case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE", "EDITIONING", "NONEDITIONING":
+			continue
+		}
+		_, ok := plsqlObjects[t.upper()]
+		return ok
+	}
+	return false
+}
+
+// isSlashLine returns true if the token is a "/" which is alone in its line,
+// it ends the statement like SQL*Plus.
+func isSlashLine(sql string, t token) bool {
+	if !t.isSymbol("/") {
+		return false
+	}
+	lineStart := strings.LastIndexByte(sql[:t.start], '\n') + 1
+	lineEnd := strings.IndexByte(sql[t.end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(sql)
+	} else {
+		lineEnd += t.end
+	}
+	return strings.TrimSpace(sql[lineStart:t.start]) == "" && strings.TrimSpace(sql[t.end:lineEnd]) == ""
+}
+
+// SplitStatements splits the SQL script into statements. The SQL statement is
+// ended by ";" or a "/" line, and the PL/SQL block is ended by a "/" line or
+// the end of script, so the semicolons in the block are kept. The ";" which
+// ends the SQL statement is removed, because Oracle refuses to execute it.
+func SplitStatements(sql string) ([]string, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{}
+	add := func(text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			statements = append(statements, text)
+		}
+	}
+
+	start := -1
+	plsql := false
+	for i, t := range tokens {
+		if start < 0 {
+			if t.isSymbol(";") || isSlashLine(sql, t) {
+				continue
+			}
+			start = i
+			plsql = isPLSQL(tokens[i:])
+		}
+		switch {
+		case isSlashLine(sql, t):
+			add(sql[tokens[start].start:t.start])
+			start = -1
+		case t.isSymbol(";") && !plsql:
+			add(sql[tokens[start].start:t.start])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		add(sql[tokens[start].start:tokens[len(tokens)-1].end])
+	}
+	return statements, nil
+}
@@ -0,0 +1,73 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	cases := []struct {
+		sql    string
+		expect []string
+	}{
+		{
+			sql:    "select 1 from dual; select 2 from dual;",
+			expect: []string{"select 1 from dual", "select 2 from dual"},
+		},
+		{
+			sql:    "select ';' from dual -- comment;\n; select q'[a;b]' from dual",
+			expect: []string{"select ';' from dual -- comment;", "select q'[a;b]' from dual"},
+		},
+		{
+			sql: `create table t1 (id number);
+CREATE OR REPLACE PROCEDURE p1 AS
+BEGIN
+  insert into t1 values (1);
+  commit;
+END;
+/
+begin
+  p1;
+end;
+/
+select /* ; */ * from t1`,
+			expect: []string{
+				"create table t1 (id number)",
+				"CREATE OR REPLACE PROCEDURE p1 AS\nBEGIN\n  insert into t1 values (1);\n  commit;\nEND;",
+				"begin\n  p1;\nend;",
+				"select /* ; */ * from t1",
+			},
+		},
+		{
+			// the PL/SQL block at the end of script doesn't need "/".
+			sql:    "declare\n  a number := 10 / 2;\nbegin\n  null;\nend;",
+			expect: []string{"declare\n  a number := 10 / 2;\nbegin\n  null;\nend;"},
+		},
+		{
+			sql:    "update t1 set a = 1\n/\n",
+			expect: []string{"update t1 set a = 1"},
+		},
+		{
+			sql:    "  ;\n-- only comment\n",
+			expect: []string{},
+		},
+	}
+	for _, c := range cases {
+		statements, err := SplitStatements(c.sql)
+		assert.NoError(t, err, c.sql)
+		assert.Equal(t, c.expect, statements, c.sql)
+	}
+}
+
+func TestSplitStatements_Error(t *testing.T) {
+	for _, sql := range []string{
+		"select 'a from dual",
+		`select "a from dual`,
+		"select 1 from dual /* comment",
+		"select q'[a' from dual",
+	} {
+		_, err := SplitStatements(sql)
+		assert.Error(t, err, sql)
+	}
+}
@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+type DSN struct {
+	Host         string
+	Port         string
+	User         string
+	Password     string
+	DatabaseName string
+}
+
+func (d *DSN) String() string {
+	return fmt.Sprintf("%s:%s/%s", d.Host, d.Port, d.DatabaseName)
+}
+
+// connString returns the URL of the connection, the user, the password and
+// the database name are escaped.
+func (d *DSN) connString() string {
+	return (&url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.User, d.Password),
+		Host:   net.JoinHostPort(d.Host, d.Port),
+		Path:   d.DatabaseName,
+	}).String()
+}
+
+type DB struct {
+	db *sql.DB
+}
+
+func NewDB(dsn *DSN) (*DB, error) {
+	if dsn.DatabaseName == "" {
+		dsn.DatabaseName = "postgres"
+	}
+
+	sqlDB, err := sql.Open("pgx", dsn.connString())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", dsn.String())
+	}
+	err = sqlDB.Ping()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to ping %s", dsn.String())
+	}
+
+	return &DB{db: sqlDB}, nil
+}
+
+func (p *DB) Close() error {
+	return p.db.Close()
+}
+
+func (p *DB) serverVersionNum(ctx context.Context) (int, error) {
+	var version int
+	err := p.db.QueryRowContext(ctx, "SHOW server_version_num").Scan(&version)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to query server_version_num")
+	}
+	return version, nil
+}
+
+// QueryTopSQLs query top N SQLs from pg_stat_statements, the extension must be installed
+// in the connected database. If datname is not empty, only SQLs executed in that database are returned.
+func (p *DB) QueryTopSQLs(ctx context.Context, datname string, topN int, orderBy string) ([]*PgStatStatement, error) {
+	version, err := p.serverVersionNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, err := buildTopSQLsQuery(version, topN, orderBy)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.db.QueryContext(ctx, query, datname)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query %s", query)
+	}
+	defer rows.Close()
+
+	var ret []*PgStatStatement
+	for rows.Next() {
+		res := PgStatStatement{}
+		err = rows.Scan(&res.Query, &res.Calls, &res.TotalExecTime, &res.MeanExecTime, &res.Rows, &res.SharedBlksHit, &res.SharedBlksRead)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to scan %s", query)
+		}
+		ret = append(ret, &res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate %s", query)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,31 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSNConnString(t *testing.T) {
+	dsn := &DSN{
+		Host:         "127.0.0.1",
+		Port:         "5432",
+		User:         "admin@corp",
+		Password:     "p@ss/w:rd#1%",
+		DatabaseName: "db 1",
+	}
+	cfg, err := pgx.ParseConfig(dsn.connString())
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.Host)
+	assert.Equal(t, uint16(5432), cfg.Port)
+	assert.Equal(t, "admin@corp", cfg.User)
+	assert.Equal(t, "p@ss/w:rd#1%", cfg.Password)
+	assert.Equal(t, "db 1", cfg.Database)
+
+	// the IPv6 host is bracketed.
+	dsn.Host = "::1"
+	cfg, err = pgx.ParseConfig(dsn.connString())
+	assert.NoError(t, err)
+	assert.Equal(t, "::1", cfg.Host)
+}
@@ -0,0 +1,69 @@
+package postgresql
+
+import "fmt"
+
+// PgStatStatement ref to https://www.postgresql.org/docs/current/pgstatstatements.html
+type PgStatStatement struct {
+	Query          string  `json:"query"`
+	Calls          int64   `json:"calls"`
+	TotalExecTime  float64 `json:"total_exec_time"`
+	MeanExecTime   float64 `json:"mean_exec_time"`
+	Rows           int64   `json:"rows"`
+	SharedBlksHit  int64   `json:"shared_blks_hit"`
+	SharedBlksRead int64   `json:"shared_blks_read"`
+}
+
+// Note:
+// pg_stat_statements renamed "total_time" and "mean_time" to "total_exec_time" and
+// "mean_exec_time" since PostgreSQL 13, so the time columns are chosen by server version.
+const (
+	PgStatStatementsTpl = `
+	SELECT
+		s.query,
+		s.calls,
+		s.%[1]v AS total_exec_time,
+		s.%[2]v AS mean_exec_time,
+		s.rows,
+		s.shared_blks_hit,
+		s.shared_blks_read
+	FROM
+		pg_stat_statements s
+	JOIN
+		pg_database d ON d.oid = s.dbid
+	WHERE
+		s.calls > 0 AND ($1::text = '' OR d.datname = $1::text)
+	ORDER BY %[3]v DESC
+	LIMIT %[4]v
+	`
+	PgStatStatementsColumnCalls          = "calls"
+	PgStatStatementsColumnTotalExecTime  = "total_exec_time"
+	PgStatStatementsColumnMeanExecTime   = "mean_exec_time"
+	PgStatStatementsColumnRows           = "rows"
+	PgStatStatementsColumnSharedBlksHit  = "shared_blks_hit"
+	PgStatStatementsColumnSharedBlksRead = "shared_blks_read"
+
+	// serverVersionNumPG13 is the "server_version_num" of PostgreSQL 13.
+	serverVersionNumPG13 = 130000
+)
+
+var pgStatStatementsOrderByColumns = map[string]struct{}{
+	PgStatStatementsColumnCalls:          {},
+	PgStatStatementsColumnTotalExecTime:  {},
+	PgStatStatementsColumnMeanExecTime:   {},
+	PgStatStatementsColumnRows:           {},
+	PgStatStatementsColumnSharedBlksHit:  {},
+	PgStatStatementsColumnSharedBlksRead: {},
+}
+
+// buildTopSQLsQuery returns the query of top SQLs for the given server version.
+// orderBy must be one of the PgStatStatementsColumn* values.
+func buildTopSQLsQuery(serverVersionNum int, topN int, orderBy string) (string, error) {
+	if _, ok := pgStatStatementsOrderByColumns[orderBy]; !ok {
+		return "", fmt.Errorf("order by column %v is not supported", orderBy)
+	}
+	totalTimeColumn, meanTimeColumn := "total_exec_time", "mean_exec_time"
+	if serverVersionNum < serverVersionNumPG13 {
+		totalTimeColumn, meanTimeColumn = "total_time", "mean_time"
+	}
+	return fmt.Sprintf(PgStatStatementsTpl, totalTimeColumn, meanTimeColumn, orderBy, topN), nil
+}
@@ -0,0 +1,27 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTopSQLsQuery(t *testing.T) {
+	query, err := buildTopSQLsQuery(140005, 10, PgStatStatementsColumnMeanExecTime)
+	assert.NoError(t, err)
+	assert.Contains(t, query, "s.total_exec_time AS total_exec_time")
+	assert.Contains(t, query, "s.mean_exec_time AS mean_exec_time")
+	assert.Contains(t, query, "ORDER BY mean_exec_time DESC")
+	assert.Contains(t, query, "LIMIT 10")
+
+	query, err = buildTopSQLsQuery(120010, 3, PgStatStatementsColumnSharedBlksRead)
+	assert.NoError(t, err)
+	assert.Contains(t, query, "s.total_time AS total_exec_time")
+	assert.Contains(t, query, "s.mean_time AS mean_exec_time")
+	assert.Contains(t, query, "ORDER BY shared_blks_read DESC")
+	assert.False(t, strings.Contains(query, "s.mean_exec_time"))
+
+	_, err = buildTopSQLsQuery(140005, 10, "1; DROP TABLE t")
+	assert.Error(t, err)
+}
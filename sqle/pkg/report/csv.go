@@ -0,0 +1,24 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+// CSV exports the SQLs of report, the summary is not included since it's hard
+// to be represented in a table.
+func (r *Report) CSV() ([]byte, error) {
+	buff := &bytes.Buffer{}
+	buff.WriteString("\xEF\xBB\xBF") // 写入UTF-8 BOM
+	cw := csv.NewWriter(buff)
+	if err := cw.Write(r.Columns); err != nil {
+		return nil, err
+	}
+	for _, row := range r.Rows {
+		if err := cw.Write(row.Values); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+	return buff.Bytes(), cw.Error()
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var htmlTpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Helvetica Neue", Arial, sans-serif; margin: 24px; color: #333; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 24px; }
+th, td { border: 1px solid #ddd; padding: 6px 8px; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+td { white-space: pre-wrap; word-break: break-all; }
+.summary { width: auto; }
+.level-error { color: #f5222d; }
+.level-warn { color: #fa8c16; }
+.level-notice { color: #1890ff; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<h2>汇总</h2>
+<table class="summary">
+<tr><th>审核评分</th><td>{{ .Score }}</td></tr>
+<tr><th>审核通过率</th><td>{{ .PassRate }}</td></tr>
+<tr><th>审核结果等级</th><td class="level-{{ .AuditLevel }}">{{ .AuditLevel }}</td></tr>
+</table>
+<h2>审核结果等级分布</h2>
+<table class="summary">
+<tr><th>等级</th><th>SQL数</th></tr>
+{{- range .Histogram }}
+<tr><td class="level-{{ .Level }}">{{ .Level }}</td><td>{{ .Count }}</td></tr>
+{{- end }}
+</table>
+<h2>SQL</h2>
+<table>
+<tr>{{ range .Columns }}<th>{{ . }}</th>{{ end }}</tr>
+{{- range .Rows }}
+<tr class="level-{{ .AuditLevel }}">{{ range .Values }}<td>{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// HTML exports the report to a standalone HTML file, the summary is followed
+// by the SQLs.
+func (r *Report) HTML() ([]byte, error) {
+	buff := &bytes.Buffer{}
+	err := htmlTpl.Execute(buff, map[string]interface{}{
+		"Title":      r.Title,
+		"Score":      r.Score,
+		"PassRate":   formatPassRate(r.PassRate),
+		"AuditLevel": r.AuditLevel,
+		"Histogram":  r.LevelHistogram(),
+		"Columns":    r.Columns,
+		"Rows":       r.Rows,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+// Package report exports the audit report of task and audit plan to the file
+// which can be downloaded, e.g. CSV, XLSX and HTML.
+package report
+
+import (
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatHTML Format = "html"
+)
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/csv"
+	}
+}
+
+// Report is the audit report to be exported. Each row is a SQL of the report,
+// the values of row are corresponding to the columns.
+type Report struct {
+	Title      string
+	Score      int32
+	PassRate   float64
+	AuditLevel string
+	Columns    []string
+	Rows       []*Row
+}
+
+type Row struct {
+	// AuditLevel is the highest rule level of the SQL, it's used to count the
+	// level histogram.
+	AuditLevel string
+	Values     []string
+}
+
+type LevelCount struct {
+	Level string
+	Count int
+}
+
+var histogramLevels = []driver.RuleLevel{
+	driver.RuleLevelError,
+	driver.RuleLevelWarn,
+	driver.RuleLevelNotice,
+	driver.RuleLevelNormal,
+}
+
+// LevelHistogram counts the SQLs by audit level, from error to normal. The SQL
+// without audit level is counted as normal.
+func (r *Report) LevelHistogram() []LevelCount {
+	counts := map[driver.RuleLevel]int{}
+	for _, row := range r.Rows {
+		level := driver.RuleLevel(row.AuditLevel)
+		if level == driver.RuleLevelNull {
+			level = driver.RuleLevelNormal
+		}
+		counts[level]++
+	}
+	histogram := make([]LevelCount, 0, len(histogramLevels))
+	for _, level := range histogramLevels {
+		histogram = append(histogram, LevelCount{Level: string(level), Count: counts[level]})
+	}
+	return histogram
+}
+
+func (r *Report) Export(format Format) ([]byte, error) {
+	switch format {
+	case FormatCSV, "":
+		return r.CSV()
+	case FormatXLSX:
+		return r.XLSX()
+	case FormatHTML:
+		return r.HTML()
+	default:
+		return nil, fmt.Errorf("export format %s is not supported", format)
+	}
+}
+
+func (r *Report) summary() [][]string {
+	summary := [][]string{
+		{"审核评分", fmt.Sprintf("%d", r.Score)},
+		{"审核通过率", formatPassRate(r.PassRate)},
+		{"审核结果等级", r.AuditLevel},
+	}
+	for _, lc := range r.LevelHistogram() {
+		summary = append(summary, []string{fmt.Sprintf("%s级别SQL数", lc.Level), fmt.Sprintf("%d", lc.Count)})
+	}
+	return summary
+}
+
+func formatPassRate(passRate float64) string {
+	return fmt.Sprintf("%.2f%%", passRate*100)
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	return &Report{
+		Title:      "test report",
+		Score:      60,
+		PassRate:   0.5,
+		AuditLevel: "error",
+		Columns:    []string{"序号", "SQL", "SQL审核结果"},
+		Rows: []*Row{
+			{AuditLevel: "error", Values: []string{"1", "select * from t1 where a < 1", "[error]xxx"}},
+			{AuditLevel: "", Values: []string{"2", "select 1", ""}},
+		},
+	}
+}
+
+func TestLevelHistogram(t *testing.T) {
+	assert.Equal(t, []LevelCount{
+		{Level: "error", Count: 1},
+		{Level: "warn", Count: 0},
+		{Level: "notice", Count: 0},
+		{Level: "normal", Count: 1},
+	}, newTestReport().LevelHistogram())
+}
+
+func TestExportCSV(t *testing.T) {
+	content, err := newTestReport().Export(FormatCSV)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(content, []byte("\xEF\xBB\xBF")))
+
+	records, err := csv.NewReader(bytes.NewReader(content[3:])).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"序号", "SQL", "SQL审核结果"},
+		{"1", "select * from t1 where a < 1", "[error]xxx"},
+		{"2", "select 1", ""},
+	}, records)
+}
+
+func TestExportXLSX(t *testing.T) {
+	content, err := newTestReport().Export(FormatXLSX)
+	assert.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		b, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="汇总" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<t xml:space="preserve">50.00%</t>`)
+	assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<c r="B2" t="inlineStr"><is><t xml:space="preserve">select * from t1 where a &lt; 1</t></is></c>`)
+}
+
+func TestExportHTML(t *testing.T) {
+	content, err := newTestReport().Export(FormatHTML)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "<title>test report</title>")
+	assert.Contains(t, string(content), "<td>50.00%</td>")
+	assert.Contains(t, string(content), `<tr><td class="level-error">error</td><td>1</td></tr>`)
+	assert.Contains(t, string(content), "<td>select * from t1 where a &lt; 1</td>")
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	_, err := newTestReport().Export("pdf")
+	assert.Error(t, err)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
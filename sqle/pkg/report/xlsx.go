@@ -0,0 +1,134 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XLSX exports the report to a workbook with two sheets, the summary and the
+// SQLs. It only writes the minimal parts of Office Open XML which are required
+// by the spreadsheet applications, the cells are all inline strings.
+func (r *Report) XLSX() ([]byte, error) {
+	sheets := []struct {
+		name string
+		rows [][]string
+	}{
+		{name: "汇总", rows: r.summary()},
+		{name: "SQL", rows: r.table()},
+	}
+
+	buff := &bytes.Buffer{}
+	zw := zip.NewWriter(buff)
+	write := func(name, content string) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, content)
+		return err
+	}
+
+	contentTypes := &strings.Builder{}
+	workbook := &strings.Builder{}
+	workbookRels := &strings.Builder{}
+	for i, sheet := range sheets {
+		fmt.Fprintf(contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.name), i+1, i+1)
+		fmt.Fprintf(workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	parts := []xlsxPart{
+		{
+			name: "[Content_Types].xml",
+			content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+				`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+				`<Default Extension="xml" ContentType="application/xml"/>` +
+				`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+				contentTypes.String() + `</Types>`,
+		},
+		{
+			name: "_rels/.rels",
+			content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+				`</Relationships>`,
+		},
+		{
+			name: "xl/workbook.xml",
+			content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets>` + workbook.String() + `</sheets></workbook>`,
+		},
+		{
+			name: "xl/_rels/workbook.xml.rels",
+			content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				workbookRels.String() + `</Relationships>`,
+		},
+	}
+	for i, sheet := range sheets {
+		parts = append(parts, xlsxPart{
+			name:    fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			content: worksheetXML(sheet.rows),
+		})
+	}
+
+	for _, part := range parts {
+		if err := write(part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+type xlsxPart struct {
+	name    string
+	content string
+}
+
+func (r *Report) table() [][]string {
+	rows := make([][]string, 0, len(r.Rows)+1)
+	rows = append(rows, r.Columns)
+	for _, row := range r.Rows {
+		rows = append(rows, row.Values)
+	}
+	return rows
+}
+
+func worksheetXML(rows [][]string) string {
+	sb := &strings.Builder{}
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(sb, `<row r="%d">`, i+1)
+		for j, value := range row {
+			fmt.Fprintf(sb, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+				columnName(j), i+1, escapeXML(value))
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+// columnName converts the zero-based column index to the column name, e.g.
+// 0 -> "A", 25 -> "Z", 26 -> "AA".
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	sb := &strings.Builder{}
+	// EscapeText only fails if the writer fails.
+	_ = xml.EscapeText(sb, []byte(s))
+	return sb.String()
+}
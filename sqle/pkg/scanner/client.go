@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	sqleErr "github.com/actiontech/sqle/sqle/errors"
 
 	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
 	v2 "github.com/actiontech/sqle/sqle/api/controller/v2"
@@ -31,17 +33,57 @@ const (
 	PartialUpload = "/v1/audit_plans/%s/sqls/partial"
 	// Get										%v=report_id
 	GetAuditReport = "/v2/audit_plans/%s/report/%v/?page_index=%d&page_size=%d"
+	// Post
+	DirectAudit = "/v1/sql_audit"
 )
 
 type (
 	BaseRes                     = controller.BaseRes
 	GetAuditPlanReportSQLsRes   = v2.GetAuditPlanReportSQLsResV2
+	AuditPlanReportSQLRes       = v2.AuditPlanReportSQLResV2
+	DirectAuditReq              = v1.DirectAuditReqV1
+	DirectAuditRes              = v1.DirectAuditResV1
+	AuditResData                = v1.AuditResDataV1
 	AuditPlanSQLReq             = v1.AuditPlanSQLReqV1
 	FullSyncAuditPlanSQLsReq    = v1.FullSyncAuditPlanSQLsReqV1
 	PartialSyncAuditPlanSQLsReq = v1.PartialSyncAuditPlanSQLsReqV1
 	TriggerAuditPlanRes         = v1.TriggerAuditPlanResV1
 )
 
+// RejectedError is returned when the request is rejected by SQLE, e.g. the
+// audit plan is deleted or the request is invalid. Unlike the network error and
+// the server error, sending the same request again won't succeed.
+type RejectedError struct {
+	Err error
+}
+
+func (e *RejectedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// IsRejected reports whether err is caused by RejectedError.
+func IsRejected(err error) bool {
+	var rejected *RejectedError
+	return errors.As(err, &rejected)
+}
+
+// checkBaseRes returns the error of the response. The errors caused by the
+// request, whose code are in [2000, 5000), are RejectedError.
+func checkBaseRes(url string, res BaseRes) error {
+	if res.Code == 0 {
+		return nil
+	}
+	err := fmt.Errorf("failed to request %s, error:%s", url, res.Message)
+	if res.Code >= int(sqleErr.HttpRequestFormatError) && res.Code < int(sqleErr.ConnectStorageError) {
+		return &RejectedError{Err: err}
+	}
+	return err
+}
+
 type Client struct {
 	baseURL    string
 	httpClient *client
@@ -90,10 +132,7 @@ func (sc *Client) UploadReq(uri string, auditPlanName string, sqlList []AuditPla
 	if err != nil {
 		return err
 	}
-	if baseRes.Code != 0 {
-		return fmt.Errorf("failed to request %s, error:%s", url, baseRes.Message)
-	}
-	return nil
+	return checkBaseRes(url, *baseRes)
 }
 
 func (sc *Client) TriggerAuditReq(auditPlanName string) (string, error) {
@@ -109,8 +148,8 @@ func (sc *Client) TriggerAuditReq(auditPlanName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if triggerRes.Code != 0 {
-		return "", fmt.Errorf("failed to request %s, error:%s", url, triggerRes.Message)
+	if err := checkBaseRes(url, triggerRes.BaseRes); err != nil {
+		return "", err
 	}
 	return triggerRes.Data.Id, nil
 }
@@ -154,6 +193,64 @@ func (sc *Client) GetAuditReportReq(auditPlanName string, reportID string) error
 	return nil
 }
 
+// GetAuditReportSQLsReq returns all SQLs of the audit plan report.
+func (sc *Client) GetAuditReportSQLsReq(auditPlanName string, reportID string) ([]AuditPlanReportSQLRes, error) {
+	var sqls []AuditPlanReportSQLRes
+	var pageIndex, pageSize uint64 = 1, 100
+	for {
+		url := sc.baseURL + fmt.Sprintf(GetAuditReport, auditPlanName, reportID, pageIndex, pageSize)
+		resBody, err := sc.httpClient.sendRequest(context.TODO(), url, http.MethodGet, sc.token, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		auditRes := new(GetAuditPlanReportSQLsRes)
+		err = json.Unmarshal(resBody, auditRes)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBaseRes(url, auditRes.BaseRes); err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, auditRes.Data...)
+		if pageIndex*pageSize >= auditRes.TotalNums || len(auditRes.Data) == 0 {
+			return sqls, nil
+		}
+		pageIndex++
+	}
+}
+
+// DirectAuditReq audits the SQLs with the default rule template of instance type,
+// the SQLs are not saved in SQLE.
+func (sc *Client) DirectAuditReq(instanceType string, sqlContent string) (*AuditResData, error) {
+	bodyBuf := &bytes.Buffer{}
+	encoder := json.NewEncoder(bodyBuf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(&DirectAuditReq{
+		InstanceType: instanceType,
+		SQLContent:   sqlContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := sc.baseURL + DirectAudit
+	resBody, err := sc.httpClient.sendRequest(context.TODO(), url, http.MethodPost, sc.token, bodyBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRes := new(DirectAuditRes)
+	err = json.Unmarshal(resBody, auditRes)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBaseRes(url, auditRes.BaseRes); err != nil {
+		return nil, err
+	}
+	return auditRes.Data, nil
+}
+
 const (
 	DefaultTimeout = time.Second * 10
 )
@@ -232,14 +329,20 @@ func (c *client) post(ctx context.Context, url, token string, body io.Reader) ([
 	return checkHTTPResponse(res)
 }
 
-// checkHTTPResponse checks if an HTTP response is with normal status codes
+// checkHTTPResponse checks if an HTTP response is with normal status codes,
+// the client error except timeout and rate limit is RejectedError.
 func checkHTTPResponse(res *http.Response) ([]byte, error) {
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
 	}
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		return body, fmt.Errorf("error requesting %s, response: %s, code %d", res.Request.URL, string(body), res.StatusCode)
+		err := fmt.Errorf("error requesting %s, response: %s, code %d", res.Request.URL, string(body), res.StatusCode)
+		if res.StatusCode < http.StatusInternalServerError &&
+			res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+			return body, &RejectedError{Err: err}
+		}
+		return body, err
 	}
 	return body, nil
 }
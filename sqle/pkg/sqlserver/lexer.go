@@ -0,0 +1,204 @@
+package sqlserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	// tokenVariable is the local variable such as "@id", and the system
+	// function such as "@@ROWCOUNT".
+	tokenVariable
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	typ tokenType
+	// text is the raw text, the quotes are kept.
+	text string
+	// start and end are the offsets of the token in SQL.
+	start int
+	end   int
+}
+
+// upper returns the keyword form of the word.
+func (t token) upper() string {
+	if t.typ != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+func (t token) is(keyword string) bool {
+	return t.upper() == keyword
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.typ == tokenSymbol && t.text == symbol
+}
+
+func isWordStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '#' || c >= 0x80
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '@' || c == '$'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+var multiCharSymbols = []string{"<=", ">=", "<>", "!=", "!<", "!>", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "::"}
+
+// tokenize splits the T-SQL into tokens, the comments and whitespaces are
+// skipped. It returns error only if the string, quoted identifier or comment
+// is not closed.
+func tokenize(sql string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 1
+			}
+			continue
+		case strings.HasPrefix(sql[i:], "/*"):
+			end, err := scanComment(sql, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			continue
+		case (c == 'n' || c == 'N') && i+1 < len(sql) && sql[i+1] == '\'':
+			end, err := scanQuoted(sql, i+1, '\'', '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case c == '\'':
+			end, err := scanQuoted(sql, i, '\'', '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenString, text: sql[start:i], start: start, end: i})
+		case c == '"':
+			end, err := scanQuoted(sql, i, '"', '"')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenQuotedIdentifier, text: sql[start:i], start: start, end: i})
+		case c == '[':
+			end, err := scanQuoted(sql, i, '[', ']')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{typ: tokenQuotedIdentifier, text: sql[start:i], start: start, end: i})
+		case isWordStart(c):
+			for i < len(sql) && isWordChar(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenWord, text: sql[start:i], start: start, end: i})
+		case c == '@':
+			for i++; i < len(sql) && (isWordChar(sql[i]) || sql[i] == '@'); i++ {
+			}
+			tokens = append(tokens, token{typ: tokenVariable, text: sql[start:i], start: start, end: i})
+		case isDigit(c) || c == '.' && i+1 < len(sql) && isDigit(sql[i+1]):
+			i = scanNumber(sql, i)
+			tokens = append(tokens, token{typ: tokenNumber, text: sql[start:i], start: start, end: i})
+		default:
+			i++
+			for _, symbol := range multiCharSymbols {
+				if strings.HasPrefix(sql[start:], symbol) {
+					i = start + len(symbol)
+					break
+				}
+			}
+			tokens = append(tokens, token{typ: tokenSymbol, text: sql[start:i], start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
+
+// scanComment returns the end of the block comment which starts at i, the
+// block comments can be nested in T-SQL.
+func scanComment(sql string, i int) (int, error) {
+	depth := 0
+	for j := i; j+1 < len(sql); {
+		switch {
+		case sql[j] == '/' && sql[j+1] == '*':
+			depth++
+			j += 2
+		case sql[j] == '*' && sql[j+1] == '/':
+			depth--
+			j += 2
+			if depth == 0 {
+				return j, nil
+			}
+		default:
+			j++
+		}
+	}
+	return 0, fmt.Errorf("comment at offset %v is not closed", i)
+}
+
+// scanQuoted returns the end of the quoted text which starts at i, the
+// closing quote is escaped by doubling it.
+func scanQuoted(sql string, i int, open, close byte) (int, error) {
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] != close {
+			continue
+		}
+		if j+1 < len(sql) && sql[j+1] == close {
+			j++
+			continue
+		}
+		return j + 1, nil
+	}
+	return 0, fmt.Errorf("quoted text %c at offset %v is not closed", open, i)
+}
+
+func scanNumber(sql string, i int) int {
+	// the binary constant, such as 0x1F.
+	if strings.HasPrefix(sql[i:], "0x") || strings.HasPrefix(sql[i:], "0X") {
+		i += 2
+		for i < len(sql) && (isDigit(sql[i]) || sql[i] >= 'a' && sql[i] <= 'f' || sql[i] >= 'A' && sql[i] <= 'F') {
+			i++
+		}
+		return i
+	}
+	for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.') {
+		i++
+	}
+	if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+		j := i + 1
+		if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+			j++
+		}
+		if j < len(sql) && isDigit(sql[j]) {
+			i = j
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
@@ -0,0 +1,641 @@
+package sqlserver
+
+import (
+	"strings"
+)
+
+type StatementKind string
+
+const (
+	KindSelect      StatementKind = "SELECT"
+	KindInsert      StatementKind = "INSERT"
+	KindUpdate      StatementKind = "UPDATE"
+	KindDelete      StatementKind = "DELETE"
+	KindMerge       StatementKind = "MERGE"
+	KindCreateTable StatementKind = "CREATE TABLE"
+	KindCreateIndex StatementKind = "CREATE INDEX"
+	// KindCreate is the CREATE statement of the other objects, such as
+	// procedure and view.
+	KindCreate   StatementKind = "CREATE"
+	KindAlter    StatementKind = "ALTER"
+	KindDrop     StatementKind = "DROP"
+	KindTruncate StatementKind = "TRUNCATE"
+	KindDeclare  StatementKind = "DECLARE"
+	KindSet      StatementKind = "SET"
+	KindOther    StatementKind = "OTHER"
+)
+
+// ObjectName is the multi-part name of table or index, the brackets and
+// quotes are removed.
+type ObjectName struct {
+	Database string
+	Schema   string
+	Name     string
+}
+
+func (n ObjectName) String() string {
+	parts := []string{}
+	for _, part := range []string{n.Database, n.Schema, n.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// IsTemporary returns true if the table is the local or global temporary
+// table, such as "#t" and "##t".
+func (n ObjectName) IsTemporary() bool {
+	return strings.HasPrefix(n.Name, "#")
+}
+
+// Statement is the result of parsing one statement in the batch, it keeps the
+// information which the rules need instead of the full syntax tree.
+type Statement struct {
+	Text string
+	Kind StatementKind
+
+	// Table is the target table of DML and DDL, or the table of the created
+	// index.
+	Table ObjectName
+	// Index is the created index.
+	Index ObjectName
+	// Clustered is true if the created table has the clustered index, or the
+	// created index is clustered.
+	Clustered       bool
+	MemoryOptimized bool
+
+	// Tables are the tables referenced by DML.
+	Tables []ObjectName
+	// Top and Where are true if DML has TOP clause and WHERE clause.
+	Top   bool
+	Where bool
+	// TableHints are the hints which read the uncommitted data, such as NOLOCK.
+	TableHints []string
+	// Cursor is true if the statement declares a cursor.
+	Cursor bool
+	// ReadUncommitted is true if the statement sets the isolation level to
+	// READ UNCOMMITTED.
+	ReadUncommitted bool
+}
+
+func (s *Statement) IsDML() bool {
+	switch s.Kind {
+	case KindSelect, KindInsert, KindUpdate, KindDelete, KindMerge:
+		return true
+	}
+	return false
+}
+
+func (s *Statement) IsDDL() bool {
+	switch s.Kind {
+	case KindCreateTable, KindCreateIndex, KindCreate, KindAlter, KindDrop, KindTruncate:
+		return true
+	}
+	return false
+}
+
+// Batch is the statements between two "GO" commands, which are sent to SQL
+// Server together.
+type Batch struct {
+	Text       string
+	Statements []*Statement
+}
+
+// IsDDL returns true if any statement in the batch is DDL.
+func (b *Batch) IsDDL() bool {
+	for _, stmt := range b.Statements {
+		if stmt.IsDDL() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDML returns true if any statement in the batch is DML.
+func (b *Batch) HasDML() bool {
+	for _, stmt := range b.Statements {
+		if stmt.IsDML() {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses the batch split by SplitBatches. T-SQL doesn't require
+// semicolons between statements, so the statements are split by the keywords
+// which start a statement.
+func Parse(batch string) (*Batch, error) {
+	tokens, err := tokenize(batch)
+	if err != nil {
+		return nil, err
+	}
+	b := &Batch{Text: batch, Statements: []*Statement{}}
+	for _, stmtTokens := range splitStatements(tokens) {
+		b.Statements = append(b.Statements, parseStatement(batch, stmtTokens))
+	}
+	return b, nil
+}
+
+// statementKeywords are the keywords which may start a statement.
+var statementKeywords = map[string]struct{}{
+	"SELECT": {}, "INSERT": {}, "UPDATE": {}, "DELETE": {}, "MERGE": {}, "CREATE": {}, "ALTER": {}, "DROP": {},
+	"TRUNCATE": {}, "DECLARE": {}, "SET": {}, "EXEC": {}, "EXECUTE": {}, "PRINT": {}, "RETURN": {}, "USE": {},
+	"GRANT": {}, "REVOKE": {}, "DENY": {}, "IF": {}, "ELSE": {}, "WHILE": {}, "BEGIN": {}, "END": {}, "OPEN": {},
+	"FETCH": {}, "CLOSE": {}, "DEALLOCATE": {}, "COMMIT": {}, "ROLLBACK": {}, "SAVE": {}, "THROW": {},
+	"RAISERROR": {}, "WAITFOR": {}, "GOTO": {}, "BREAK": {}, "CONTINUE": {}, "BULK": {}, "DBCC": {},
+	"BACKUP": {}, "RESTORE": {}, "KILL": {}, "CHECKPOINT": {}, "RECONFIGURE": {}, "REVERT": {},
+}
+
+var dmlKeywords = map[string]struct{}{
+	"SELECT": {}, "INSERT": {}, "UPDATE": {}, "DELETE": {}, "MERGE": {},
+}
+
+// droppableObjects are the objects in "DROP ... IF EXISTS", they are not
+// dropped by ALTER TABLE.
+var droppableObjects = map[string]struct{}{
+	"TABLE": {}, "VIEW": {}, "PROCEDURE": {}, "PROC": {}, "FUNCTION": {}, "TRIGGER": {}, "INDEX": {},
+	"DATABASE": {}, "SCHEMA": {}, "TYPE": {}, "SEQUENCE": {}, "SYNONYM": {}, "USER": {}, "ROLE": {},
+	"LOGIN": {}, "STATISTICS": {}, "DEFAULT": {}, "RULE": {},
+}
+
+// statementState is the state of the statement being split.
+type statementState struct {
+	// first is the first keyword, such as "SELECT" and "WITH".
+	first string
+	// object is the object type of CREATE and ALTER, such as "VIEW".
+	object string
+	// main is the DML keyword after the common table expressions.
+	main string
+	// values is true if INSERT has the VALUES clause.
+	values bool
+}
+
+func (s *statementState) kind() string {
+	if s.first == "WITH" {
+		return s.main
+	}
+	return s.first
+}
+
+func (s *statementState) observe(t token, depth int) {
+	switch {
+	case s.first == "":
+		s.first = t.upper()
+	case (s.first == "CREATE" || s.first == "ALTER") && s.object == "":
+		if kw := t.upper(); kw != "OR" && kw != "ALTER" {
+			s.object = kw
+		}
+	}
+	if depth != 0 {
+		return
+	}
+	if _, ok := dmlKeywords[t.upper()]; ok && s.first == "WITH" && s.main == "" {
+		s.main = t.upper()
+	}
+	if t.is("VALUES") {
+		s.values = true
+	}
+}
+
+// startsStatement returns true if the i-th token, which isn't the first token
+// of the current statement, starts a new statement.
+func startsStatement(tokens []token, i int, s *statementState, caseDepth int) bool {
+	t := tokens[i]
+	kw := t.upper()
+	if _, ok := statementKeywords[kw]; !ok {
+		return false
+	}
+	prev := tokens[i-1]
+	var next token
+	if i+1 < len(tokens) {
+		next = tokens[i+1]
+	}
+	// the permission list of GRANT, and the events of trigger.
+	if prev.is("GRANT") || prev.is("REVOKE") || prev.is("DENY") || prev.isSymbol(",") || prev.isSymbol(".") {
+		return false
+	}
+
+	kind := s.kind()
+	switch kw {
+	case "SELECT":
+		switch prev.upper() {
+		case "UNION", "ALL", "EXCEPT", "INTERSECT", "FOR", "RETURN":
+			return false
+		case "AS":
+			return s.object != "VIEW"
+		}
+		if s.first == "WITH" && s.main == "" {
+			return false
+		}
+		return kind != "INSERT" || s.values
+	case "INSERT", "UPDATE", "DELETE":
+		switch prev.upper() {
+		case "THEN", "FOR", "AFTER", "OF", "ON", "BULK":
+			return false
+		}
+		// the UPDATE() function in trigger.
+		if kw == "UPDATE" && next.isSymbol("(") {
+			return false
+		}
+		return !(s.first == "WITH" && s.main == "")
+	case "MERGE":
+		return !(s.first == "WITH" && s.main == "")
+	case "SET":
+		// ON DELETE SET NULL
+		if prev.is("DELETE") || prev.is("UPDATE") {
+			return false
+		}
+		switch kind {
+		case "UPDATE", "MERGE", "ALTER":
+			return false
+		}
+	case "ALTER":
+		return !(kind == "ALTER" && next.is("COLUMN"))
+	case "DROP":
+		if kind == "ALTER" {
+			_, ok := droppableObjects[next.upper()]
+			return ok
+		}
+	case "EXEC", "EXECUTE":
+		// EXECUTE AS in the module, and INSERT ... EXEC.
+		return !prev.is("WITH") && kind != "INSERT"
+	case "END", "ELSE":
+		return caseDepth == 0
+	case "FETCH":
+		// OFFSET ... ROWS FETCH NEXT ... ROWS ONLY
+		return !prev.is("ROWS") && !prev.is("ROW")
+	case "ROLLBACK":
+		// ALTER DATABASE ... SET SINGLE_USER WITH ROLLBACK IMMEDIATE
+		return !prev.is("WITH")
+	case "IF":
+		// DROP TABLE IF EXISTS
+		_, ok := droppableObjects[prev.upper()]
+		return !ok && !prev.is("COLUMN") && !prev.is("CONSTRAINT")
+	}
+	return true
+}
+
+// splitStatements splits the tokens of the batch into statements. It's based
+// on the keywords instead of the full grammar, the statements are split as
+// SQL Server does in most cases.
+func splitStatements(tokens []token) [][]token {
+	statements := [][]token{}
+	start := -1
+	depth, caseDepth := 0, 0
+	state := &statementState{}
+	end := func(i int) {
+		if start >= 0 && i > start {
+			statements = append(statements, tokens[start:i])
+		}
+		start = -1
+	}
+	for i, t := range tokens {
+		if t.isSymbol(";") && depth == 0 {
+			end(i)
+			continue
+		}
+		if start < 0 || depth == 0 && startsStatement(tokens, i, state, caseDepth) {
+			end(i)
+			start = i
+			depth, caseDepth = 0, 0
+			state = &statementState{}
+		}
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")") && depth > 0:
+			depth--
+		case t.is("CASE"):
+			caseDepth++
+		case t.is("END") && caseDepth > 0:
+			caseDepth--
+		}
+		state.observe(t, depth)
+	}
+	end(len(tokens))
+	return statements
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{typ: tokenSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the keyword if it's the next token.
+func (p *parser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func identifier(t token) (string, bool) {
+	switch t.typ {
+	case tokenWord:
+		return t.text, true
+	case tokenQuotedIdentifier:
+		quote := t.text[len(t.text)-1:]
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], quote+quote, quote), true
+	}
+	return "", false
+}
+
+// objectName parses the name like "db.schema.table" and "db..table", the
+// name is empty if the next token isn't identifier.
+func (p *parser) objectName() ObjectName {
+	parts := []string{}
+	for {
+		part, ok := identifier(p.peek())
+		if ok {
+			p.pos++
+		} else if len(parts) == 0 {
+			return ObjectName{}
+		}
+		parts = append(parts, part)
+		if !p.peek().isSymbol(".") {
+			break
+		}
+		p.pos++
+	}
+	name := ObjectName{Name: parts[len(parts)-1]}
+	if len(parts) >= 2 {
+		name.Schema = parts[len(parts)-2]
+	}
+	if len(parts) >= 3 {
+		name.Database = parts[len(parts)-3]
+	}
+	return name
+}
+
+// skipTop skips "TOP (n) [PERCENT]" of DML.
+func (p *parser) skipTop() bool {
+	if !p.accept("TOP") {
+		return false
+	}
+	if p.peek().isSymbol("(") {
+		depth := 0
+		for p.pos < len(p.tokens) {
+			t := p.next()
+			if t.isSymbol("(") {
+				depth++
+			} else if t.isSymbol(")") {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+	} else {
+		p.next()
+	}
+	p.accept("PERCENT")
+	return true
+}
+
+func (p *parser) parseCreate(stmt *Statement) {
+	for {
+		switch p.peek().upper() {
+		case "TABLE":
+			p.pos++
+			stmt.Kind = KindCreateTable
+			stmt.Table = p.objectName()
+			return
+		case "UNIQUE", "NONCLUSTERED", "COLUMNSTORE":
+			p.pos++
+		case "CLUSTERED":
+			p.pos++
+			stmt.Clustered = true
+		case "INDEX":
+			p.pos++
+			stmt.Kind = KindCreateIndex
+			stmt.Index = p.objectName()
+			if p.accept("ON") {
+				stmt.Table = p.objectName()
+			}
+			return
+		default:
+			stmt.Kind = KindCreate
+			return
+		}
+	}
+}
+
+func parseStatement(batch string, tokens []token) *Statement {
+	stmt := &Statement{
+		Text: batch[tokens[0].start:tokens[len(tokens)-1].end],
+		Kind: KindOther,
+	}
+	p := &parser{tokens: tokens}
+	first := p.next().upper()
+	if first == "WITH" {
+		// the DML after the common table expressions.
+		depth := 0
+		for p.pos < len(tokens) {
+			t := p.next()
+			if t.isSymbol("(") {
+				depth++
+			} else if t.isSymbol(")") {
+				depth--
+			} else if _, ok := dmlKeywords[t.upper()]; ok && depth == 0 {
+				first = t.upper()
+				break
+			}
+		}
+	}
+
+	switch first {
+	case "SELECT":
+		stmt.Kind = KindSelect
+	case "INSERT":
+		stmt.Kind = KindInsert
+		stmt.Top = p.skipTop()
+		p.accept("INTO")
+		stmt.Table = p.objectName()
+	case "UPDATE":
+		if p.peek().is("STATISTICS") {
+			break
+		}
+		stmt.Kind = KindUpdate
+		stmt.Top = p.skipTop()
+		stmt.Table = p.objectName()
+	case "DELETE":
+		stmt.Kind = KindDelete
+		stmt.Top = p.skipTop()
+		p.accept("FROM")
+		stmt.Table = p.objectName()
+	case "MERGE":
+		stmt.Kind = KindMerge
+		stmt.Top = p.skipTop()
+		p.accept("INTO")
+		stmt.Table = p.objectName()
+	case "CREATE":
+		p.parseCreate(stmt)
+	case "ALTER":
+		stmt.Kind = KindAlter
+	case "DROP":
+		stmt.Kind = KindDrop
+	case "TRUNCATE":
+		stmt.Kind = KindTruncate
+	case "DECLARE":
+		stmt.Kind = KindDeclare
+	case "SET":
+		stmt.Kind = KindSet
+	}
+
+	analyzeTokens(stmt, tokens)
+	if stmt.IsDML() {
+		stmt.Tables = referencedTables(tokens)
+	}
+	return stmt
+}
+
+func analyzeTokens(stmt *Statement, tokens []token) {
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+		case t.is("WHERE") && depth == 0:
+			stmt.Where = true
+		case t.is("NOLOCK") || t.is("READUNCOMMITTED"):
+			if depth > 0 && !containsString(stmt.TableHints, t.upper()) {
+				stmt.TableHints = append(stmt.TableHints, t.upper())
+			}
+		case t.is("CURSOR"):
+			// DECLARE c CURSOR FOR ..., and SET @c = CURSOR FOR ...
+			if stmt.Kind == KindDeclare || stmt.Kind == KindSet {
+				stmt.Cursor = true
+			}
+		case t.is("UNCOMMITTED"):
+			// SET TRANSACTION ISOLATION LEVEL READ UNCOMMITTED
+			if stmt.Kind == KindSet && i > 0 && tokens[i-1].is("READ") {
+				stmt.ReadUncommitted = true
+			}
+		case t.is("PRIMARY") && stmt.Kind == KindCreateTable:
+			// the primary key is clustered by default.
+			if i+2 < len(tokens) && tokens[i+1].is("KEY") && !tokens[i+2].is("NONCLUSTERED") {
+				stmt.Clustered = true
+			}
+		case t.is("CLUSTERED") && stmt.Kind == KindCreateTable:
+			stmt.Clustered = true
+		case t.is("MEMORY_OPTIMIZED") && stmt.Kind == KindCreateTable:
+			stmt.MemoryOptimized = true
+		}
+	}
+}
+
+// tableClauseEnds are the keywords which end the table list in FROM clause.
+var tableClauseEnds = map[string]struct{}{
+	"WHERE": {}, "GROUP": {}, "ORDER": {}, "HAVING": {}, "UNION": {}, "EXCEPT": {}, "INTERSECT": {},
+	"FOR": {}, "ON": {}, "SET": {}, "VALUES": {}, "OUTPUT": {}, "WHEN": {}, "OPTION": {},
+	"CROSS": {}, "OUTER": {}, "INNER": {}, "LEFT": {}, "RIGHT": {}, "FULL": {}, "JOIN": {},
+}
+
+// tableHints are the table hints, ref to https://learn.microsoft.com/en-us/sql/t-sql/queries/hints-transact-sql-table
+var tableHints = map[string]struct{}{
+	"NOLOCK": {}, "READUNCOMMITTED": {}, "READCOMMITTED": {}, "READCOMMITTEDLOCK": {}, "REPEATABLEREAD": {},
+	"SERIALIZABLE": {}, "HOLDLOCK": {}, "UPDLOCK": {}, "XLOCK": {}, "ROWLOCK": {}, "PAGLOCK": {}, "TABLOCK": {},
+	"TABLOCKX": {}, "READPAST": {}, "NOWAIT": {}, "INDEX": {}, "FORCESEEK": {}, "FORCESCAN": {}, "SNAPSHOT": {},
+	"NOEXPAND": {}, "KEEPIDENTITY": {}, "KEEPDEFAULTS": {}, "IGNORE_CONSTRAINTS": {}, "IGNORE_TRIGGERS": {},
+}
+
+func referencedTables(tokens []token) []ObjectName {
+	tables := []ObjectName{}
+	// inTableList means the following names after "," are tables, and it's
+	// kept for each depth of brackets.
+	inTableList := map[int]bool{}
+	depth := 0
+	for i, t := range tokens {
+		var prev token
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		switch {
+		case t.isSymbol("("):
+			depth++
+			continue
+		case t.isSymbol(")"):
+			inTableList[depth] = false
+			depth--
+			continue
+		}
+
+		readTable := false
+		switch t.upper() {
+		case "FROM":
+			inTableList[depth] = true
+			readTable = true
+		case "JOIN", "INTO", "USING":
+			readTable = true
+		case "UPDATE", "DELETE", "INSERT", "MERGE":
+			readTable = !prev.is("THEN") && !prev.is("FOR")
+		default:
+			if _, ok := tableClauseEnds[t.upper()]; ok {
+				inTableList[depth] = false
+			} else if t.isSymbol(",") && inTableList[depth] {
+				readTable = true
+			}
+		}
+		if !readTable {
+			continue
+		}
+		p := &parser{tokens: tokens, pos: i + 1}
+		p.skipTop()
+		if p.peek().is("FROM") || p.peek().is("INTO") {
+			continue
+		}
+		name := p.objectName()
+		if name.Name == "" {
+			// the variable and subquery.
+			continue
+		}
+		if p.peek().isSymbol("(") && !t.is("INTO") {
+			// the table-valued function, but the brackets after INTO are
+			// columns, and the table hints can be used without WITH.
+			p.pos++
+			if _, ok := tableHints[p.peek().upper()]; !ok {
+				continue
+			}
+		}
+		if _, ok := tableClauseEnds[strings.ToUpper(name.Name)]; ok {
+			continue
+		}
+		tables = appendTable(tables, name)
+	}
+	return tables
+}
+
+func appendTable(tables []ObjectName, name ObjectName) []ObjectName {
+	for _, t := range tables {
+		if t == name {
+			return tables
+		}
+	}
+	return append(tables, name)
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package sqlserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func statementTexts(b *Batch) []string {
+	texts := []string{}
+	for _, stmt := range b.Statements {
+		texts = append(texts, stmt.Text)
+	}
+	return texts
+}
+
+func TestParse_SplitStatements(t *testing.T) {
+	cases := []struct {
+		sql    string
+		expect []string
+	}{
+		{
+			sql:    "select 1 select 2; select 3 union all select 4",
+			expect: []string{"select 1", "select 2", "select 3 union all select 4"},
+		},
+		{
+			sql: `DECLARE @id INT
+DECLARE c CURSOR LOCAL FOR SELECT id FROM t1 FOR UPDATE OF name
+OPEN c
+FETCH NEXT FROM c INTO @id
+WHILE @@FETCH_STATUS = 0
+BEGIN
+  UPDATE t1 SET name = CASE WHEN id > 1 THEN 'a' ELSE 'b' END WHERE CURRENT OF c
+  FETCH NEXT FROM c INTO @id
+END
+CLOSE c`,
+			expect: []string{
+				"DECLARE @id INT",
+				"DECLARE c CURSOR LOCAL FOR SELECT id FROM t1 FOR UPDATE OF name",
+				"OPEN c",
+				"FETCH NEXT FROM c INTO @id",
+				"WHILE @@FETCH_STATUS = 0",
+				"BEGIN",
+				"UPDATE t1 SET name = CASE WHEN id > 1 THEN 'a' ELSE 'b' END WHERE CURRENT OF c",
+				"FETCH NEXT FROM c INTO @id",
+				"END",
+				"CLOSE c",
+			},
+		},
+		{
+			sql: `;WITH cte AS (SELECT id FROM t1) DELETE FROM cte WHERE id > 1
+INSERT INTO t2 (id) SELECT id FROM t1
+INSERT INTO t2 EXEC p1
+MERGE t2 USING t1 ON t1.id = t2.id WHEN MATCHED THEN UPDATE SET t2.id = t1.id WHEN NOT MATCHED THEN INSERT (id) VALUES (t1.id);`,
+			expect: []string{
+				"WITH cte AS (SELECT id FROM t1) DELETE FROM cte WHERE id > 1",
+				"INSERT INTO t2 (id) SELECT id FROM t1",
+				"INSERT INTO t2 EXEC p1",
+				"MERGE t2 USING t1 ON t1.id = t2.id WHEN MATCHED THEN UPDATE SET t2.id = t1.id WHEN NOT MATCHED THEN INSERT (id) VALUES (t1.id)",
+			},
+		},
+		{
+			sql: `DROP TABLE IF EXISTS t1
+ALTER TABLE t2 ALTER COLUMN name NVARCHAR(100)
+ALTER TABLE t2 DROP COLUMN age
+ALTER TABLE t2 ADD CONSTRAINT fk FOREIGN KEY (pid) REFERENCES t3 (id) ON DELETE SET NULL
+DROP TABLE t4
+GRANT SELECT, INSERT, UPDATE ON t2 TO u1
+SELECT id FROM t2 ORDER BY id OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY`,
+			expect: []string{
+				"DROP TABLE IF EXISTS t1",
+				"ALTER TABLE t2 ALTER COLUMN name NVARCHAR(100)",
+				"ALTER TABLE t2 DROP COLUMN age",
+				"ALTER TABLE t2 ADD CONSTRAINT fk FOREIGN KEY (pid) REFERENCES t3 (id) ON DELETE SET NULL",
+				"DROP TABLE t4",
+				"GRANT SELECT, INSERT, UPDATE ON t2 TO u1",
+				"SELECT id FROM t2 ORDER BY id OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY",
+			},
+		},
+		{
+			sql: `CREATE TRIGGER tr ON t1 AFTER INSERT, UPDATE AS
+IF UPDATE(name) PRINT 'changed'`,
+			expect: []string{
+				"CREATE TRIGGER tr ON t1 AFTER INSERT, UPDATE AS",
+				"IF UPDATE(name)",
+				"PRINT 'changed'",
+			},
+		},
+		{
+			sql:    "CREATE VIEW v1 WITH SCHEMABINDING AS SELECT id FROM dbo.t1",
+			expect: []string{"CREATE VIEW v1 WITH SCHEMABINDING AS SELECT id FROM dbo.t1"},
+		},
+	}
+	for _, c := range cases {
+		b, err := Parse(c.sql)
+		assert.NoError(t, err, c.sql)
+		assert.Equal(t, c.expect, statementTexts(b), c.sql)
+	}
+}
+
+func TestParse_Statement(t *testing.T) {
+	b, err := Parse(`SELECT a.id FROM [db].dbo.t1 a WITH (NOLOCK), t2 (READUNCOMMITTED) JOIN #t3 ON a.id = #t3.id WHERE a.id = @id`)
+	assert.NoError(t, err)
+	stmt := b.Statements[0]
+	assert.Equal(t, KindSelect, stmt.Kind)
+	assert.Equal(t, []string{"NOLOCK", "READUNCOMMITTED"}, stmt.TableHints)
+	assert.Equal(t, []ObjectName{{Database: "db", Schema: "dbo", Name: "t1"}, {Name: "t2"}, {Name: "#t3"}}, stmt.Tables)
+	assert.True(t, stmt.Where)
+
+	b, err = Parse("DELETE TOP (1000) FROM dbo.t1 WHERE id < 10 UPDATE t2 SET a = (SELECT MAX(id) FROM t1 WHERE id > 1)")
+	assert.NoError(t, err)
+	assert.Equal(t, KindDelete, b.Statements[0].Kind)
+	assert.True(t, b.Statements[0].Top)
+	assert.Equal(t, ObjectName{Schema: "dbo", Name: "t1"}, b.Statements[0].Table)
+	assert.Equal(t, KindUpdate, b.Statements[1].Kind)
+	assert.False(t, b.Statements[1].Top)
+	assert.False(t, b.Statements[1].Where)
+	assert.Equal(t, ObjectName{Name: "t2"}, b.Statements[1].Table)
+
+	b, err = Parse("INSERT INTO t1 (id, name) VALUES (1, N'a')")
+	assert.NoError(t, err)
+	assert.Equal(t, KindInsert, b.Statements[0].Kind)
+	assert.Equal(t, []ObjectName{{Name: "t1"}}, b.Statements[0].Tables)
+
+	b, err = Parse("SET TRANSACTION ISOLATION LEVEL READ UNCOMMITTED; SET @c = CURSOR FOR SELECT id FROM t1")
+	assert.NoError(t, err)
+	assert.True(t, b.Statements[0].ReadUncommitted)
+	assert.True(t, b.Statements[1].Cursor)
+	assert.False(t, b.IsDDL())
+
+	b, err = Parse("UPDATE STATISTICS t1")
+	assert.NoError(t, err)
+	assert.Equal(t, KindOther, b.Statements[0].Kind)
+}
+
+func TestParse_DDL(t *testing.T) {
+	b, err := Parse(`CREATE TABLE dbo.t1 (id INT NOT NULL PRIMARY KEY, name NVARCHAR(10))
+CREATE TABLE t2 (id INT, CONSTRAINT pk_t2 PRIMARY KEY NONCLUSTERED (id))
+CREATE TABLE t3 (id INT, INDEX cx CLUSTERED (id))
+CREATE UNIQUE CLUSTERED INDEX cx_t2 ON dbo.t2 (id)
+CREATE TABLE t4 (id INT PRIMARY KEY NONCLUSTERED) WITH (MEMORY_OPTIMIZED = ON)`)
+	assert.NoError(t, err)
+	assert.True(t, b.IsDDL())
+	assert.Len(t, b.Statements, 5)
+
+	assert.Equal(t, KindCreateTable, b.Statements[0].Kind)
+	assert.Equal(t, ObjectName{Schema: "dbo", Name: "t1"}, b.Statements[0].Table)
+	assert.True(t, b.Statements[0].Clustered)
+	assert.False(t, b.Statements[1].Clustered)
+	assert.True(t, b.Statements[2].Clustered)
+
+	assert.Equal(t, KindCreateIndex, b.Statements[3].Kind)
+	assert.True(t, b.Statements[3].Clustered)
+	assert.Equal(t, ObjectName{Name: "cx_t2"}, b.Statements[3].Index)
+	assert.Equal(t, ObjectName{Schema: "dbo", Name: "t2"}, b.Statements[3].Table)
+
+	assert.False(t, b.Statements[4].Clustered)
+	assert.True(t, b.Statements[4].MemoryOptimized)
+
+	b, err = Parse("CREATE OR ALTER PROCEDURE p1 AS SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, KindCreate, b.Statements[0].Kind)
+}
@@ -0,0 +1,276 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/pkg/driver/astrule"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	RuleTypeIndexingConvention = "索引规范"
+	RuleTypeDMLConvention      = "DML规范"
+	RuleTypeUsageSuggestion    = "使用建议"
+)
+
+const (
+	DMLCheckNoLock             = "dml_check_nolock"
+	DDLCheckClusteredIndex     = "ddl_check_clustered_index"
+	DMLCheckCursor             = "dml_check_cursor"
+	DMLCheckImplicitConversion = "dml_check_implicit_conversion"
+	DMLCheckLargeDMLWithoutTop = "dml_check_large_dml_without_top"
+)
+
+const ParamKeyMaxAffectedRows = "max_affected_rows"
+
+const readUncommittedIsolationDesc = "READ UNCOMMITTED 隔离级别"
+
+// AuditContext keeps the state of the audit session, it's shared by the
+// batches audited by the same driver.
+type AuditContext struct {
+	*astrule.AuditContext
+
+	// plan is the cached plan of the batch plannedBatch, it's shared by the
+	// rules checking the same batch.
+	plan         *ShowPlan
+	plannedBatch *Batch
+}
+
+func NewAuditContext(conn *sql.Conn, logger hclog.Logger) *AuditContext {
+	c := &AuditContext{}
+	c.AuditContext = astrule.NewAuditContext(conn, logger, func(ast interface{}) {
+		if batch, ok := ast.(*Batch); ok {
+			c.Update(batch)
+		}
+	})
+	return c
+}
+
+// Update records the effect of the audited batch.
+func (c *AuditContext) Update(batch *Batch) {
+	for _, stmt := range batch.Statements {
+		if stmt.Kind == KindCreateTable {
+			c.AddCreatedTable(strings.ToLower(stmt.Table.Name))
+		}
+	}
+}
+
+// Plan returns the plan of the current batch, it's nil if the batch is
+// audited offline or can't be explained.
+func (c *AuditContext) Plan(ctx context.Context) *ShowPlan {
+	batch, _ := c.Current().(*Batch)
+	if batch == nil {
+		return nil
+	}
+	if c.plannedBatch == batch {
+		return c.plan
+	}
+	c.plannedBatch = batch
+	c.plan = nil
+	if c.Conn == nil || !batch.HasDML() || batch.IsDDL() {
+		return nil
+	}
+	// the tables created by the previous batches can't be explained.
+	for _, stmt := range batch.Statements {
+		for _, table := range stmt.Tables {
+			if c.IsCreatedTable(strings.ToLower(table.Name)) {
+				return nil
+			}
+		}
+	}
+	plan, err := Explain(ctx, c.Conn, batch.Text)
+	if err != nil {
+		// the batch may depend on the objects which don't exist now, it's not
+		// the problem of the batch.
+		c.Logger.Warn("failed to explain batch, skip checking the plan", "sql", batch.Text, "err", err)
+		return nil
+	}
+	c.plan = plan
+	return plan
+}
+
+type RuleHandler = astrule.RuleHandler
+
+// batchRule converts the rule function of batch to the rule function of the
+// astrule.
+func batchRule(f func(ctx context.Context, actx *AuditContext, rule *driver.Rule, batch *Batch) ([]interface{}, error)) astrule.RuleFunc {
+	return func(ctx context.Context, state astrule.AuditState, rule *driver.Rule, ast interface{}) ([]interface{}, error) {
+		actx, ok := state.(*AuditContext)
+		if !ok {
+			return nil, fmt.Errorf("unexpected audit context %T", state)
+		}
+		batch, ok := ast.(*Batch)
+		if !ok {
+			return nil, fmt.Errorf("unexpected AST %T", ast)
+		}
+		return f(ctx, actx, rule, batch)
+	}
+}
+
+var RuleHandlers = []RuleHandler{
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckNoLock,
+			Desc:     "不建议使用 NOLOCK 提示和 READ UNCOMMITTED 隔离级别",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+		},
+		Message: "不建议使用 %v，可能读取到未提交、重复或缺失的数据",
+		Func:    batchRule(checkNoLock),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DDLCheckClusteredIndex,
+			Desc:     "表需要有聚集索引",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeIndexingConvention,
+		},
+		Message: "表 %v 没有聚集索引，建议创建主键或聚集索引",
+		Func:    batchRule(checkClusteredIndex),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckCursor,
+			Desc:     "不建议使用游标",
+			Level:    driver.RuleLevelNotice,
+			Category: RuleTypeUsageSuggestion,
+		},
+		Message: "不建议使用游标，建议改为基于集合的操作",
+		Func:    batchRule(checkCursor),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckImplicitConversion,
+			Desc:     "执行计划中不建议出现隐式类型转换",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+		},
+		Message: "执行计划中存在影响索引使用的隐式类型转换：%v",
+		Func:    batchRule(checkImplicitConversion),
+	},
+	{
+		Rule: driver.Rule{
+			Name:     DMLCheckLargeDMLWithoutTop,
+			Desc:     "大批量的 UPDATE 和 DELETE 建议使用 TOP 分批执行",
+			Level:    driver.RuleLevelWarn,
+			Category: RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   ParamKeyMaxAffectedRows,
+					Value: "10000",
+					Desc:  "最大影响行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message: "%v",
+		Func:    batchRule(checkLargeDMLWithoutTop),
+	},
+}
+
+func checkNoLock(_ context.Context, _ *AuditContext, _ *driver.Rule, batch *Batch) ([]interface{}, error) {
+	hints := []string{}
+	for _, stmt := range batch.Statements {
+		for _, hint := range stmt.TableHints {
+			if !containsString(hints, hint) {
+				hints = append(hints, hint)
+			}
+		}
+		if stmt.ReadUncommitted && !containsString(hints, readUncommittedIsolationDesc) {
+			hints = append(hints, readUncommittedIsolationDesc)
+		}
+	}
+	if len(hints) == 0 {
+		return nil, nil
+	}
+	return []interface{}{strings.Join(hints, ", ")}, nil
+}
+
+func checkClusteredIndex(_ context.Context, _ *AuditContext, _ *driver.Rule, batch *Batch) ([]interface{}, error) {
+	// the clustered index may be created after the table in the batch.
+	clustered := map[string]struct{}{}
+	for _, stmt := range batch.Statements {
+		if stmt.Kind == KindCreateIndex && stmt.Clustered {
+			clustered[strings.ToLower(stmt.Table.Name)] = struct{}{}
+		}
+	}
+	tables := []string{}
+	for _, stmt := range batch.Statements {
+		if stmt.Kind != KindCreateTable || stmt.Clustered || stmt.MemoryOptimized ||
+			stmt.Table.Name == "" || stmt.Table.IsTemporary() {
+			continue
+		}
+		if _, ok := clustered[strings.ToLower(stmt.Table.Name)]; !ok {
+			tables = append(tables, stmt.Table.String())
+		}
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	return []interface{}{strings.Join(tables, ", ")}, nil
+}
+
+func checkCursor(_ context.Context, _ *AuditContext, _ *driver.Rule, batch *Batch) ([]interface{}, error) {
+	for _, stmt := range batch.Statements {
+		if stmt.Cursor {
+			return []interface{}{}, nil
+		}
+	}
+	return nil, nil
+}
+
+func checkImplicitConversion(ctx context.Context, actx *AuditContext, _ *driver.Rule, batch *Batch) ([]interface{}, error) {
+	plan := actx.Plan(ctx)
+	if plan == nil || len(plan.ImplicitConversions) == 0 {
+		return nil, nil
+	}
+	return []interface{}{strings.Join(plan.ImplicitConversions, ", ")}, nil
+}
+
+func checkLargeDMLWithoutTop(ctx context.Context, actx *AuditContext, rule *driver.Rule, batch *Batch) ([]interface{}, error) {
+	maxRows := rule.Params.GetParam(ParamKeyMaxAffectedRows).Int()
+
+	// the UPDATE and DELETE statements in the plan are in the same order as
+	// the batch.
+	planStatements := []*PlanStatement{}
+	if plan := actx.Plan(ctx); plan != nil {
+		for _, s := range plan.Statements {
+			if s.Type == string(KindUpdate) || s.Type == string(KindDelete) {
+				planStatements = append(planStatements, s)
+			}
+		}
+	}
+
+	messages := []string{}
+	n := 0
+	for _, stmt := range batch.Statements {
+		if stmt.Kind != KindUpdate && stmt.Kind != KindDelete {
+			continue
+		}
+		n++
+		if stmt.Top {
+			continue
+		}
+		if n <= len(planStatements) {
+			rows := planStatements[n-1].EstimateRows
+			if maxRows > 0 && rows > float64(maxRows) {
+				messages = append(messages, fmt.Sprintf("%v %v 预计影响 %.0f 行，超过 %v 行，建议使用 TOP 分批执行",
+					stmt.Kind, stmt.Table, rows, maxRows))
+			}
+			continue
+		}
+		if !stmt.Where {
+			messages = append(messages, fmt.Sprintf("%v %v 没有 WHERE 条件，会影响全表，建议使用 TOP 分批执行", stmt.Kind, stmt.Table))
+		}
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return []interface{}{strings.Join(messages, "; ")}, nil
+}
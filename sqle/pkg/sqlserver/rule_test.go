@@ -0,0 +1,110 @@
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func getRuleHandler(name string) *RuleHandler {
+	for i := range RuleHandlers {
+		if RuleHandlers[i].Rule.Name == name {
+			return &RuleHandlers[i]
+		}
+	}
+	return nil
+}
+
+func checkRule(t *testing.T, actx *AuditContext, name, sql string) string {
+	h := getRuleHandler(name)
+	if !assert.NotNil(t, h, name) {
+		return ""
+	}
+	batch, err := Parse(sql)
+	assert.NoError(t, err)
+	actx.Begin(batch)
+	rule := h.Rule
+	msg, err := h.Check(context.Background(), actx, &rule, batch)
+	assert.NoError(t, err)
+	return msg
+}
+
+func TestRules_Offline(t *testing.T) {
+	actx := NewAuditContext(nil, nil)
+
+	assert.Equal(t, "不建议使用 READ UNCOMMITTED 隔离级别, NOLOCK，可能读取到未提交、重复或缺失的数据",
+		checkRule(t, actx, DMLCheckNoLock, "SET TRANSACTION ISOLATION LEVEL READ UNCOMMITTED SELECT id FROM t1 WITH (NOLOCK)"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckNoLock, "SELECT id FROM t1 WITH (INDEX(ix_id))"))
+
+	assert.Equal(t, "表 dbo.t1 没有聚集索引，建议创建主键或聚集索引",
+		checkRule(t, actx, DDLCheckClusteredIndex, "CREATE TABLE dbo.t1 (id INT)"))
+	assert.Empty(t, checkRule(t, actx, DDLCheckClusteredIndex, "CREATE TABLE t2 (id INT) CREATE CLUSTERED INDEX cx ON t2 (id)"))
+	assert.Empty(t, checkRule(t, actx, DDLCheckClusteredIndex, "CREATE TABLE t3 (id INT PRIMARY KEY)"))
+	assert.Empty(t, checkRule(t, actx, DDLCheckClusteredIndex, "CREATE TABLE #t4 (id INT)"))
+
+	assert.NotEmpty(t, checkRule(t, actx, DMLCheckCursor, "DECLARE c CURSOR FOR SELECT id FROM t1"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckCursor, "DECLARE @id INT"))
+
+	// it's skipped offline.
+	assert.Empty(t, checkRule(t, actx, DMLCheckImplicitConversion, "SELECT id FROM t1 WHERE code = 1"))
+
+	assert.Equal(t, "DELETE t1 没有 WHERE 条件，会影响全表，建议使用 TOP 分批执行",
+		checkRule(t, actx, DMLCheckLargeDMLWithoutTop, "DELETE FROM t1"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckLargeDMLWithoutTop, "DELETE TOP (1000) FROM t1"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckLargeDMLWithoutTop, "UPDATE t1 SET a = 1 WHERE id = 1"))
+}
+
+func TestRules_Online(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	actx := NewAuditContext(conn, nil)
+
+	batch, err := Parse("SELECT id FROM t1 WHERE code = @code DELETE FROM t2 WHERE id > 0")
+	assert.NoError(t, err)
+	actx.Begin(batch)
+
+	// the plan is explained once for all rules.
+	mock.ExpectExec("SET SHOWPLAN_XML ON").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t1").WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow(testShowPlan))
+	mock.ExpectExec("SET SHOWPLAN_XML OFF").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	h := getRuleHandler(DMLCheckImplicitConversion)
+	msg, err := h.Check(context.Background(), actx, &h.Rule, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, "执行计划中存在影响索引使用的隐式类型转换：CONVERT_IMPLICIT(nvarchar(20),[db].[dbo].[t1].[code],0)=[@code]", msg)
+
+	h = getRuleHandler(DMLCheckLargeDMLWithoutTop)
+	msg, err = h.Check(context.Background(), actx, &h.Rule, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE t2 预计影响 50000 行，超过 10000 行，建议使用 TOP 分批执行", msg)
+
+	// the error of explain is ignored.
+	mock.ExpectExec("SET SHOWPLAN_XML ON").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t3").WillReturnError(fmt.Errorf("Invalid object name 't3'"))
+	mock.ExpectExec("SET SHOWPLAN_XML OFF").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Empty(t, checkRule(t, actx, DMLCheckImplicitConversion, "SELECT id FROM t3"))
+
+	// the table created by the previous batch is not explained.
+	assert.Empty(t, checkRule(t, actx, DDLCheckClusteredIndex, "CREATE TABLE t4 (id INT PRIMARY KEY)"))
+	assert.Empty(t, checkRule(t, actx, DMLCheckImplicitConversion, "SELECT id FROM t4"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleHandlers(t *testing.T) {
+	names := map[string]struct{}{}
+	for _, h := range RuleHandlers {
+		assert.NotEmpty(t, h.Rule.Desc, h.Rule.Name)
+		assert.Contains(t, []driver.RuleLevel{driver.RuleLevelNotice, driver.RuleLevelWarn, driver.RuleLevelError}, h.Rule.Level)
+		_, ok := names[h.Rule.Name]
+		assert.False(t, ok, "duplicated rule %v", h.Rule.Name)
+		names[h.Rule.Name] = struct{}{}
+	}
+}
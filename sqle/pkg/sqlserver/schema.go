@@ -0,0 +1,45 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// QuoteIdentifier quotes the identifier by brackets like QUOTENAME().
+func QuoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+const listTablesTpl = `SELECT TABLE_SCHEMA, TABLE_NAME FROM %vINFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_SCHEMA, TABLE_NAME`
+
+// ListTables returns the tables in the database like "schema.table", the
+// current database is used if the database is empty.
+func ListTables(ctx context.Context, conn *sql.Conn, database string) ([]string, error) {
+	prefix := ""
+	if database != "" {
+		prefix = QuoteIdentifier(database) + "."
+	}
+	query := fmt.Sprintf(listTablesTpl, prefix)
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query %s", query)
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan %s", query)
+		}
+		tables = append(tables, schema+"."+table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate %s", query)
+	}
+	return tables, nil
+}
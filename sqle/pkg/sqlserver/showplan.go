@@ -0,0 +1,209 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	_driver "database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+
+	"github.com/pkg/errors"
+)
+
+// PlanStatement is the statement in the showplan, such as "StmtSimple".
+type PlanStatement struct {
+	Text string
+	// Type is the type of statement, such as "SELECT" and "UPDATE".
+	Type string
+	// EstimateRows is the estimated number of rows returned or affected.
+	EstimateRows float64
+}
+
+// PlanOperator is the "RelOp" in the showplan.
+type PlanOperator struct {
+	// Statement is the index of the statement which the operator belongs to.
+	Statement int
+	NodeID    string
+	// Depth is the depth of the operator in the plan tree, it's 0 for the root.
+	Depth        int
+	PhysicalOp   string
+	LogicalOp    string
+	Object       string
+	EstimateRows string
+	EstimateCost string
+}
+
+// ShowPlan is the estimated execution plan returned by "SET SHOWPLAN_XML ON",
+// ref to https://learn.microsoft.com/en-us/sql/relational-databases/showplan-logical-and-physical-operators-reference
+type ShowPlan struct {
+	Statements []*PlanStatement
+	Operators  []*PlanOperator
+	// ImplicitConversions are the expressions in PlanAffectingConvert
+	// warnings, the implicit conversions which affect the plan.
+	ImplicitConversions []string
+}
+
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, attr := range e.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// ParseShowPlan parses the showplan XML, the plans of the batch are merged if
+// there are many XML documents.
+func ParseShowPlan(documents ...string) (*ShowPlan, error) {
+	plan := &ShowPlan{}
+	for _, doc := range documents {
+		if err := plan.parse(doc); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func (p *ShowPlan) parse(doc string) error {
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	// the document declares "utf-16", but it has been decoded by the driver.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	// the operators which are not closed.
+	stack := []*PlanOperator{}
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to parse showplan XML")
+		}
+		switch e := t.(type) {
+		case xml.StartElement:
+			switch {
+			case strings.HasPrefix(e.Name.Local, "Stmt") && xmlAttr(e, "StatementType") != "":
+				rows, _ := strconv.ParseFloat(xmlAttr(e, "StatementEstRows"), 64)
+				p.Statements = append(p.Statements, &PlanStatement{
+					Text:         xmlAttr(e, "StatementText"),
+					Type:         xmlAttr(e, "StatementType"),
+					EstimateRows: rows,
+				})
+			case e.Name.Local == "RelOp":
+				op := &PlanOperator{
+					Statement:    len(p.Statements) - 1,
+					NodeID:       xmlAttr(e, "NodeId"),
+					Depth:        len(stack),
+					PhysicalOp:   xmlAttr(e, "PhysicalOp"),
+					LogicalOp:    xmlAttr(e, "LogicalOp"),
+					EstimateRows: xmlAttr(e, "EstimateRows"),
+					EstimateCost: xmlAttr(e, "EstimatedTotalSubtreeCost"),
+				}
+				p.Operators = append(p.Operators, op)
+				stack = append(stack, op)
+			case e.Name.Local == "Object" && len(stack) > 0:
+				op := stack[len(stack)-1]
+				if op.Object != "" {
+					continue
+				}
+				parts := []string{}
+				for _, attr := range []string{"Schema", "Table", "Index"} {
+					if v := xmlAttr(e, attr); v != "" {
+						parts = append(parts, v)
+					}
+				}
+				op.Object = strings.Join(parts, ".")
+			case e.Name.Local == "PlanAffectingConvert":
+				expr := xmlAttr(e, "Expression")
+				if expr != "" && !containsString(p.ImplicitConversions, expr) {
+					p.ImplicitConversions = append(p.ImplicitConversions, expr)
+				}
+			}
+		case xml.EndElement:
+			if e.Name.Local == "RelOp" && len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+// Table returns the operators in the table format of analysis driver.
+func (p *ShowPlan) Table() driver.AnalysisInfoInTableFormat {
+	table := driver.AnalysisInfoInTableFormat{
+		Columns: []driver.AnalysisInfoHead{
+			{Name: "statement", Desc: "语句"},
+			{Name: "node_id", Desc: "节点ID"},
+			{Name: "physical_op", Desc: "物理运算符"},
+			{Name: "logical_op", Desc: "逻辑运算符"},
+			{Name: "object", Desc: "对象"},
+			{Name: "estimate_rows", Desc: "预估行数"},
+			{Name: "estimate_cost", Desc: "预估子树成本"},
+		},
+		Rows: [][]string{},
+	}
+	for _, op := range p.Operators {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(op.Statement + 1),
+			op.NodeID,
+			strings.Repeat("  ", op.Depth) + op.PhysicalOp,
+			op.LogicalOp,
+			op.Object,
+			op.EstimateRows,
+			op.EstimateCost,
+		})
+	}
+	return table
+}
+
+// Explain returns the estimated plan of the batch by "SET SHOWPLAN_XML ON",
+// the batch is not executed.
+func Explain(ctx context.Context, conn *sql.Conn, batch string) (plan *ShowPlan, err error) {
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, errors.Wrap(err, "failed to set showplan_xml on")
+	}
+	defer func() {
+		// the statements on the connection are not executed until it's off.
+		if _, offErr := conn.ExecContext(context.Background(), "SET SHOWPLAN_XML OFF"); offErr != nil {
+			plan = nil
+			err = errors.Wrap(offErr, "failed to set showplan_xml off")
+			// the connection is still in showplan mode, it's discarded so
+			// the following statements fail instead of being not executed.
+			_ = conn.Raw(func(driverConn interface{}) error {
+				return _driver.ErrBadConn
+			})
+		}
+	}()
+
+	rows, err := conn.QueryContext(ctx, batch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to explain %s", batch)
+	}
+	defer rows.Close()
+
+	documents := []string{}
+	for {
+		for rows.Next() {
+			var doc string
+			if err := rows.Scan(&doc); err != nil {
+				return nil, errors.Wrapf(err, "failed to scan plan of %s", batch)
+			}
+			documents = append(documents, doc)
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate plan of %s", batch)
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no plan is returned for %s", batch)
+	}
+	return ParseShowPlan(documents...)
+}
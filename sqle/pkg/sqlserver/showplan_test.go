@@ -0,0 +1,122 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testShowPlan = `<?xml version="1.0" encoding="utf-16"?>
+<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan" Version="1.539" Build="15.0.2000.5">
+  <BatchSequence>
+    <Batch>
+      <Statements>
+        <StmtSimple StatementText="SELECT id FROM t1 WHERE code = @code" StatementId="1" StatementType="SELECT" StatementEstRows="1">
+          <QueryPlan>
+            <Warnings>
+              <PlanAffectingConvert ConvertIssue="Seek Plan" Expression="CONVERT_IMPLICIT(nvarchar(20),[db].[dbo].[t1].[code],0)=[@code]" />
+            </Warnings>
+            <RelOp NodeId="0" PhysicalOp="Clustered Index Scan" LogicalOp="Clustered Index Scan" EstimateRows="1" EstimatedTotalSubtreeCost="0.1">
+              <IndexScan>
+                <Object Database="[db]" Schema="[dbo]" Table="[t1]" Index="[pk_t1]" />
+              </IndexScan>
+            </RelOp>
+          </QueryPlan>
+        </StmtSimple>
+        <StmtSimple StatementText="DELETE FROM t2" StatementId="2" StatementType="DELETE" StatementEstRows="50000">
+          <QueryPlan>
+            <RelOp NodeId="0" PhysicalOp="Table Delete" LogicalOp="Delete" EstimateRows="50000" EstimatedTotalSubtreeCost="2.5">
+              <TableUpdate>
+                <Object Database="[db]" Schema="[dbo]" Table="[t2]" />
+                <RelOp NodeId="1" PhysicalOp="Table Scan" LogicalOp="Table Scan" EstimateRows="50000" EstimatedTotalSubtreeCost="0.5">
+                  <TableScan>
+                    <Object Database="[db]" Schema="[dbo]" Table="[t2]" />
+                  </TableScan>
+                </RelOp>
+              </TableUpdate>
+            </RelOp>
+          </QueryPlan>
+        </StmtSimple>
+      </Statements>
+    </Batch>
+  </BatchSequence>
+</ShowPlanXML>`
+
+func TestParseShowPlan(t *testing.T) {
+	plan, err := ParseShowPlan(testShowPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, []*PlanStatement{
+		{Text: "SELECT id FROM t1 WHERE code = @code", Type: "SELECT", EstimateRows: 1},
+		{Text: "DELETE FROM t2", Type: "DELETE", EstimateRows: 50000},
+	}, plan.Statements)
+	assert.Equal(t, []string{"CONVERT_IMPLICIT(nvarchar(20),[db].[dbo].[t1].[code],0)=[@code]"}, plan.ImplicitConversions)
+	assert.Equal(t, []*PlanOperator{
+		{Statement: 0, NodeID: "0", Depth: 0, PhysicalOp: "Clustered Index Scan", LogicalOp: "Clustered Index Scan",
+			Object: "[dbo].[t1].[pk_t1]", EstimateRows: "1", EstimateCost: "0.1"},
+		{Statement: 1, NodeID: "0", Depth: 0, PhysicalOp: "Table Delete", LogicalOp: "Delete",
+			Object: "[dbo].[t2]", EstimateRows: "50000", EstimateCost: "2.5"},
+		{Statement: 1, NodeID: "1", Depth: 1, PhysicalOp: "Table Scan", LogicalOp: "Table Scan",
+			Object: "[dbo].[t2]", EstimateRows: "50000", EstimateCost: "0.5"},
+	}, plan.Operators)
+
+	table := plan.Table()
+	assert.Len(t, table.Columns, 7)
+	assert.Equal(t, []string{"2", "1", "  Table Scan", "Table Scan", "[dbo].[t2]", "50000", "0.5"}, table.Rows[2])
+
+	_, err = ParseShowPlan("<ShowPlanXML><BatchSequence>")
+	assert.Error(t, err)
+}
+
+func TestExplain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+
+	mock.ExpectExec("SET SHOWPLAN_XML ON").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t1").WillReturnRows(sqlmock.NewRows([]string{"Microsoft SQL Server 2005 XML Showplan"}).AddRow(testShowPlan))
+	mock.ExpectExec("SET SHOWPLAN_XML OFF").WillReturnResult(sqlmock.NewResult(0, 0))
+	plan, err := Explain(context.Background(), conn, "SELECT id FROM t1 WHERE code = @code DELETE FROM t2")
+	assert.NoError(t, err)
+	assert.Len(t, plan.Statements, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExplainSetShowPlanOffFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+
+	mock.ExpectExec("SET SHOWPLAN_XML ON").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t1").WillReturnRows(sqlmock.NewRows([]string{"Microsoft SQL Server 2005 XML Showplan"}).AddRow(testShowPlan))
+	mock.ExpectExec("SET SHOWPLAN_XML OFF").WillReturnError(assert.AnError)
+	plan, err := Explain(context.Background(), conn, "SELECT id FROM t1 WHERE code = @code DELETE FROM t2")
+	assert.Error(t, err)
+	assert.Nil(t, plan)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// the connection in showplan mode is discarded.
+	_, err = conn.ExecContext(context.Background(), "DELETE FROM t2")
+	assert.Equal(t, sql.ErrConnDone, err)
+}
+
+func TestListTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT TABLE_SCHEMA, TABLE_NAME FROM \[my\]\]db\]\.INFORMATION_SCHEMA\.TABLES`).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).AddRow("dbo", "t1").AddRow("sales", "t2"))
+	tables, err := ListTables(context.Background(), conn, "my]db")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dbo.t1", "sales.t2"}, tables)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
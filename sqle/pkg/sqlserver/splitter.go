@@ -0,0 +1,88 @@
+package sqlserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxBatchCount limits the count of "GO", the batch is repeated by the count,
+// a huge count makes too many batches to audit.
+const maxBatchCount = 1000
+
+// isBatchSeparator returns true if the i-th token is a "GO" command, which is
+// alone in its line with the optional count, such as "GO 10".
+func isBatchSeparator(sql string, tokens []token, i int) bool {
+	t := tokens[i]
+	if !t.is("GO") {
+		return false
+	}
+	lineStart := strings.LastIndexByte(sql[:t.start], '\n') + 1
+	if strings.TrimSpace(sql[lineStart:t.start]) != "" {
+		return false
+	}
+	lineEnd := strings.IndexByte(sql[t.end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(sql)
+	} else {
+		lineEnd += t.end
+	}
+	rest := 0
+	for _, next := range tokens[i+1:] {
+		if next.start >= lineEnd {
+			break
+		}
+		if next.typ != tokenNumber || rest > 0 {
+			return false
+		}
+		rest++
+	}
+	return true
+}
+
+// SplitBatches splits the T-SQL script into batches by the "GO" command like
+// sqlcmd and SSMS. The "GO" in the string, comment or the middle of line is
+// not the separator. The batch followed by "GO <count>" is repeated count
+// times, as it's executed count times.
+func SplitBatches(sql string) ([]string, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := []string{}
+	// empty means there is no token in the batch, the batch of comments is
+	// skipped.
+	empty := true
+	add := func(text string, count int) {
+		if !empty {
+			for j := 0; j < count; j++ {
+				batches = append(batches, strings.TrimSpace(text))
+			}
+		}
+		empty = true
+	}
+
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		if !isBatchSeparator(sql, tokens, i) {
+			empty = false
+			continue
+		}
+		text := sql[start:tokens[i].start]
+		count := 1
+		end := tokens[i].end
+		if i+1 < len(tokens) && tokens[i+1].typ == tokenNumber && !strings.Contains(sql[end:tokens[i+1].start], "\n") {
+			i++
+			end = tokens[i].end
+			count, err = strconv.Atoi(tokens[i].text)
+			if err != nil || count < 1 || count > maxBatchCount {
+				return nil, fmt.Errorf("invalid count \"%v\" of GO, it should be an integer between 1 and %v", tokens[i].text, maxBatchCount)
+			}
+		}
+		add(text, count)
+		start = end
+	}
+	add(sql[start:], 1)
+	return batches, nil
+}
@@ -0,0 +1,60 @@
+package sqlserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBatches(t *testing.T) {
+	cases := []struct {
+		sql    string
+		expect []string
+	}{
+		{
+			sql:    "select 1\nGO\nselect 2\ngo",
+			expect: []string{"select 1", "select 2"},
+		},
+		{
+			sql:    "select 'GO'\n/*\nGO\n*/\nselect 1 as go\n  Go 1  \nprint 1",
+			expect: []string{"select 'GO'\n/*\nGO\n*/\nselect 1 as go", "print 1"},
+		},
+		{
+			sql:    "insert into t1 values (1)\nGO 3\nprint 1",
+			expect: []string{"insert into t1 values (1)", "insert into t1 values (1)", "insert into t1 values (1)", "print 1"},
+		},
+		{
+			sql: `CREATE PROCEDURE p1 AS
+BEGIN
+  SET NOCOUNT ON;
+  SELECT id FROM t1;
+END
+GO
+EXEC p1`,
+			expect: []string{"CREATE PROCEDURE p1 AS\nBEGIN\n  SET NOCOUNT ON;\n  SELECT id FROM t1;\nEND", "EXEC p1"},
+		},
+		{
+			sql:    "-- only comment\nGO\nGO\n",
+			expect: []string{},
+		},
+	}
+	for _, c := range cases {
+		batches, err := SplitBatches(c.sql)
+		assert.NoError(t, err, c.sql)
+		assert.Equal(t, c.expect, batches, c.sql)
+	}
+}
+
+func TestSplitBatches_Error(t *testing.T) {
+	for _, sql := range []string{
+		"select 'a",
+		"select [a",
+		"select 1 /* /* nested */",
+		"select 1\nGO 0",
+		"select 1\nGO 1.5",
+		"select 1\nGO 100000",
+	} {
+		_, err := SplitBatches(sql)
+		assert.Error(t, err, sql)
+	}
+}
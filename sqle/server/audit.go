@@ -77,6 +77,8 @@ func audit(l *logrus.Entry, task *model.Task, d driver.Driver) (err error) {
 	return hookAudit(l, task, d, &EmptyAuditHook{})
 }
 
+// AuditHook is called around the audit of each SQL. The SQLs are audited in
+// batch, so BeforeAudit is called for all SQLs before AfterAudit is called.
 type AuditHook interface {
 	BeforeAudit(sql *model.ExecuteSQL)
 	AfterAudit(sql *model.ExecuteSQL)
@@ -95,7 +97,28 @@ func hookAudit(l *logrus.Entry, task *model.Task, d driver.Driver, hook AuditHoo
 	if err != nil {
 		return err
 	}
-	for _, executeSQL := range task.ExecuteSQLs {
+	// the whitelist is parsed once for all SQLs.
+	whitelistFingerprints := map[string]struct{}{}
+	whitelistValues := map[string]struct{}{}
+	for _, wl := range whitelist {
+		if wl.MatchType == model.SQLWhitelistFPMatch {
+			wlNode, err := parse(l, d, wl.Value)
+			if err != nil {
+				return err
+			}
+			whitelistFingerprints[wlNode.Fingerprint] = struct{}{}
+		} else {
+			whitelistValues[wl.CapitalizedValue] = struct{}{}
+		}
+	}
+
+	nodes := make([]driver.Node, len(task.ExecuteSQLs))
+	results := make([]*driver.AuditResult, len(task.ExecuteSQLs))
+	// auditIndexes and auditSQLs keep the SQLs which are not in whitelist, they
+	// are audited in order in one batch.
+	auditIndexes := []int{}
+	auditSQLs := []string{}
+	for i, executeSQL := range task.ExecuteSQLs {
 		// We always trust the ExecuteSQL.Content is single SQL.
 		//
 		// The audit() function has two producers for now:
@@ -109,37 +132,36 @@ func hookAudit(l *logrus.Entry, task *model.Task, d driver.Driver, hook AuditHoo
 		if err != nil {
 			return err
 		}
-		var whitelistMatch bool
-		for _, wl := range whitelist {
-			if wl.MatchType == model.SQLWhitelistFPMatch {
-				wlNode, err := parse(l, d, wl.Value)
-				if err != nil {
-					return err
-				}
-				if node.Fingerprint == wlNode.Fingerprint {
-					whitelistMatch = true
-				}
-			} else {
-				if wl.CapitalizedValue == strings.ToUpper(node.Text) {
-					whitelistMatch = true
-				}
-			}
-		}
-		result := driver.NewInspectResults()
-		if whitelistMatch {
+		nodes[i] = node
+
+		_, fingerprintMatch := whitelistFingerprints[node.Fingerprint]
+		_, valueMatch := whitelistValues[strings.ToUpper(node.Text)]
+		if fingerprintMatch || valueMatch {
+			result := driver.NewInspectResults()
 			result.Add(driver.RuleLevelNormal, "白名单")
-		} else {
-			hook.BeforeAudit(executeSQL)
-			result, err = d.Audit(context.TODO(), executeSQL.Content)
-			if err != nil {
-				return err
-			}
+			results[i] = result
+			continue
 		}
+		hook.BeforeAudit(executeSQL)
+		auditIndexes = append(auditIndexes, i)
+		auditSQLs = append(auditSQLs, executeSQL.Content)
+	}
+
+	auditResults, err := driver.AuditBatch(context.TODO(), d, auditSQLs)
+	if err != nil {
+		return err
+	}
+	for i, idx := range auditIndexes {
+		results[idx] = auditResults[i]
+	}
+
+	for i, executeSQL := range task.ExecuteSQLs {
+		result := results[i]
 		hook.AfterAudit(executeSQL)
 		executeSQL.AuditStatus = model.SQLAuditStatusFinished
 		executeSQL.AuditLevel = string(result.Level())
 		executeSQL.AuditResult = result.Message()
-		executeSQL.AuditFingerprint = utils.Md5String(string(append([]byte(result.Message()), []byte(node.Fingerprint)...)))
+		executeSQL.AuditFingerprint = utils.Md5String(string(append([]byte(result.Message()), []byte(nodes[i].Fingerprint)...)))
 
 		l.WithFields(logrus.Fields{
 			"SQL":    executeSQL.Content,
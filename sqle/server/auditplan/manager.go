@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
@@ -15,6 +16,7 @@ import (
 
 var ErrAuditPlanNotExist = errors.New(errors.DataNotExist, fmt.Errorf("audit plan not exist"))
 var ErrAuditPlanExisted = errors.New(errors.DataExist, fmt.Errorf("audit plan existed"))
+var ErrAuditPlanDisabled = errors.New(errors.DataConflict, fmt.Errorf("audit plan is paused"))
 
 var manager *Manager
 
@@ -132,13 +134,20 @@ func (mgr *Manager) startAuditPlan(ap *model.AuditPlan) error {
 	}
 
 	task = NewTask(mgr.logger, ap)
+	// the task of paused audit plan is kept to query the collected SQLs, but
+	// it's not started and scheduled.
+	if !ap.IsEnabled() {
+		mgr.tasks[ap.Name] = task
+		mgr.logger.WithField("name", ap.Name).Infoln("audit plan is paused")
+		return nil
+	}
 	if err := task.Start(); err != nil {
 		return err
 	}
 	mgr.tasks[ap.Name] = task
 
 	return mgr.scheduler.addJob(mgr.logger, ap, func() {
-		_, err := mgr.Audit(ap.Name)
+		_, err := mgr.audit(ap.Name, model.AuditPlanRunTriggerTypeSchedule)
 		if err != nil {
 			mgr.logger.WithField("name", ap.Name).Errorf("schedule to audit task failed, error: %v", err)
 		}
@@ -171,7 +180,28 @@ func (mgr *Manager) getTask(apName string) (Task, error) {
 	return task, nil
 }
 
+// Audit audits the audit plan manually.
 func (mgr *Manager) Audit(apName string) (*model.AuditPlanReportV2, error) {
+	return mgr.audit(apName, model.AuditPlanRunTriggerTypeManual)
+}
+
+func (mgr *Manager) audit(apName, triggerType string) (*model.AuditPlanReportV2, error) {
+	report, err := mgr.auditTask(apName, triggerType)
+	if err != nil {
+		return nil, err
+	}
+	// The notifiers may retry on slow endpoints, so they are not called with
+	// the lock held, and the failure of notification doesn't fail the audit
+	// whose report has been saved.
+	go func() {
+		if err := notification.NotifyAuditPlan(apName, report); err != nil {
+			mgr.logger.WithField("name", apName).Errorf("notify audit plan failed, error: %v", err)
+		}
+	}()
+	return report, nil
+}
+
+func (mgr *Manager) auditTask(apName, triggerType string) (*model.AuditPlanReportV2, error) {
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 
@@ -179,11 +209,24 @@ func (mgr *Manager) Audit(apName string) (*model.AuditPlanReportV2, error) {
 	if err != nil {
 		return nil, err
 	}
+	ap, exist, err := mgr.persist.GetAuditPlanByName(apName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrAuditPlanNotExist
+	}
+	if !ap.IsEnabled() {
+		return nil, ErrAuditPlanDisabled
+	}
+
+	startAt := time.Now()
 	report, err := task.Audit()
+	saveRun(mgr.persist, mgr.logger.WithField("name", apName), ap.ID, model.AuditPlanRunTypeAudit, triggerType, startAt, report, err)
 	if err != nil {
 		return nil, err
 	}
-	return report, notification.NotifyAuditPlan(apName, report)
+	return report, nil
 }
 
 func (mgr *Manager) UploadSQLs(apName string, sqls []*SQL, isPartialSync bool) error {
@@ -212,6 +255,38 @@ func (mgr *Manager) GetSQLs(apName string, args map[string]interface{}) ([]Head,
 	return task.GetSQLs(args)
 }
 
+// saveRun saves the run history of audit plan. The failure of saving is only
+// logged, it should not break the run.
+func saveRun(persist *model.Storage, logger *logrus.Entry, apID uint, runType, triggerType string,
+	startAt time.Time, report *model.AuditPlanReportV2, runErr error) {
+
+	run := &model.AuditPlanRun{
+		AuditPlanID: apID,
+		RunType:     runType,
+		TriggerType: triggerType,
+		StartAt:     startAt,
+		EndAt:       time.Now(),
+		Status:      model.AuditPlanRunStatusSuccess,
+	}
+	if report != nil {
+		run.AuditPlanReportID = report.ID
+		run.AuditedSQLCount = uint(len(report.AuditPlanReportSQLs))
+	}
+	if runErr != nil {
+		run.Status = model.AuditPlanRunStatusFailed
+		run.ErrorMessage = runErr.Error()
+	}
+	count, err := persist.GetAuditPlanSQLCount(apID)
+	if err != nil {
+		logger.Warnf("get audit plan sql count fail, error: %v", err)
+	}
+	run.SQLCount = uint(count)
+
+	if err := persist.Save(run); err != nil {
+		logger.Errorf("save audit plan run fail, error: %v", err)
+	}
+}
+
 // scheduler is not goroutine safe.
 type scheduler struct {
 	// cron is a AuditPlan scheduler.
@@ -2,9 +2,11 @@ package auditplan
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,3 +71,71 @@ func TestManager(t *testing.T) {
 	assert.Equal(t, ok, true)
 	assert.Equal(t, dt.ap.CronExpression, "*/2 * * * *")
 }
+
+func TestSaveRun(t *testing.T) {
+	mockDB, mockHandle, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+	storage := model.GetStorage()
+
+	mockHandle.ExpectQuery("SELECT count\\(\\*\\) FROM `audit_plan_sqls_v2`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(3))
+	mockHandle.ExpectBegin()
+	mockHandle.ExpectExec("INSERT INTO `audit_plan_runs`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 1, model.AuditPlanRunTypeAudit, model.AuditPlanRunTriggerTypeManual,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 0, 0, model.AuditPlanRunStatusFailed, errNoSQLNeedToBeAudited.Error()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockHandle.ExpectCommit()
+
+	saveRun(storage, logrus.NewEntry(logrus.New()), 1, model.AuditPlanRunTypeAudit, model.AuditPlanRunTriggerTypeManual,
+		time.Now(), nil, errNoSQLNeedToBeAudited)
+	assert.NoError(t, mockHandle.ExpectationsWereMet())
+}
+
+func TestManagerPauseAuditPlan(t *testing.T) {
+	mockDB, mockHandle, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+	storage := model.GetStorage()
+
+	mockHandle.ExpectQuery("SELECT * FROM `audit_plans`  WHERE `audit_plans`.`deleted_at` IS NULL").
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "cron_expression", "status"}).
+			AddRow("test_ap_1", "default", "*/1 * * * *", model.AuditPlanStatusDisabled))
+
+	InitManager(storage)
+
+	// the task of paused audit plan is kept, but not scheduled.
+	assert.Len(t, manager.scheduler.cron.Entries(), 0)
+	assert.Len(t, manager.tasks, 1)
+
+	mockHandle.ExpectQuery("SELECT * FROM `audit_plans`  WHERE `audit_plans`.`deleted_at` IS NULL AND ((name = ?))").
+		WithArgs("test_ap_1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "cron_expression", "status"}).
+			AddRow("test_ap_1", "default", "*/1 * * * *", model.AuditPlanStatusDisabled))
+	_, err = manager.Audit("test_ap_1")
+	assert.Equal(t, ErrAuditPlanDisabled, err)
+
+	// resume
+	mockHandle.ExpectQuery("SELECT * FROM `audit_plans`  WHERE `audit_plans`.`deleted_at` IS NULL AND ((name = ?))").
+		WithArgs("test_ap_1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "cron_expression", "status"}).
+			AddRow("test_ap_1", "default", "*/1 * * * *", model.AuditPlanStatusEnabled))
+	assert.NoError(t, manager.SyncTask("test_ap_1"))
+	assert.Len(t, manager.scheduler.cron.Entries(), 1)
+	assert.Len(t, manager.tasks, 1)
+
+	// pause again
+	mockHandle.ExpectQuery("SELECT * FROM `audit_plans`  WHERE `audit_plans`.`deleted_at` IS NULL AND ((name = ?))").
+		WithArgs("test_ap_1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "cron_expression", "status"}).
+			AddRow("test_ap_1", "default", "*/1 * * * *", model.AuditPlanStatusDisabled))
+	assert.NoError(t, manager.SyncTask("test_ap_1"))
+	assert.Len(t, manager.scheduler.cron.Entries(), 0)
+	_, err = manager.getTask("test_ap_1")
+	assert.NoError(t, err)
+	assert.NoError(t, mockHandle.ExpectationsWereMet())
+}
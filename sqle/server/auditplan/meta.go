@@ -5,6 +5,7 @@ import (
 
 	"github.com/actiontech/sqle/sqle/pkg/oracle"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/pkg/postgresql"
 )
 
 type Meta struct {
@@ -15,25 +16,29 @@ type Meta struct {
 }
 
 const (
-	TypeDefault         = "default"
-	TypeMySQLSlowLog    = "mysql_slow_log"
-	TypeMySQLMybatis    = "mysql_mybatis"
-	TypeMySQLSchemaMeta = "mysql_schema_meta"
-	TypeOracleTopSQL    = "oracle_top_sql"
-	TypeTiDBAuditLog    = "tidb_audit_log"
-	TypeAllAppExtract   = "all_app_extract"
+	TypeDefault          = "default"
+	TypeMySQLSlowLog     = "mysql_slow_log"
+	TypeMySQLMybatis     = "mysql_mybatis"
+	TypeMySQLSchemaMeta  = "mysql_schema_meta"
+	TypeOracleTopSQL     = "oracle_top_sql"
+	TypeTiDBAuditLog     = "tidb_audit_log"
+	TypeTiDBSlowQuery    = "tidb_slow_query"
+	TypeAllAppExtract    = "all_app_extract"
+	TypePostgreSQLTopSQL = "postgresql_top_sql"
 )
 
 const (
-	InstanceTypeAll    = ""
-	InstanceTypeMySQL  = "MySQL"
-	InstanceTypeOracle = "Oracle"
-	InstanceTypeTiDB   = "TiDB"
+	InstanceTypeAll        = ""
+	InstanceTypeMySQL      = "MySQL"
+	InstanceTypeOracle     = "Oracle"
+	InstanceTypeTiDB       = "TiDB"
+	InstanceTypePostgreSQL = "PostgreSQL"
 )
 
 const (
 	paramKeyCollectIntervalMinute               = "collect_interval_minute"
 	paramKeyAuditSQLsScrappedInLastPeriodMinute = "audit_sqls_scrapped_in_last_period_minute"
+	paramKeySkipCollectInMaintenancePeriod      = "skip_collect_in_maintenance_period"
 )
 
 var Metas = []Meta{
@@ -71,6 +76,12 @@ var Metas = []Meta{
 				Value: "60",
 				Type:  params.ParamTypeInt,
 			},
+			&params.Param{
+				Key:   paramKeySkipCollectInMaintenancePeriod,
+				Desc:  "运维时间内不采集",
+				Value: "0",
+				Type:  params.ParamTypeBool,
+			},
 			&params.Param{
 				Key:   "collect_view",
 				Desc:  "是否采集视图信息",
@@ -90,6 +101,12 @@ var Metas = []Meta{
 				Value: "60",
 				Type:  params.ParamTypeInt,
 			},
+			{
+				Key:   paramKeySkipCollectInMaintenancePeriod,
+				Desc:  "运维时间内不采集",
+				Value: "0",
+				Type:  params.ParamTypeBool,
+			},
 			{
 				Key:   "top_n",
 				Desc:  "Top N",
@@ -104,6 +121,37 @@ var Metas = []Meta{
 			},
 		},
 	},
+	{
+		Type:         TypePostgreSQLTopSQL,
+		Desc:         "PostgreSQL TOP SQL",
+		InstanceType: InstanceTypePostgreSQL,
+		Params: []*params.Param{
+			{
+				Key:   paramKeyCollectIntervalMinute,
+				Desc:  "采集周期（分钟）",
+				Value: "60",
+				Type:  params.ParamTypeInt,
+			},
+			{
+				Key:   paramKeySkipCollectInMaintenancePeriod,
+				Desc:  "运维时间内不采集",
+				Value: "0",
+				Type:  params.ParamTypeBool,
+			},
+			{
+				Key:   "top_n",
+				Desc:  "Top N",
+				Value: "3",
+				Type:  params.ParamTypeInt,
+			},
+			{
+				Key:   "order_by_column",
+				Desc:  "pg_stat_statements中的排序字段",
+				Value: postgresql.PgStatStatementsColumnTotalExecTime,
+				Type:  params.ParamTypeString,
+			},
+		},
+	},
 	{
 		Type:         TypeAllAppExtract,
 		Desc:         "应用程序SQL抓取",
@@ -122,6 +170,37 @@ var Metas = []Meta{
 			},
 		},
 	},
+	{
+		Type:         TypeTiDBSlowQuery,
+		Desc:         "TiDB慢查询",
+		InstanceType: InstanceTypeTiDB,
+		Params: []*params.Param{
+			{
+				Key:   paramKeyCollectIntervalMinute,
+				Desc:  "采集周期（分钟）",
+				Value: "60",
+				Type:  params.ParamTypeInt,
+			},
+			{
+				Key:   paramKeySkipCollectInMaintenancePeriod,
+				Desc:  "运维时间内不采集",
+				Value: "0",
+				Type:  params.ParamTypeBool,
+			},
+			{
+				Key:   "top_n",
+				Desc:  "Top N",
+				Value: "10",
+				Type:  params.ParamTypeInt,
+			},
+			{
+				Key:   "collect_statements_summary",
+				Desc:  "是否采集STATEMENTS_SUMMARY中的SQL",
+				Value: "0",
+				Type:  params.ParamTypeBool,
+			},
+		},
+	},
 }
 
 var MetaMap = map[string]Meta{}
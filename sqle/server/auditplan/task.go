@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +18,7 @@ import (
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/pkg/oracle"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/pkg/postgresql"
 	"github.com/actiontech/sqle/sqle/server"
 	"github.com/actiontech/sqle/sqle/utils"
 
@@ -28,6 +30,7 @@ import (
 
 var errNoSQLInAuditPlan = errors.New(errors.DataConflict, fmt.Errorf("there is no SQLs in audit plan"))
 var errNoSQLNeedToBeAudited = errors.New(errors.DataConflict, fmt.Errorf("there is no SQLs need to be audited in audit plan"))
+var errInstanceNotExist = errors.New(errors.DataNotExist, fmt.Errorf("instance is not exist"))
 
 type Task interface {
 	Start() error
@@ -53,12 +56,20 @@ type SQL struct {
 func NewTask(entry *logrus.Entry, ap *model.AuditPlan) Task {
 	entry = entry.WithField("name", ap.Name)
 	switch ap.Type {
+	case TypeMySQLSlowLog:
+		return NewMySQLSlowLogTask(entry, ap)
+	case TypeMySQLMybatis, TypeAllAppExtract:
+		return NewSourceCodeTask(entry, ap)
 	case TypeMySQLSchemaMeta:
 		return NewSchemaMetaTask(entry, ap)
 	case TypeOracleTopSQL:
 		return NewOracleTopSQLTask(entry, ap)
 	case TypeTiDBAuditLog:
 		return NewTiDBAuditLogTask(entry, ap)
+	case TypeTiDBSlowQuery:
+		return NewTiDBSlowQueryTask(entry, ap)
+	case TypePostgreSQLTopSQL:
+		return NewPostgreSQLTopSQLTask(entry, ap)
 	default:
 		return NewDefaultTask(entry, ap)
 	}
@@ -120,7 +131,12 @@ func (at *baseTask) audit(task *model.Task) (*model.AuditPlanReportV2, error) {
 	if err != nil {
 		return nil, err
 	}
+	return at.saveReport(task, filteredSqls)
+}
 
+// saveReport saves the audit result of task as a report, the findings in the
+// report are compared with the previous report.
+func (at *baseTask) saveReport(task *model.Task, auditedSQLs []*model.AuditPlanSQLV2) (*model.AuditPlanReportV2, error) {
 	auditPlanReport := &model.AuditPlanReportV2{
 		AuditPlanID: at.ap.ID,
 		PassRate:    task.PassRate,
@@ -132,8 +148,22 @@ func (at *baseTask) audit(task *model.Task) (*model.AuditPlanReportV2, error) {
 			SQL:         executeSQL.Content,
 			Number:      uint(i + 1),
 			AuditResult: executeSQL.AuditResult,
+			AuditLevel:  executeSQL.AuditLevel,
+			Location:    sqlLocation(auditedSQLs[i].Info),
+			Fingerprint: auditedSQLs[i].Fingerprint,
 		})
 	}
+
+	previous, exist, err := at.persist.GetLatestAuditPlanReport(at.ap.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		diffReport(previous, auditPlanReport)
+	} else {
+		diffReport(nil, auditPlanReport)
+	}
+
 	err = at.persist.Save(auditPlanReport)
 	if err != nil {
 		return nil, err
@@ -141,6 +171,98 @@ func (at *baseTask) audit(task *model.Task) (*model.AuditPlanReportV2, error) {
 	return auditPlanReport, nil
 }
 
+// diffReport classifies the findings of report by comparing with the previous
+// report. The finding is the SQL which violates rules above normal level, it's
+// identified by fingerprint. The findings are all new if previous is nil.
+func diffReport(previous, report *model.AuditPlanReportV2) {
+	// previousFindings records whether the finding of previous report is still
+	// found in the report.
+	previousFindings := map[string]bool{}
+	if previous != nil {
+		report.PreviousReportID = previous.ID
+		for _, sql := range previous.AuditPlanReportSQLs {
+			if isFinding(sql) {
+				previousFindings[findingKey(sql)] = false
+			}
+		}
+	}
+
+	for _, sql := range report.AuditPlanReportSQLs {
+		if !isFinding(sql) {
+			continue
+		}
+		// the previous report SQL saved by old version is keyed by the SQL, so
+		// both the fingerprint and the SQL are looked up.
+		persisting := false
+		for _, key := range []string{sql.Fingerprint, sql.SQL} {
+			if _, ok := previousFindings[key]; ok && key != "" {
+				previousFindings[key] = true
+				persisting = true
+			}
+		}
+		if persisting {
+			sql.FindingStatus = model.FindingStatusPersisting
+			report.PersistingFindingCount++
+		} else {
+			sql.FindingStatus = model.FindingStatusNew
+			report.NewFindingCount++
+		}
+	}
+	for _, found := range previousFindings {
+		if !found {
+			report.ResolvedFindingCount++
+		}
+	}
+}
+
+// findingKey identifies the SQL across reports. The report SQL saved by old
+// version has no fingerprint, the SQL is used instead.
+func findingKey(sql *model.AuditPlanReportSQLV2) string {
+	if sql.Fingerprint != "" {
+		return sql.Fingerprint
+	}
+	return sql.SQL
+}
+
+var auditResultLevelRegexp = regexp.MustCompile(`^\[(normal|notice|warn|error)\]`)
+
+func isFinding(sql *model.AuditPlanReportSQLV2) bool {
+	level := sql.AuditLevel
+	if level == "" {
+		// the report SQL saved by old version has no audit level.
+		for _, line := range strings.Split(sql.AuditResult, "\n") {
+			m := auditResultLevelRegexp.FindStringSubmatch(line)
+			if m != nil && driver.RuleLevel(m[1]).More(driver.RuleLevel(level)) {
+				level = m[1]
+			}
+		}
+	}
+	return driver.RuleLevel(level).More(driver.RuleLevelNormal)
+}
+
+// sqlLocation formats the location of SQL in source code which is uploaded by
+// scannerd, e.g. "mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)".
+func sqlLocation(info model.JSON) string {
+	var location = struct {
+		MapperNamespace   string `json:"mapper_namespace"`
+		MapperStatementID string `json:"mapper_statement_id"`
+		SourceFile        string `json:"source_file"`
+		SourceLine        uint64 `json:"source_line"`
+	}{}
+	if len(info) == 0 || json.Unmarshal(info, &location) != nil || location.SourceFile == "" {
+		return ""
+	}
+
+	ret := location.SourceFile
+	if location.SourceLine > 0 {
+		ret = fmt.Sprintf("%s:%d", ret, location.SourceLine)
+	}
+	if location.MapperStatementID != "" {
+		ret = fmt.Sprintf("%s (%s.%s)", ret, location.MapperNamespace, location.MapperStatementID)
+	}
+	return ret
+}
+
 func filterSQLsByPeriod(params params.Params, sqls []*model.AuditPlanSQLV2) (filteredSqls []*model.AuditPlanSQLV2, err error) {
 	period := params.GetParam(paramKeyAuditSQLsScrappedInLastPeriodMinute).Int()
 	if period <= 0 {
@@ -173,7 +295,7 @@ type sqlCollector struct {
 	sync.WaitGroup
 	isStarted bool
 	cancel    chan struct{}
-	do        func()
+	do        func() error
 }
 
 func newSQLCollector(entry *logrus.Entry, ap *model.AuditPlan) *sqlCollector {
@@ -182,8 +304,9 @@ func newSQLCollector(entry *logrus.Entry, ap *model.AuditPlan) *sqlCollector {
 		sync.WaitGroup{},
 		false,
 		make(chan struct{}),
-		func() { // default
+		func() error { // default
 			entry.Warn("sql collector do nothing")
+			return nil
 		},
 	}
 }
@@ -192,9 +315,11 @@ func (at *sqlCollector) Start() error {
 	if at.isStarted {
 		return nil
 	}
+	// it's set before the loop runs, otherwise the task which is stopped
+	// right after started, such as a paused audit plan, keeps running.
+	at.isStarted = true
 	at.WaitGroup.Add(1)
 	go func() {
-		at.isStarted = true
 		at.logger.Infof("start task")
 		at.loop(at.cancel)
 		at.WaitGroup.Done()
@@ -228,7 +353,7 @@ func (at *sqlCollector) loop(cancel chan struct{}) {
 	if interval == 0 {
 		interval = 60
 	}
-	at.do()
+	at.collect()
 
 	tk := time.NewTicker(time.Duration(interval) * time.Minute)
 	for {
@@ -238,11 +363,39 @@ func (at *sqlCollector) loop(cancel chan struct{}) {
 			return
 		case <-tk.C:
 			at.logger.Infof("tick %s", at.ap.Name)
-			at.do()
+			at.collect()
 		}
 	}
 }
 
+// collect collects SQLs and saves the run history, so that the failure of
+// collection is visible to users.
+func (at *sqlCollector) collect() {
+	if at.ap.Params.GetParam(paramKeySkipCollectInMaintenancePeriod).Bool() && at.inMaintenancePeriod() {
+		at.logger.Infof("skip collecting sql in maintenance period of instance %s", at.ap.InstanceName)
+		return
+	}
+	startAt := time.Now()
+	err := at.do()
+	if err != nil {
+		at.logger.Errorf("collect sql fail, error: %v", err)
+	}
+	saveRun(at.persist, at.logger, at.ap.ID, model.AuditPlanRunTypeCollect, model.AuditPlanRunTriggerTypeSchedule, startAt, nil, err)
+}
+
+// inMaintenancePeriod checks whether now is in the maintenance period of the
+// instance, the heavy queries of collection may affect the maintenance.
+func (at *sqlCollector) inMaintenancePeriod() bool {
+	if at.ap.InstanceName == "" {
+		return false
+	}
+	instance, exist, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+	if err != nil || !exist {
+		return false
+	}
+	return len(instance.MaintenancePeriod) != 0 && instance.MaintenancePeriod.IsWithinScope(time.Now())
+}
+
 type DefaultTask struct {
 	*baseTask
 }
@@ -348,6 +501,137 @@ func (at *baseTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string]
 	return head, rows, count, nil
 }
 
+// MySQLSlowLogTask implement the Task interface.
+//
+// MySQLSlowLogTask receives slow queries uploaded by scannerd, the query time is
+// aggregated by fingerprint.
+type MySQLSlowLogTask struct {
+	*DefaultTask
+}
+
+func NewMySQLSlowLogTask(entry *logrus.Entry, ap *model.AuditPlan) *MySQLSlowLogTask {
+	return &MySQLSlowLogTask{NewDefaultTask(entry, ap)}
+}
+
+func (at *MySQLSlowLogTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	head := []Head{
+		{
+			Name: "fingerprint",
+			Desc: "SQL指纹",
+			Type: "sql",
+		},
+		{
+			Name: "sql",
+			Desc: "最后一次匹配到该指纹的语句",
+			Type: "sql",
+		},
+		{
+			Name: "counter",
+			Desc: "匹配到该指纹的语句数量",
+		},
+		{
+			Name: "last_receive_timestamp",
+			Desc: "最后一次匹配到该指纹的时间",
+		},
+		{
+			Name: "query_time_avg",
+			Desc: "平均执行时间(s)",
+		},
+		{
+			Name: "query_time_max",
+			Desc: "最大执行时间(s)",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			Counter              uint64  `json:"counter"`
+			LastReceiveTimestamp string  `json:"last_receive_timestamp"`
+			QueryTimeAvg         float64 `json:"query_time_avg"`
+			QueryTimeMax         float64 `json:"query_time_max"`
+		}{}
+		err := json.Unmarshal(sql.Info, &info)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		rows = append(rows, map[string]string{
+			"sql":                    sql.SQLContent,
+			"fingerprint":            sql.Fingerprint,
+			"counter":                strconv.FormatUint(info.Counter, 10),
+			"last_receive_timestamp": info.LastReceiveTimestamp,
+			"query_time_avg":         fmt.Sprintf("%v", utils.Round(info.QueryTimeAvg, 3)),
+			"query_time_max":         fmt.Sprintf("%v", utils.Round(info.QueryTimeMax, 3)),
+		})
+	}
+	return head, rows, count, nil
+}
+
+// SourceCodeTask implement the Task interface.
+//
+// SourceCodeTask receives SQLs extracted from MyBatis mapper or application
+// source code by scannerd, the location of SQL in code is shown with SQL.
+type SourceCodeTask struct {
+	*DefaultTask
+}
+
+func NewSourceCodeTask(entry *logrus.Entry, ap *model.AuditPlan) *SourceCodeTask {
+	return &SourceCodeTask{NewDefaultTask(entry, ap)}
+}
+
+func (at *SourceCodeTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	head := []Head{
+		{
+			Name: "fingerprint",
+			Desc: "SQL指纹",
+			Type: "sql",
+		},
+		{
+			Name: "sql",
+			Desc: "最后一次匹配到该指纹的语句",
+			Type: "sql",
+		},
+		{
+			Name: "counter",
+			Desc: "匹配到该指纹的语句数量",
+		},
+		{
+			Name: "last_receive_timestamp",
+			Desc: "最后一次匹配到该指纹的时间",
+		},
+		{
+			Name: "location",
+			Desc: "SQL所在位置",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			Counter              uint64 `json:"counter"`
+			LastReceiveTimestamp string `json:"last_receive_timestamp"`
+		}{}
+		err := json.Unmarshal(sql.Info, &info)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		rows = append(rows, map[string]string{
+			"sql":                    sql.SQLContent,
+			"fingerprint":            sql.Fingerprint,
+			"counter":                strconv.FormatUint(info.Counter, 10),
+			"last_receive_timestamp": info.LastReceiveTimestamp,
+			"location":               sqlLocation(sql.Info),
+		})
+	}
+	return head, rows, count, nil
+}
+
 type SchemaMetaTask struct {
 	*sqlCollector
 }
@@ -361,18 +645,16 @@ func NewSchemaMetaTask(entry *logrus.Entry, ap *model.AuditPlan) *SchemaMetaTask
 	return task
 }
 
-func (at *SchemaMetaTask) collectorDo() {
+func (at *SchemaMetaTask) collectorDo() error {
 	if at.ap.InstanceName == "" {
-		at.logger.Warnf("instance is not configured")
-		return
+		return fmt.Errorf("instance is not configured")
 	}
 	if at.ap.InstanceDatabase == "" {
-		at.logger.Warnf("instance schema is not configured")
-		return
+		return fmt.Errorf("instance schema is not configured")
 	}
 	instance, _, err := at.persist.GetInstanceByName(at.ap.InstanceName)
 	if err != nil {
-		return
+		return err
 	}
 	db, err := executor.NewExecutor(at.logger, &driver.DSN{
 		Host:             instance.Host,
@@ -384,47 +666,43 @@ func (at *SchemaMetaTask) collectorDo() {
 	},
 		at.ap.InstanceDatabase)
 	if err != nil {
-		at.logger.Errorf("connect to instance fail, error: %v", err)
-		return
+		return fmt.Errorf("connect to instance fail, error: %v", err)
 	}
 	defer db.Db.Close()
 
 	tables, err := db.ShowSchemaTables(at.ap.InstanceDatabase)
 	if err != nil {
-		at.logger.Errorf("get schema table fail, error: %v", err)
-		return
+		return fmt.Errorf("get schema table fail, error: %v", err)
 	}
 	var views []string
 	if at.ap.Params.GetParam("collect_view").Bool() {
 		views, err = db.ShowSchemaViews(at.ap.InstanceDatabase)
 		if err != nil {
-			at.logger.Errorf("get schema view fail, error: %v", err)
-			return
+			return fmt.Errorf("get schema view fail, error: %v", err)
 		}
 	}
 	sqls := make([]string, 0, len(tables)+len(views))
 	for _, table := range tables {
 		sql, err := db.ShowCreateTable(utils.SupplementalQuotationMarks(at.ap.InstanceDatabase), utils.SupplementalQuotationMarks(table))
 		if err != nil {
-			at.logger.Errorf("show create table fail, error: %v", err)
-			return
+			return fmt.Errorf("show create table fail, error: %v", err)
 		}
 		sqls = append(sqls, sql)
 	}
 	for _, view := range views {
 		sql, err := db.ShowCreateView(utils.SupplementalQuotationMarks(view))
 		if err != nil {
-			at.logger.Errorf("show create view fail, error: %v", err)
-			return
+			return fmt.Errorf("show create view fail, error: %v", err)
 		}
 		sqls = append(sqls, sql)
 	}
 	if len(sqls) > 0 {
 		err = at.persist.OverrideAuditPlanSQLs(at.ap.Name, convertRawSQLToModelSQLs(sqls))
 		if err != nil {
-			at.logger.Errorf("save schema meta to storage fail, error: %v", err)
+			return fmt.Errorf("save schema meta to storage fail, error: %v", err)
 		}
 	}
+	return nil
 }
 
 func (at *SchemaMetaTask) Audit() (*model.AuditPlanReportV2, error) {
@@ -470,25 +748,24 @@ func NewOracleTopSQLTask(entry *logrus.Entry, ap *model.AuditPlan) *OracleTopSQL
 	return task
 }
 
-func (at *OracleTopSQLTask) collectorDo() {
+func (at *OracleTopSQLTask) collectorDo() error {
 	select {
 	case <-at.cancel:
 		at.logger.Info("cancel task")
-		return
+		return nil
 	default:
 	}
 
 	if at.ap.InstanceName == "" {
-		at.logger.Warnf("instance is not configured")
-		return
+		return fmt.Errorf("instance is not configured")
 	}
 
 	inst, _, err := at.persist.GetInstanceByName(at.ap.InstanceName)
 	if err != nil {
-		at.logger.Warnf("get instance fail, error: %v", err)
-		return
+		return fmt.Errorf("get instance fail, error: %v", err)
 	}
-	// This depends on: https://github.com/actiontech/sqle-oracle-plugin.
+	// This depends on: https://github.com/actiontech/sqle-oracle-plugin or the
+	// plugin built from cmd/plugins/oracle.
 	// If your Oracle db plugin does not implement the parameter `service_name`,
 	// you can only use the default service name `XE`.
 	// TODO: using DB plugin to query SQL.
@@ -502,8 +779,7 @@ func (at *OracleTopSQLTask) collectorDo() {
 	}
 	db, err := oracle.NewDB(dsn)
 	if err != nil {
-		at.logger.Errorf("connect to instance fail, error: %v", err)
-		return
+		return fmt.Errorf("connect to instance fail, error: %v", err)
 	}
 	defer db.Close()
 
@@ -512,8 +788,7 @@ func (at *OracleTopSQLTask) collectorDo() {
 
 	sqls, err := db.QueryTopSQLs(ctx, at.ap.Params.GetParam("top_n").Int(), at.ap.Params.GetParam("order_by_column").String())
 	if err != nil {
-		at.logger.Errorf("query top sql fail, error: %v", err)
-		return
+		return fmt.Errorf("query top sql fail, error: %v", err)
 	}
 	if len(sqls) > 0 {
 		apSQLs := make([]*SQL, 0, len(sqls))
@@ -534,14 +809,33 @@ func (at *OracleTopSQLTask) collectorDo() {
 
 		err = at.persist.OverrideAuditPlanSQLs(at.ap.Name, convertSQLsToModelSQLs(apSQLs))
 		if err != nil {
-			at.logger.Errorf("save top sql to storage fail, error: %v", err)
+			return fmt.Errorf("save top sql to storage fail, error: %v", err)
 		}
 	}
+	return nil
 }
 
+// Audit audits the top SQLs with the instance, so the rules of Oracle plugin
+// which depend on the execution plan can check them.
 func (at *OracleTopSQLTask) Audit() (*model.AuditPlanReportV2, error) {
-	task := &model.Task{
-		DBType: at.ap.DBType,
+	var task *model.Task
+	if at.ap.InstanceName == "" {
+		task = &model.Task{
+			DBType: at.ap.DBType,
+		}
+	} else {
+		instance, exist, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return nil, errInstanceNotExist
+		}
+		task = &model.Task{
+			Instance: instance,
+			Schema:   at.ap.InstanceDatabase,
+			DBType:   at.ap.DBType,
+		}
 	}
 	return at.baseTask.audit(task)
 }
@@ -601,6 +895,146 @@ func (at *OracleTopSQLTask) GetSQLs(args map[string]interface{}) ([]Head, []map[
 	return heads, rows, count, nil
 }
 
+// PostgreSQLTopSQLTask implement the Task interface.
+//
+// PostgreSQLTopSQLTask is a loop task which collect Top SQL from pg_stat_statements of postgresql instance.
+type PostgreSQLTopSQLTask struct {
+	*sqlCollector
+}
+
+func NewPostgreSQLTopSQLTask(entry *logrus.Entry, ap *model.AuditPlan) *PostgreSQLTopSQLTask {
+	task := &PostgreSQLTopSQLTask{
+		sqlCollector: newSQLCollector(entry, ap),
+	}
+	task.sqlCollector.do = task.collectorDo
+	return task
+}
+
+func (at *PostgreSQLTopSQLTask) collectorDo() error {
+	select {
+	case <-at.cancel:
+		at.logger.Info("cancel task")
+		return nil
+	default:
+	}
+
+	if at.ap.InstanceName == "" {
+		return fmt.Errorf("instance is not configured")
+	}
+
+	inst, _, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+	if err != nil {
+		return fmt.Errorf("get instance fail, error: %v", err)
+	}
+	// pg_stat_statements is a cluster wide view, but it can only be queried in
+	// the database which the extension is created in, so connect to the default
+	// database and filter statements by the audit plan database.
+	dsn := &postgresql.DSN{
+		Host:     inst.Host,
+		Port:     inst.Port,
+		User:     inst.User,
+		Password: inst.Password,
+	}
+	db, err := postgresql.NewDB(dsn)
+	if err != nil {
+		return fmt.Errorf("connect to instance fail, error: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sqls, err := db.QueryTopSQLs(ctx, at.ap.InstanceDatabase, at.ap.Params.GetParam("top_n").Int(), at.ap.Params.GetParam("order_by_column").String())
+	if err != nil {
+		return fmt.Errorf("query top sql fail, error: %v", err)
+	}
+	if len(sqls) > 0 {
+		apSQLs := make([]*SQL, 0, len(sqls))
+		for _, sql := range sqls {
+			apSQLs = append(apSQLs, &SQL{
+				SQLContent:  sql.Query,
+				Fingerprint: sql.Query,
+				Info: map[string]interface{}{
+					postgresql.PgStatStatementsColumnCalls:          sql.Calls,
+					postgresql.PgStatStatementsColumnTotalExecTime:  sql.TotalExecTime,
+					postgresql.PgStatStatementsColumnMeanExecTime:   sql.MeanExecTime,
+					postgresql.PgStatStatementsColumnRows:           sql.Rows,
+					postgresql.PgStatStatementsColumnSharedBlksHit:  sql.SharedBlksHit,
+					postgresql.PgStatStatementsColumnSharedBlksRead: sql.SharedBlksRead,
+				},
+			})
+		}
+
+		err = at.persist.OverrideAuditPlanSQLs(at.ap.Name, convertSQLsToModelSQLs(apSQLs))
+		if err != nil {
+			return fmt.Errorf("save top sql to storage fail, error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (at *PostgreSQLTopSQLTask) Audit() (*model.AuditPlanReportV2, error) {
+	task := &model.Task{
+		DBType: at.ap.DBType,
+	}
+	return at.baseTask.audit(task)
+}
+
+func (at *PostgreSQLTopSQLTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	heads := []Head{
+		{
+			Name: "sql",
+			Desc: "SQL语句",
+			Type: "sql",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnCalls,
+			Desc: "总执行次数",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnTotalExecTime,
+			Desc: "总执行时间(ms)",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnMeanExecTime,
+			Desc: "平均执行时间(ms)",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnRows,
+			Desc: "返回行数",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnSharedBlksHit,
+			Desc: "共享块命中数",
+		},
+		{
+			Name: postgresql.PgStatStatementsColumnSharedBlksRead,
+			Desc: "共享块读取数",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		info := &postgresql.PgStatStatement{}
+		if err := json.Unmarshal(sql.Info, info); err != nil {
+			return nil, nil, 0, err
+		}
+		rows = append(rows, map[string]string{
+			"sql":                                  sql.SQLContent,
+			postgresql.PgStatStatementsColumnCalls: strconv.FormatInt(info.Calls, 10),
+			postgresql.PgStatStatementsColumnTotalExecTime:  fmt.Sprintf("%v", utils.Round(info.TotalExecTime, 3)),
+			postgresql.PgStatStatementsColumnMeanExecTime:   fmt.Sprintf("%v", utils.Round(info.MeanExecTime, 3)),
+			postgresql.PgStatStatementsColumnRows:           strconv.FormatInt(info.Rows, 10),
+			postgresql.PgStatStatementsColumnSharedBlksHit:  strconv.FormatInt(info.SharedBlksHit, 10),
+			postgresql.PgStatStatementsColumnSharedBlksRead: strconv.FormatInt(info.SharedBlksRead, 10),
+		})
+	}
+	return heads, rows, count, nil
+}
+
 type TiDBAuditLogTask struct {
 	*DefaultTask
 }
@@ -610,6 +1044,12 @@ func NewTiDBAuditLogTask(entry *logrus.Entry, ap *model.AuditPlan) *TiDBAuditLog
 }
 
 func (at *TiDBAuditLogTask) Audit() (*model.AuditPlanReportV2, error) {
+	return at.baseTask.tidbAudit()
+}
+
+// tidbAudit audits the SQLs with the schema recorded in SQL info, the missing
+// schema of SQL is filled in by TiDBAuditHook.
+func (at *baseTask) tidbAudit() (*model.AuditPlanReportV2, error) {
 	var task *model.Task
 	if at.ap.InstanceName == "" {
 		task = &model.Task{
@@ -665,37 +1105,264 @@ func (at *TiDBAuditLogTask) Audit() (*model.AuditPlanReportV2, error) {
 	if err != nil {
 		return nil, err
 	}
+	return at.saveReport(task, filteredSqls)
+}
 
-	auditPlanReport := &model.AuditPlanReportV2{
-		AuditPlanID: at.ap.ID,
-		PassRate:    task.PassRate,
-		Score:       task.Score,
-		AuditLevel:  task.AuditLevel,
+const (
+	// tidbSlowQuerySQL aggregates slow queries of all TiDB servers in the cluster by digest.
+	tidbSlowQuerySQL = `
+SELECT
+	Digest AS digest,
+	ANY_VALUE(Query) AS query,
+	ANY_VALUE(DB) AS db,
+	COUNT(*) AS counter,
+	SUM(Query_time) AS query_time_sum,
+	MAX(Query_time) AS query_time_max,
+	SUM(Process_keys) AS process_keys,
+	SUM(Total_keys) AS total_keys,
+	MAX(Time) AS last_receive_timestamp
+FROM
+	INFORMATION_SCHEMA.CLUSTER_SLOW_QUERY
+WHERE
+	Is_internal = false AND Time > DATE_SUB(NOW(), INTERVAL ? MINUTE)
+GROUP BY Digest
+ORDER BY query_time_sum DESC
+LIMIT ?`
+
+	// tidbStatementsSummarySQL queries the statements which are not recorded in slow log,
+	// the unit of latency is nanosecond.
+	tidbStatementsSummarySQL = `
+SELECT
+	DIGEST AS digest,
+	ANY_VALUE(QUERY_SAMPLE_TEXT) AS query,
+	ANY_VALUE(SCHEMA_NAME) AS db,
+	SUM(EXEC_COUNT) AS counter,
+	SUM(SUM_LATENCY) / 1000000000 AS query_time_sum,
+	MAX(MAX_LATENCY) / 1000000000 AS query_time_max,
+	SUM(AVG_PROCESSED_KEYS * EXEC_COUNT) AS process_keys,
+	SUM(AVG_TOTAL_KEYS * EXEC_COUNT) AS total_keys,
+	MAX(LAST_SEEN) AS last_receive_timestamp
+FROM
+	INFORMATION_SCHEMA.CLUSTER_STATEMENTS_SUMMARY
+WHERE
+	STMT_TYPE <> 'Use' AND LAST_SEEN > DATE_SUB(NOW(), INTERVAL ? MINUTE)
+GROUP BY DIGEST
+ORDER BY query_time_sum DESC
+LIMIT ?`
+)
+
+// TiDBSlowQueryTask implement the Task interface.
+//
+// TiDBSlowQueryTask is a loop task which collect slow queries from
+// INFORMATION_SCHEMA.CLUSTER_SLOW_QUERY (and CLUSTER_STATEMENTS_SUMMARY optionally) of TiDB.
+type TiDBSlowQueryTask struct {
+	*sqlCollector
+}
+
+func NewTiDBSlowQueryTask(entry *logrus.Entry, ap *model.AuditPlan) *TiDBSlowQueryTask {
+	task := &TiDBSlowQueryTask{
+		sqlCollector: newSQLCollector(entry, ap),
 	}
-	for i, executeSQL := range task.ExecuteSQLs {
-		auditPlanReport.AuditPlanReportSQLs = append(auditPlanReport.AuditPlanReportSQLs, &model.AuditPlanReportSQLV2{
-			SQL:         executeSQL.Content,
-			Number:      uint(i + 1),
-			AuditResult: executeSQL.AuditResult,
-		})
+	task.sqlCollector.do = task.collectorDo
+	return task
+}
+
+func (at *TiDBSlowQueryTask) collectorDo() error {
+	select {
+	case <-at.cancel:
+		at.logger.Info("cancel task")
+		return nil
+	default:
 	}
-	err = at.persist.Save(auditPlanReport)
+
+	if at.ap.InstanceName == "" {
+		return fmt.Errorf("instance is not configured")
+	}
+
+	inst, _, err := at.persist.GetInstanceByName(at.ap.InstanceName)
+	if err != nil {
+		return fmt.Errorf("get instance fail, error: %v", err)
+	}
+	db, err := executor.NewExecutor(at.logger, &driver.DSN{
+		Host:             inst.Host,
+		Port:             inst.Port,
+		User:             inst.User,
+		Password:         inst.Password,
+		AdditionalParams: inst.AdditionalParams,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("connect to instance fail, error: %v", err)
+	}
+	defer db.Db.Close()
+
+	interval := at.ap.Params.GetParam(paramKeyCollectIntervalMinute).Int()
+	if interval == 0 {
+		interval = 60
+	}
+	topN := at.ap.Params.GetParam("top_n").Int()
+
+	sqls, err := at.querySQLs(db, tidbSlowQuerySQL, interval, topN)
+	if err != nil {
+		return fmt.Errorf("query slow query fail, error: %v", err)
+	}
+	if at.ap.Params.GetParam("collect_statements_summary").Bool() {
+		summarySQLs, err := at.querySQLs(db, tidbStatementsSummarySQL, interval, topN)
+		if err != nil {
+			return fmt.Errorf("query statements summary fail, error: %v", err)
+		}
+		// the statement recorded in slow log has more accurate execution stats.
+		collected := make(map[string]struct{}, len(sqls))
+		for _, sql := range sqls {
+			collected[sql.Fingerprint] = struct{}{}
+		}
+		for _, sql := range summarySQLs {
+			if _, ok := collected[sql.Fingerprint]; !ok {
+				sqls = append(sqls, sql)
+			}
+		}
+	}
+
+	if len(sqls) > 0 {
+		err = at.persist.OverrideAuditPlanSQLs(at.ap.Name, convertSQLsToModelSQLs(sqls))
+		if err != nil {
+			return fmt.Errorf("save slow query to storage fail, error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (at *TiDBSlowQueryTask) querySQLs(db *executor.Executor, query string, intervalMinute, topN int) ([]*SQL, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	columns, rows, err := db.Db.QueryWithContext(ctx, query, intervalMinute, topN)
 	if err != nil {
 		return nil, err
 	}
-	return auditPlanReport, nil
+
+	sqls := make([]*SQL, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(columns))
+		for i, column := range columns {
+			record[column] = row[i].String
+		}
+		content := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(record["query"]), ";"))
+		if content == "" {
+			continue
+		}
+
+		counter, _ := strconv.ParseUint(record["counter"], 10, 64)
+		queryTimeSum, _ := strconv.ParseFloat(record["query_time_sum"], 64)
+		queryTimeMax, _ := strconv.ParseFloat(record["query_time_max"], 64)
+		processKeys, _ := strconv.ParseFloat(record["process_keys"], 64)
+		totalKeys, _ := strconv.ParseFloat(record["total_keys"], 64)
+		var queryTimeAvg float64
+		if counter > 0 {
+			queryTimeAvg = queryTimeSum / float64(counter)
+		}
+		sqls = append(sqls, &SQL{
+			SQLContent:  content,
+			Fingerprint: record["digest"],
+			Info: map[string]interface{}{
+				"counter":                counter,
+				"last_receive_timestamp": record["last_receive_timestamp"],
+				"query_time_avg":         queryTimeAvg,
+				"query_time_max":         queryTimeMax,
+				"process_keys":           int64(processKeys),
+				"total_keys":             int64(totalKeys),
+				server.AuditSchema:       record["db"],
+			},
+		})
+	}
+	return sqls, nil
+}
+
+func (at *TiDBSlowQueryTask) Audit() (*model.AuditPlanReportV2, error) {
+	return at.baseTask.tidbAudit()
+}
+
+func (at *TiDBSlowQueryTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	heads := []Head{
+		{
+			Name: "sql",
+			Desc: "SQL语句",
+			Type: "sql",
+		},
+		{
+			Name: "schema",
+			Desc: "库名",
+		},
+		{
+			Name: "counter",
+			Desc: "执行次数",
+		},
+		{
+			Name: "query_time_avg",
+			Desc: "平均执行时间(s)",
+		},
+		{
+			Name: "query_time_max",
+			Desc: "最大执行时间(s)",
+		},
+		{
+			Name: "process_keys",
+			Desc: "处理的key数量",
+		},
+		{
+			Name: "total_keys",
+			Desc: "扫描的key数量",
+		},
+		{
+			Name: "last_receive_timestamp",
+			Desc: "最后一次执行的时间",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			Counter              uint64  `json:"counter"`
+			LastReceiveTimestamp string  `json:"last_receive_timestamp"`
+			QueryTimeAvg         float64 `json:"query_time_avg"`
+			QueryTimeMax         float64 `json:"query_time_max"`
+			ProcessKeys          int64   `json:"process_keys"`
+			TotalKeys            int64   `json:"total_keys"`
+			Schema               string  `json:"AuditSchema"`
+		}{}
+		if err := json.Unmarshal(sql.Info, &info); err != nil {
+			return nil, nil, 0, err
+		}
+		rows = append(rows, map[string]string{
+			"sql":                    sql.SQLContent,
+			"schema":                 info.Schema,
+			"counter":                strconv.FormatUint(info.Counter, 10),
+			"query_time_avg":         fmt.Sprintf("%v", utils.Round(info.QueryTimeAvg, 3)),
+			"query_time_max":         fmt.Sprintf("%v", utils.Round(info.QueryTimeMax, 3)),
+			"process_keys":           strconv.FormatInt(info.ProcessKeys, 10),
+			"total_keys":             strconv.FormatInt(info.TotalKeys, 10),
+			"last_receive_timestamp": info.LastReceiveTimestamp,
+		})
+	}
+	return heads, rows, count, nil
 }
 
 // 审核前填充上缺失的schema, 审核后还原被审核SQL, 并添加注释说明sql在哪个库执行的
 type TiDBAuditHook struct {
-	originalSQL string
+	// originalSQLs 记录每条SQL填充schema前的内容, 批量审核时所有SQL的BeforeAudit先于AfterAudit执行
+	originalSQLs map[*model.ExecuteSQL]string
 }
 
 func (t *TiDBAuditHook) BeforeAudit(sql *model.ExecuteSQL) {
 	if sql.Schema == "" {
 		return
 	}
-	t.originalSQL = sql.Content
+	if t.originalSQLs == nil {
+		t.originalSQLs = map[*model.ExecuteSQL]string{}
+	}
+	t.originalSQLs[sql] = sql.Content
 	newSQL, err := tidbCompletionSchema(sql.Content, sql.Schema)
 	if err != nil {
 		return
@@ -707,7 +1374,11 @@ func (t *TiDBAuditHook) AfterAudit(sql *model.ExecuteSQL) {
 	if sql.Schema == "" {
 		return
 	}
-	sql.Content = fmt.Sprintf("%v -- current schema: %v", t.originalSQL, sql.Schema)
+	originalSQL, ok := t.originalSQLs[sql]
+	if !ok {
+		originalSQL = sql.Content
+	}
+	sql.Content = fmt.Sprintf("%v -- current schema: %v", originalSQL, sql.Schema)
 }
 
 // 填充sql缺失的schema
@@ -0,0 +1,230 @@
+package auditplan
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiDBSlowQueryTask_querySQLs(t *testing.T) {
+	db, mock, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("INFORMATION_SCHEMA.CLUSTER_SLOW_QUERY").
+		WithArgs(60, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"digest", "query", "db", "counter", "query_time_sum", "query_time_max", "process_keys", "total_keys", "last_receive_timestamp"}).
+			AddRow("digest1", "select * from t1 where id = 1;", "db1", "4", "2.0", "1.2", "400", "800", "2022-08-19T06:59:02+08:00").
+			AddRow("digest2", "", "db1", "1", "1.0", "1.0", "1", "1", "2022-08-19T06:59:02+08:00"))
+
+	task := NewTiDBSlowQueryTask(log.NewEntry(), &model.AuditPlan{})
+	sqls, err := task.querySQLs(db, tidbSlowQuerySQL, 60, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Len(t, sqls, 1)
+	assert.Equal(t, "digest1", sqls[0].Fingerprint)
+	assert.Equal(t, "select * from t1 where id = 1", sqls[0].SQLContent)
+	assert.Equal(t, uint64(4), sqls[0].Info["counter"])
+	assert.Equal(t, 0.5, sqls[0].Info["query_time_avg"])
+	assert.Equal(t, 1.2, sqls[0].Info["query_time_max"])
+	assert.Equal(t, int64(400), sqls[0].Info["process_keys"])
+	assert.Equal(t, int64(800), sqls[0].Info["total_keys"])
+	assert.Equal(t, "db1", sqls[0].Info[server.AuditSchema])
+}
+
+func TestSQLLocation(t *testing.T) {
+	assert.Equal(t, "", sqlLocation(nil))
+	assert.Equal(t, "", sqlLocation(model.JSON(`{"counter": 1}`)))
+	assert.Equal(t, "main.go:10", sqlLocation(model.JSON(`{"source_file": "main.go", "source_line": 10}`)))
+	assert.Equal(t, "mapper/UserMapper.xml:12 (com.example.UserMapper.getUser)", sqlLocation(model.JSON(
+		`{"source_file": "mapper/UserMapper.xml", "source_line": 12, "mapper_namespace": "com.example.UserMapper", "mapper_statement_id": "getUser"}`)))
+}
+
+func TestSourceCodeTask_GetSQLs(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	mock.ExpectPrepare("SELECT").ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "sql_content", "info"}).
+			AddRow("select * from t1 where id = ?", "select * from t1 where id = 1",
+				`{"counter": 2, "last_receive_timestamp": "2022-08-19T06:59:02+08:00", "source_file": "main.go", "source_line": 10}`))
+	mock.ExpectPrepare("SELECT COUNT").ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow("1"))
+
+	task := NewSourceCodeTask(log.NewEntry(), &model.AuditPlan{Name: "ap"})
+	head, rows, count, err := task.GetSQLs(map[string]interface{}{"audit_plan_name": "ap"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, uint64(1), count)
+
+	names := []string{}
+	for _, h := range head {
+		names = append(names, h.Name)
+	}
+	assert.Equal(t, []string{"fingerprint", "sql", "counter", "last_receive_timestamp", "location"}, names)
+	assert.Equal(t, []map[string]string{{
+		"fingerprint":            "select * from t1 where id = ?",
+		"sql":                    "select * from t1 where id = 1",
+		"counter":                "2",
+		"last_receive_timestamp": "2022-08-19T06:59:02+08:00",
+		"location":               "main.go:10",
+	}}, rows)
+}
+
+func TestDiffReport(t *testing.T) {
+	previous := &model.AuditPlanReportV2{
+		Model: model.Model{ID: 1},
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{Fingerprint: "select * from t1", AuditLevel: "warn"},
+			{Fingerprint: "select * from t2", AuditLevel: "error"},
+			{Fingerprint: "select * from t3", AuditLevel: "normal"},
+			// the report SQL saved by old version
+			{SQL: "select * from t4 where id = 1", AuditResult: "[notice]xxx\n[warn]yyy"},
+		},
+	}
+	report := &model.AuditPlanReportV2{
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{Fingerprint: "select * from t1", AuditLevel: "warn"},
+			{Fingerprint: "select * from t3", AuditLevel: "warn"},
+			{Fingerprint: "select * from t5", AuditLevel: "normal"},
+			{SQL: "select * from t4 where id = 1", AuditResult: "[warn]yyy"},
+		},
+	}
+	diffReport(previous, report)
+	assert.Equal(t, uint(1), report.PreviousReportID)
+	assert.Equal(t, model.FindingStatusPersisting, report.AuditPlanReportSQLs[0].FindingStatus)
+	assert.Equal(t, model.FindingStatusNew, report.AuditPlanReportSQLs[1].FindingStatus)
+	assert.Equal(t, "", report.AuditPlanReportSQLs[2].FindingStatus)
+	assert.Equal(t, model.FindingStatusPersisting, report.AuditPlanReportSQLs[3].FindingStatus)
+	assert.Equal(t, uint(1), report.NewFindingCount)
+	assert.Equal(t, uint(2), report.PersistingFindingCount)
+	assert.Equal(t, uint(1), report.ResolvedFindingCount)
+
+	report = &model.AuditPlanReportV2{
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{Fingerprint: "select * from t1", AuditLevel: "notice"},
+			{Fingerprint: "select * from t2", AuditLevel: "normal"},
+		},
+	}
+	diffReport(nil, report)
+	assert.Equal(t, uint(0), report.PreviousReportID)
+	assert.Equal(t, model.FindingStatusNew, report.AuditPlanReportSQLs[0].FindingStatus)
+	assert.Equal(t, uint(1), report.NewFindingCount)
+	assert.Equal(t, uint(0), report.ResolvedFindingCount)
+
+	// the previous report is saved by old version without fingerprint.
+	previous = &model.AuditPlanReportV2{
+		Model: model.Model{ID: 2},
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{SQL: "select * from t1 where id = 1", AuditResult: "[warn]xxx"},
+			{SQL: "select * from t2 where id = 1", AuditResult: "[error]yyy"},
+		},
+	}
+	report = &model.AuditPlanReportV2{
+		AuditPlanReportSQLs: []*model.AuditPlanReportSQLV2{
+			{SQL: "select * from t1 where id = 1", Fingerprint: "select * from t1 where id = ?", AuditLevel: "warn"},
+			{SQL: "select * from t3 where id = 1", Fingerprint: "select * from t3 where id = ?", AuditLevel: "warn"},
+		},
+	}
+	diffReport(previous, report)
+	assert.Equal(t, model.FindingStatusPersisting, report.AuditPlanReportSQLs[0].FindingStatus)
+	assert.Equal(t, model.FindingStatusNew, report.AuditPlanReportSQLs[1].FindingStatus)
+	assert.Equal(t, uint(1), report.NewFindingCount)
+	assert.Equal(t, uint(1), report.PersistingFindingCount)
+	assert.Equal(t, uint(1), report.ResolvedFindingCount)
+}
+
+func TestTiDBAuditHook(t *testing.T) {
+	sqls := []*model.ExecuteSQL{
+		{BaseSQL: model.BaseSQL{Content: "select * from t1", Schema: "db1"}},
+		{BaseSQL: model.BaseSQL{Content: "select * from t2", Schema: "db2"}},
+		{BaseSQL: model.BaseSQL{Content: "select * from t3"}},
+	}
+	hook := &TiDBAuditHook{}
+	// the SQLs are audited in batch.
+	for _, sql := range sqls {
+		hook.BeforeAudit(sql)
+	}
+	assert.Equal(t, "SELECT * FROM db1.t1", sqls[0].Content)
+	assert.Equal(t, "SELECT * FROM db2.t2", sqls[1].Content)
+	for _, sql := range sqls {
+		hook.AfterAudit(sql)
+	}
+	assert.Equal(t, "select * from t1 -- current schema: db1", sqls[0].Content)
+	assert.Equal(t, "select * from t2 -- current schema: db2", sqls[1].Content)
+	assert.Equal(t, "select * from t3", sqls[2].Content)
+}
+
+func TestOracleTopSQLTask_AuditInstanceNotExist(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	mock.ExpectQuery("SELECT \\* FROM `instances`").WithArgs("inst_1").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	task := NewOracleTopSQLTask(log.NewEntry(), &model.AuditPlan{Name: "ap", InstanceName: "inst_1"})
+	_, err = task.Audit()
+	assert.Equal(t, errInstanceNotExist, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLCollector_SkipInMaintenancePeriod(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+	model.InitMockStorage(mockDB)
+
+	// the period of the whole day always contains now, and the period two
+	// hours later never contains now.
+	hour := (time.Now().Hour() + 2) % 24
+	inside := `[{"start_hour": 0, "start_minute": 0, "end_hour": 23, "end_minute": 59}]`
+	outside := fmt.Sprintf(`[{"start_hour": %d, "start_minute": 0, "end_hour": %d, "end_minute": 30}]`, hour, hour)
+
+	cases := []struct {
+		skip              bool
+		maintenancePeriod string
+		collected         int
+	}{
+		{skip: true, maintenancePeriod: inside, collected: 0},
+		{skip: true, maintenancePeriod: outside, collected: 1},
+		{skip: false, maintenancePeriod: inside, collected: 1},
+	}
+	for _, c := range cases {
+		ap := &model.AuditPlan{
+			Name:         "ap",
+			InstanceName: "inst_1",
+			Params: params.Params{
+				{Key: paramKeyCollectIntervalMinute, Value: "60", Type: params.ParamTypeInt},
+				{Key: paramKeySkipCollectInMaintenancePeriod, Value: fmt.Sprintf("%v", c.skip), Type: params.ParamTypeBool},
+			},
+		}
+		if c.skip {
+			mock.ExpectQuery("SELECT \\* FROM `instances`").WithArgs("inst_1").
+				WillReturnRows(sqlmock.NewRows([]string{"name", "maintenance_period"}).AddRow("inst_1", []byte(c.maintenancePeriod)))
+		}
+
+		collector := newSQLCollector(log.NewEntry(), ap)
+		collected := 0
+		collector.do = func() error {
+			collected++
+			return nil
+		}
+		// the collector collects once at start, Stop waits for it.
+		assert.NoError(t, collector.Start())
+		assert.NoError(t, collector.Stop())
+		assert.Equal(t, c.collected, collected, "skip: %v, maintenance period: %v", c.skip, c.maintenancePeriod)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/notification"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NotifyDigestHour is the hour of day when the daily digest is sent.
+const NotifyDigestHour = 9
+
+func (s *Sqled) notifyDigestLoop() {
+	tick := time.NewTicker(1 * time.Hour)
+	defer tick.Stop()
+	entry := log.NewEntry().WithField("type", "notify_digest")
+	for {
+		select {
+		case <-s.exit:
+			return
+		case now := <-tick.C:
+			// the ticker fires once an hour, so the digest is sent once a day.
+			if now.Hour() == NotifyDigestHour {
+				s.SendNotifyDigests(entry)
+			}
+		}
+	}
+}
+
+func (s *Sqled) SendNotifyDigests(entry *logrus.Entry) {
+	if err := notification.SendNotifyDigests(); err != nil {
+		entry.Errorf("send notify digests error: %v", err)
+	}
+}
@@ -96,6 +96,7 @@ func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
 		goto Error
 	}
 	if d, err = drvMgr.GetAuditDriver(); err != nil {
+		drvMgr.Close(context.TODO())
 		goto Error
 	}
 	action.driver = d
@@ -129,6 +130,7 @@ func (s *Sqled) Start() {
 	go s.taskLoop()
 	go s.cleanLoop()
 	go s.workflowScheduleLoop()
+	go s.notifyDigestLoop()
 }
 
 // taskLoop is a task loop used to receive action from queue.
@@ -245,19 +247,13 @@ func (a *action) audit() (err error) {
 	// skip generate if audit is static
 	if a.task.SQLSource == model.TaskSQLSourceFromMyBatisXMLFile || a.task.InstanceId == 0 {
 		a.entry.Warn("skip generate rollback SQLs")
+	} else if c, _ := driver.GetCapabilities(a.task.DBType); !c.Rollback {
+		a.entry.Warnf("skip generate rollback SQLs, driver %v doesn't support rollback", a.task.DBType)
 	} else {
-		drvMgr, err := newDriverManagerWithAudit(a.entry, a.task.Instance, a.task.Schema, a.task.DBType, "")
-		if err != nil {
-			return xerrors.Wrap(err, "new driver for generate rollback SQL")
-		}
-		defer drvMgr.Close(context.TODO())
-
-		d, err := drvMgr.GetAuditDriver()
-		if err != nil {
-			return err
-		}
-
-		rollbackSQLs, err := genRollbackSQL(a.entry, a.task, d)
+		// The driver of the action is used to generate rollback SQLs, a new
+		// driver needs another process from the plugin pool, which may be
+		// exhausted by the concurrent actions.
+		rollbackSQLs, err := genRollbackSQL(a.entry, a.task, a.driver)
 		if err != nil {
 			return err
 		}
@@ -299,8 +295,10 @@ func (a *action) execute() (err error) {
 		return err
 	}
 
-	// txSQLs keep adjacent DMLs, execute in one transaction.
+	// txSQLs keep adjacent DMLs, execute in one transaction. The DDLs are kept
+	// too if the driver supports transactional DDL.
 	var txSQLs []*model.ExecuteSQL
+	capabilities, _ := driver.GetCapabilities(task.DBType)
 
 outerLoop:
 	for i, executeSQL := range task.ExecuteSQLs {
@@ -309,7 +307,11 @@ outerLoop:
 			break outerLoop
 		}
 
-		switch nodes[0].Type {
+		sqlType := nodes[0].Type
+		if sqlType == driver.SQLTypeDDL && capabilities.TransactionalDDL {
+			sqlType = driver.SQLTypeDML
+		}
+		switch sqlType {
 		case driver.SQLTypeDML:
 			txSQLs = append(txSQLs, executeSQL)
 
@@ -468,6 +470,9 @@ func newDriverManagerWithAudit(l *logrus.Entry, inst *model.Instance, database s
 	if dbType == "" {
 		dbType = inst.DbType
 	}
+	if c, exist := driver.GetCapabilities(dbType); inst == nil && exist && !c.OfflineAudit {
+		return nil, xerrors.Errorf("driver %v doesn't support offline audit", dbType)
+	}
 
 	st := model.GetStorage()
 
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/actiontech/sqle/sqle/driver"
@@ -15,6 +17,7 @@ import (
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/agiledragon/gomonkey"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -316,3 +319,76 @@ func TestScoreTask(t *testing.T) {
 
 	assert.Equal(t, int32(45), score)
 }
+
+type mockRollbackDriver struct {
+	mockDriver
+}
+
+func (d *mockRollbackDriver) Audit(ctx context.Context, sql string) (*driver.AuditResult, error) {
+	return driver.NewInspectResults(), nil
+}
+
+func (d *mockRollbackDriver) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
+	return "rollback " + sql, "", nil
+}
+
+func Test_action_audit_ConcurrentWithPluginPool(t *testing.T) {
+	// pool simulates the process pool of plugin, each action holds a process
+	// until it's done.
+	pool := make(chan struct{}, driver.DefaultPluginPoolSize)
+	acquire := func() error {
+		select {
+		case pool <- struct{}{}:
+			return nil
+		case <-time.After(100 * time.Millisecond):
+			return errors.New("no process of plugin is available")
+		}
+	}
+
+	patches := gomonkey.ApplyFunc(newDriverManagerWithAudit, func(_ *logrus.Entry, _ *model.Instance, _, _, _ string) (driver.DriverManager, error) {
+		if err := acquire(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("unexpected driver manager")
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetSqlWhitelist", func(_ *model.Storage, _, _ uint32) ([]model.SqlWhitelist, uint32, error) {
+		return nil, 0, nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateRollbackSQLs", func(_ *model.Storage, rollbackSQLs []*model.RollbackSQL) error {
+		assert.Len(t, rollbackSQLs, 1)
+		assert.Equal(t, "rollback create table t1(id int)", rollbackSQLs[0].Content)
+		return nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateExecuteSQLs", func(_ *model.Storage, _ []*model.ExecuteSQL) error {
+		return nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateTask", func(_ *model.Storage, _ *model.Task, _ ...interface{}) error {
+		return nil
+	})
+
+	actions := make([]*action, driver.DefaultPluginPoolSize)
+	for i := range actions {
+		assert.NoError(t, acquire())
+		actions[i] = getAction([]string{"create table t1(id int)"}, ActionTypeAudit, &mockRollbackDriver{})
+		actions[i].task.SQLSource = model.TaskSQLSourceFromFormData
+		actions[i].task.InstanceId = 1
+		actions[i].task.DBType = driver.DriverTypeMySQL
+	}
+
+	wg := sync.WaitGroup{}
+	errs := make([]error, len(actions))
+	for i := range actions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = actions[i].audit()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, act := range actions {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, model.TaskStatusAudited, act.task.Status)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/utils"
 
@@ -33,7 +34,27 @@ func Run(config *config.Config) error {
 		}
 	}
 
-	if err := driver.InitPlugins(config.Server.SqleCnf.PluginPath); err != nil {
+	var pluginMemoryLimit uint64
+	if config.Server.SqleCnf.PluginMemoryLimit > 0 {
+		pluginMemoryLimit = uint64(config.Server.SqleCnf.PluginMemoryLimit) * 1024 * 1024
+	}
+	pluginPoolCfg := &driver.PluginPoolConfig{
+		Size:                config.Server.SqleCnf.PluginPoolSize,
+		HealthCheckInterval: time.Duration(config.Server.SqleCnf.PluginHealthCheckInterval) * time.Second,
+		AcquireTimeout:      time.Duration(config.Server.SqleCnf.PluginAcquireTimeout) * time.Second,
+		RPCTimeout: driver.PluginRPCTimeout{
+			Audit:   time.Duration(config.Server.SqleCnf.PluginAuditTimeout) * time.Second,
+			Exec:    time.Duration(config.Server.SqleCnf.PluginExecTimeout) * time.Second,
+			Query:   time.Duration(config.Server.SqleCnf.PluginQueryTimeout) * time.Second,
+			Explain: time.Duration(config.Server.SqleCnf.PluginExplainTimeout) * time.Second,
+		},
+		ResourceLimit: driver.PluginResourceLimit{
+			Memory:     pluginMemoryLimit,
+			CPU:        config.Server.SqleCnf.PluginCPULimit,
+			CgroupPath: config.Server.SqleCnf.PluginCgroupPath,
+		},
+	}
+	if err := driver.InitPlugins(config.Server.SqleCnf.PluginPath, pluginPoolCfg); err != nil {
 		return fmt.Errorf("init plugins error: %v", err)
 	}
 
@@ -75,6 +96,8 @@ func Run(config *config.Config) error {
 			return fmt.Errorf("create default workflow template failed while auto migrating table: %v", err)
 		}
 	}
+	recordPluginVersions(s)
+
 	exitChan := make(chan struct{})
 	server.InitSqled(exitChan)
 	auditPlanMgrQuitCh := auditplan.InitManager(model.GetStorage())
@@ -106,3 +129,28 @@ func Run(config *config.Config) error {
 	log.Logger().Info("stop sqled server")
 	return nil
 }
+
+// recordPluginVersions records the version of plugin if its binary is changed
+// since the last startup.
+func recordPluginVersions(s *model.Storage) {
+	for _, p := range driver.AllPlugins() {
+		latest, exist, err := s.GetLatestPluginVersion(p.Name)
+		if err != nil {
+			log.Logger().Warnf("get version of plugin %v failed: %v", p.Name, err)
+			continue
+		}
+		if exist && latest.Action != model.PluginActionUnload && latest.Checksum == p.Checksum {
+			continue
+		}
+		err = s.Save(&model.PluginVersion{
+			PluginName: p.Name,
+			Version:    p.Version,
+			Checksum:   p.Checksum,
+			Action:     model.PluginActionStartup,
+			RuleCount:  len(p.Rules),
+		})
+		if err != nil {
+			log.Logger().Warnf("save version of plugin %v failed: %v", p.Name, err)
+		}
+	}
+}